@@ -0,0 +1,141 @@
+package cluster
+
+import (
+	"fmt"
+	"os"
+	"sort"
+
+	"sigs.k8s.io/yaml"
+)
+
+// Display returns the name to show for the cluster: its alias if one is set, otherwise its context
+func (c ClusterInfo) Display() string {
+	if c.DisplayName != "" {
+		return c.DisplayName
+	}
+	return c.Context
+}
+
+// LoadAliases reads a YAML file mapping real context names to friendly names, e.g.
+//
+//	cluster-7f3a9c-prod-eu: prod-eu
+func LoadAliases(path string) (map[string]string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read aliases file: %v", err)
+	}
+	aliases := map[string]string{}
+	if err := yaml.Unmarshal(data, &aliases); err != nil {
+		return nil, fmt.Errorf("failed to parse aliases file %s: %v", path, err)
+	}
+	// An alias must name a single cluster, or --clusters would select one of them at random
+	contexts := make(map[string]string, len(aliases))
+	for _, context := range sortedContexts(aliases) {
+		alias := aliases[context]
+		if other, ok := contexts[alias]; ok {
+			return nil, fmt.Errorf("aliases file %s gives the alias %q to both %s and %s", path, alias, other, context)
+		}
+		contexts[alias] = context
+	}
+	return aliases, nil
+}
+
+// ClusterAliases returns the aliases of the clusters, read from the aliases file and, with label, taken
+// from the value of that label of every cluster, and shows every cluster by its alias. Aliases from the
+// file take precedence and clusters without either keep their context. Label values that would name
+// several clusters are not used and returned sorted in conflicts.
+func ClusterAliases(clusters []ClusterInfo, file, label string) (aliases map[string]string, conflicts []string, err error) {
+	aliases = map[string]string{}
+	if file != "" {
+		if aliases, err = LoadAliases(file); err != nil {
+			return nil, nil, err
+		}
+	}
+	if label != "" {
+		aliases, conflicts = withLabelAliases(clusters, aliases, label)
+	}
+	ApplyAliases(clusters, aliases)
+	return aliases, conflicts, nil
+}
+
+// withLabelAliases adds the label aliases of the clusters without an alias to aliases, leaving out the
+// values shared by several clusters or already given to another cluster
+func withLabelAliases(clusters []ClusterInfo, aliases map[string]string, label string) (map[string]string, []string) {
+	labelAliases, conflicts := LabelAliases(clusters, label)
+	taken := make(map[string]bool, len(aliases))
+	for _, alias := range aliases {
+		taken[alias] = true
+	}
+	merged := make(map[string]string, len(aliases)+len(labelAliases))
+	for context, alias := range aliases {
+		merged[context] = alias
+	}
+	for _, context := range sortedContexts(labelAliases) {
+		if _, ok := aliases[context]; ok {
+			continue
+		}
+		if alias := labelAliases[context]; taken[alias] {
+			conflicts = append(conflicts, alias)
+		} else {
+			merged[context] = alias
+		}
+	}
+	sort.Strings(conflicts)
+	return merged, conflicts
+}
+
+// sortedContexts returns the contexts of aliases in order
+func sortedContexts(aliases map[string]string) []string {
+	contexts := make([]string, 0, len(aliases))
+	for context := range aliases {
+		contexts = append(contexts, context)
+	}
+	sort.Strings(contexts)
+	return contexts
+}
+
+// ApplyAliases sets the DisplayName of every cluster that has an alias
+func ApplyAliases(clusters []ClusterInfo, aliases map[string]string) {
+	for i := range clusters {
+		if alias, ok := aliases[clusters[i].Context]; ok {
+			clusters[i].DisplayName = alias
+		}
+	}
+}
+
+// LabelAliases maps the context of every cluster to the value of its label, for clusters that have it.
+// Values shared by several clusters are left out and returned sorted in conflicts, so that every
+// alias still names a single cluster.
+func LabelAliases(clusters []ClusterInfo, label string) (aliases map[string]string, conflicts []string) {
+	contexts := make(map[string][]string)
+	for _, c := range clusters {
+		if value := c.Labels[label]; value != "" {
+			contexts[value] = append(contexts[value], c.Context)
+		}
+	}
+
+	aliases = make(map[string]string)
+	for value, ctxs := range contexts {
+		if len(ctxs) > 1 {
+			conflicts = append(conflicts, value)
+			continue
+		}
+		aliases[ctxs[0]] = value
+	}
+	sort.Strings(conflicts)
+	return aliases, conflicts
+}
+
+// ResolveContext returns the real context for name, which may be either a context or an alias.
+// Should several contexts have the alias, the first of them in order is returned.
+func ResolveContext(name string, aliases map[string]string) string {
+	if _, ok := aliases[name]; ok {
+		return name
+	}
+	for _, context := range sortedContexts(aliases) {
+		if aliases[context] == name {
+			return context
+		}
+	}
+	return name
+}
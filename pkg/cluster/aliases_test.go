@@ -0,0 +1,124 @@
+package cluster
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// TestResolveContext ensures both real context names and aliases resolve to the real context
+func TestResolveContext(t *testing.T) {
+	aliases := map[string]string{"cluster-7f3a9c-prod-eu": "prod-eu"}
+
+	tests := map[string]string{
+		"prod-eu":                "cluster-7f3a9c-prod-eu",
+		"cluster-7f3a9c-prod-eu": "cluster-7f3a9c-prod-eu",
+		"unaliased":              "unaliased",
+	}
+	for name, want := range tests {
+		if got := ResolveContext(name, aliases); got != want {
+			t.Errorf("ResolveContext(%q) = %q, want %q", name, got, want)
+		}
+	}
+}
+
+// TestApplyAliases ensures aliased clusters display their friendly name and others their context
+func TestApplyAliases(t *testing.T) {
+	clusters := []ClusterInfo{{Context: "cluster-7f3a9c-prod-eu"}, {Context: "wds1"}}
+	ApplyAliases(clusters, map[string]string{"cluster-7f3a9c-prod-eu": "prod-eu"})
+
+	if got := clusters[0].Display(); got != "prod-eu" {
+		t.Errorf("expected alias prod-eu, got %q", got)
+	}
+	if got := clusters[1].Display(); got != "wds1" {
+		t.Errorf("expected context wds1, got %q", got)
+	}
+	if clusters[0].Context != "cluster-7f3a9c-prod-eu" {
+		t.Errorf("expected the real context to be kept, got %q", clusters[0].Context)
+	}
+}
+
+// TestLoadAliases ensures the aliases file is parsed as a context to name map
+func TestLoadAliases(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "aliases.yaml")
+	if err := os.WriteFile(path, []byte("cluster-7f3a9c-prod-eu: prod-eu\ncluster-1b2c3d-dev: dev\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	aliases, err := LoadAliases(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(aliases) != 2 || aliases["cluster-1b2c3d-dev"] != "dev" {
+		t.Errorf("unexpected aliases: %v", aliases)
+	}
+}
+
+// TestLabelAliases ensures clusters are named by their label, clusters without it are left out and
+// values shared by several clusters are reported instead of used
+func TestLabelAliases(t *testing.T) {
+	clusters := []ClusterInfo{
+		{Context: "cluster-7f3a9c", Labels: map[string]string{"region": "eu-west"}},
+		{Context: "cluster-1b2c3d"},
+		{Context: "cluster-a1", Labels: map[string]string{"region": "us-east"}},
+		{Context: "cluster-a2", Labels: map[string]string{"region": "us-east"}},
+	}
+	aliases, conflicts := LabelAliases(clusters, "region")
+
+	if len(aliases) != 1 || aliases["cluster-7f3a9c"] != "eu-west" {
+		t.Errorf("expected only cluster-7f3a9c to be named by its region, got %v", aliases)
+	}
+	if len(conflicts) != 1 || conflicts[0] != "us-east" {
+		t.Errorf("expected us-east to be reported as shared, got %v", conflicts)
+	}
+
+	ApplyAliases(clusters, aliases)
+	if got := clusters[1].Display(); got != "cluster-1b2c3d" {
+		t.Errorf("expected a cluster without the label to fall back to its context, got %q", got)
+	}
+}
+
+// TestLoadAliasesDuplicate ensures an aliases file giving the same alias to several contexts is rejected
+func TestLoadAliasesDuplicate(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "aliases.yaml")
+	if err := os.WriteFile(path, []byte("cluster-b: prod\ncluster-a: prod\ncluster-c: dev\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := LoadAliases(path)
+	if err == nil || !strings.Contains(err.Error(), `gives the alias "prod" to both cluster-a and cluster-b`) {
+		t.Errorf("expected the duplicate alias to be rejected, got %v", err)
+	}
+}
+
+// TestClusterAliasesLabelClash ensures a label value already used as an alias of another cluster is not
+// used, so that every alias resolves to a single cluster
+func TestClusterAliasesLabelClash(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "aliases.yaml")
+	if err := os.WriteFile(path, []byte("cluster-a: prod\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	clusters := []ClusterInfo{
+		{Context: "cluster-a", Labels: map[string]string{"env": "staging"}},
+		{Context: "cluster-b", Labels: map[string]string{"env": "prod"}},
+		{Context: "cluster-c", Labels: map[string]string{"env": "dev"}},
+	}
+
+	aliases, conflicts, err := ClusterAliases(clusters, path, "env")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(conflicts) != 1 || conflicts[0] != "prod" {
+		t.Errorf("expected prod to be reported as a conflict, got %v", conflicts)
+	}
+	want := []string{"prod", "cluster-b", "dev"}
+	for i, c := range clusters {
+		if got := c.Display(); got != want[i] {
+			t.Errorf("expected %s to be shown as %q, got %q", c.Context, want[i], got)
+		}
+	}
+	if got := ResolveContext("prod", aliases); got != "cluster-a" {
+		t.Errorf("expected prod to resolve to cluster-a, got %q", got)
+	}
+}
@@ -0,0 +1,52 @@
+package cluster
+
+import (
+	"fmt"
+
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+// ApplyContextNamespaces sets the Namespace of every cluster from its kubeconfig context, so operations
+// given no --namespace run in the namespace the context pins, as kubectl does
+func ApplyContextNamespaces(kubeconfig string, clusters []ClusterInfo) error {
+	loading := clientcmd.NewDefaultClientConfigLoadingRules()
+	if kubeconfig != "" {
+		loading.ExplicitPath = kubeconfig
+	}
+	rawCfg, err := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loading, &clientcmd.ConfigOverrides{}).RawConfig()
+	if err != nil {
+		return fmt.Errorf("failed to load kubeconfig: %v", err)
+	}
+
+	for i := range clusters {
+		if ctx, ok := rawCfg.Contexts[clusters[i].Context]; ok {
+			clusters[i].Namespace = ctx.Namespace
+		}
+	}
+	return nil
+}
+
+// MarkStaleContexts reloads the kubeconfig and marks the clusters whose context no longer exists in it
+// as failed, e.g. clusters of a --from-file list whose context was removed since the list was written.
+// It returns the stale contexts.
+func MarkStaleContexts(kubeconfig string, clusters []ClusterInfo) ([]string, error) {
+	loading := clientcmd.NewDefaultClientConfigLoadingRules()
+	if kubeconfig != "" {
+		loading.ExplicitPath = kubeconfig
+	}
+	rawCfg, err := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loading, &clientcmd.ConfigOverrides{}).RawConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to reload kubeconfig: %v", err)
+	}
+
+	var stale []string
+	for i := range clusters {
+		// An empty context is the in-cluster configuration, which has no kubeconfig entry
+		if _, ok := rawCfg.Contexts[clusters[i].Context]; ok || clusters[i].Context == "" {
+			continue
+		}
+		stale = append(stale, clusters[i].Context)
+		clusters[i].DiscoveryErr = fmt.Errorf("context %q no longer exists in the kubeconfig", clusters[i].Context)
+	}
+	return stale, nil
+}
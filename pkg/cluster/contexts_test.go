@@ -0,0 +1,132 @@
+package cluster
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"sigs.k8s.io/yaml"
+)
+
+// TestMarkStaleContexts ensures a cached cluster whose context was removed from the kubeconfig is
+// reported as stale while the others are left untouched
+func TestMarkStaleContexts(t *testing.T) {
+	data, err := yaml.Marshal(NewClusterList(dumpedClusters))
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "clusters.yaml")
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	// cluster2 was removed from the kubeconfig after the list was dumped
+	kubeconfig := filepath.Join(t.TempDir(), "config")
+	config := `apiVersion: v1
+kind: Config
+clusters:
+- name: cluster1
+  cluster: {server: "https://127.0.0.1:6443"}
+- name: kind-kubeflex
+  cluster: {server: "https://127.0.0.1:7443"}
+contexts:
+- name: cluster1
+  context: {cluster: cluster1}
+- name: its1
+  context: {cluster: kind-kubeflex}
+`
+	if err := os.WriteFile(kubeconfig, []byte(config), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	clusters, err := LoadClustersFromFile(kubeconfig, path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	stale, err := MarkStaleContexts(kubeconfig, clusters)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(stale, []string{"cluster2"}) {
+		t.Errorf("expected cluster2 to be stale, got %v", stale)
+	}
+	for _, c := range clusters {
+		switch {
+		case c.Context == "cluster2":
+			if c.DiscoveryErr == nil || !strings.Contains(c.DiscoveryErr.Error(), "no longer exists in the kubeconfig") {
+				t.Errorf("expected cluster2 to be marked stale, got %v", c.DiscoveryErr)
+			}
+		case c.DiscoveryErr != nil:
+			t.Errorf("expected %s to be left untouched, got %v", c.Context, c.DiscoveryErr)
+		}
+	}
+}
+
+// TestApplyContextNamespaces ensures every cluster gets the namespace its kubeconfig context pins, and
+// that operations given no --namespace target it
+func TestApplyContextNamespaces(t *testing.T) {
+	kubeconfig := filepath.Join(t.TempDir(), "config")
+	config := `apiVersion: v1
+kind: Config
+clusters:
+- name: cluster1
+  cluster: {server: "https://127.0.0.1:6443"}
+- name: cluster2
+  cluster: {server: "https://127.0.0.1:7443"}
+contexts:
+- name: cluster1
+  context: {cluster: cluster1, namespace: shop}
+- name: cluster2
+  context: {cluster: cluster2}
+current-context: cluster1
+`
+	if err := os.WriteFile(kubeconfig, []byte(config), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	clusters := []ClusterInfo{{Context: "cluster1"}, {Context: "cluster2"}, {Context: "gone"}}
+	if err := ApplyContextNamespaces(kubeconfig, clusters); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var got []string
+	for _, c := range clusters {
+		got = append(got, c.Namespace)
+	}
+	if want := []string{"shop", "", ""}; !reflect.DeepEqual(got, want) {
+		t.Errorf("expected namespaces %q, got %q", want, got)
+	}
+
+	for _, tc := range []struct {
+		cluster   ClusterInfo
+		namespace string
+		want      string
+	}{
+		{clusters[0], "", "shop"},
+		{clusters[0], "billing", "billing"},
+		{clusters[1], "", "default"},
+		{clusters[1], "billing", "billing"},
+	} {
+		if got := TargetNamespace(tc.cluster, tc.namespace); got != tc.want {
+			t.Errorf("TargetNamespace(%s, %q): expected %q, got %q", tc.cluster.Context, tc.namespace, tc.want, got)
+		}
+	}
+	if got := GetTargetNamespace(""); got != "default" {
+		t.Errorf("expected the deprecated GetTargetNamespace to default to \"default\", got %q", got)
+	}
+	if got := GetTargetNamespace("billing"); got != "billing" {
+		t.Errorf("expected the deprecated GetTargetNamespace to keep billing, got %q", got)
+	}
+}
+
+// TestApplyContextNamespacesBadKubeconfig ensures an unreadable kubeconfig is reported as an error
+func TestApplyContextNamespacesBadKubeconfig(t *testing.T) {
+	kubeconfig := filepath.Join(t.TempDir(), "config")
+	if err := os.WriteFile(kubeconfig, []byte("contexts: [not yaml"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if err := ApplyContextNamespaces(kubeconfig, []ClusterInfo{{Context: "cluster1"}}); err == nil {
+		t.Error("expected an error")
+	}
+}
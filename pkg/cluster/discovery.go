@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"sort"
 	"strings"
+	"time"
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime/schema"
@@ -23,10 +24,60 @@ type ClusterInfo struct {
 	DynamicClient   dynamic.Interface
 	DiscoveryClient discovery.DiscoveryInterface
 	RestConfig      *rest.Config
+	// DisplayName is the friendly name shown for the cluster, set from the --aliases file
+	DisplayName string
+	// DiscoveryErr is set when the cluster could not be reached during discovery, its clients are then nil
+	DiscoveryErr error
+	// Labels are the labels of the cluster's ManagedCluster object, empty for the local cluster
+	Labels map[string]string
+	// Role tells how the cluster was discovered, one of RoleManaged, RoleITS or RoleLocal
+	Role string
+	// Namespace is the namespace set in the cluster's kubeconfig context, empty when it sets none
+	Namespace string
+}
+
+// Cluster roles
+const (
+	// RoleManaged is a KubeStellar managed (workload execution) cluster
+	RoleManaged = "managed"
+	// RoleITS is the ITS (control) cluster selected with --remote-context
+	RoleITS = "its"
+	// RoleLocal is the cluster of the current kubeconfig context when it is not the ITS
+	RoleLocal = "local"
+	// RoleWDS is a WDS context, only discovered with DiscoveryOptions.AllContexts
+	RoleWDS = "wds"
+	// RoleContext is any other kubeconfig context, only discovered with DiscoveryOptions.AllContexts
+	RoleContext = "context"
+)
+
+// DiscoveryOptions configures DiscoverClustersWithOptions
+type DiscoveryOptions struct {
+	// Retries is how many times to retry connecting to a managed cluster before marking it with a
+	// DiscoveryErr. 0 disables the reachability check.
+	Retries int
+	// Backoff is the delay before the first retry, doubled after every attempt
+	Backoff time.Duration
+	// AllContexts discovers every context of the kubeconfig instead of the KubeStellar managed clusters.
+	// The role of each context is still set, but WDS contexts and the ITS are not left out.
+	AllContexts bool
+	// Reachability skips the check of clusters it found unreachable recently and records the result
+	// of every check
+	Reachability *ReachabilityCache
 }
 
 // DiscoverClusters finds all clusters including the local cluster and managed clusters
 func DiscoverClusters(kubeconfig, remoteCtx string) ([]ClusterInfo, error) {
+	return DiscoverClustersWithOptions(kubeconfig, remoteCtx, DiscoveryOptions{})
+}
+
+// DiscoverClustersWithOptions finds all clusters like DiscoverClusters. With opts.Retries set, every managed
+// cluster's API server is checked and retried with backoff, and clusters that stay unreachable are returned
+// with a DiscoveryErr instead of being dropped.
+func DiscoverClustersWithOptions(kubeconfig, remoteCtx string, opts DiscoveryOptions) ([]ClusterInfo, error) {
+	if opts.AllContexts {
+		return discoverAllContexts(kubeconfig, remoteCtx, opts)
+	}
+
 	var clusters []ClusterInfo
 
 	// Add managed clusters first (excluding WDS clusters)
@@ -35,12 +86,21 @@ func DiscoverClusters(kubeconfig, remoteCtx string) ([]ClusterInfo, error) {
 		if err != nil {
 			fmt.Printf("Warning: could not list managed clusters: %v\n", err)
 		} else {
-			for _, mcName := range managedClusters {
+			for _, mc := range managedClusters {
+				mcName := mc.Name
 				// Skip WDS clusters - they are for workflow staging, not workload execution
 				if isWDSCluster(mcName) {
 					continue
 				}
 
+				if opts.Retries > 0 {
+					info := connectWithRetries(kubeconfig, mcName, opts)
+					info.Labels = mc.Labels
+					info.Role = RoleManaged
+					clusters = append(clusters, info)
+					continue
+				}
+
 				// Use the managed cluster name as the context, not remoteCtx
 				_, _, cs, dyn, disc, restCfg := buildClusterClient(kubeconfig, mcName)
 				if cs != nil { // Only add if we can connect
@@ -51,6 +111,8 @@ func DiscoverClusters(kubeconfig, remoteCtx string) ([]ClusterInfo, error) {
 						DynamicClient:   dyn,
 						DiscoveryClient: disc,
 						RestConfig:      restCfg,
+						Labels:          mc.Labels,
+						Role:            RoleManaged,
 					})
 				}
 			}
@@ -69,6 +131,10 @@ func DiscoverClusters(kubeconfig, remoteCtx string) ([]ClusterInfo, error) {
 			}
 		}
 		if !found {
+			role := RoleLocal
+			if localCtx == remoteCtx {
+				role = RoleITS
+			}
 			clusters = append(clusters, ClusterInfo{
 				Name:            localCluster,
 				Context:         localCtx,
@@ -76,6 +142,7 @@ func DiscoverClusters(kubeconfig, remoteCtx string) ([]ClusterInfo, error) {
 				DynamicClient:   localDynamic,
 				DiscoveryClient: localDiscovery,
 				RestConfig:      localRestConfig,
+				Role:            role,
 			})
 		}
 	}
@@ -83,6 +150,114 @@ func DiscoverClusters(kubeconfig, remoteCtx string) ([]ClusterInfo, error) {
 	return clusters, nil
 }
 
+// discoverAllContexts returns a cluster for every context of the kubeconfig, sorted by context name
+func discoverAllContexts(kubeconfig, remoteCtx string, opts DiscoveryOptions) ([]ClusterInfo, error) {
+	loading := clientcmd.NewDefaultClientConfigLoadingRules()
+	if kubeconfig != "" {
+		loading.ExplicitPath = kubeconfig
+	}
+	rawCfg, err := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loading, &clientcmd.ConfigOverrides{}).RawConfig()
+	if err != nil {
+		return nil, fmt.Errorf("failed to load kubeconfig: %v", err)
+	}
+
+	names := make([]string, 0, len(rawCfg.Contexts))
+	for name := range rawCfg.Contexts {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var clusters []ClusterInfo
+	for _, name := range names {
+		if opts.Retries > 0 {
+			info := connectWithRetries(kubeconfig, name, opts)
+			info.Role = contextRole(name, remoteCtx)
+			clusters = append(clusters, info)
+			continue
+		}
+
+		_, _, cs, dyn, disc, restCfg := buildClusterClient(kubeconfig, name)
+		if cs == nil {
+			continue
+		}
+		clusters = append(clusters, ClusterInfo{
+			Name:            name,
+			Context:         name,
+			Client:          cs,
+			DynamicClient:   dyn,
+			DiscoveryClient: disc,
+			RestConfig:      restCfg,
+			Role:            contextRole(name, remoteCtx),
+		})
+	}
+	return clusters, nil
+}
+
+// contextRole classifies a kubeconfig context discovered with DiscoveryOptions.AllContexts
+func contextRole(context, remoteCtx string) string {
+	switch {
+	case context == remoteCtx:
+		return RoleITS
+	case isWDSCluster(context):
+		return RoleWDS
+	default:
+		return RoleContext
+	}
+}
+
+// connectWithRetries builds the clients of a managed cluster and checks that its API server answers,
+// retrying with backoff. The returned cluster carries a DiscoveryErr if every attempt failed.
+func connectWithRetries(kubeconfig, mcName string, opts DiscoveryOptions) ClusterInfo {
+	if err := opts.Reachability.Lookup(mcName); err != nil {
+		return ClusterInfo{Name: mcName, Context: mcName, DiscoveryErr: err}
+	}
+
+	var info ClusterInfo
+	err := withRetries(opts, func() error {
+		_, _, cs, dyn, disc, restCfg := buildClusterClient(kubeconfig, mcName)
+		if cs == nil {
+			return fmt.Errorf("failed to build client")
+		}
+		if _, err := disc.ServerVersion(); err != nil {
+			return err
+		}
+		info = ClusterInfo{
+			Name:            mcName,
+			Context:         mcName,
+			Client:          cs,
+			DynamicClient:   dyn,
+			DiscoveryClient: disc,
+			RestConfig:      restCfg,
+		}
+		return nil
+	})
+	opts.Reachability.Record(mcName, err)
+	if err != nil {
+		return ClusterInfo{
+			Name:         mcName,
+			Context:      mcName,
+			DiscoveryErr: fmt.Errorf("unreachable after %d attempts: %v", opts.Retries+1, err),
+		}
+	}
+	return info
+}
+
+// withRetries calls fn until it succeeds, at most opts.Retries+1 times, doubling the delay between attempts
+func withRetries(opts DiscoveryOptions, fn func() error) error {
+	delay := opts.Backoff
+	var err error
+	for attempt := 0; attempt <= opts.Retries; attempt++ {
+		if attempt > 0 {
+			time.Sleep(delay)
+			delay *= 2
+		}
+		if err = fn(); err == nil {
+			return nil
+		}
+	}
+	return err
+}
+
 // isWDSCluster checks if a cluster name indicates it's a Workload Description Space cluster
 func isWDSCluster(clusterName string) bool {
 	// WDS clusters typically have names like "wds1", "wds2", etc.
@@ -114,6 +289,7 @@ func buildClusterClient(kcfg, ctxOverride string) (string, string, *kubernetes.C
 		fmt.Printf("Warning: failed to create rest config: %v\n", err)
 		return "", "", nil, nil, nil, nil
 	}
+	applyClientRateLimit(restCfg)
 
 	cs, err := kubernetes.NewForConfig(restCfg)
 	if err != nil {
@@ -142,8 +318,14 @@ func buildClusterClient(kcfg, ctxOverride string) (string, string, *kubernetes.C
 	return ctxName, clusterName, cs, dyn, disc, restCfg
 }
 
+// managedCluster is a KubeStellar managed cluster and its labels
+type managedCluster struct {
+	Name   string
+	Labels map[string]string
+}
+
 // listManagedClusters discovers KubeStellar managed clusters
-func listManagedClusters(kubeconfig, remoteCtx string) ([]string, error) {
+func listManagedClusters(kubeconfig, remoteCtx string) ([]managedCluster, error) {
 	_, _, _, dyn, _, _ := buildClusterClient(kubeconfig, remoteCtx)
 	if dyn == nil {
 		return nil, fmt.Errorf("failed to create dynamic client for remote context %s", remoteCtx)
@@ -160,24 +342,33 @@ func listManagedClusters(kubeconfig, remoteCtx string) ([]string, error) {
 		return nil, fmt.Errorf("failed to list managed clusters: %v", err)
 	}
 
-	var clusters []string
+	var clusters []managedCluster
 	for _, mc := range mcs.Items {
 		clusterName := mc.GetName()
 		// Filter out WDS clusters at the discovery level too
 		if !isWDSCluster(clusterName) {
-			clusters = append(clusters, clusterName)
+			clusters = append(clusters, managedCluster{Name: clusterName, Labels: mc.GetLabels()})
 		}
 	}
-	sort.Strings(clusters)
+	sort.Slice(clusters, func(i, j int) bool { return clusters[i].Name < clusters[j].Name })
 	return clusters, nil
 }
 
-// GetTargetNamespace determines the target namespace for operations
-func GetTargetNamespace(namespace string) string {
+// TargetNamespace returns the namespace an operation targets in cluster c: the namespace given with
+// --namespace, else the one of the cluster's kubeconfig context like kubectl, else "default"
+func TargetNamespace(c ClusterInfo, namespace string) string {
 	if namespace != "" {
 		return namespace
 	}
-	// Try to get namespace from environment or kubeconfig context
-	// For now, default to "default"
+	if c.Namespace != "" {
+		return c.Namespace
+	}
 	return "default"
 }
+
+// GetTargetNamespace determines the target namespace for operations
+//
+// Deprecated: use TargetNamespace, which also honors the namespace of the cluster's kubeconfig context.
+func GetTargetNamespace(namespace string) string {
+	return TargetNamespace(ClusterInfo{}, namespace)
+}
@@ -0,0 +1,67 @@
+package cluster
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestWithRetriesSucceeds ensures fn is retried until it succeeds
+func TestWithRetriesSucceeds(t *testing.T) {
+	attempts := 0
+	err := withRetries(DiscoveryOptions{Retries: 3}, func() error {
+		attempts++
+		if attempts < 3 {
+			return errors.New("connection refused")
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if attempts != 3 {
+		t.Errorf("expected 3 attempts, got %d", attempts)
+	}
+}
+
+// TestWithRetriesGivesUp ensures the last error is returned once the retries are exhausted
+func TestWithRetriesGivesUp(t *testing.T) {
+	attempts := 0
+	err := withRetries(DiscoveryOptions{Retries: 2}, func() error {
+		attempts++
+		return errors.New("connection refused")
+	})
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+	if attempts != 3 {
+		t.Errorf("expected 1 attempt and 2 retries, got %d attempts", attempts)
+	}
+}
+
+// TestContextRole ensures contexts discovered with AllContexts are classified without being dropped
+func TestContextRole(t *testing.T) {
+	tests := map[string]string{
+		"its1":       RoleITS,
+		"wds1":       RoleWDS,
+		"prod-wds-1": RoleWDS,
+		"cluster1":   RoleContext,
+	}
+	for context, want := range tests {
+		if got := contextRole(context, "its1"); got != want {
+			t.Errorf("contextRole(%q): expected %q, got %q", context, want, got)
+		}
+	}
+}
+
+// TestDiscoverAllContextsBadKubeconfig ensures an unreadable kubeconfig is reported as an error
+func TestDiscoverAllContextsBadKubeconfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config")
+	if err := os.WriteFile(path, []byte("not: [valid"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := DiscoverClustersWithOptions(path, "", DiscoveryOptions{AllContexts: true}); err == nil {
+		t.Error("expected an error for an invalid kubeconfig")
+	}
+}
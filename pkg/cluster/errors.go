@@ -0,0 +1,60 @@
+package cluster
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ClusterError is the failure of an operation on a single cluster
+type ClusterError struct {
+	Context string
+	Err     error
+}
+
+func (e ClusterError) Error() string {
+	return fmt.Sprintf("%s: %v", e.Context, e.Err)
+}
+
+func (e ClusterError) Unwrap() error {
+	return e.Err
+}
+
+// MultiClusterError is returned when an operation fails on one or more clusters.
+// Use errors.As to extract it and inspect which clusters failed and why.
+type MultiClusterError struct {
+	// Total is the number of clusters the operation ran on
+	Total  int
+	Errors []ClusterError
+}
+
+func (e *MultiClusterError) Error() string {
+	return fmt.Sprintf("operation failed on %d of %d clusters: %s", len(e.Errors), e.Total, strings.Join(e.Contexts(), ", "))
+}
+
+// Unwrap returns the per-cluster errors so errors.Is and errors.As can match them
+func (e *MultiClusterError) Unwrap() []error {
+	errs := make([]error, len(e.Errors))
+	for i, ce := range e.Errors {
+		errs[i] = ce
+	}
+	return errs
+}
+
+// Contexts returns the contexts of the clusters that failed
+func (e *MultiClusterError) Contexts() []string {
+	contexts := make([]string, len(e.Errors))
+	for i, ce := range e.Errors {
+		contexts[i] = ce.Context
+	}
+	return contexts
+}
+
+// ErrorFor returns the error of the given cluster context, or nil if it did not fail
+func (e *MultiClusterError) ErrorFor(context string) error {
+	for _, ce := range e.Errors {
+		if ce.Context == context {
+			return ce.Err
+		}
+	}
+	return nil
+}
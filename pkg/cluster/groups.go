@@ -0,0 +1,49 @@
+package cluster
+
+import (
+	"fmt"
+	"os"
+
+	"sigs.k8s.io/yaml"
+)
+
+// LoadGroups reads a YAML file mapping group names to lists of contexts, e.g.
+//
+//	prod:
+//	- prod-eu
+//	- prod-us
+//
+// A missing file is not an error and yields no groups.
+func LoadGroups(path string) (map[string][]string, error) {
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return map[string][]string{}, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read groups file: %v", err)
+	}
+	groups := map[string][]string{}
+	if err := yaml.Unmarshal(data, &groups); err != nil {
+		return nil, fmt.Errorf("failed to parse groups file %s: %v", path, err)
+	}
+	return groups, nil
+}
+
+// ExpandGroups returns the contexts of the named groups, in group order and without duplicates
+func ExpandGroups(groups map[string][]string, names []string) ([]string, error) {
+	var contexts []string
+	seen := make(map[string]bool)
+	for _, name := range names {
+		members, ok := groups[name]
+		if !ok {
+			return nil, fmt.Errorf("unknown cluster group %q", name)
+		}
+		for _, context := range members {
+			if !seen[context] {
+				seen[context] = true
+				contexts = append(contexts, context)
+			}
+		}
+	}
+	return contexts, nil
+}
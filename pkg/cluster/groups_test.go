@@ -0,0 +1,55 @@
+package cluster
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// TestLoadGroups ensures groups are read from YAML and a missing file yields no groups
+func TestLoadGroups(t *testing.T) {
+	dir := t.TempDir()
+
+	groups, err := LoadGroups(filepath.Join(dir, "missing.yaml"))
+	if err != nil || len(groups) != 0 {
+		t.Fatalf("expected no groups and no error for a missing file, got %v, %v", groups, err)
+	}
+
+	path := filepath.Join(dir, "groups.yaml")
+	if err := os.WriteFile(path, []byte("prod:\n- prod-eu\n- prod-us\nstaging:\n- staging-eu\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	groups, err = LoadGroups(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(groups["prod"]) != 2 || groups["staging"][0] != "staging-eu" {
+		t.Errorf("unexpected groups: %v", groups)
+	}
+}
+
+// TestExpandGroups ensures group members are merged without duplicates and unknown groups are rejected
+func TestExpandGroups(t *testing.T) {
+	groups := map[string][]string{
+		"prod": {"prod-eu", "prod-us"},
+		"eu":   {"prod-eu", "staging-eu"},
+	}
+
+	contexts, err := ExpandGroups(groups, []string{"prod", "eu"})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := []string{"prod-eu", "prod-us", "staging-eu"}
+	if len(contexts) != len(want) {
+		t.Fatalf("expected %v, got %v", want, contexts)
+	}
+	for i := range want {
+		if contexts[i] != want[i] {
+			t.Errorf("expected %v, got %v", want, contexts)
+		}
+	}
+
+	if _, err := ExpandGroups(groups, []string{"dev"}); err == nil {
+		t.Error("expected an error for an unknown group")
+	}
+}
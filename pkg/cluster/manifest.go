@@ -0,0 +1,82 @@
+package cluster
+
+import (
+	"fmt"
+	"os"
+
+	"sigs.k8s.io/yaml"
+)
+
+// ClusterManifest is the serialized form of a discovered cluster, written by `clusters dump`
+type ClusterManifest struct {
+	Context string            `json:"context"`
+	Name    string            `json:"name"`
+	Role    string            `json:"role,omitempty"`
+	Labels  map[string]string `json:"labels,omitempty"`
+}
+
+// ClusterList is a list of discovered clusters that can be loaded instead of running discovery
+type ClusterList struct {
+	Clusters []ClusterManifest `json:"clusters"`
+}
+
+// NewClusterList returns the manifest of the given clusters
+func NewClusterList(clusters []ClusterInfo) ClusterList {
+	list := ClusterList{Clusters: []ClusterManifest{}}
+	for _, c := range clusters {
+		list.Clusters = append(list.Clusters, ClusterManifest{
+			Context: c.Context,
+			Name:    c.Name,
+			Role:    c.Role,
+			Labels:  c.Labels,
+		})
+	}
+	return list
+}
+
+// ParseClusterList parses a YAML or JSON cluster list written by `clusters dump`
+func ParseClusterList(data []byte) (ClusterList, error) {
+	var list ClusterList
+	if err := yaml.UnmarshalStrict(data, &list); err != nil {
+		return ClusterList{}, err
+	}
+	for i, m := range list.Clusters {
+		if m.Context == "" {
+			return ClusterList{}, fmt.Errorf("cluster %d has no context", i)
+		}
+	}
+	return list, nil
+}
+
+// LoadClustersFromFile returns the clusters listed in a file written by `clusters dump` without running
+// discovery. Clients are built from the kubeconfig for every listed context.
+func LoadClustersFromFile(kubeconfig, path string) ([]ClusterInfo, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read clusters file: %v", err)
+	}
+	list, err := ParseClusterList(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse clusters file %s: %v", path, err)
+	}
+
+	var clusters []ClusterInfo
+	for _, m := range list.Clusters {
+		_, _, cs, dyn, disc, restCfg := buildClusterClient(kubeconfig, m.Context)
+		name := m.Name
+		if name == "" {
+			name = m.Context
+		}
+		clusters = append(clusters, ClusterInfo{
+			Name:            name,
+			Context:         m.Context,
+			Client:          cs,
+			DynamicClient:   dyn,
+			DiscoveryClient: disc,
+			RestConfig:      restCfg,
+			Labels:          m.Labels,
+			Role:            m.Role,
+		})
+	}
+	return clusters, nil
+}
@@ -0,0 +1,96 @@
+package cluster
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"sigs.k8s.io/yaml"
+)
+
+var dumpedClusters = []ClusterInfo{
+	{Name: "cluster1", Context: "cluster1", Role: RoleManaged, Labels: map[string]string{"region": "eu-west"}},
+	{Name: "cluster2", Context: "cluster2", Role: RoleManaged},
+	{Name: "kind-kubeflex", Context: "its1", Role: RoleITS},
+}
+
+// TestClusterListRoundTripYAML ensures a dumped YAML list parses back to the same clusters
+func TestClusterListRoundTripYAML(t *testing.T) {
+	want := NewClusterList(dumpedClusters)
+	data, err := yaml.Marshal(want)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	got, err := ParseClusterList(data)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("round trip mismatch:\nwant %+v\ngot  %+v", want, got)
+	}
+}
+
+// TestClusterListRoundTripJSON ensures a dumped JSON list parses back to the same clusters
+func TestClusterListRoundTripJSON(t *testing.T) {
+	want := NewClusterList(dumpedClusters)
+	data, err := json.Marshal(want)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	got, err := ParseClusterList(data)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("round trip mismatch:\nwant %+v\ngot  %+v", want, got)
+	}
+}
+
+// TestLoadClustersFromFile ensures the listed clusters are returned without discovery
+func TestLoadClustersFromFile(t *testing.T) {
+	data, err := yaml.Marshal(NewClusterList(dumpedClusters))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	path := filepath.Join(t.TempDir(), "clusters.yaml")
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	// The contexts are not in this kubeconfig, the clusters are still listed without clients
+	kubeconfig := filepath.Join(t.TempDir(), "config")
+	if err := os.WriteFile(kubeconfig, []byte("apiVersion: v1\nkind: Config\n"), 0o644); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	clusters, err := LoadClustersFromFile(kubeconfig, path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(clusters) != len(dumpedClusters) {
+		t.Fatalf("expected %d clusters, got %d", len(dumpedClusters), len(clusters))
+	}
+	for i, c := range clusters {
+		want := dumpedClusters[i]
+		if c.Name != want.Name || c.Context != want.Context || c.Role != want.Role || !reflect.DeepEqual(c.Labels, want.Labels) {
+			t.Errorf("cluster %d: expected %+v, got %+v", i, want, c)
+		}
+	}
+}
+
+// TestParseClusterListInvalid ensures entries without a context and unknown fields are rejected
+func TestParseClusterListInvalid(t *testing.T) {
+	for _, data := range []string{
+		"clusters:\n- name: cluster1\n",
+		"clusters:\n- context: cluster1\n  region: eu\n",
+		"not: [valid",
+	} {
+		if _, err := ParseClusterList([]byte(data)); err == nil {
+			t.Errorf("expected an error for %q", data)
+		}
+	}
+}
@@ -0,0 +1,21 @@
+package cluster
+
+// openShiftProjectGroup is an API group only served by OpenShift clusters
+const openShiftProjectGroup = "project.openshift.io"
+
+// IsOpenShift reports whether the cluster serves the OpenShift project API group
+func IsOpenShift(c ClusterInfo) bool {
+	if c.DiscoveryClient == nil {
+		return false
+	}
+	groups, err := c.DiscoveryClient.ServerGroups()
+	if err != nil {
+		return false
+	}
+	for _, g := range groups.Groups {
+		if g.Name == openShiftProjectGroup {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,41 @@
+package cluster
+
+import (
+	"fmt"
+
+	"k8s.io/client-go/rest"
+)
+
+// ClientRateLimit is the client-side rate limit of the API clients built for each cluster
+type ClientRateLimit struct {
+	// QPS is the sustained number of requests per second to a cluster, 0 keeps the client-go default of 5
+	QPS float32
+	// Burst is the number of requests allowed above QPS for short periods, 0 keeps the client-go default of 10
+	Burst int
+}
+
+// clientRateLimit applies to every cluster client built after SetClientRateLimit
+var clientRateLimit ClientRateLimit
+
+// SetClientRateLimit validates l and applies it to the clients built from now on for every cluster.
+// Each cluster gets its own limit, so the total rate grows with the number of clusters.
+func SetClientRateLimit(l ClientRateLimit) error {
+	if l.QPS < 0 {
+		return fmt.Errorf("--qps must not be negative, got %v", l.QPS)
+	}
+	if l.Burst < 0 {
+		return fmt.Errorf("--burst must not be negative, got %d", l.Burst)
+	}
+	clientRateLimit = l
+	return nil
+}
+
+// applyClientRateLimit sets the configured rate limit on the REST config of a cluster
+func applyClientRateLimit(cfg *rest.Config) {
+	if clientRateLimit.QPS > 0 {
+		cfg.QPS = clientRateLimit.QPS
+	}
+	if clientRateLimit.Burst > 0 {
+		cfg.Burst = clientRateLimit.Burst
+	}
+}
@@ -0,0 +1,37 @@
+package cluster
+
+import (
+	"testing"
+
+	"k8s.io/client-go/rest"
+)
+
+// TestSetClientRateLimit ensures negative values are rejected and zero values keep the client defaults
+func TestSetClientRateLimit(t *testing.T) {
+	defer func(l ClientRateLimit) { clientRateLimit = l }(clientRateLimit)
+
+	if err := SetClientRateLimit(ClientRateLimit{QPS: -1}); err == nil {
+		t.Error("expected an error for a negative QPS")
+	}
+	if err := SetClientRateLimit(ClientRateLimit{Burst: -1}); err == nil {
+		t.Error("expected an error for a negative burst")
+	}
+
+	if err := SetClientRateLimit(ClientRateLimit{QPS: 50}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	cfg := &rest.Config{Burst: 10}
+	applyClientRateLimit(cfg)
+	if cfg.QPS != 50 || cfg.Burst != 10 {
+		t.Errorf("expected QPS 50 with the burst unchanged, got %v/%d", cfg.QPS, cfg.Burst)
+	}
+
+	if err := SetClientRateLimit(ClientRateLimit{QPS: 20, Burst: 40}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	cfg = &rest.Config{}
+	applyClientRateLimit(cfg)
+	if cfg.QPS != 20 || cfg.Burst != 40 {
+		t.Errorf("expected QPS 20 and burst 40, got %v/%d", cfg.QPS, cfg.Burst)
+	}
+}
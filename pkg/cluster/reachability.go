@@ -0,0 +1,98 @@
+package cluster
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// ReachabilityCache remembers the clusters found unreachable for a short time, so that commands run
+// back-to-back mark them unreachable right away instead of probing them again. A nil cache, or one
+// with a TTL of 0, remembers nothing.
+type ReachabilityCache struct {
+	// Unreachable maps contexts to when they were last found unreachable and why
+	Unreachable map[string]UnreachableEntry `json:"unreachable"`
+
+	path string
+	ttl  time.Duration
+	now  func() time.Time
+}
+
+// UnreachableEntry records a failed attempt to reach a cluster
+type UnreachableEntry struct {
+	Since time.Time `json:"since"`
+	Error string    `json:"error"`
+}
+
+// LoadReachabilityCache reads the cache file at path, a missing file is an empty cache.
+// Entries older than ttl are ignored.
+func LoadReachabilityCache(path string, ttl time.Duration) (*ReachabilityCache, error) {
+	cache := &ReachabilityCache{Unreachable: map[string]UnreachableEntry{}, path: path, ttl: ttl, now: time.Now}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return cache, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read reachability cache: %v", err)
+	}
+	if err := json.Unmarshal(data, cache); err != nil {
+		return nil, fmt.Errorf("failed to parse reachability cache %s: %v", path, err)
+	}
+	if cache.Unreachable == nil {
+		cache.Unreachable = map[string]UnreachableEntry{}
+	}
+	return cache, nil
+}
+
+// Lookup returns an error if context was found unreachable less than the TTL ago
+func (c *ReachabilityCache) Lookup(context string) error {
+	if c == nil || c.ttl <= 0 {
+		return nil
+	}
+	entry, ok := c.Unreachable[context]
+	if !ok {
+		return nil
+	}
+	age := c.now().Sub(entry.Since)
+	if age >= c.ttl {
+		return nil
+	}
+	return fmt.Errorf("unreachable %s ago, not probed again for %s: %s", age.Round(time.Second), (c.ttl - age).Round(time.Second), entry.Error)
+}
+
+// Record remembers that context could not be reached with err, or forgets it when err is nil
+func (c *ReachabilityCache) Record(context string, err error) {
+	if c == nil || c.ttl <= 0 {
+		return
+	}
+	if err == nil {
+		delete(c.Unreachable, context)
+		return
+	}
+	c.Unreachable[context] = UnreachableEntry{Since: c.now(), Error: err.Error()}
+}
+
+// Save writes the cache file, leaving out expired entries
+func (c *ReachabilityCache) Save() error {
+	if c == nil || c.ttl <= 0 {
+		return nil
+	}
+	for context, entry := range c.Unreachable {
+		if c.now().Sub(entry.Since) >= c.ttl {
+			delete(c.Unreachable, context)
+		}
+	}
+	if err := os.MkdirAll(filepath.Dir(c.path), 0o755); err != nil {
+		return fmt.Errorf("failed to create cache directory: %v", err)
+	}
+	data, err := json.MarshalIndent(c, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(c.path, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write reachability cache: %v", err)
+	}
+	return nil
+}
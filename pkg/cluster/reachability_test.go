@@ -0,0 +1,82 @@
+package cluster
+
+import (
+	"errors"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// TestReachabilityCacheExpiry ensures an unreachable cluster is only reported until the TTL expires
+func TestReachabilityCacheExpiry(t *testing.T) {
+	cache, err := LoadReachabilityCache(filepath.Join(t.TempDir(), "reachability.json"), 30*time.Second)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	cache.now = func() time.Time { return now }
+
+	cache.Record("cluster1", errors.New("connection refused"))
+	now = now.Add(10 * time.Second)
+	if err := cache.Lookup("cluster1"); err == nil {
+		t.Errorf("expected cluster1 to still be unreachable after 10s")
+	}
+	if err := cache.Lookup("cluster2"); err != nil {
+		t.Errorf("expected an unknown cluster to be probed, got %v", err)
+	}
+
+	now = now.Add(20 * time.Second)
+	if err := cache.Lookup("cluster1"); err != nil {
+		t.Errorf("expected cluster1 to be probed again once the TTL expired, got %v", err)
+	}
+}
+
+// TestReachabilityCacheRecordReachable ensures a cluster that answers again is forgotten
+func TestReachabilityCacheRecordReachable(t *testing.T) {
+	cache, _ := LoadReachabilityCache(filepath.Join(t.TempDir(), "reachability.json"), time.Minute)
+	cache.Record("cluster1", errors.New("no route to host"))
+	cache.Record("cluster1", nil)
+	if err := cache.Lookup("cluster1"); err != nil {
+		t.Errorf("expected cluster1 to be forgotten, got %v", err)
+	}
+}
+
+// TestReachabilityCacheSave ensures entries survive a save and load, except the expired ones
+func TestReachabilityCacheSave(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache", "reachability.json")
+	cache, _ := LoadReachabilityCache(path, time.Minute)
+	now := time.Now()
+	cache.now = func() time.Time { return now.Add(-2 * time.Minute) }
+	cache.Record("stale", errors.New("i/o timeout"))
+	cache.now = func() time.Time { return now }
+	cache.Record("down", errors.New("connection refused"))
+	if err := cache.Save(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	loaded, err := LoadReachabilityCache(path, time.Minute)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, ok := loaded.Unreachable["stale"]; ok {
+		t.Errorf("expected the expired entry to be dropped on save")
+	}
+	if err := loaded.Lookup("down"); err == nil {
+		t.Errorf("expected the saved entry to still mark the cluster unreachable")
+	}
+}
+
+// TestReachabilityCacheDisabled ensures a TTL of 0, or a nil cache, remembers nothing
+func TestReachabilityCacheDisabled(t *testing.T) {
+	cache, _ := LoadReachabilityCache(filepath.Join(t.TempDir(), "reachability.json"), 0)
+	cache.Record("cluster1", errors.New("connection refused"))
+	if err := cache.Lookup("cluster1"); err != nil {
+		t.Errorf("expected a TTL of 0 to disable the cache, got %v", err)
+	}
+
+	var none *ReachabilityCache
+	none.Record("cluster1", errors.New("connection refused"))
+	if err := none.Lookup("cluster1"); err != nil || none.Save() != nil {
+		t.Errorf("expected a nil cache to remember nothing")
+	}
+}
@@ -0,0 +1,88 @@
+package cluster
+
+import (
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+// DiscoveryAPIVersion is the version of the DiscoveryReport schema. It changes whenever a field is
+// removed or its meaning changes, new fields may be added without a new version.
+const DiscoveryAPIVersion = "kubectl-multi/v1"
+
+// DiscoveryReportKind is the kind of a DiscoveryReport
+const DiscoveryReportKind = "DiscoveryReport"
+
+// DiscoveryReport is the machine-readable result of discovery, printed by `discover` for other tools
+type DiscoveryReport struct {
+	APIVersion string              `json:"apiVersion"`
+	Kind       string              `json:"kind"`
+	Clusters   []DiscoveredCluster `json:"clusters"`
+}
+
+// DiscoveredCluster describes a discovered cluster in a DiscoveryReport
+type DiscoveredCluster struct {
+	Context string `json:"context"`
+	Name    string `json:"name"`
+	// DisplayName is the alias of the cluster, if any
+	DisplayName string `json:"displayName,omitempty"`
+	// Server is the URL of the cluster's API server, from the kubeconfig
+	Server string            `json:"server,omitempty"`
+	Role   string            `json:"role,omitempty"`
+	Labels map[string]string `json:"labels,omitempty"`
+	// Reachable is set when the API server answered, ServerVersion then holds its version
+	Reachable     bool   `json:"reachable"`
+	ServerVersion string `json:"serverVersion,omitempty"`
+	// Error tells why the cluster is not reachable
+	Error string `json:"error,omitempty"`
+}
+
+// NewDiscoveryReport describes the given clusters. probe checks that a cluster's API server answers
+// and returns its version. Clusters that failed discovery are reported unreachable without being probed.
+// servers maps contexts to their API server URL, for the clusters that have no client.
+func NewDiscoveryReport(clusters []ClusterInfo, servers map[string]string, probe func(c ClusterInfo) (string, error)) DiscoveryReport {
+	report := DiscoveryReport{APIVersion: DiscoveryAPIVersion, Kind: DiscoveryReportKind, Clusters: []DiscoveredCluster{}}
+	for _, c := range clusters {
+		d := DiscoveredCluster{
+			Context:     c.Context,
+			Name:        c.Name,
+			DisplayName: c.DisplayName,
+			Server:      servers[c.Context],
+			Role:        c.Role,
+			Labels:      c.Labels,
+		}
+		if c.RestConfig != nil {
+			d.Server = c.RestConfig.Host
+		}
+
+		err := c.DiscoveryErr
+		if err == nil {
+			d.ServerVersion, err = probe(c)
+		}
+		if err != nil {
+			d.Error = err.Error()
+		} else {
+			d.Reachable = true
+		}
+		report.Clusters = append(report.Clusters, d)
+	}
+	return report
+}
+
+// KubeconfigServers maps every context of the kubeconfig to the API server URL of its cluster
+func KubeconfigServers(kubeconfig string) map[string]string {
+	loading := clientcmd.NewDefaultClientConfigLoadingRules()
+	if kubeconfig != "" {
+		loading.ExplicitPath = kubeconfig
+	}
+	rawCfg, err := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loading, &clientcmd.ConfigOverrides{}).RawConfig()
+	if err != nil {
+		return nil
+	}
+
+	servers := make(map[string]string, len(rawCfg.Contexts))
+	for name, ctx := range rawCfg.Contexts {
+		if c, ok := rawCfg.Clusters[ctx.Cluster]; ok {
+			servers[name] = c.Server
+		}
+	}
+	return servers
+}
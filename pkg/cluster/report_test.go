@@ -0,0 +1,53 @@
+package cluster
+
+import (
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+
+	"k8s.io/client-go/rest"
+)
+
+// TestNewDiscoveryReport ensures reachable, unreachable and failed clusters are described with their server
+func TestNewDiscoveryReport(t *testing.T) {
+	clusters := []ClusterInfo{
+		{Name: "cluster1", Context: "cluster1", Role: RoleManaged, Labels: map[string]string{"region": "eu-west"}, RestConfig: &rest.Config{Host: "https://10.0.0.1:6443"}},
+		{Name: "cluster2", Context: "cluster2", Role: RoleManaged, DiscoveryErr: errors.New("unreachable after 3 attempts")},
+		{Name: "its1", Context: "its1", Role: RoleITS, RestConfig: &rest.Config{Host: "https://10.0.0.3:6443"}},
+	}
+	servers := map[string]string{"cluster2": "https://10.0.0.2:6443"}
+	probed := 0
+	report := NewDiscoveryReport(clusters, servers, func(c ClusterInfo) (string, error) {
+		probed++
+		if c.Context == "its1" {
+			return "", errors.New("connection refused")
+		}
+		return "v1.29.0", nil
+	})
+
+	if report.APIVersion != DiscoveryAPIVersion || report.Kind != DiscoveryReportKind {
+		t.Errorf("expected a versioned report, got %s %s", report.APIVersion, report.Kind)
+	}
+	if probed != 2 {
+		t.Errorf("expected only the clusters that passed discovery to be probed, got %d probes", probed)
+	}
+	c1, c2, its := report.Clusters[0], report.Clusters[1], report.Clusters[2]
+	if !c1.Reachable || c1.ServerVersion != "v1.29.0" || c1.Server != "https://10.0.0.1:6443" || c1.Labels["region"] != "eu-west" {
+		t.Errorf("unexpected reachable cluster: %+v", c1)
+	}
+	if c2.Reachable || c2.Error == "" || c2.Server != "https://10.0.0.2:6443" {
+		t.Errorf("expected cluster2 to be unreachable with its kubeconfig server, got %+v", c2)
+	}
+	if its.Reachable || its.Error != "connection refused" || its.Role != RoleITS {
+		t.Errorf("expected the failed probe to be reported, got %+v", its)
+	}
+
+	data, err := json.Marshal(report)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.HasPrefix(string(data), `{"apiVersion":"kubectl-multi/v1","kind":"DiscoveryReport"`) {
+		t.Errorf("unexpected JSON: %s", data)
+	}
+}
@@ -0,0 +1,38 @@
+package cluster
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"sigs.k8s.io/yaml"
+)
+
+// LoadTimeoutOverrides reads a YAML file mapping contexts (or aliases) to the per-cluster timeout
+// they need instead of the global one, e.g.
+//
+//	slow-edge-cluster: 5m
+//	lab: 10s
+func LoadTimeoutOverrides(path string) (map[string]time.Duration, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read timeout overrides file: %v", err)
+	}
+	raw := map[string]string{}
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("failed to parse timeout overrides file %s: %v", path, err)
+	}
+
+	overrides := make(map[string]time.Duration, len(raw))
+	for context, value := range raw {
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return nil, fmt.Errorf("invalid timeout %q for %s in %s: %v", value, context, path, err)
+		}
+		if d <= 0 {
+			return nil, fmt.Errorf("invalid timeout %q for %s in %s: must be positive", value, context, path)
+		}
+		overrides[context] = d
+	}
+	return overrides, nil
+}
@@ -0,0 +1,40 @@
+package cluster
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func writeTimeoutOverrides(t *testing.T, data string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "timeouts.yaml")
+	if err := os.WriteFile(path, []byte(data), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+// TestLoadTimeoutOverrides ensures durations are parsed per context
+func TestLoadTimeoutOverrides(t *testing.T) {
+	path := writeTimeoutOverrides(t, "slow-edge: 5m\nlab: 10s\n")
+	overrides, err := LoadTimeoutOverrides(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if overrides["slow-edge"] != 5*time.Minute || overrides["lab"] != 10*time.Second {
+		t.Errorf("unexpected overrides: %v", overrides)
+	}
+}
+
+// TestLoadTimeoutOverridesInvalid ensures invalid and non-positive durations are rejected
+func TestLoadTimeoutOverridesInvalid(t *testing.T) {
+	for _, data := range []string{"slow-edge: 5 minutes\n", "lab: 0s\n", "lab: -1m\n"} {
+		_, err := LoadTimeoutOverrides(writeTimeoutOverrides(t, data))
+		if err == nil || !strings.Contains(err.Error(), "invalid timeout") {
+			t.Errorf("%q: expected an invalid timeout error, got %v", data, err)
+		}
+	}
+}
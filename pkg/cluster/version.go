@@ -0,0 +1,176 @@
+package cluster
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"k8s.io/apimachinery/pkg/util/version"
+)
+
+// ServerVersionTTL is how long the version of an API server is cached. Clusters are upgraded far
+// less often than commands are run against them.
+const ServerVersionTTL = time.Hour
+
+// ServerVersionCache remembers the versions of the API servers across commands, so that checks such as
+// the minimum server version of a fan-out don't query every server again. A nil cache remembers nothing.
+type ServerVersionCache struct {
+	// Versions maps contexts and their servers to the version found
+	Versions map[string]ServerVersionEntry `json:"versions"`
+
+	path    string
+	ttl     time.Duration
+	now     func() time.Time
+	mu      sync.Mutex
+	changed bool
+}
+
+// ServerVersionEntry records the version of an API server and when it was found
+type ServerVersionEntry struct {
+	GitVersion string    `json:"gitVersion"`
+	Since      time.Time `json:"since"`
+}
+
+// LoadServerVersionCache reads the cache file at path, a missing file is an empty cache.
+// Entries older than ttl are ignored.
+func LoadServerVersionCache(path string, ttl time.Duration) (*ServerVersionCache, error) {
+	cache := &ServerVersionCache{Versions: map[string]ServerVersionEntry{}, path: path, ttl: ttl, now: time.Now}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return cache, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read server version cache: %v", err)
+	}
+	if err := json.Unmarshal(data, cache); err != nil {
+		return nil, fmt.Errorf("failed to parse server version cache %s: %v", path, err)
+	}
+	if cache.Versions == nil {
+		cache.Versions = map[string]ServerVersionEntry{}
+	}
+	return cache, nil
+}
+
+// serverVersionKey identifies the API server of a cluster, a context pointed at another server is not
+// mistaken for the old one
+func serverVersionKey(c ClusterInfo) string {
+	if c.RestConfig != nil && c.RestConfig.Host != "" {
+		return c.Context + " " + c.RestConfig.Host
+	}
+	return c.Context
+}
+
+// Record remembers the gitVersion reported by the API server of a cluster
+func (s *ServerVersionCache) Record(c ClusterInfo, gitVersion string) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.Versions[serverVersionKey(c)] = ServerVersionEntry{GitVersion: gitVersion, Since: s.now()}
+	s.changed = true
+}
+
+// lookup returns the cached gitVersion of a cluster, if it is recent enough
+func (s *ServerVersionCache) lookup(c ClusterInfo) (string, bool) {
+	if s == nil {
+		return "", false
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	entry, ok := s.Versions[serverVersionKey(c)]
+	if !ok || s.now().Sub(entry.Since) >= s.ttl {
+		return "", false
+	}
+	return entry.GitVersion, true
+}
+
+// ServerVersion returns the Kubernetes version of a cluster's API server, from the cache when it
+// was found less than the TTL ago
+func (s *ServerVersionCache) ServerVersion(c ClusterInfo) (*version.Version, error) {
+	gitVersion, ok := s.lookup(c)
+	if !ok {
+		var err error
+		if gitVersion, err = serverGitVersion(c); err != nil {
+			return nil, err
+		}
+		s.Record(c, gitVersion)
+	}
+	return ParseServerVersion(gitVersion)
+}
+
+// MeetsMinServerVersion reports whether the cluster's API server is at least minVersion, along with the server version found
+func (s *ServerVersionCache) MeetsMinServerVersion(c ClusterInfo, minVersion string) (bool, *version.Version, error) {
+	required, err := version.ParseGeneric(minVersion)
+	if err != nil {
+		return false, nil, fmt.Errorf("invalid minimum server version %q: %v", minVersion, err)
+	}
+	v, err := s.ServerVersion(c)
+	if err != nil {
+		return false, nil, err
+	}
+	return v.AtLeast(required), v, nil
+}
+
+// Save writes the cache file if versions were found since it was loaded, leaving out expired entries
+func (s *ServerVersionCache) Save() error {
+	if s == nil {
+		return nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if !s.changed {
+		return nil
+	}
+	for key, entry := range s.Versions {
+		if s.now().Sub(entry.Since) >= s.ttl {
+			delete(s.Versions, key)
+		}
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), 0o755); err != nil {
+		return fmt.Errorf("failed to create cache directory: %v", err)
+	}
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(s.path, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write server version cache: %v", err)
+	}
+	s.changed = false
+	return nil
+}
+
+// ServerVersion queries the Kubernetes version of a cluster's API server
+func ServerVersion(c ClusterInfo) (*version.Version, error) {
+	gitVersion, err := serverGitVersion(c)
+	if err != nil {
+		return nil, err
+	}
+	return ParseServerVersion(gitVersion)
+}
+
+// serverGitVersion queries the gitVersion of a cluster's API server, e.g. v1.29.3-eks-adc7111
+func serverGitVersion(c ClusterInfo) (string, error) {
+	if c.DiscoveryClient == nil {
+		return "", fmt.Errorf("no discovery client available for cluster %s", c.Name)
+	}
+	info, err := c.DiscoveryClient.ServerVersion()
+	if err != nil {
+		return "", fmt.Errorf("failed to get server version: %v", err)
+	}
+	return info.GitVersion, nil
+}
+
+// ParseServerVersion parses the gitVersion of an API server. The suffixes of distributions, such as
+// +k3s1 or -eks-adc7111, are ignored.
+func ParseServerVersion(gitVersion string) (*version.Version, error) {
+	v, err := version.ParseGeneric(gitVersion)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse server version %q: %v", gitVersion, err)
+	}
+	return v, nil
+}
@@ -0,0 +1,84 @@
+package cluster
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"k8s.io/client-go/rest"
+)
+
+// TestMeetsMinServerVersion ensures server versions compare by their numbers, whatever the suffix of
+// the distribution
+func TestMeetsMinServerVersion(t *testing.T) {
+	for _, tc := range []struct {
+		gitVersion string
+		min        string
+		want       bool
+		err        string
+	}{
+		{"v1.29.3", "1.22", true, ""},
+		{"v1.22.0", "1.22", true, ""},
+		{"v1.21.14", "1.22", false, ""},
+		{"v1.22.17-eks-0a21954", "1.22", true, ""},
+		{"v1.21.14-eks-fb459a0", "1.22", false, ""},
+		{"v1.28.5+k3s1", "1.28.5", true, ""},
+		{"v1.28.4+k3s2", "1.28.5", false, ""},
+		{"v1.27.8-gke.1067004", "v1.27", true, ""},
+		{"v1.30", "1.29.9", true, ""},
+		{"v1.29.3", "latest", false, `invalid minimum server version "latest"`},
+		{"unknown", "1.22", false, `failed to parse server version "unknown"`},
+	} {
+		cache, err := LoadServerVersionCache(filepath.Join(t.TempDir(), "versions.json"), time.Hour)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		c := ClusterInfo{Name: "cluster1", Context: "cluster1"}
+		cache.Record(c, tc.gitVersion)
+		ok, _, err := cache.MeetsMinServerVersion(c, tc.min)
+		if tc.err != "" {
+			if err == nil || !strings.Contains(err.Error(), tc.err) {
+				t.Errorf("%s >= %s: expected an error containing %q, got %v", tc.gitVersion, tc.min, tc.err, err)
+			}
+			continue
+		}
+		if err != nil || ok != tc.want {
+			t.Errorf("%s >= %s: expected %v, got %v, %v", tc.gitVersion, tc.min, tc.want, ok, err)
+		}
+	}
+}
+
+// TestServerVersionCacheSave ensures the versions survive a save and load, except the expired ones and
+// those of a context now pointing at another server
+func TestServerVersionCacheSave(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache", "versions.json")
+	cache, _ := LoadServerVersionCache(path, time.Hour)
+	now := time.Now()
+	old := ClusterInfo{Name: "old", Context: "old"}
+	cache.now = func() time.Time { return now.Add(-2 * time.Hour) }
+	cache.Record(old, "v1.20.0")
+	cache.now = func() time.Time { return now }
+	prod := ClusterInfo{Name: "prod", Context: "prod", RestConfig: &rest.Config{Host: "https://10.0.0.1:6443"}}
+	cache.Record(prod, "v1.29.3")
+	if err := cache.Save(); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	loaded, err := LoadServerVersionCache(path, time.Hour)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if v, err := loaded.ServerVersion(prod); err != nil || v.String() != "1.29.3" {
+		t.Errorf("expected the cached version of prod, got %v, %v", v, err)
+	}
+	// Without a discovery client, a cluster missing from the cache cannot be queried
+	if _, err := loaded.ServerVersion(old); err == nil {
+		t.Errorf("expected the expired version of old to be dropped")
+	}
+	moved := prod
+	moved.RestConfig = &rest.Config{Host: "https://10.0.0.2:6443"}
+	if _, err := loaded.ServerVersion(moved); err == nil {
+		t.Errorf("expected the version of prod's old server not to be used for its new one")
+	}
+}
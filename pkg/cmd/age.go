@@ -0,0 +1,142 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// agedResource identifies a resource together with its creation time
+type agedResource struct {
+	Name              string
+	Namespace         string
+	CreationTimestamp time.Time
+}
+
+// agedObject is the part of a Kubernetes object needed to filter it by age
+type agedObject struct {
+	Metadata struct {
+		Name              string    `json:"name"`
+		Namespace         string    `json:"namespace"`
+		CreationTimestamp time.Time `json:"creationTimestamp"`
+	} `json:"metadata"`
+}
+
+// parseAgedResources extracts the name, namespace and creation time of the objects in
+// `kubectl get -o json` output, which is either a List or a single object
+func parseAgedResources(data []byte) ([]agedResource, error) {
+	var obj struct {
+		agedObject
+		Kind  string       `json:"kind"`
+		Items []agedObject `json:"items"`
+	}
+	if err := json.Unmarshal(data, &obj); err != nil {
+		return nil, fmt.Errorf("failed to parse kubectl output: %v", err)
+	}
+
+	objects := []agedObject{obj.agedObject}
+	if strings.HasSuffix(obj.Kind, "List") {
+		objects = obj.Items
+	}
+
+	var resources []agedResource
+	for _, o := range objects {
+		if o.Metadata.Name == "" {
+			continue
+		}
+		resources = append(resources, agedResource{
+			Name:              o.Metadata.Name,
+			Namespace:         o.Metadata.Namespace,
+			CreationTimestamp: o.Metadata.CreationTimestamp,
+		})
+	}
+	return resources, nil
+}
+
+// filterOlderThan returns the resources created more than olderThan before now.
+// Resources without a creation timestamp are never selected.
+func filterOlderThan(resources []agedResource, olderThan time.Duration, now time.Time) []agedResource {
+	var old []agedResource
+	for _, r := range resources {
+		if r.CreationTimestamp.IsZero() {
+			continue
+		}
+		if now.Sub(r.CreationTimestamp) > olderThan {
+			old = append(old, r)
+		}
+	}
+	return old
+}
+
+// groupByNamespace groups resource names by namespace, returning the namespaces in sorted order
+func groupByNamespace(resources []agedResource) ([]string, map[string][]string) {
+	names := make(map[string][]string)
+	for _, r := range resources {
+		names[r.Namespace] = append(names[r.Namespace], r.Name)
+	}
+	namespaces := make([]string, 0, len(names))
+	for ns := range names {
+		namespaces = append(namespaces, ns)
+	}
+	sort.Strings(namespaces)
+	return namespaces, names
+}
+
+// filterCreatedSince returns the resources created at or after since.
+// Resources without a creation timestamp are never selected.
+func filterCreatedSince(resources []agedResource, since time.Time) []agedResource {
+	var recent []agedResource
+	for _, r := range resources {
+		if r.CreationTimestamp.IsZero() {
+			continue
+		}
+		if !r.CreationTimestamp.Before(since) {
+			recent = append(recent, r)
+		}
+	}
+	return recent
+}
+
+// ageFilter selects resources by creation time, with --older-than and --since-last-run
+type ageFilter struct {
+	// OlderThan selects resources created more than this long ago, 0 disables it
+	OlderThan time.Duration
+	// SinceLastRun enables Since
+	SinceLastRun bool
+	// Since selects resources created at or after this time, the zero time selects everything
+	Since time.Time
+}
+
+// active reports whether the filter selects resources at all
+func (f ageFilter) active() bool {
+	return f.OlderThan > 0 || f.SinceLastRun
+}
+
+// apply returns the resources matching the filter
+func (f ageFilter) apply(resources []agedResource, now time.Time) []agedResource {
+	if f.OlderThan > 0 {
+		resources = filterOlderThan(resources, f.OlderThan, now)
+	}
+	if f.SinceLastRun {
+		resources = filterCreatedSince(resources, f.Since)
+	}
+	return resources
+}
+
+// String describes the filter, e.g. "older than 24h0m0s" or "created since the last run at 2024-01-01T00:00:00Z"
+func (f ageFilter) String() string {
+	var parts []string
+	if f.OlderThan > 0 {
+		parts = append(parts, fmt.Sprintf("older than %s", f.OlderThan))
+	}
+	if f.SinceLastRun {
+		if f.Since.IsZero() {
+			parts = append(parts, "created at any time (no previous run)")
+		} else {
+			parts = append(parts, fmt.Sprintf("created since the last run at %s", f.Since.Format(time.RFC3339)))
+		}
+	}
+	return strings.Join(parts, " and ")
+}
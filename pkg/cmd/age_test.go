@@ -0,0 +1,114 @@
+package cmd
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"kubectl-multi/pkg/cluster"
+)
+
+// TestParseAgedResourcesList ensures List output yields every item with its namespace and creation time
+func TestParseAgedResourcesList(t *testing.T) {
+	data := []byte(`{
+		"apiVersion": "v1",
+		"kind": "List",
+		"items": [
+			{"metadata": {"name": "old", "namespace": "test", "creationTimestamp": "2024-01-01T00:00:00Z"}},
+			{"metadata": {"name": "new", "namespace": "test", "creationTimestamp": "2024-01-02T00:00:00Z"}}
+		]
+	}`)
+
+	resources, err := parseAgedResources(data)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(resources) != 2 {
+		t.Fatalf("expected 2 resources, got %d", len(resources))
+	}
+	if resources[0].Name != "old" || resources[0].Namespace != "test" {
+		t.Errorf("unexpected first resource: %+v", resources[0])
+	}
+	want := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+	if !resources[0].CreationTimestamp.Equal(want) {
+		t.Errorf("expected creation time %v, got %v", want, resources[0].CreationTimestamp)
+	}
+}
+
+// TestParseAgedResourcesSingleObject ensures a single object (from `get TYPE NAME`) is parsed as one resource
+func TestParseAgedResourcesSingleObject(t *testing.T) {
+	data := []byte(`{"kind": "Namespace", "metadata": {"name": "e2e-1234", "creationTimestamp": "2024-01-01T00:00:00Z"}}`)
+
+	resources, err := parseAgedResources(data)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(resources) != 1 || resources[0].Name != "e2e-1234" || resources[0].Namespace != "" {
+		t.Errorf("unexpected resources: %+v", resources)
+	}
+}
+
+// TestParseAgedResourcesInvalid ensures malformed output is reported as an error
+func TestParseAgedResourcesInvalid(t *testing.T) {
+	if _, err := parseAgedResources([]byte("not json")); err == nil {
+		t.Error("expected an error for invalid JSON")
+	}
+}
+
+// TestFilterOlderThan ensures only resources created before the cutoff are selected
+func TestFilterOlderThan(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	resources := []agedResource{
+		{Name: "two-days", CreationTimestamp: now.Add(-48 * time.Hour)},
+		{Name: "one-hour", CreationTimestamp: now.Add(-time.Hour)},
+		{Name: "exactly-a-day", CreationTimestamp: now.Add(-24 * time.Hour)},
+		{Name: "no-timestamp"},
+		{Name: "future", CreationTimestamp: now.Add(time.Hour)},
+	}
+
+	old := filterOlderThan(resources, 24*time.Hour, now)
+	if len(old) != 1 || old[0].Name != "two-days" {
+		t.Errorf("expected only two-days to be selected, got %+v", old)
+	}
+}
+
+// TestGroupByNamespace ensures names are grouped per namespace in a stable order
+func TestGroupByNamespace(t *testing.T) {
+	resources := []agedResource{
+		{Name: "a", Namespace: "team-b"},
+		{Name: "b", Namespace: "team-a"},
+		{Name: "c", Namespace: "team-b"},
+	}
+
+	namespaces, names := groupByNamespace(resources)
+	if len(namespaces) != 2 || namespaces[0] != "team-a" || namespaces[1] != "team-b" {
+		t.Fatalf("unexpected namespaces: %v", namespaces)
+	}
+	if got := names["team-b"]; len(got) != 2 || got[0] != "a" || got[1] != "c" {
+		t.Errorf("unexpected names for team-b: %v", got)
+	}
+}
+
+// fakeAgedKubectl lists an old and a new pod labelled app=web, and an old pod of another app unless the
+// list is narrowed with -l app=web
+const fakeAgedKubectl = `#!/bin/sh
+echo '{"kind": "List", "items": ['
+echo '{"metadata": {"name": "web-old", "namespace": "shop", "creationTimestamp": "2020-01-01T00:00:00Z"}},'
+case "$*" in *"-l app=web"*) ;; *) echo '{"metadata": {"name": "db-old", "namespace": "shop", "creationTimestamp": "2020-01-01T00:00:00Z"}},';; esac
+echo '{"metadata": {"name": "web-new", "namespace": "shop", "creationTimestamp": "2999-01-01T00:00:00Z"}}'
+echo ']}'
+`
+
+// TestListAgedResourcesSelector ensures delete -l with --older-than only selects the resources matching
+// both the label selector and the age filter
+func TestListAgedResourcesSelector(t *testing.T) {
+	installFakeKubectl(t, fakeAgedKubectl)
+
+	resources, err := listAgedResources(context.Background(), cluster.ClusterInfo{Context: "cluster1"}, "pods", "", "app=web", "", "shop", false, ageFilter{OlderThan: time.Hour})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(resources) != 1 || resources[0].Name != "web-old" {
+		t.Errorf("expected only web-old to be selected, got %+v", resources)
+	}
+}
@@ -0,0 +1,164 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"kubectl-multi/pkg/cluster"
+
+	"github.com/spf13/cobra"
+)
+
+func newAPIResourcesCommand() *cobra.Command {
+	var apiGroup string
+	var diff bool
+
+	cmd := &cobra.Command{
+		Use:   "api-resources",
+		Short: "Print the supported API resources of every managed cluster",
+		Long: `Print the API resources served by every managed cluster, as kubectl api-resources -o wide does.
+
+With --diff, print a matrix of the resources served by some clusters but not others instead,
+typically CRDs installed in only part of the fleet.`,
+		Example: `# List the API resources of every managed cluster
+kubectl multi api-resources
+
+# Show the CRDs that are not installed in every cluster
+kubectl multi api-resources --diff
+
+# Compare the cert-manager resources of the clusters
+kubectl multi api-resources --diff --api-group cert-manager.io`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			kubeconfig, remoteCtx, _, _, _ := GetGlobalFlags()
+			return handleAPIResourcesCommand(apiGroup, diff, kubeconfig, remoteCtx)
+		},
+	}
+
+	cmd.Flags().StringVar(&apiGroup, "api-group", "", "limit to resources in the specified API group")
+	cmd.Flags().BoolVar(&diff, "diff", false, "only print the resources missing from some of the clusters, in a presence matrix")
+	return cmd
+}
+
+func handleAPIResourcesCommand(apiGroup string, diff bool, kubeconfig, remoteCtx string) error {
+	clusters, unreachable, err := discoverClusters(kubeconfig, remoteCtx)
+	if err != nil {
+		return fmt.Errorf("failed to discover clusters: %v", err)
+	}
+	if len(clusters) == 0 {
+		return fmt.Errorf("no clusters discovered")
+	}
+
+	if !diff {
+		_, err = fanOut(clusters, kubeconfig, remoteCtx, fanOutOptions{Unreachable: unreachable}, kubectlOp(kubeconfig, func(c cluster.ClusterInfo) []string {
+			return buildAPIResourcesArgs("wide", apiGroup, c.Context)
+		}))
+		return err
+	}
+
+	targets, _ := fanOutTargets(clusters, "", itsContext(remoteCtx))
+	var served []clusterAPIResources
+	for _, c := range targets {
+		r := clusterAPIResources{Cluster: c.Display()}
+		output, err := runKubectl(buildAPIResourcesArgs("name", apiGroup, c.Context), kubeconfig)
+		if err != nil {
+			r.Err = fmt.Errorf("%v: %s", err, strings.TrimSpace(output))
+			fmt.Printf("Warning: failed to list the API resources of cluster %s: %v\n", c.Display(), r.Err)
+		} else {
+			r.Resources = parseAPIResourceNames(output)
+		}
+		served = append(served, r)
+	}
+	for _, c := range unreachable {
+		served = append(served, clusterAPIResources{Cluster: c.Display(), Err: c.DiscoveryErr})
+	}
+	printAPIResourceDiff(os.Stdout, served)
+	return nil
+}
+
+// buildAPIResourcesArgs builds the arguments of `kubectl api-resources` for a cluster
+func buildAPIResourcesArgs(outputFormat, apiGroup, context string) []string {
+	args := []string{"api-resources", "-o", outputFormat}
+	if apiGroup != "" {
+		args = append(args, "--api-group", apiGroup)
+	}
+	return append(args, "--context", context)
+}
+
+// clusterAPIResources holds the API resources served by a cluster, as resource.group names
+type clusterAPIResources struct {
+	Cluster   string
+	Resources []string
+	Err       error
+}
+
+// parseAPIResourceNames parses the output of `kubectl api-resources -o name`, one resource per line
+func parseAPIResourceNames(output string) []string {
+	var names []string
+	for _, line := range strings.Split(output, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			names = append(names, line)
+		}
+	}
+	return names
+}
+
+// printAPIResourceDiff prints the resources that are served by some of the clusters but not all of
+// them, one column per cluster. Clusters that could not be listed show "?" and are not compared.
+func printAPIResourceDiff(out io.Writer, served []clusterAPIResources) {
+	present := make([]map[string]bool, len(served))
+	counts := make(map[string]int)
+	listed := 0
+	for i, s := range served {
+		present[i] = make(map[string]bool)
+		if s.Err != nil {
+			continue
+		}
+		listed++
+		for _, r := range s.Resources {
+			if !present[i][r] {
+				present[i][r] = true
+				counts[r]++
+			}
+		}
+	}
+
+	var differing []string
+	for r, n := range counts {
+		if n < listed {
+			differing = append(differing, r)
+		}
+	}
+	if len(differing) == 0 {
+		fmt.Fprintf(out, "All %d clusters serve the same API resources\n", listed)
+		return
+	}
+	sort.Strings(differing)
+
+	tw := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprint(tw, "RESOURCE")
+	for _, s := range served {
+		fmt.Fprintf(tw, "\t%s", s.Cluster)
+	}
+	fmt.Fprintln(tw)
+	for _, r := range differing {
+		fmt.Fprint(tw, r)
+		for i, s := range served {
+			cell := "-"
+			switch {
+			case s.Err != nil:
+				cell = "?"
+			case present[i][r]:
+				cell = "yes"
+			}
+			fmt.Fprintf(tw, "\t%s", cell)
+		}
+		fmt.Fprintln(tw)
+	}
+	tw.Flush()
+	fmt.Fprintf(out, "\n%d resources are not served by all %d clusters\n", len(differing), listed)
+}
@@ -0,0 +1,52 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// TestPrintAPIResourceDiff ensures only the resources missing from some clusters are listed, and that
+// a cluster that could not be listed does not make every resource look missing
+func TestPrintAPIResourceDiff(t *testing.T) {
+	served := []clusterAPIResources{
+		{Cluster: "cluster1", Resources: parseAPIResourceNames("pods\ndeployments.apps\ncertificates.cert-manager.io\n")},
+		{Cluster: "cluster2", Resources: parseAPIResourceNames("pods\ndeployments.apps\n")},
+		{Cluster: "cluster3", Err: errors.New("connection refused")},
+		{Cluster: "cluster4", Resources: parseAPIResourceNames("pods\ndeployments.apps\ncertificates.cert-manager.io\nwidgets.example.com\n")},
+	}
+
+	var out bytes.Buffer
+	printAPIResourceDiff(&out, served)
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 5 {
+		t.Fatalf("expected a header, 2 resources and a summary, got:\n%s", out.String())
+	}
+	want := [][]string{
+		{"RESOURCE", "cluster1", "cluster2", "cluster3", "cluster4"},
+		{"certificates.cert-manager.io", "yes", "-", "?", "yes"},
+		{"widgets.example.com", "-", "-", "?", "yes"},
+	}
+	for i, w := range want {
+		if got := strings.Fields(lines[i]); strings.Join(got, " ") != strings.Join(w, " ") {
+			t.Errorf("line %d: expected %v, got %v", i, w, got)
+		}
+	}
+	if lines[4] != "2 resources are not served by all 3 clusters" {
+		t.Errorf("unexpected summary %q", lines[4])
+	}
+}
+
+// TestPrintAPIResourceDiffSame ensures identical clusters are reported as such
+func TestPrintAPIResourceDiffSame(t *testing.T) {
+	served := []clusterAPIResources{
+		{Cluster: "cluster1", Resources: []string{"pods", "deployments.apps"}},
+		{Cluster: "cluster2", Resources: []string{"deployments.apps", "pods"}},
+	}
+	var out bytes.Buffer
+	printAPIResourceDiff(&out, served)
+	if out.String() != "All 2 clusters serve the same API resources\n" {
+		t.Errorf("unexpected output %q", out.String())
+	}
+}
@@ -2,15 +2,17 @@ package cmd
 
 import (
 	"bytes"
+	"context"
 	"fmt"
+	"io"
 	"os"
 	"os/exec"
+	"sync"
 
 	"kubectl-multi/pkg/cluster"
 	"kubectl-multi/pkg/util"
 
 	"github.com/spf13/cobra"
-	"k8s.io/client-go/tools/clientcmd"
 )
 
 // Custom help function for apply command
@@ -38,7 +40,16 @@ kubectl multi apply -k dir/
 kubectl multi apply -f deployment.yaml --dry-run=client
 
 # Apply resources recursively from a directory
-kubectl multi apply -f dir/ -R`
+kubectl multi apply -f dir/ -R
+
+# Apply several manifests at once to all clusters
+kubectl multi apply -f namespace.yaml -f deployment.yaml
+
+# Use server-side apply (clusters older than v1.22 are skipped)
+kubectl multi apply -f deployment.yaml --server-side
+
+# Apply without validating against each cluster first
+kubectl multi apply -f deployment.yaml --validate=false`
 
 	// Multi-cluster usage
 	multiClusterUsage := `kubectl multi apply (-f FILENAME | -k DIRECTORY) [flags]`
@@ -49,24 +60,37 @@ kubectl multi apply -f dir/ -R`
 }
 
 func newApplyCommand() *cobra.Command {
-	var filename string
+	var filenames []string
 	var recursive bool
 	var dryRun string
+	var serverSide bool
+	var validate bool
+	var kustomize string
+	var buildOnce bool
 
 	cmd := &cobra.Command{
-		Use:   "apply (-f FILENAME | --filename=FILENAME)",
+		Use:   "apply (-f FILENAME | -k DIRECTORY)",
 		Short: "Apply a configuration to resources across all managed clusters",
 		Long: `Apply a configuration to resources across all managed clusters.
 This command applies manifests to all KubeStellar managed clusters.`,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			kubeconfig, remoteCtx, _, namespace, allNamespaces := GetGlobalFlags()
-			return handleApplyCommand(filename, recursive, dryRun, kubeconfig, remoteCtx, namespace, allNamespaces)
+			filenames, kustomize, cleanup, err := resolveManifestSource(filenames, kustomize, buildOnce, kubeconfig)
+			if err != nil {
+				return err
+			}
+			defer cleanup()
+			return handleApplyCommand(filenames, kustomize, recursive, dryRun, serverSide, validate, kubeconfig, remoteCtx, namespace, allNamespaces)
 		},
 	}
 
-	cmd.Flags().StringVarP(&filename, "filename", "f", "", "filename, directory, or URL to files to use to apply the resource")
+	cmd.Flags().StringArrayVarP(&filenames, "filename", "f", nil, "filename, directory, or URL to files to use to apply the resource, can be repeated")
+	cmd.Flags().StringVarP(&kustomize, "kustomize", "k", "", "process a kustomization directory")
+	cmd.Flags().BoolVar(&buildOnce, "build-once", true, "build the kustomization once and apply the result to every cluster instead of building it per cluster")
 	cmd.Flags().BoolVarP(&recursive, "recursive", "R", false, "process the directory used in -f, --filename recursively")
 	cmd.Flags().StringVar(&dryRun, "dry-run", "none", "must be \"none\", \"server\", or \"client\"")
+	cmd.Flags().BoolVar(&serverSide, "server-side", false, "if true, apply runs in the server instead of the client (skips clusters older than v"+serverSideApplyMinVersion+")")
+	cmd.Flags().BoolVar(&validate, "validate", true, "validate the manifests against every cluster with a server-side dry run before applying")
 
 	// Set custom help function
 	cmd.SetHelpFunc(applyHelpFunc)
@@ -79,8 +103,11 @@ This command applies manifests to all KubeStellar managed clusters.`,
 	return cmd
 }
 
-func handleApplyCommand(filename string, recursive bool, dryRun, kubeconfig, remoteCtx, namespace string, allNamespaces bool) error {
-	clusters, err := cluster.DiscoverClusters(kubeconfig, remoteCtx)
+// serverSideApplyMinVersion is the oldest Kubernetes version on which server-side apply is GA
+const serverSideApplyMinVersion = "1.22.0"
+
+func handleApplyCommand(filenames []string, kustomize string, recursive bool, dryRun string, serverSide, validate bool, kubeconfig, remoteCtx, namespace string, allNamespaces bool) error {
+	clusters, unreachable, err := discoverClusters(kubeconfig, remoteCtx)
 	if err != nil {
 		return fmt.Errorf("failed to discover clusters: %v", err)
 	}
@@ -88,84 +115,47 @@ func handleApplyCommand(filename string, recursive bool, dryRun, kubeconfig, rem
 		return fmt.Errorf("no clusters discovered")
 	}
 
-	// Find current context from kubeconfig
-	currentContext := ""
-	{
-		loading := clientcmd.NewDefaultClientConfigLoadingRules()
-		if kubeconfig != "" {
-			loading.ExplicitPath = kubeconfig
-		}
-		cfg := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loading, &clientcmd.ConfigOverrides{})
-		rawCfg, err := cfg.RawConfig()
-		if err == nil {
-			currentContext = rawCfg.CurrentContext
-		}
-	}
-
-	// Identify ITS (control) cluster context
-	itsContext := remoteCtx
-
-	// Build maps for quick lookup
-	contextToCluster := make(map[string]cluster.ClusterInfo)
-	for _, c := range clusters {
-		contextToCluster[c.Context] = c
+	opts := fanOutOptions{Namespace: namespaceOption(namespace, allNamespaces), Unreachable: unreachable}
+	if serverSide {
+		opts.MinServerVersion = serverSideApplyMinVersion
 	}
 
-	// 1. Run for current context (if present)
-	if cinfo, ok := contextToCluster[currentContext]; ok {
-		args := []string{"apply", "-f", filename, "--context", cinfo.Context}
+	buildArgs := func(c cluster.ClusterInfo) []string {
+		args := append([]string{"apply"}, manifestArgs(filenames, kustomize)...)
+		args = append(args, "--context", c.Context)
 		if recursive {
 			args = append(args, "-R")
 		}
-		if dryRun != "none" && dryRun != "" {
-			args = append(args, "--dry-run="+dryRun)
+		if serverSide {
+			args = append(args, "--server-side")
 		}
 		if namespace != "" {
 			args = append(args, "-n", namespace)
 		}
-		output, err := runKubectl(args, kubeconfig)
-		fmt.Printf("=== Cluster: %s ===\n", cinfo.Context)
-		if err != nil {
-			fmt.Printf("Error: %v\n", err)
-		} else {
-			fmt.Print(output)
-		}
-		fmt.Println()
+		return args
 	}
 
-	// 2. Run for KubeStellar clusters (excluding ITS and current)
-	for _, c := range clusters {
-		if c.Context == currentContext || c.Context == itsContext {
-			continue
-		}
-		args := []string{"apply", "-f", filename, "--context", c.Context}
-		if recursive {
-			args = append(args, "-R")
+	// A dry run already validates, so only validate before a real apply
+	if validate && (dryRun == "none" || dryRun == "") {
+		if err := validateOnClusters(clusters, kubeconfig, remoteCtx, opts, buildArgs, nil); err != nil {
+			return err
 		}
+	}
+
+	results, err := fanOut(clusters, kubeconfig, remoteCtx, opts, kubectlOp(kubeconfig, func(c cluster.ClusterInfo) []string {
+		args := buildArgs(c)
 		if dryRun != "none" && dryRun != "" {
 			args = append(args, "--dry-run="+dryRun)
 		}
-		if namespace != "" {
-			args = append(args, "-n", namespace)
-		}
-		output, err := runKubectl(args, kubeconfig)
-		fmt.Printf("=== Cluster: %s ===\n", c.Context)
-		if err != nil {
-			fmt.Printf("Error: %v\n", err)
-		} else {
-			fmt.Print(output)
-		}
+		return args
+	}))
+	// Admission webhooks may warn or reject differently per cluster, so report them side by side
+	if isServerDryRun(dryRun) {
 		fmt.Println()
+		printDryRunSummary(util.GetOutputStream(), newDryRunReports(results))
+		printDryRunMatrix(util.GetOutputStream(), results)
 	}
-
-	// 3. Print warning for ITS (control) cluster
-	if cinfo, ok := contextToCluster[itsContext]; ok {
-		fmt.Printf("=== Cluster: %s ===\n", cinfo.Context)
-		fmt.Printf("Cannot perform this operation on ITS (control) cluster: %s\n", cinfo.Context)
-		fmt.Println()
-	}
-
-	return nil
+	return err
 }
 
 func newViewLastAppliedCommand() *cobra.Command {
@@ -191,7 +181,7 @@ func newViewLastAppliedCommand() *cobra.Command {
 }
 
 func handleViewLastAppliedCommand(filename, output string, recursive bool, extraArgs []string, kubeconfig, remoteCtx, namespace string, allNamespaces bool) error {
-	clusters, err := cluster.DiscoverClusters(kubeconfig, remoteCtx)
+	clusters, unreachable, err := discoverClusters(kubeconfig, remoteCtx)
 	if err != nil {
 		return fmt.Errorf("failed to discover clusters: %v", err)
 	}
@@ -199,31 +189,7 @@ func handleViewLastAppliedCommand(filename, output string, recursive bool, extra
 		return fmt.Errorf("no clusters discovered")
 	}
 
-	// Find current context from kubeconfig
-	currentContext := ""
-	{
-		loading := clientcmd.NewDefaultClientConfigLoadingRules()
-		if kubeconfig != "" {
-			loading.ExplicitPath = kubeconfig
-		}
-		cfg := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loading, &clientcmd.ConfigOverrides{})
-		rawCfg, err := cfg.RawConfig()
-		if err == nil {
-			currentContext = rawCfg.CurrentContext
-		}
-	}
-
-	// Identify ITS (control) cluster context
-	itsContext := remoteCtx
-
-	// Build maps for quick lookup
-	contextToCluster := make(map[string]cluster.ClusterInfo)
-	for _, c := range clusters {
-		contextToCluster[c.Context] = c
-	}
-
-	// 1. Run for current context (if present)
-	if cinfo, ok := contextToCluster[currentContext]; ok {
+	_, err = fanOut(clusters, kubeconfig, remoteCtx, fanOutOptions{Unreachable: unreachable}, kubectlOp(kubeconfig, func(c cluster.ClusterInfo) []string {
 		args := []string{"apply", "view-last-applied"}
 		if filename != "" {
 			args = append(args, "-f", filename)
@@ -237,54 +203,9 @@ func handleViewLastAppliedCommand(filename, output string, recursive bool, extra
 		if len(extraArgs) > 0 {
 			args = append(args, extraArgs...)
 		}
-		args = append(args, "--context", cinfo.Context)
-		cmdOutput, err := runKubectl(args, kubeconfig)
-		fmt.Printf("=== Cluster: %s ===\n", cinfo.Context)
-		if err != nil {
-			fmt.Printf("Error: %v\n", err)
-		} else {
-			fmt.Print(cmdOutput)
-		}
-		fmt.Println()
-	}
-
-	// 2. Run for KubeStellar clusters (excluding ITS and current)
-	for _, c := range clusters {
-		if c.Context == currentContext || c.Context == itsContext {
-			continue
-		}
-		args := []string{"apply", "view-last-applied"}
-		if filename != "" {
-			args = append(args, "-f", filename)
-		}
-		if output != "" {
-			args = append(args, "-o", output)
-		}
-		if recursive {
-			args = append(args, "-R")
-		}
-		if len(extraArgs) > 0 {
-			args = append(args, extraArgs...)
-		}
-		args = append(args, "--context", c.Context)
-		cmdOutput, err := runKubectl(args, kubeconfig)
-		fmt.Printf("=== Cluster: %s ===\n", c.Context)
-		if err != nil {
-			fmt.Printf("Error: %v\n", err)
-		} else {
-			fmt.Print(cmdOutput)
-		}
-		fmt.Println()
-	}
-
-	// 3. Print warning for ITS (control) cluster
-	if cinfo, ok := contextToCluster[itsContext]; ok {
-		fmt.Printf("=== Cluster: %s ===\n", cinfo.Context)
-		fmt.Printf("Cannot perform this operation on ITS (control) cluster: %s\n", cinfo.Context)
-		fmt.Println()
-	}
-
-	return nil
+		return append(args, "--context", c.Context)
+	}))
+	return err
 }
 
 func newEditLastAppliedCommand() *cobra.Command {
@@ -346,7 +267,7 @@ func handleSetLastAppliedCommand(filename, output string, createAnnotation bool,
 
 // runKubectl runs a kubectl command with the given args and kubeconfig, returns output and error
 func runKubectl(args []string, kubeconfig string) (string, error) {
-	cmd := exec.Command("kubectl", args...)
+	cmd := exec.Command(kubectlBinary(args), args...)
 	if kubeconfig != "" {
 		cmd.Env = append(os.Environ(), "KUBECONFIG="+kubeconfig)
 	}
@@ -354,7 +275,38 @@ func runKubectl(args []string, kubeconfig string) (string, error) {
 	cmd.Stdout = &stdout
 	cmd.Stderr = &stderr
 	if err := cmd.Run(); err != nil {
-		return stdout.String() + stderr.String(), err
+		return stdout.String() + stderr.String(), newKubectlError(err, stderr.String())
 	}
 	return stdout.String(), nil
 }
+
+// runKubectlTo runs a kubectl command with the given args and kubeconfig, writing its stdout and stderr to out as they are produced
+// The command is killed if ctx is cancelled, after --timeout-grace if set.
+func runKubectlTo(ctx context.Context, args []string, kubeconfig string, out io.Writer) error {
+	cmd := exec.CommandContext(ctx, kubectlBinary(args), args...)
+	stopGracefully(cmd, timeoutGrace)
+	if kubeconfig != "" {
+		cmd.Env = append(os.Environ(), "KUBECONFIG="+kubeconfig)
+	}
+	// stdout and stderr are copied concurrently, stderr is also kept to classify failures
+	sw := &syncWriter{w: out}
+	var stderr bytes.Buffer
+	cmd.Stdout = sw
+	cmd.Stderr = io.MultiWriter(sw, &stderr)
+	if err := cmd.Run(); err != nil {
+		return newKubectlError(err, stderr.String())
+	}
+	return nil
+}
+
+// syncWriter serializes writes to w
+type syncWriter struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func (s *syncWriter) Write(p []byte) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.w.Write(p)
+}
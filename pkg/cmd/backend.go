@@ -0,0 +1,89 @@
+package cmd
+
+import (
+	"os/exec"
+	"strings"
+	"sync"
+
+	"kubectl-multi/pkg/cluster"
+)
+
+var (
+	backendMu sync.Mutex
+	// contextBinaries maps a context to the CLI binary used for it (e.g. "oc" for OpenShift clusters).
+	// Contexts without an entry, or with an empty one, use --kubectl-path.
+	contextBinaries = map[string]string{}
+	// openShiftCandidates are the discovered clusters whose binary is not set yet. They are checked for
+	// OpenShift when a command first runs on them, so that clusters left out by the selection flags are
+	// never queried.
+	openShiftCandidates = map[string]cluster.ClusterInfo{}
+)
+
+// isOpenShift and ocInstalled detect the OpenShift clusters, they are replaced in tests
+var (
+	isOpenShift = cluster.IsOpenShift
+	ocInstalled = sync.OnceValue(func() bool {
+		_, err := exec.LookPath("oc")
+		return err == nil
+	})
+)
+
+// contextFromArgs returns the value of the --context flag in kubectl args, or "" if there is none
+func contextFromArgs(args []string) string {
+	for i, arg := range args {
+		if arg == "--context" && i+1 < len(args) {
+			return args[i+1]
+		}
+		if strings.HasPrefix(arg, "--context=") {
+			return strings.TrimPrefix(arg, "--context=")
+		}
+	}
+	return ""
+}
+
+// kubectlBinary returns the binary to run for kubectl args, selected by their --context
+func kubectlBinary(args []string) string {
+	if bin := contextBinary(contextFromArgs(args)); bin != "" {
+		return bin
+	}
+	if kubectlPath != "" {
+		return kubectlPath
+	}
+	return "kubectl"
+}
+
+// contextBinary returns the binary set for context, or "" for --kubectl-path. A discovered cluster is
+// checked for OpenShift the first time, and the result kept for the following commands of the run.
+func contextBinary(context string) string {
+	backendMu.Lock()
+	bin, ok := contextBinaries[context]
+	c, candidate := openShiftCandidates[context]
+	backendMu.Unlock()
+	if ok || !candidate {
+		return bin
+	}
+
+	if ocInstalled() && isOpenShift(c) {
+		bin = "oc"
+	}
+	backendMu.Lock()
+	defer backendMu.Unlock()
+	contextBinaries[context] = bin
+	delete(openShiftCandidates, context)
+	return bin
+}
+
+// configureBackends sets the binary of every cluster from --context-binary. The other clusters fall back
+// to oc if they are OpenShift clusters and oc is installed, which is only checked once a command runs on them.
+func configureBackends(clusters []cluster.ClusterInfo, aliases map[string]string) {
+	backendMu.Lock()
+	defer backendMu.Unlock()
+	for context, bin := range contextBinaryFlags {
+		contextBinaries[cluster.ResolveContext(context, aliases)] = bin
+	}
+	for _, c := range clusters {
+		if _, ok := contextBinaries[c.Context]; !ok {
+			openShiftCandidates[c.Context] = c
+		}
+	}
+}
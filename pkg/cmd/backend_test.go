@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"testing"
+
+	"kubectl-multi/pkg/cluster"
+)
+
+// TestContextFromArgs ensures the context is found in both --context forms
+func TestContextFromArgs(t *testing.T) {
+	tests := []struct {
+		args []string
+		want string
+	}{
+		{[]string{"get", "pods", "--context", "wds1"}, "wds1"},
+		{[]string{"get", "pods", "--context=wds2", "-n", "default"}, "wds2"},
+		{[]string{"get", "pods"}, ""},
+		{[]string{"get", "pods", "--context"}, ""},
+	}
+	for _, tt := range tests {
+		if got := contextFromArgs(tt.args); got != tt.want {
+			t.Errorf("contextFromArgs(%v) = %q, want %q", tt.args, got, tt.want)
+		}
+	}
+}
+
+// TestKubectlBinary ensures mapped contexts use their binary and the rest use --kubectl-path
+func TestKubectlBinary(t *testing.T) {
+	defer func(m map[string]string, p string) { contextBinaries, kubectlPath = m, p }(contextBinaries, kubectlPath)
+	contextBinaries = map[string]string{"prod-ocp": "oc"}
+	kubectlPath = "/usr/local/bin/kubectl"
+
+	if got := kubectlBinary([]string{"get", "pods", "--context", "prod-ocp"}); got != "oc" {
+		t.Errorf("expected oc for prod-ocp, got %q", got)
+	}
+	if got := kubectlBinary([]string{"get", "pods", "--context", "wds1"}); got != "/usr/local/bin/kubectl" {
+		t.Errorf("expected --kubectl-path for wds1, got %q", got)
+	}
+}
+
+// TestContextBinaryOpenShift ensures only the clusters a command runs on are checked for OpenShift, once
+func TestContextBinaryOpenShift(t *testing.T) {
+	defer func(m map[string]string, c map[string]cluster.ClusterInfo, detect func(cluster.ClusterInfo) bool, oc func() bool) {
+		contextBinaries, openShiftCandidates, isOpenShift, ocInstalled = m, c, detect, oc
+	}(contextBinaries, openShiftCandidates, isOpenShift, ocInstalled)
+	contextBinaries = map[string]string{}
+	openShiftCandidates = map[string]cluster.ClusterInfo{}
+	checked := map[string]int{}
+	isOpenShift = func(c cluster.ClusterInfo) bool {
+		checked[c.Context]++
+		return c.Context == "prod-ocp"
+	}
+	ocInstalled = func() bool { return true }
+
+	configureBackends([]cluster.ClusterInfo{{Context: "prod-ocp"}, {Context: "wds1"}, {Context: "wds2"}}, nil)
+	if len(checked) != 0 {
+		t.Fatalf("expected no cluster to be checked before a command runs, got %v", checked)
+	}
+	for i := 0; i < 2; i++ {
+		if got := contextBinary("prod-ocp"); got != "oc" {
+			t.Errorf("expected oc for prod-ocp, got %q", got)
+		}
+		if got := contextBinary("wds1"); got != "" {
+			t.Errorf("expected --kubectl-path for wds1, got %q", got)
+		}
+	}
+	if checked["prod-ocp"] != 1 || checked["wds1"] != 1 || checked["wds2"] != 0 {
+		t.Errorf("expected prod-ocp and wds1 to be checked once and wds2 never, got %v", checked)
+	}
+}
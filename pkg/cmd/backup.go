@@ -0,0 +1,96 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"kubectl-multi/pkg/cluster"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/yaml"
+)
+
+// clusterScopedBackupDir is the directory name used in place of a namespace for cluster-scoped resources
+const clusterScopedBackupDir = "_cluster"
+
+// backupPath returns where the backup of obj read from a cluster is written:
+// <dir>/<context>/<namespace>/<kind>-<name>.yaml
+func backupPath(dir, context string, obj *unstructured.Unstructured) string {
+	ns := obj.GetNamespace()
+	if ns == "" {
+		ns = clusterScopedBackupDir
+	}
+	file := strings.ToLower(obj.GetKind()) + "-" + obj.GetName() + ".yaml"
+	return filepath.Join(dir, safePathComponent(context), safePathComponent(ns), safePathComponent(file))
+}
+
+// safePathComponent replaces the path separators of a context name, such as those of EKS ARNs,
+// so it can be used as a single directory name
+func safePathComponent(name string) string {
+	return strings.NewReplacer("/", "_", `\`, "_").Replace(name)
+}
+
+// writeBackup writes every object of a cluster to its backup file, stripped of the fields set by the
+// cluster so the backup can be applied again. Backups may hold Secrets, so they are only readable by the user.
+func writeBackup(dir, context string, objects []*unstructured.Unstructured) ([]string, error) {
+	var paths []string
+	for _, obj := range objects {
+		obj = obj.DeepCopy()
+		stripClusterFields(obj)
+		data, err := yaml.Marshal(obj.Object)
+		if err != nil {
+			return paths, fmt.Errorf("failed to render %s/%s: %v", obj.GetKind(), obj.GetName(), err)
+		}
+
+		path := backupPath(dir, context, obj)
+		if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+			return paths, fmt.Errorf("failed to create backup directory: %v", err)
+		}
+		if err := os.WriteFile(path, data, 0o600); err != nil {
+			return paths, fmt.Errorf("failed to write backup: %v", err)
+		}
+		paths = append(paths, path)
+	}
+	return paths, nil
+}
+
+// backupResources exports the resources listed by `kubectl get` with getArgs in every cluster to dir,
+// keeping only those accepted by keep when it is set. Resources that do not exist in a cluster are
+// not backed up. It returns the clusters whose backup failed, which must not be deleted from.
+func backupResources(dir string, targets []cluster.ClusterInfo, kubeconfig string, getArgs func(context string) []string, keep func(c cluster.ClusterInfo, obj *unstructured.Unstructured) bool) map[string]error {
+	failed := make(map[string]error)
+	for _, c := range targets {
+		output, err := runKubectl(getArgs(c.Context), kubeconfig)
+		if err != nil {
+			failed[c.Context] = fmt.Errorf("backup failed, not deleting: %v: %s", err, strings.TrimSpace(output))
+			fmt.Printf("Warning: failed to back up cluster %s: %v\n", c.Display(), err)
+			continue
+		}
+		objects, err := parseObjects([]byte(output))
+		if err != nil {
+			failed[c.Context] = fmt.Errorf("backup failed, not deleting: %v", err)
+			fmt.Printf("Warning: failed to back up cluster %s: %v\n", c.Display(), err)
+			continue
+		}
+		if keep != nil {
+			var kept []*unstructured.Unstructured
+			for _, obj := range objects {
+				if keep(c, obj) {
+					kept = append(kept, obj)
+				}
+			}
+			objects = kept
+		}
+
+		paths, err := writeBackup(dir, c.Context, objects)
+		if err != nil {
+			failed[c.Context] = fmt.Errorf("backup failed, not deleting: %v", err)
+			fmt.Printf("Warning: failed to back up cluster %s: %v\n", c.Display(), err)
+			continue
+		}
+		fmt.Printf("Backed up %d resources from cluster %s to %s\n", len(paths), c.Display(), filepath.Join(dir, safePathComponent(c.Context)))
+	}
+	return failed
+}
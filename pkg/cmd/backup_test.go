@@ -0,0 +1,76 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// TestBackupPath ensures backups are laid out per context and namespace, with cluster-scoped
+// resources and context names containing slashes kept in a single directory
+func TestBackupPath(t *testing.T) {
+	cm := &unstructured.Unstructured{}
+	cm.SetKind("ConfigMap")
+	cm.SetNamespace("shop")
+	cm.SetName("settings")
+	if got, want := backupPath("backup", "cluster1", cm), filepath.Join("backup", "cluster1", "shop", "configmap-settings.yaml"); got != want {
+		t.Errorf("expected %s, got %s", want, got)
+	}
+
+	ns := &unstructured.Unstructured{}
+	ns.SetKind("Namespace")
+	ns.SetName("shop")
+	want := filepath.Join("backup", "arn:aws:eks:eu-west-1:1234:cluster_prod", clusterScopedBackupDir, "namespace-shop.yaml")
+	if got := backupPath("backup", "arn:aws:eks:eu-west-1:1234:cluster/prod", ns); got != want {
+		t.Errorf("expected %s, got %s", want, got)
+	}
+}
+
+// TestWriteBackup ensures backups are stripped of cluster-set fields and only readable by the user
+func TestWriteBackup(t *testing.T) {
+	dir := t.TempDir()
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "ConfigMap",
+		"metadata": map[string]interface{}{
+			"name":            "settings",
+			"namespace":       "shop",
+			"uid":             "1234",
+			"resourceVersion": "42",
+		},
+		"data": map[string]interface{}{"mode": "fast"},
+	}}
+
+	paths, err := writeBackup(dir, "cluster1", []*unstructured.Unstructured{obj})
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(paths) != 1 {
+		t.Fatalf("expected 1 backup, got %v", paths)
+	}
+	data, err := os.ReadFile(paths[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	backup := string(data)
+	if strings.Contains(backup, "uid") || strings.Contains(backup, "resourceVersion") {
+		t.Errorf("expected cluster-set fields to be stripped, got:\n%s", backup)
+	}
+	if !strings.Contains(backup, "mode: fast") {
+		t.Errorf("expected the data to be kept, got:\n%s", backup)
+	}
+	if obj.GetUID() == "" {
+		t.Error("expected the original object to be left unchanged")
+	}
+
+	info, err := os.Stat(paths[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+	if perm := info.Mode().Perm(); perm != 0o600 {
+		t.Errorf("expected mode 0600, got %o", perm)
+	}
+}
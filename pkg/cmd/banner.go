@@ -0,0 +1,58 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"text/template"
+
+	"kubectl-multi/pkg/cluster"
+)
+
+// defaultBannerTemplate is the banner printed above the output of each cluster
+const defaultBannerTemplate = "=== Cluster: {{.ClusterName}} ==="
+
+// bannerData is what the --banner-template is executed with
+type bannerData struct {
+	Context string
+	// ClusterName is the name shown for the cluster, its alias if it has one, else its context
+	ClusterName string
+	// Role tells how the cluster was discovered, e.g. managed, local or wds
+	Role string
+	// Index is the 1-based position of the cluster among the Total clusters, 0 for the ITS notice
+	Index int
+	Total int
+}
+
+// bannerFuncs are the functions available to --banner-template besides the text/template builtins
+var bannerFuncs = template.FuncMap{
+	"upper": strings.ToUpper,
+	"lower": strings.ToLower,
+}
+
+// bannerTemplate is the parsed --banner-template, set during discovery
+var bannerTemplate = template.Must(template.New("banner").Parse(defaultBannerTemplate))
+
+// parseBannerTemplate parses a --banner-template and executes it once with sample data, so that unknown
+// fields fail before any cluster is contacted instead of in the middle of the output
+func parseBannerTemplate(text string) (*template.Template, error) {
+	tmpl, err := template.New("banner").Funcs(bannerFuncs).Option("missingkey=error").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid --banner-template: %v", err)
+	}
+	sample := bannerData{Context: "context", ClusterName: "cluster", Role: cluster.RoleManaged, Index: 1, Total: 1}
+	if err := tmpl.Execute(&bytes.Buffer{}, sample); err != nil {
+		return nil, fmt.Errorf("invalid --banner-template: %v", err)
+	}
+	return tmpl, nil
+}
+
+// clusterBanner returns the banner of a cluster, the index-th of total, from the --banner-template
+func clusterBanner(c cluster.ClusterInfo, index, total int) string {
+	data := bannerData{Context: c.Context, ClusterName: c.Display(), Role: c.Role, Index: index, Total: total}
+	var buf bytes.Buffer
+	if err := bannerTemplate.Execute(&buf, data); err != nil {
+		return fmt.Sprintf("=== Cluster: %s ===", c.Display())
+	}
+	return strings.TrimSuffix(buf.String(), "\n")
+}
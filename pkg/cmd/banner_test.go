@@ -0,0 +1,37 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+	"text/template"
+
+	"kubectl-multi/pkg/cluster"
+)
+
+// TestClusterBanner ensures the default banner is unchanged and a custom template gets every field
+func TestClusterBanner(t *testing.T) {
+	defer func(old *template.Template) { bannerTemplate = old }(bannerTemplate)
+
+	c := cluster.ClusterInfo{Context: "prod-wds-1", DisplayName: "wds-eu", Role: cluster.RoleWDS}
+	if got := clusterBanner(c, 1, 5); got != "=== Cluster: wds-eu ===" {
+		t.Errorf("unexpected default banner %q", got)
+	}
+
+	tmpl, err := parseBannerTemplate(">>> [{{.Index}}/{{.Total}}] {{.Context}} ({{upper .Role}})\n")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	bannerTemplate = tmpl
+	if got := clusterBanner(c, 1, 5); got != ">>> [1/5] prod-wds-1 (WDS)" {
+		t.Errorf("unexpected custom banner %q", got)
+	}
+}
+
+// TestParseBannerTemplateInvalid ensures malformed templates and unknown fields are rejected upfront
+func TestParseBannerTemplateInvalid(t *testing.T) {
+	for _, text := range []string{"=== {{.ClusterName ===", "=== {{.Cluster}} ===", "{{title .Role}}"} {
+		if _, err := parseBannerTemplate(text); err == nil || !strings.Contains(err.Error(), "--banner-template") {
+			t.Errorf("%q: expected an invalid --banner-template error, got %v", text, err)
+		}
+	}
+}
@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// TestBuildKubectlGetArgsChunkSize ensures --chunk-size is only passed when it differs from kubectl's default
+func TestBuildKubectlGetArgsChunkSize(t *testing.T) {
+	args := strings.Join(buildKubectlGetArgs("pods", "", "json", "", "", true, "ctx1", defaultChunkSize), " ")
+	if strings.Contains(args, "--chunk-size") {
+		t.Errorf("expected no --chunk-size with the default, got %q", args)
+	}
+	for _, size := range []int64{0, 100} {
+		args := buildKubectlGetArgs("pods", "", "json", "", "", true, "ctx1", size)
+		if want := fmt.Sprintf("--chunk-size=%d", size); args[len(args)-1] != want {
+			t.Errorf("expected %s, got %v", want, args)
+		}
+	}
+}
+
+// TestListPodsChunked ensures every page is requested with the continue token of the previous
+// one and the pages are merged into a single list
+func TestListPodsChunked(t *testing.T) {
+	pages := [][]string{{"web-1", "web-2"}, {"web-3", "web-4"}, {"web-5"}}
+	var requests []metav1.ListOptions
+	list := func(ctx context.Context, opts metav1.ListOptions) (*corev1.PodList, error) {
+		requests = append(requests, opts)
+		page := 0
+		if opts.Continue != "" {
+			fmt.Sscanf(opts.Continue, "page-%d", &page)
+		}
+		pods := &corev1.PodList{}
+		for _, name := range pages[page] {
+			pods.Items = append(pods.Items, corev1.Pod{ObjectMeta: metav1.ObjectMeta{Name: name}})
+		}
+		if page+1 < len(pages) {
+			pods.Continue = fmt.Sprintf("page-%d", page+1)
+		}
+		return pods, nil
+	}
+
+	pods, err := listPodsChunked(context.Background(), list, "app=web", 2)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(requests) != 3 {
+		t.Fatalf("expected 3 requests, got %d", len(requests))
+	}
+	for _, r := range requests {
+		if r.Limit != 2 || r.LabelSelector != "app=web" {
+			t.Errorf("expected every page to keep the limit and selector, got %+v", r)
+		}
+	}
+	var names []string
+	for _, p := range pods.Items {
+		names = append(names, p.Name)
+	}
+	if got := strings.Join(names, ","); got != "web-1,web-2,web-3,web-4,web-5" {
+		t.Errorf("expected all pages merged in order, got %s", got)
+	}
+	if pods.Continue != "" {
+		t.Errorf("expected the merged list to have no continue token, got %q", pods.Continue)
+	}
+}
@@ -0,0 +1,86 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"kubectl-multi/pkg/util"
+
+	"github.com/spf13/cobra"
+)
+
+// defaultCleanupAge is how old the temporary files must be for cleanup to remove them, those of an
+// invocation still running, such as build-once manifests and backups, are younger
+const defaultCleanupAge = time.Hour
+
+func newCleanupCommand() *cobra.Command {
+	var dryRun bool
+	var olderThan time.Duration
+
+	cmd := &cobra.Command{
+		Use:   "cleanup",
+		Short: "Remove the plugin's cache directory and leftover temporary files",
+		Long: `Remove the plugin's cache directory and any leftover temporary files.
+Use this to reset the plugin state, for example after the cache has been corrupted.
+
+Only the temporary files last modified more than --older-than ago (default 1h) are removed, so the
+files of kubectl multi commands running at the same time are kept.`,
+		Example: `# Remove the cache and temporary files
+kubectl multi cleanup
+
+# Show what would be removed without deleting anything
+kubectl multi cleanup --dry-run`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if olderThan < 0 {
+				return fmt.Errorf("--older-than must not be negative")
+			}
+			return handleCleanupCommand(dryRun, olderThan)
+		},
+	}
+
+	cmd.Flags().BoolVar(&dryRun, "dry-run", false, "only print what would be removed")
+	cmd.Flags().DurationVar(&olderThan, "older-than", defaultCleanupAge, "only remove the temporary files last modified longer ago than this, those of running commands are younger")
+
+	return cmd
+}
+
+func handleCleanupCommand(dryRun bool, olderThan time.Duration) error {
+	var paths []string
+
+	cacheDir, err := util.CacheDir()
+	if err != nil {
+		return err
+	}
+	if _, err := os.Stat(cacheDir); err == nil {
+		paths = append(paths, cacheDir)
+	}
+
+	tempFiles, err := util.TempFiles()
+	if err != nil {
+		return fmt.Errorf("failed to list temporary files: %v", err)
+	}
+	for _, f := range tempFiles {
+		if info, err := os.Lstat(f); err == nil && time.Since(info.ModTime()) > olderThan {
+			paths = append(paths, f)
+		}
+	}
+
+	if len(paths) == 0 {
+		fmt.Println("Nothing to clean up")
+		return nil
+	}
+
+	for _, p := range paths {
+		if dryRun {
+			fmt.Printf("Would remove %s\n", p)
+			continue
+		}
+		if err := os.RemoveAll(p); err != nil {
+			return fmt.Errorf("failed to remove %s: %v", p, err)
+		}
+		fmt.Printf("Removed %s\n", p)
+	}
+	return nil
+}
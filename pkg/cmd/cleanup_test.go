@@ -0,0 +1,77 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"kubectl-multi/pkg/util"
+)
+
+// useTempDirs makes the plugin's cache directory and the temporary directory fresh directories until
+// the test ends, whatever the platform
+func useTempDirs(t *testing.T) (cacheDir, tempDir string) {
+	t.Helper()
+	base := t.TempDir()
+	for _, env := range []string{"XDG_CACHE_HOME", "HOME", "LocalAppData"} {
+		t.Setenv(env, filepath.Join(base, "cache"))
+	}
+	tempDir = filepath.Join(base, "tmp")
+	if err := os.Mkdir(tempDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	for _, env := range []string{"TMPDIR", "TMP", "TEMP"} {
+		t.Setenv(env, tempDir)
+	}
+	cacheDir, err := util.CacheDir()
+	if err != nil || !strings.HasPrefix(cacheDir, base) || os.TempDir() != tempDir {
+		t.Skipf("cannot move the cache and temporary directories on this platform: %s, %s", cacheDir, os.TempDir())
+	}
+	return cacheDir, tempDir
+}
+
+// TestCleanupKeepsRecentTempFiles ensures cleanup removes the cache and the old temporary files, but
+// keeps those of commands that may still be running
+func TestCleanupKeepsRecentTempFiles(t *testing.T) {
+	cacheDir, tempDir := useTempDirs(t)
+	if err := os.MkdirAll(cacheDir, 0o755); err != nil {
+		t.Fatal(err)
+	}
+	old := filepath.Join(tempDir, util.TempFilePrefix+"manifest-old.yaml")
+	recent := filepath.Join(tempDir, util.TempFilePrefix+"manifest-recent.yaml")
+	other := filepath.Join(tempDir, "other-old.yaml")
+	for _, f := range []string{old, recent, other} {
+		if err := os.WriteFile(f, nil, 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	twoHoursAgo := time.Now().Add(-2 * time.Hour)
+	for _, f := range []string{old, other} {
+		if err := os.Chtimes(f, twoHoursAgo, twoHoursAgo); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := handleCleanupCommand(true, time.Hour); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := os.Stat(old); err != nil {
+		t.Errorf("expected --dry-run to remove nothing, got %v", err)
+	}
+
+	if err := handleCleanupCommand(false, time.Hour); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, f := range []string{cacheDir, old} {
+		if _, err := os.Stat(f); !os.IsNotExist(err) {
+			t.Errorf("expected %s to be removed, got %v", f, err)
+		}
+	}
+	for _, f := range []string{recent, other} {
+		if _, err := os.Stat(f); err != nil {
+			t.Errorf("expected %s to be kept, got %v", f, err)
+		}
+	}
+}
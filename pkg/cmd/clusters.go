@@ -0,0 +1,323 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"kubectl-multi/pkg/cluster"
+	"kubectl-multi/pkg/selector"
+	"kubectl-multi/pkg/util"
+)
+
+// discoverClusters discovers the clusters to operate on (or loads them from --from-file), names them from --aliases,
+// reads their --timeout-override,
+// narrows them to --clusters/--group and --where, drops --exclude-clusters and orders them according to --sort-clusters.
+// The clusters that could not be reached are returned apart, they have no clients.
+func discoverClusters(kubeconfig, remoteCtx string) (reachable, unreachable []cluster.ClusterInfo, err error) {
+	var clusters []cluster.ClusterInfo
+
+	// Parse --banner-template and --where before discovery, so invalid ones fail early
+	if bannerTemplate, err = parseBannerTemplate(bannerTemplateText); err != nil {
+		return nil, nil, err
+	}
+	var where *selector.Selector
+	if whereExpr != "" {
+		if where, err = selector.Parse(whereExpr); err != nil {
+			return nil, nil, fmt.Errorf("invalid --where: %v", err)
+		}
+	}
+
+	// Read --timeout-override before discovery, so an invalid duration fails early
+	var overrides map[string]time.Duration
+	if timeoutOverridesFile != "" {
+		if overrides, err = cluster.LoadTimeoutOverrides(timeoutOverridesFile); err != nil {
+			return nil, nil, err
+		}
+	}
+
+	// The rate limit applies to the clients built for every cluster during discovery
+	if err := cluster.SetClientRateLimit(cluster.ClientRateLimit{QPS: clientQPS, Burst: clientBurst}); err != nil {
+		return nil, nil, err
+	}
+
+	reachabilityCache = loadReachabilityCache()
+	serverVersionCache = loadServerVersionCache()
+	if clustersFile != "" {
+		clusters, err = cluster.LoadClustersFromFile(kubeconfig, clustersFile)
+	} else {
+		clusters, err = cluster.DiscoverClustersWithOptions(kubeconfig, remoteCtx, cluster.DiscoveryOptions{
+			Retries:      discoveryRetries,
+			Backoff:      discoveryBackoff,
+			AllContexts:  allContexts,
+			Reachability: reachabilityCache,
+		})
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+	// A cached cluster list may name contexts removed from the kubeconfig since, they fail clearly instead of mid-fan-out
+	if stale, err := cluster.MarkStaleContexts(kubeconfig, clusters); err != nil {
+		fmt.Printf("Warning: could not verify the cluster contexts: %v\n", err)
+	} else if len(stale) > 0 && clustersFile != "" {
+		fmt.Printf("Warning: %s lists contexts missing from the kubeconfig (%s), run 'clusters dump' again to refresh it\n", clustersFile, strings.Join(stale, ", "))
+	}
+	// Operations given no --namespace use the namespace of each cluster's context
+	if err := cluster.ApplyContextNamespaces(kubeconfig, clusters); err != nil {
+		fmt.Printf("Warning: could not read the namespaces of the cluster contexts: %v\n", err)
+	}
+	// Clusters that were not probed during discovery are still marked unreachable if a recent command could not reach them
+	for i := range clusters {
+		if clusters[i].DiscoveryErr == nil {
+			clusters[i].DiscoveryErr = reachabilityCache.Lookup(clusters[i].Context)
+		}
+	}
+	saveReachabilityCache()
+	if clusterRole != "" {
+		if clusters = clustersWithRole(clusters, clusterRole); len(clusters) == 0 {
+			return nil, nil, fmt.Errorf("no %s contexts found in the kubeconfig", strings.ToUpper(clusterRole))
+		}
+	}
+
+	aliases, conflicts, err := cluster.ClusterAliases(clusters, aliasesFile, displayLabel)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, value := range conflicts {
+		fmt.Printf("Warning: several clusters have %s=%s, showing their contexts instead\n", displayLabel, value)
+	}
+	clusterTimeoutOverrides = make(map[string]time.Duration, len(overrides))
+	for name, d := range overrides {
+		clusterTimeoutOverrides[cluster.ResolveContext(name, aliases)] = d
+	}
+
+	if len(selectedClusters) > 0 || len(selectedGroups) > 0 {
+		var groupContexts []string
+		if len(selectedGroups) > 0 {
+			groups, err := loadGroups()
+			if err != nil {
+				return nil, nil, err
+			}
+			if groupContexts, err = cluster.ExpandGroups(groups, selectedGroups); err != nil {
+				return nil, nil, err
+			}
+		}
+		if clusters, err = selectClusters(clusters, selectedClusters, groupContexts, aliases, itsContext(remoteCtx)); err != nil {
+			return nil, nil, err
+		}
+	}
+	if len(excludedClusters) > 0 {
+		clusters = excludeClusters(clusters, excludedClusters, aliases)
+	}
+	if where != nil {
+		clusters = whereClusters(clusters, where)
+	}
+
+	if err := sortClusters(clusters, sortClustersBy); err != nil {
+		return nil, nil, err
+	}
+	// Only the selected clusters get a binary, and are checked for OpenShift when a command runs on them
+	configureBackends(clusters, aliases)
+
+	// Unreachable clusters have no clients, keep them aside so fan-outs report them as failed
+	for _, c := range clusters {
+		if c.DiscoveryErr != nil {
+			fmt.Fprintf(os.Stderr, "Warning: cluster %s: %v\n", c.Display(), c.DiscoveryErr)
+			unreachable = append(unreachable, c)
+			continue
+		}
+		reachable = append(reachable, c)
+	}
+	return reachable, unreachable, nil
+}
+
+// clusterRole, when set, keeps only the discovered clusters of that role, e.g. cluster.RoleWDS for the wds commands
+var clusterRole string
+
+// clustersWithRole returns the clusters of the given role, in their order
+func clustersWithRole(clusters []cluster.ClusterInfo, role string) []cluster.ClusterInfo {
+	var kept []cluster.ClusterInfo
+	for _, c := range clusters {
+		if c.Role == role {
+			kept = append(kept, c)
+		}
+	}
+	return kept
+}
+
+// itsContext returns the context of the ITS (control) cluster, which commands do not run on.
+// With --all-contexts, every context is a target and there is no such cluster.
+func itsContext(remoteCtx string) string {
+	if allContexts {
+		return ""
+	}
+	return remoteCtx
+}
+
+// reachabilityCache holds the clusters found unreachable by recent commands, nil when it could not be loaded
+var reachabilityCache *cluster.ReachabilityCache
+
+// serverVersionCache holds the API server versions found by recent commands, nil when it could not be loaded
+var serverVersionCache *cluster.ServerVersionCache
+
+// loadServerVersionCache reads the server version cache from the plugin's cache directory. A cache that
+// cannot be read is only reported, the versions are then queried again.
+func loadServerVersionCache() *cluster.ServerVersionCache {
+	dir, err := util.CacheDir()
+	if err == nil {
+		var cache *cluster.ServerVersionCache
+		if cache, err = cluster.LoadServerVersionCache(filepath.Join(dir, "server-versions.json"), cluster.ServerVersionTTL); err == nil {
+			return cache
+		}
+	}
+	fmt.Printf("Warning: ignoring the server version cache: %v\n", err)
+	return nil
+}
+
+// reachabilityCacheFile returns the path of the reachability cache in the plugin's cache directory
+func reachabilityCacheFile() (string, error) {
+	dir, err := util.CacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "reachability.json"), nil
+}
+
+// loadReachabilityCache reads the reachability cache for --reachability-ttl. A cache that cannot be
+// read is only reported, since every cluster is then simply probed again.
+func loadReachabilityCache() *cluster.ReachabilityCache {
+	if reachabilityTTL <= 0 {
+		return nil
+	}
+	path, err := reachabilityCacheFile()
+	if err == nil {
+		var cache *cluster.ReachabilityCache
+		if cache, err = cluster.LoadReachabilityCache(path, reachabilityTTL); err == nil {
+			return cache
+		}
+	}
+	fmt.Printf("Warning: ignoring the reachability cache: %v\n", err)
+	return nil
+}
+
+// saveReachabilityCache writes the reachability cache, reporting failures as warnings
+func saveReachabilityCache() {
+	if err := reachabilityCache.Save(); err != nil {
+		fmt.Printf("Warning: %v\n", err)
+	}
+}
+
+// recordReachability remembers the clusters of a fan-out that could not be reached, and forgets
+// those that answered
+func recordReachability(results []clusterResult) {
+	if reachabilityCache == nil {
+		return
+	}
+	for _, r := range results {
+		if r.Skipped != "" {
+			continue
+		}
+		if r.Err != nil && classifyError(r.Err) != categoryUnreachable {
+			continue
+		}
+		reachabilityCache.Record(r.Context, r.Err)
+	}
+	saveReachabilityCache()
+}
+
+// discoveryBackoff is the delay before the first discovery retry
+const discoveryBackoff = 500 * time.Millisecond
+
+// sortClusters orders clusters in place: "name" sorts by context name, "discovery" keeps discovery order
+func sortClusters(clusters []cluster.ClusterInfo, by string) error {
+	switch by {
+	case "name", "":
+		sort.SliceStable(clusters, func(i, j int) bool {
+			return clusters[i].Context < clusters[j].Context
+		})
+	case "discovery":
+	default:
+		return fmt.Errorf("invalid --sort-clusters value %q: must be one of name|discovery", by)
+	}
+	return nil
+}
+
+// selectClusters keeps only the clusters named in names or groupContexts, which may be contexts or aliases.
+// Every name must match a discovered cluster, while group members that were not discovered are ignored.
+// The ITS cluster is kept so commands can still report that it is not targeted.
+func selectClusters(clusters []cluster.ClusterInfo, names, groupContexts []string, aliases map[string]string, itsContext string) ([]cluster.ClusterInfo, error) {
+	wanted := make(map[string]bool)
+	for _, name := range append(append([]string{}, names...), groupContexts...) {
+		wanted[cluster.ResolveContext(name, aliases)] = true
+	}
+
+	var selected []cluster.ClusterInfo
+	found := make(map[string]bool)
+	for _, c := range clusters {
+		if wanted[c.Context] {
+			selected = append(selected, c)
+			found[c.Context] = true
+		} else if c.Context == itsContext {
+			selected = append(selected, c)
+		}
+	}
+
+	var unknown []string
+	for _, name := range names {
+		if !found[cluster.ResolveContext(name, aliases)] {
+			unknown = append(unknown, name)
+		}
+	}
+	if len(unknown) > 0 {
+		return nil, fmt.Errorf("unknown cluster(s) in --clusters: %s", strings.Join(unknown, ", "))
+	}
+	return selected, nil
+}
+
+// excludeClusters drops the clusters named in names, which may be contexts or aliases
+func excludeClusters(clusters []cluster.ClusterInfo, names []string, aliases map[string]string) []cluster.ClusterInfo {
+	excluded := make(map[string]bool)
+	for _, name := range names {
+		excluded[cluster.ResolveContext(name, aliases)] = true
+	}
+
+	var kept []cluster.ClusterInfo
+	for _, c := range clusters {
+		if !excluded[c.Context] {
+			kept = append(kept, c)
+		}
+	}
+	return kept
+}
+
+// whereClusters returns the clusters matching the --where expression
+func whereClusters(clusters []cluster.ClusterInfo, where *selector.Selector) []cluster.ClusterInfo {
+	var kept []cluster.ClusterInfo
+	for _, c := range clusters {
+		if where.Matches(c) {
+			kept = append(kept, c)
+		}
+	}
+	return kept
+}
+
+// groupsFilePath returns the path of the cluster groups file
+func groupsFilePath() (string, error) {
+	dir, err := util.ConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "groups.yaml"), nil
+}
+
+// loadGroups loads the cluster groups defined in the groups file
+func loadGroups() (map[string][]string, error) {
+	path, err := groupsFilePath()
+	if err != nil {
+		return nil, err
+	}
+	return cluster.LoadGroups(path)
+}
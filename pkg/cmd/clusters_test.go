@@ -0,0 +1,183 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"kubectl-multi/pkg/cluster"
+	"kubectl-multi/pkg/selector"
+)
+
+func contextsOf(clusters []cluster.ClusterInfo) []string {
+	var contexts []string
+	for _, c := range clusters {
+		contexts = append(contexts, c.Context)
+	}
+	return contexts
+}
+
+// TestSelectClustersWithGroups ensures group members are intersected with discovery and composed with exclusions
+func TestSelectClustersWithGroups(t *testing.T) {
+	clusters := []cluster.ClusterInfo{{Context: "its1"}, {Context: "prod-eu"}, {Context: "prod-us"}, {Context: "staging-eu"}}
+
+	selected, err := selectClusters(clusters, nil, []string{"prod-eu", "prod-us", "prod-asia"}, nil, "its1")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	selected = excludeClusters(selected, []string{"prod-us"}, nil)
+
+	got := contextsOf(selected)
+	if len(got) != 2 || got[0] != "its1" || got[1] != "prod-eu" {
+		t.Errorf("expected [its1 prod-eu], got %v", got)
+	}
+}
+
+// TestWhereClusters ensures --where composes with exclusions and keeps the cluster order
+func TestWhereClusters(t *testing.T) {
+	clusters := []cluster.ClusterInfo{
+		{Context: "prod-eu", Name: "prod-eu", Labels: map[string]string{"region": "eu-west"}},
+		{Context: "prod-us", Name: "prod-us", Labels: map[string]string{"region": "us-east"}},
+		{Context: "prod-us2", Name: "prod-us2", Labels: map[string]string{"region": "us-east"}},
+		{Context: "staging-us", Name: "staging-us", Labels: map[string]string{"region": "us-east"}},
+	}
+	where, err := selector.Parse("name =~ prod-.* && region == us-east")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	got := contextsOf(whereClusters(excludeClusters(clusters, []string{"prod-us2"}, nil), where))
+	if len(got) != 1 || got[0] != "prod-us" {
+		t.Errorf("expected [prod-us], got %v", got)
+	}
+}
+
+// TestSelectClustersUnknownName ensures an explicitly named cluster must exist
+func TestSelectClustersUnknownName(t *testing.T) {
+	clusters := []cluster.ClusterInfo{{Context: "wds1"}}
+	if _, err := selectClusters(clusters, []string{"wds9"}, nil, nil, "its1"); err == nil {
+		t.Error("expected an error for an unknown cluster")
+	}
+}
+
+// TestAllContextsTargetsITS ensures the ITS is an ordinary target with --all-contexts
+func TestAllContextsTargetsITS(t *testing.T) {
+	old := allContexts
+	defer func() { allContexts = old }()
+	clusters := []cluster.ClusterInfo{{Context: "its1"}, {Context: "kind-dev"}, {Context: "wds1"}}
+
+	allContexts = false
+	if targets, its := fanOutTargets(clusters, "", itsContext("its1")); len(targets) != 2 || its == nil {
+		t.Errorf("expected the ITS to be left out by default, got targets %v", contextsOf(targets))
+	}
+
+	allContexts = true
+	targets, its := fanOutTargets(clusters, "", itsContext("its1"))
+	if len(targets) != 3 || its != nil {
+		t.Errorf("expected every context to be a target, got %v", contextsOf(targets))
+	}
+
+	selected, err := selectClusters(clusters, []string{"kind-dev"}, nil, nil, itsContext("its1"))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := contextsOf(selected); len(got) != 1 || got[0] != "kind-dev" {
+		t.Errorf("expected only [kind-dev] to be selected, got %v", got)
+	}
+}
+
+// TestClusterAliases ensures clusters are shown and selected by their label, the --aliases file takes
+// precedence and clusters without the label fall back to their context
+func TestClusterAliases(t *testing.T) {
+	clusters := []cluster.ClusterInfo{
+		{Context: "cluster-7f3a9c", Labels: map[string]string{"region": "eu-west"}},
+		{Context: "cluster-1b2c3d", Labels: map[string]string{"region": "us-east"}},
+		{Context: "cluster-9e8d7c"},
+	}
+	file := filepath.Join(t.TempDir(), "aliases.yaml")
+	if err := os.WriteFile(file, []byte("cluster-1b2c3d: dev\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	aliases, conflicts, err := cluster.ClusterAliases(clusters, file, "region")
+	if err != nil || len(conflicts) != 0 {
+		t.Fatalf("unexpected conflicts %v or error %v", conflicts, err)
+	}
+
+	want := []string{"eu-west", "dev", "cluster-9e8d7c"}
+	for i, c := range clusters {
+		if got := c.Display(); got != want[i] {
+			t.Errorf("expected %s to be shown as %q, got %q", c.Context, want[i], got)
+		}
+	}
+
+	selected, err := selectClusters(clusters, []string{"eu-west", "cluster-9e8d7c"}, nil, aliases, "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := contextsOf(selected); len(got) != 2 || got[0] != "cluster-7f3a9c" || got[1] != "cluster-9e8d7c" {
+		t.Errorf("expected the label value and the context to select their clusters, got %v", got)
+	}
+}
+
+// TestClustersWithRole ensures only the clusters of the role are kept, in their order
+func TestClustersWithRole(t *testing.T) {
+	clusters := []cluster.ClusterInfo{
+		{Context: "its1", Role: cluster.RoleITS},
+		{Context: "wds2", Role: cluster.RoleWDS},
+		{Context: "cluster1", Role: cluster.RoleContext},
+		{Context: "wds1", Role: cluster.RoleWDS},
+	}
+	if got := contextsOf(clustersWithRole(clusters, cluster.RoleWDS)); len(got) != 2 || got[0] != "wds2" || got[1] != "wds1" {
+		t.Errorf("expected wds2,wds1, got %v", got)
+	}
+	if kept := clustersWithRole(clusters, cluster.RoleManaged); len(kept) != 0 {
+		t.Errorf("expected no managed clusters, got %v", kept)
+	}
+}
+
+// TestSortClusters ensures clusters are ordered by context name, or left in discovery order
+func TestSortClusters(t *testing.T) {
+	for _, tc := range []struct {
+		by   string
+		want string
+	}{
+		{"name", "cluster1,wds1,wds2"},
+		{"", "cluster1,wds1,wds2"},
+		{"discovery", "wds2,cluster1,wds1"},
+	} {
+		clusters := []cluster.ClusterInfo{{Context: "wds2"}, {Context: "cluster1"}, {Context: "wds1"}}
+		if err := sortClusters(clusters, tc.by); err != nil {
+			t.Fatalf("%q: unexpected error: %v", tc.by, err)
+		}
+		var got []string
+		for _, c := range clusters {
+			got = append(got, c.Context)
+		}
+		if strings.Join(got, ",") != tc.want {
+			t.Errorf("%q: expected %s, got %v", tc.by, tc.want, got)
+		}
+	}
+	if err := sortClusters(nil, "size"); err == nil {
+		t.Error("expected an error for an unknown order")
+	}
+}
+
+// TestDiscoverClustersSortOrder ensures discoverClusters discovers through the cluster package and
+// rejects an unknown --sort-clusters value
+func TestDiscoverClustersSortOrder(t *testing.T) {
+	defer func(by string) { sortClustersBy = by }(sortClustersBy)
+	kubeconfig := filepath.Join(t.TempDir(), "config")
+	if err := os.WriteFile(kubeconfig, []byte("apiVersion: v1\nkind: Config\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	sortClustersBy = "name"
+	if clusters, _, err := discoverClusters(kubeconfig, ""); err != nil || len(clusters) != 0 {
+		t.Errorf("expected no clusters in an empty kubeconfig, got %v, %v", clusters, err)
+	}
+	sortClustersBy = "size"
+	if _, _, err := discoverClusters(kubeconfig, ""); err == nil || !strings.Contains(err.Error(), "invalid --sort-clusters") {
+		t.Errorf("expected an invalid --sort-clusters error, got %v", err)
+	}
+}
@@ -0,0 +1,88 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"kubectl-multi/pkg/cluster"
+
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/yaml"
+)
+
+func newClustersCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "clusters",
+		Short: "Inspect the discovered clusters",
+	}
+
+	cmd.AddCommand(newClustersDumpCommand())
+	return cmd
+}
+
+func newClustersDumpCommand() *cobra.Command {
+	var outputFormat string
+	var file string
+
+	cmd := &cobra.Command{
+		Use:   "dump",
+		Short: "Write the discovered clusters as a manifest that can be loaded with --from-file",
+		Long: `Write the discovered clusters (context, name, role and labels) as a YAML or JSON manifest.
+Pass the manifest to --from-file to skip discovery and use the listed clusters directly,
+for example in CI where the ITS cannot be reached but the cluster list is known.`,
+		Example: `# Save the discovered clusters
+kubectl multi clusters dump -o yaml --file clusters.yaml
+
+# Use the saved clusters instead of discovering them
+kubectl multi get pods --from-file clusters.yaml`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			kubeconfig, remoteCtx, _, _, _ := GetGlobalFlags()
+			return handleClustersDumpCommand(outputFormat, file, kubeconfig, remoteCtx)
+		},
+	}
+
+	cmd.Flags().StringVarP(&outputFormat, "output", "o", "yaml", "output format (yaml|json)")
+	cmd.Flags().StringVar(&file, "file", "", "write the manifest to this file instead of stdout")
+	return cmd
+}
+
+func handleClustersDumpCommand(outputFormat, file, kubeconfig, remoteCtx string) error {
+	clusters, unreachable, err := discoverClusters(kubeconfig, remoteCtx)
+	if err != nil {
+		return fmt.Errorf("failed to discover clusters: %v", err)
+	}
+
+	// Unreachable clusters are still part of the fleet
+	data, err := marshalClusterList(cluster.NewClusterList(append(clusters, unreachable...)), outputFormat)
+	if err != nil {
+		return err
+	}
+
+	if file == "" {
+		_, err = os.Stdout.Write(data)
+		return err
+	}
+	if err := os.WriteFile(file, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write %s: %v", file, err)
+	}
+	fmt.Printf("Wrote %d clusters to %s\n", len(clusters)+len(unreachable), file)
+	return nil
+}
+
+// marshalClusterList renders a cluster list in the given output format
+func marshalClusterList(list cluster.ClusterList, outputFormat string) ([]byte, error) {
+	switch outputFormat {
+	case "yaml":
+		return yaml.Marshal(list)
+	case "json":
+		data, err := json.MarshalIndent(list, "", "  ")
+		if err != nil {
+			return nil, err
+		}
+		return append(data, '\n'), nil
+	default:
+		return nil, fmt.Errorf("unsupported output format %q, must be yaml or json", outputFormat)
+	}
+}
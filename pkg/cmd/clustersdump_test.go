@@ -0,0 +1,34 @@
+package cmd
+
+import (
+	"reflect"
+	"testing"
+
+	"kubectl-multi/pkg/cluster"
+)
+
+// TestMarshalClusterListRoundTrip ensures both output formats can be loaded back
+func TestMarshalClusterListRoundTrip(t *testing.T) {
+	want := cluster.NewClusterList([]cluster.ClusterInfo{
+		{Name: "cluster1", Context: "cluster1", Role: cluster.RoleManaged, Labels: map[string]string{"region": "eu-west"}},
+		{Name: "kind-kubeflex", Context: "its1", Role: cluster.RoleITS},
+	})
+
+	for _, format := range []string{"yaml", "json"} {
+		data, err := marshalClusterList(want, format)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", format, err)
+		}
+		got, err := cluster.ParseClusterList(data)
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %v", format, err)
+		}
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: round trip mismatch:\nwant %+v\ngot  %+v", format, want, got)
+		}
+	}
+
+	if _, err := marshalClusterList(want, "wide"); err == nil {
+		t.Error("expected an error for an unsupported output format")
+	}
+}
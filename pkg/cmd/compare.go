@@ -0,0 +1,363 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"kubectl-multi/pkg/util"
+
+	"github.com/spf13/cobra"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/yaml"
+)
+
+// compareContextLines is the number of unchanged lines shown around each change with --format=unified
+const compareContextLines = 3
+
+// compareIgnoredFields are fields allocated by each cluster that are left out of the comparison,
+// in addition to those removed by stripClusterFields
+var compareIgnoredFields = [][]string{
+	{"spec", "clusterIP"},
+	{"spec", "clusterIPs"},
+}
+
+func newCompareCommand() *cobra.Command {
+	var format string
+
+	cmd := &cobra.Command{
+		Use:   "compare TYPE/NAME",
+		Short: "Show where a resource differs across managed clusters",
+		Long: `Show where a resource differs across managed clusters.
+The resource is fetched from every cluster and stripped of the fields set by the cluster itself
+(status, resource versions, UIDs, timestamps, managed fields, ...) before comparing. The table
+format lists every field whose value is not the same in all clusters, with one column per cluster.
+The unified format prints a diff of each cluster against the first cluster.`,
+		Example: `# Show the fields of the web deployment that differ between clusters
+kubectl multi compare deployment/web
+
+# Show a unified diff of a configmap against the first cluster
+kubectl multi compare cm/settings -n payments --format=unified`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if format != "table" && format != "unified" {
+				return fmt.Errorf("invalid format %q: must be table or unified", format)
+			}
+			kubeconfig, remoteCtx, _, namespace, _ := GetGlobalFlags()
+			return handleCompareCommand(args[0], format, kubeconfig, remoteCtx, namespace)
+		},
+	}
+
+	cmd.Flags().StringVar(&format, "format", "table", "output format: table (differing fields per cluster) or unified (diff against the first cluster)")
+
+	return cmd
+}
+
+func handleCompareCommand(resource, format, kubeconfig, remoteCtx, namespace string) error {
+	if !strings.Contains(resource, "/") {
+		return fmt.Errorf("resource must be given as TYPE/NAME, e.g. deployment/web")
+	}
+
+	clusters, _, err := discoverClusters(kubeconfig, remoteCtx)
+	if err != nil {
+		return fmt.Errorf("failed to discover clusters: %v", err)
+	}
+	targets, _ := fanOutTargets(clusters, currentKubeContext(kubeconfig), itsContext(remoteCtx))
+
+	objects := fetchObjects(targets, kubeconfig, resource, "", "", namespace, false, defaultChunkSize)
+	found := make(map[string]bool)
+	for _, o := range objects {
+		found[o.Cluster] = true
+		normalizeForCompare(o.Object)
+	}
+	var missing []string
+	for _, c := range targets {
+		if !found[c.Display()] {
+			missing = append(missing, c.Display())
+		}
+	}
+	if len(objects) < 2 {
+		return fmt.Errorf("%s must exist in at least two clusters to compare, found in %d", resource, len(objects))
+	}
+
+	out := util.GetOutputStream()
+	if format == "unified" {
+		err = writeUnifiedComparison(out, resource, objects)
+	} else {
+		err = writeComparisonTable(out, resource, objects)
+	}
+	if err != nil {
+		return err
+	}
+	if len(missing) > 0 {
+		fmt.Fprintf(out, "\n%s not found in: %s\n", resource, strings.Join(missing, ", "))
+	}
+	return nil
+}
+
+// normalizeForCompare removes the fields of obj that are expected to differ between clusters
+func normalizeForCompare(obj *unstructured.Unstructured) {
+	stripClusterFields(obj)
+	for _, path := range compareIgnoredFields {
+		unstructured.RemoveNestedField(obj.Object, path...)
+	}
+}
+
+// flattenFields flattens a decoded JSON value into dotted field paths and their values.
+// List items that have a name, such as containers and env vars, are keyed by name rather than
+// position, so inserting an item does not make every following item look different.
+func flattenFields(path string, value interface{}, fields map[string]string) {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		if len(v) == 0 {
+			fields[path] = "{}"
+			return
+		}
+		for key, child := range v {
+			childPath := key
+			if path != "" {
+				childPath = path + "." + key
+			}
+			flattenFields(childPath, child, fields)
+		}
+	case []interface{}:
+		if len(v) == 0 {
+			fields[path] = "[]"
+			return
+		}
+		named := listItemNames(v)
+		for i, child := range v {
+			key := fmt.Sprint(i)
+			if named != nil {
+				key = named[i]
+			}
+			flattenFields(fmt.Sprintf("%s[%s]", path, key), child, fields)
+		}
+	case nil:
+		fields[path] = "null"
+	default:
+		fields[path] = fmt.Sprint(v)
+	}
+}
+
+// listItemNames returns the names of the items of a list, or nil unless every item is an object
+// with a distinct name
+func listItemNames(items []interface{}) []string {
+	names := make([]string, len(items))
+	seen := make(map[string]bool)
+	for i, item := range items {
+		m, ok := item.(map[string]interface{})
+		if !ok {
+			return nil
+		}
+		name, ok := m["name"].(string)
+		if !ok || name == "" || seen[name] {
+			return nil
+		}
+		seen[name] = true
+		names[i] = name
+	}
+	return names
+}
+
+// differingFields returns the field paths whose value is not the same in all objects, sorted,
+// along with the flattened fields of every object
+func differingFields(objects []clusterObject) ([]string, []map[string]string) {
+	flattened := make([]map[string]string, len(objects))
+	paths := make(map[string]bool)
+	for i, o := range objects {
+		flattened[i] = make(map[string]string)
+		flattenFields("", o.Object.Object, flattened[i])
+		for path := range flattened[i] {
+			paths[path] = true
+		}
+	}
+
+	var differing []string
+	for path := range paths {
+		value, ok := flattened[0][path]
+		for _, fields := range flattened[1:] {
+			if v, found := fields[path]; found != ok || v != value {
+				differing = append(differing, path)
+				break
+			}
+		}
+	}
+	sort.Strings(differing)
+	return differing, flattened
+}
+
+// writeComparisonTable prints the fields that differ between the objects, one column per cluster
+func writeComparisonTable(out io.Writer, resource string, objects []clusterObject) error {
+	differing, flattened := differingFields(objects)
+	if len(differing) == 0 {
+		_, err := fmt.Fprintf(out, "No differences found in %s across %d clusters\n", resource, len(objects))
+		return err
+	}
+
+	tw := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "FIELD")
+	for _, o := range objects {
+		fmt.Fprintf(tw, "\t%s", o.Cluster)
+	}
+	fmt.Fprintln(tw)
+	for _, path := range differing {
+		fmt.Fprint(tw, path)
+		for _, fields := range flattened {
+			value, ok := fields[path]
+			if !ok {
+				value = "<none>"
+			}
+			fmt.Fprintf(tw, "\t%s", value)
+		}
+		fmt.Fprintln(tw)
+	}
+	return tw.Flush()
+}
+
+// writeUnifiedComparison prints a unified diff of the YAML of every object against the first one
+func writeUnifiedComparison(out io.Writer, resource string, objects []clusterObject) error {
+	base, err := yaml.Marshal(objects[0].Object.Object)
+	if err != nil {
+		return fmt.Errorf("failed to render %s from cluster %s: %v", resource, objects[0].Cluster, err)
+	}
+	baseLines := strings.Split(strings.TrimSuffix(string(base), "\n"), "\n")
+
+	differences := 0
+	for _, o := range objects[1:] {
+		data, err := yaml.Marshal(o.Object.Object)
+		if err != nil {
+			return fmt.Errorf("failed to render %s from cluster %s: %v", resource, o.Cluster, err)
+		}
+		lines := strings.Split(strings.TrimSuffix(string(data), "\n"), "\n")
+		if writeUnifiedDiff(out, objects[0].Cluster+"/"+resource, o.Cluster+"/"+resource, baseLines, lines, compareContextLines) {
+			differences++
+		}
+	}
+	if differences == 0 {
+		_, err := fmt.Fprintf(out, "No differences found in %s across %d clusters\n", resource, len(objects))
+		return err
+	}
+	return nil
+}
+
+// diffLine is a line of a line-based diff: ' ' for a common line, '-' for a removed line and
+// '+' for an added line
+type diffLine struct {
+	Op   byte
+	Text string
+}
+
+// diffLines computes the shortest line diff turning a into b from their longest common subsequence
+func diffLines(a, b []string) []diffLine {
+	lcs := make([][]int, len(a)+1)
+	for i := range lcs {
+		lcs[i] = make([]int, len(b)+1)
+	}
+	for i := len(a) - 1; i >= 0; i-- {
+		for j := len(b) - 1; j >= 0; j-- {
+			if a[i] == b[j] {
+				lcs[i][j] = lcs[i+1][j+1] + 1
+			} else if lcs[i+1][j] >= lcs[i][j+1] {
+				lcs[i][j] = lcs[i+1][j]
+			} else {
+				lcs[i][j] = lcs[i][j+1]
+			}
+		}
+	}
+
+	var lines []diffLine
+	i, j := 0, 0
+	for i < len(a) && j < len(b) {
+		switch {
+		case a[i] == b[j]:
+			lines = append(lines, diffLine{' ', a[i]})
+			i++
+			j++
+		case lcs[i+1][j] >= lcs[i][j+1]:
+			lines = append(lines, diffLine{'-', a[i]})
+			i++
+		default:
+			lines = append(lines, diffLine{'+', b[j]})
+			j++
+		}
+	}
+	for ; i < len(a); i++ {
+		lines = append(lines, diffLine{'-', a[i]})
+	}
+	for ; j < len(b); j++ {
+		lines = append(lines, diffLine{'+', b[j]})
+	}
+	return lines
+}
+
+// writeUnifiedDiff writes the unified diff of a and b with context unchanged lines around every
+// change, and reports whether they differ
+func writeUnifiedDiff(out io.Writer, fromName, toName string, a, b []string, context int) bool {
+	lines := diffLines(a, b)
+
+	// Keep the changed lines and the context around them
+	keep := make([]bool, len(lines))
+	changed := false
+	for i, l := range lines {
+		if l.Op == ' ' {
+			continue
+		}
+		changed = true
+		for k := i - context; k <= i+context; k++ {
+			if k >= 0 && k < len(lines) {
+				keep[k] = true
+			}
+		}
+	}
+	if !changed {
+		return false
+	}
+
+	fmt.Fprintf(out, "--- %s\n+++ %s\n", fromName, toName)
+	oldLine, newLine := 1, 1
+	for i := 0; i < len(lines); {
+		if !keep[i] {
+			if lines[i].Op != '+' {
+				oldLine++
+			}
+			if lines[i].Op != '-' {
+				newLine++
+			}
+			i++
+			continue
+		}
+
+		end := i
+		oldCount, newCount := 0, 0
+		for ; end < len(lines) && keep[end]; end++ {
+			if lines[end].Op != '+' {
+				oldCount++
+			}
+			if lines[end].Op != '-' {
+				newCount++
+			}
+		}
+		fmt.Fprintf(out, "@@ -%s +%s @@\n", hunkRange(oldLine, oldCount), hunkRange(newLine, newCount))
+		for _, l := range lines[i:end] {
+			fmt.Fprintf(out, "%c%s\n", l.Op, l.Text)
+		}
+		oldLine += oldCount
+		newLine += newCount
+		i = end
+	}
+	return true
+}
+
+// hunkRange formats the line range of a hunk header, where an empty range refers to the line before it
+func hunkRange(start, count int) string {
+	if count == 0 {
+		start--
+	}
+	if count == 1 {
+		return fmt.Sprint(start)
+	}
+	return fmt.Sprintf("%d,%d", start, count)
+}
@@ -0,0 +1,110 @@
+package cmd
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func compareDeployment(cluster, image string, replicas int64) clusterObject {
+	obj := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "apps/v1",
+		"kind":       "Deployment",
+		"metadata": map[string]interface{}{
+			"name":            "web",
+			"uid":             "uid-" + cluster,
+			"resourceVersion": "42",
+		},
+		"spec": map[string]interface{}{
+			"replicas": replicas,
+			"template": map[string]interface{}{
+				"spec": map[string]interface{}{
+					"containers": []interface{}{
+						map[string]interface{}{"name": "web", "image": image},
+					},
+				},
+			},
+		},
+		"status": map[string]interface{}{"readyReplicas": replicas},
+	}}
+	normalizeForCompare(obj)
+	return clusterObject{Cluster: cluster, Object: obj}
+}
+
+// TestFlattenFieldsNamedItems ensures named list items are keyed by name and others by position
+func TestFlattenFieldsNamedItems(t *testing.T) {
+	fields := make(map[string]string)
+	flattenFields("", map[string]interface{}{
+		"env":  []interface{}{map[string]interface{}{"name": "LOG_LEVEL", "value": "debug"}},
+		"args": []interface{}{"--port", int64(8080)},
+		"tags": []interface{}{},
+	}, fields)
+
+	want := map[string]string{
+		"env[LOG_LEVEL].name":  "LOG_LEVEL",
+		"env[LOG_LEVEL].value": "debug",
+		"args[0]":              "--port",
+		"args[1]":              "8080",
+		"tags":                 "[]",
+	}
+	if !reflect.DeepEqual(fields, want) {
+		t.Errorf("expected %v, got %v", want, fields)
+	}
+}
+
+// TestWriteComparisonTable ensures only the differing fields are listed, ignoring cluster-set fields
+func TestWriteComparisonTable(t *testing.T) {
+	objects := []clusterObject{
+		compareDeployment("cluster1", "web:1.0", 3),
+		compareDeployment("cluster2", "web:1.1", 3),
+		compareDeployment("cluster3", "web:1.0", 5),
+	}
+
+	var out bytes.Buffer
+	if err := writeComparisonTable(&out, "deployment/web", objects); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := "FIELD                                     cluster1  cluster2  cluster3\n" +
+		"spec.replicas                             3         3         5\n" +
+		"spec.template.spec.containers[web].image  web:1.0   web:1.1   web:1.0\n"
+	if got := out.String(); got != want {
+		t.Errorf("expected:\n%s\ngot:\n%s", want, got)
+	}
+}
+
+// TestWriteComparisonTableNoDifferences ensures identical resources are reported as such
+func TestWriteComparisonTableNoDifferences(t *testing.T) {
+	objects := []clusterObject{compareDeployment("cluster1", "web:1.0", 3), compareDeployment("cluster2", "web:1.0", 3)}
+
+	var out bytes.Buffer
+	if err := writeComparisonTable(&out, "deployment/web", objects); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(out.String(), "No differences found") {
+		t.Errorf("expected no differences, got:\n%s", out.String())
+	}
+}
+
+// TestWriteUnifiedDiff ensures changes are printed in hunks with their context lines
+func TestWriteUnifiedDiff(t *testing.T) {
+	a := []string{"a", "b", "c", "d", "e", "f", "g", "h", "i", "j"}
+	b := []string{"a", "b", "c", "d", "E", "f", "g", "h", "i", "j", "k"}
+
+	var out bytes.Buffer
+	if !writeUnifiedDiff(&out, "cluster1/cm/x", "cluster2/cm/x", a, b, 1) {
+		t.Fatal("expected a difference")
+	}
+	want := "--- cluster1/cm/x\n+++ cluster2/cm/x\n" +
+		"@@ -4,3 +4,3 @@\n d\n-e\n+E\n f\n" +
+		"@@ -10 +10,2 @@\n j\n+k\n"
+	if got := out.String(); got != want {
+		t.Errorf("expected:\n%s\ngot:\n%s", want, got)
+	}
+
+	if writeUnifiedDiff(&bytes.Buffer{}, "x", "y", a, a, 3) {
+		t.Error("expected no difference for identical lines")
+	}
+}
@@ -0,0 +1,123 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"kubectl-multi/pkg/cluster"
+	"kubectl-multi/pkg/util"
+
+	"github.com/spf13/cobra"
+)
+
+func newCompletionCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "completion (bash|zsh|fish|powershell)",
+		Short: "Output shell completion code for the specified shell",
+		Long: `Output shell completion code for bash, zsh, fish or powershell.
+The completions cover all subcommands and flags, and complete cluster names for --clusters,
+--exclude-clusters and replicate --from, and group names for --group.
+
+As a standalone binary, load the completions into the current shell with:
+
+  bash:        source <(kubectl-multi completion bash)
+  zsh:         source <(kubectl-multi completion zsh)
+  fish:        kubectl-multi completion fish | source
+  powershell:  kubectl-multi completion powershell | Out-String | Invoke-Expression
+
+To load them in every session, add that line to ~/.bashrc, ~/.zshrc,
+~/.config/fish/config.fish or your PowerShell profile.
+
+When used as a kubectl plugin, kubectl 1.26 and later complete "kubectl multi" by running an
+executable named kubectl_complete-multi from your PATH. Create it with:
+
+  cat > /usr/local/bin/kubectl_complete-multi <<'EOF'
+  #!/usr/bin/env sh
+  kubectl multi __complete "$@"
+  EOF
+  chmod +x /usr/local/bin/kubectl_complete-multi`,
+		Example: `# Load bash completions in the current shell
+source <(kubectl-multi completion bash)
+
+# Install zsh completions for every session
+kubectl-multi completion zsh > "${fpath[1]}/_kubectl-multi"`,
+		ValidArgs:             []string{"bash", "zsh", "fish", "powershell"},
+		Args:                  cobra.MatchAll(cobra.ExactArgs(1), cobra.OnlyValidArgs),
+		DisableFlagsInUseLine: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return writeCompletion(cmd.Root(), args[0], util.GetOutputStream())
+		},
+	}
+	return cmd
+}
+
+// writeCompletion writes the completion script of root for shell to out
+func writeCompletion(root *cobra.Command, shell string, out io.Writer) error {
+	switch shell {
+	case "bash":
+		return root.GenBashCompletionV2(out, true)
+	case "zsh":
+		return root.GenZshCompletion(out)
+	case "fish":
+		return root.GenFishCompletion(out, true)
+	case "powershell":
+		return root.GenPowerShellCompletionWithDesc(out)
+	default:
+		return fmt.Errorf("unsupported shell %q, must be one of bash, zsh, fish or powershell", shell)
+	}
+}
+
+// completeClusterNames completes the comma-separated cluster names of --clusters and similar flags
+// with the contexts of the kubeconfig and the friendly names of the --aliases file
+func completeClusterNames(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	names := kubeconfigContextOrder(kubeconfig)
+	if aliasesFile != "" {
+		if aliases, err := cluster.LoadAliases(aliasesFile); err == nil {
+			for _, alias := range aliases {
+				names = append(names, alias)
+			}
+		}
+	}
+	return completeList(names, toComplete), cobra.ShellCompDirectiveNoFileComp | cobra.ShellCompDirectiveNoSpace
+}
+
+// completeGroupNames completes the comma-separated group names of --group with the groups
+// defined in the groups file
+func completeGroupNames(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	groups, err := loadGroups()
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+	names := make([]string, 0, len(groups))
+	for name := range groups {
+		names = append(names, name)
+	}
+	return completeList(names, toComplete), cobra.ShellCompDirectiveNoFileComp | cobra.ShellCompDirectiveNoSpace
+}
+
+// completeList returns the candidates completing the last item of a comma-separated list,
+// prefixed with the items already typed. Items already in the list are not offered again.
+func completeList(candidates []string, toComplete string) []string {
+	prefix, last := "", toComplete
+	if i := strings.LastIndex(toComplete, ","); i >= 0 {
+		prefix, last = toComplete[:i+1], toComplete[i+1:]
+	}
+	typed := make(map[string]bool)
+	for _, item := range strings.Split(prefix, ",") {
+		typed[item] = true
+	}
+
+	seen := make(map[string]bool)
+	var completions []string
+	for _, name := range candidates {
+		if seen[name] || typed[name] || !strings.HasPrefix(name, last) {
+			continue
+		}
+		seen[name] = true
+		completions = append(completions, prefix+name)
+	}
+	sort.Strings(completions)
+	return completions
+}
@@ -0,0 +1,84 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+// TestWriteCompletion ensures a script is generated for every supported shell
+func TestWriteCompletion(t *testing.T) {
+	for _, shell := range []string{"bash", "zsh", "fish", "powershell"} {
+		var out bytes.Buffer
+		if err := writeCompletion(rootCmd, shell, &out); err != nil {
+			t.Fatalf("%s: unexpected error: %v", shell, err)
+		}
+		if !strings.Contains(out.String(), "kubectl-multi") {
+			t.Errorf("%s: expected the script to complete kubectl-multi, got:\n%s", shell, out.String())
+		}
+	}
+
+	if err := writeCompletion(rootCmd, "tcsh", &bytes.Buffer{}); err == nil {
+		t.Error("expected an error for an unsupported shell")
+	}
+}
+
+// TestCompleteList ensures only the last item of a comma-separated list is completed
+func TestCompleteList(t *testing.T) {
+	candidates := []string{"prod-us", "prod-eu", "staging", "prod-eu"}
+
+	tests := []struct {
+		toComplete string
+		want       []string
+	}{
+		{"", []string{"prod-eu", "prod-us", "staging"}},
+		{"prod", []string{"prod-eu", "prod-us"}},
+		{"staging,prod-e", []string{"staging,prod-eu"}},
+		{"prod-eu,", []string{"prod-eu,prod-us", "prod-eu,staging"}},
+		{"dev", nil},
+	}
+	for _, tt := range tests {
+		if got := completeList(candidates, tt.toComplete); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("completeList(%q): expected %v, got %v", tt.toComplete, tt.want, got)
+		}
+	}
+}
+
+// TestCompleteClusterNames ensures kubeconfig contexts and aliases are offered for --clusters
+func TestCompleteClusterNames(t *testing.T) {
+	dir := t.TempDir()
+	config := filepath.Join(dir, "config")
+	if err := os.WriteFile(config, []byte("contexts:\n- name: cluster1\n- name: cluster2\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	aliases := filepath.Join(dir, "aliases.yaml")
+	if err := os.WriteFile(aliases, []byte("cluster1: prod-eu\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	oldKubeconfig, oldAliases := kubeconfig, aliasesFile
+	defer func() { kubeconfig, aliasesFile = oldKubeconfig, oldAliases }()
+	kubeconfig, aliasesFile = config, aliases
+
+	got, directive := completeClusterNames(rootCmd, nil, "")
+	if want := []string{"cluster1", "cluster2", "prod-eu"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+	if directive&cobra.ShellCompDirectiveNoFileComp == 0 {
+		t.Error("expected file completion to be disabled")
+	}
+}
+
+// TestClusterFlagsHaveCompletion ensures the cluster selection flags complete cluster and group names
+func TestClusterFlagsHaveCompletion(t *testing.T) {
+	for _, name := range []string{"clusters", "exclude-clusters", "group"} {
+		if _, ok := rootCmd.GetFlagCompletionFunc(name); !ok {
+			t.Errorf("expected a completion function for --%s", name)
+		}
+	}
+}
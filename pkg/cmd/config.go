@@ -0,0 +1,222 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"kubectl-multi/pkg/util"
+
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/yaml"
+)
+
+func newConfigCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "config",
+		Short: "Manage the default values of the global flags",
+		Long: `Default values of the global flags are kept in ~/.config/kubectl-multi/config.yaml,
+mapping flag names to their value:
+
+  kubeconfig: /home/me/.kube/fleet
+  remote-context: its1
+  parallel: 8
+  clusters: [prod-eu, prod-us]
+
+Flags given on the command line always take precedence over the file.`,
+	}
+
+	cmd.AddCommand(newConfigViewCommand())
+	cmd.AddCommand(newConfigSetCommand())
+	cmd.AddCommand(newConfigUnsetCommand())
+	return cmd
+}
+
+func newConfigViewCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "view",
+		Short: "Print the default flag values of the config file",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			path, err := configFilePath()
+			if err != nil {
+				return err
+			}
+			config, err := loadConfig(path)
+			if err != nil {
+				return err
+			}
+			if len(config) == 0 {
+				fmt.Printf("No defaults set in %s\n", path)
+				return nil
+			}
+			data, err := yaml.Marshal(config)
+			if err != nil {
+				return err
+			}
+			_, err = util.GetOutputStream().Write(data)
+			return err
+		},
+	}
+}
+
+func newConfigSetCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "set FLAG VALUE",
+		Short: "Set the default value of a global flag",
+		Example: `# Run commands on 8 clusters at a time by default
+kubectl multi config set parallel 8
+
+# Use a dedicated kubeconfig and ITS by default
+kubectl multi config set kubeconfig ~/.kube/fleet
+kubectl multi config set remote-context its1`,
+		Args: cobra.ExactArgs(2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return updateConfig(func(config map[string]string) error {
+				// Setting the flag checks that it exists and that the value parses
+				if err := setGlobalFlag(rootCmd, args[0], args[1]); err != nil {
+					return err
+				}
+				config[args[0]] = args[1]
+				return nil
+			})
+		},
+	}
+}
+
+func newConfigUnsetCommand() *cobra.Command {
+	return &cobra.Command{
+		Use:   "unset FLAG",
+		Short: "Remove the default value of a global flag",
+		Args:  cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return updateConfig(func(config map[string]string) error {
+				if _, ok := config[args[0]]; !ok {
+					return fmt.Errorf("%s is not set in the config file", args[0])
+				}
+				delete(config, args[0])
+				return nil
+			})
+		},
+	}
+}
+
+// configFilePath returns the path of the config file holding the defaults of the global flags
+func configFilePath() (string, error) {
+	dir, err := util.ConfigDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "config.yaml"), nil
+}
+
+// loadConfig reads the config file as flag names mapped to their value, a missing file sets no defaults.
+// Lists are joined with commas, as they are given on the command line.
+func loadConfig(path string) (map[string]string, error) {
+	config := map[string]string{}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return config, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read config file: %v", err)
+	}
+	raw := map[string]interface{}{}
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("failed to parse config file %s: %v", path, err)
+	}
+	for name, value := range raw {
+		switch v := value.(type) {
+		case nil:
+			config[name] = ""
+		case []interface{}:
+			items := make([]string, 0, len(v))
+			for _, item := range v {
+				items = append(items, fmt.Sprint(item))
+			}
+			config[name] = strings.Join(items, ",")
+		default:
+			config[name] = fmt.Sprint(v)
+		}
+	}
+	return config, nil
+}
+
+// updateConfig loads the config file, applies update to it and writes it back
+func updateConfig(update func(config map[string]string) error) error {
+	path, err := configFilePath()
+	if err != nil {
+		return err
+	}
+	config, err := loadConfig(path)
+	if err != nil {
+		return err
+	}
+	if err := update(config); err != nil {
+		return err
+	}
+	data, err := yaml.Marshal(config)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("failed to create config directory: %v", err)
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write config file: %v", err)
+	}
+	return nil
+}
+
+// setGlobalFlag sets a global flag of root to a value from the config file
+func setGlobalFlag(root *cobra.Command, name, value string) error {
+	flags := root.PersistentFlags()
+	if flags.Lookup(name) == nil {
+		return fmt.Errorf("unknown global flag %q", name)
+	}
+	if err := flags.Set(name, value); err != nil {
+		return fmt.Errorf("invalid value %q for %s: %v", value, name, err)
+	}
+	return nil
+}
+
+// applyConfigDefaults sets every global flag of root that was not given on the command line to its value
+// in the config file, so explicit flags always win over the file
+func applyConfigDefaults(root *cobra.Command, config map[string]string) error {
+	names := make([]string, 0, len(config))
+	for name := range config {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for _, name := range names {
+		if f := root.PersistentFlags().Lookup(name); f != nil && f.Changed {
+			continue
+		}
+		if err := setGlobalFlag(root, name, config[name]); err != nil {
+			return fmt.Errorf("config file: %v", err)
+		}
+	}
+	return nil
+}
+
+// loadConfigDefaults applies the config file to the global flags before a command runs. The config
+// commands themselves run without it, so that a broken file can still be fixed with them.
+func loadConfigDefaults(cmd *cobra.Command, args []string) error {
+	for c := cmd; c != nil; c = c.Parent() {
+		if c.Name() == "config" && c.Parent() == rootCmd {
+			return nil
+		}
+	}
+	path, err := configFilePath()
+	if err != nil {
+		return nil
+	}
+	config, err := loadConfig(path)
+	if err != nil {
+		return err
+	}
+	return applyConfigDefaults(rootCmd, config)
+}
@@ -0,0 +1,67 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/cobra"
+)
+
+// newConfigTestRoot returns a root command with a few global flags, parsed from args
+func newConfigTestRoot(t *testing.T, args ...string) (*cobra.Command, *string, *int, *[]string) {
+	root := &cobra.Command{Use: "root"}
+	ctx := root.PersistentFlags().String("remote-context", "its1", "")
+	par := root.PersistentFlags().Int("parallel", 1, "")
+	clusters := root.PersistentFlags().StringSlice("clusters", nil, "")
+	if err := root.PersistentFlags().Parse(args); err != nil {
+		t.Fatal(err)
+	}
+	return root, ctx, par, clusters
+}
+
+// TestLoadConfig ensures scalars and lists are read as flag values
+func TestLoadConfig(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte("remote-context: its2\nparallel: 8\nclusters: [prod-eu, prod-us]\n"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	config, err := loadConfig(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if config["remote-context"] != "its2" || config["parallel"] != "8" || config["clusters"] != "prod-eu,prod-us" {
+		t.Errorf("unexpected config: %v", config)
+	}
+
+	missing, err := loadConfig(filepath.Join(t.TempDir(), "missing.yaml"))
+	if err != nil || len(missing) != 0 {
+		t.Errorf("expected a missing file to set no defaults, got %v, %v", missing, err)
+	}
+}
+
+// TestApplyConfigDefaults ensures the config file sets the flags not given on the command line
+func TestApplyConfigDefaults(t *testing.T) {
+	root, ctx, par, clusters := newConfigTestRoot(t, "--parallel", "2")
+	config := map[string]string{"remote-context": "its2", "parallel": "8", "clusters": "prod-eu,prod-us"}
+	if err := applyConfigDefaults(root, config); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if *ctx != "its2" || len(*clusters) != 2 || (*clusters)[1] != "prod-us" {
+		t.Errorf("expected the config file values, got %q %v", *ctx, *clusters)
+	}
+	if *par != 2 {
+		t.Errorf("expected the explicit --parallel to win over the config file, got %d", *par)
+	}
+}
+
+// TestApplyConfigDefaultsInvalid ensures unknown flags and invalid values in the config file are reported
+func TestApplyConfigDefaultsInvalid(t *testing.T) {
+	root, _, _, _ := newConfigTestRoot(t)
+	if err := applyConfigDefaults(root, map[string]string{"paralel": "8"}); err == nil {
+		t.Errorf("expected an unknown flag to be rejected")
+	}
+	if err := applyConfigDefaults(root, map[string]string{"parallel": "many"}); err == nil {
+		t.Errorf("expected an invalid value to be rejected")
+	}
+}
@@ -0,0 +1,98 @@
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+// assumeYesEnv is the environment variable that answers yes to every confirmation prompt, so CI
+// jobs and wrapper scripts can pre-confirm the commands they run
+const assumeYesEnv = "KUBECTL_MULTI_ASSUME_YES"
+
+// assumeYes tells whether a command skips its confirmation prompt. An explicit -y/--yes, including
+// --yes=false, takes precedence over KUBECTL_MULTI_ASSUME_YES, which applies when the flag is not given.
+func assumeYes(cmd *cobra.Command, yes bool) bool {
+	return resolveAssumeYes(yes, cmd.Flags().Changed("yes"), os.Getenv(assumeYesEnv))
+}
+
+// dryRunSkipsConfirmation tells whether a command run with the given --dry-run value skips its
+// prompt: with --skip-confirmation-for-dry-run, --dry-run=client|server never prompts since it
+// changes nothing
+func dryRunSkipsConfirmation(dryRun string) bool {
+	return skipConfirmationForDryRun && (dryRun == "client" || dryRun == "server")
+}
+
+// kubectlArgsDryRun returns the --dry-run value among the kubectl args a command passes through, or ""
+// when it is not a dry run. A bare --dry-run is kubectl's client.
+func kubectlArgsDryRun(args []string) string {
+	dryRun := ""
+	for i, arg := range args {
+		switch {
+		case arg == "--":
+			return dryRun
+		case strings.HasPrefix(arg, "--dry-run="):
+			dryRun = strings.TrimPrefix(arg, "--dry-run=")
+		case arg == "--dry-run":
+			dryRun = "client"
+			if i+1 < len(args) && (args[i+1] == "client" || args[i+1] == "server" || args[i+1] == "none") {
+				dryRun = args[i+1]
+			}
+		}
+	}
+	return dryRun
+}
+
+// resolveAssumeYes applies the precedence of assumeYes to the flag value, whether it was given,
+// and the value of the environment variable. Values other than true or false are ignored with a warning.
+func resolveAssumeYes(yes, flagGiven bool, env string) bool {
+	if flagGiven || env == "" {
+		return yes
+	}
+	value, err := strconv.ParseBool(env)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: ignoring %s=%q, it must be true or false\n", assumeYesEnv, env)
+		return yes
+	}
+	return value
+}
+
+// confirmAction asks the user to type 'yes' before a destructive operation, unless assumeYes is set or
+// the command is a dry run that skips its prompt, which is then noted on stderr
+func confirmAction(prompt string, assumeYes bool, dryRun string) (bool, error) {
+	if assumeYes {
+		return true, nil
+	}
+	if dryRunSkipsConfirmation(dryRun) {
+		fmt.Fprintf(os.Stderr, "Not asking for confirmation: nothing changes with --dry-run=%s\n", dryRun)
+		return true, nil
+	}
+
+	fmt.Println(prompt)
+	fmt.Println("Type 'yes' to confirm, or anything else to cancel.")
+	reader := bufio.NewReader(os.Stdin)
+	response, err := reader.ReadString('\n')
+	if err != nil {
+		return false, fmt.Errorf("failed to read confirmation: %v", err)
+	}
+
+	return strings.TrimSpace(strings.ToLower(response)) == "yes", nil
+}
+
+// needsConfirmation reports whether a deletion must be confirmed with --confirm-threshold. A negative
+// threshold always asks, and so does a count that failed in any cluster since the total is unknown.
+func needsConfirmation(counts []clusterCount, threshold int) bool {
+	if threshold < 0 {
+		return true
+	}
+	for _, c := range counts {
+		if c.Err != nil {
+			return true
+		}
+	}
+	return totalCount(counts) > threshold
+}
@@ -0,0 +1,94 @@
+package cmd
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+// TestNeedsConfirmation ensures the prompt is only skipped when the total is known and within the threshold
+func TestNeedsConfirmation(t *testing.T) {
+	counts := []clusterCount{{Context: "wds1", Count: 3}, {Context: "wds2", Count: 2}}
+	failed := []clusterCount{{Context: "wds1", Count: 1}, {Context: "wds2", Err: errors.New("forbidden")}}
+
+	tests := []struct {
+		name      string
+		counts    []clusterCount
+		threshold int
+		want      bool
+	}{
+		{"unset threshold always asks", counts, -1, true},
+		{"total within threshold", counts, 5, false},
+		{"total above threshold", counts, 4, true},
+		{"zero threshold with nothing to delete", []clusterCount{{Context: "wds1"}}, 0, false},
+		{"failed count always asks", failed, 10, true},
+	}
+	for _, tt := range tests {
+		if got := needsConfirmation(tt.counts, tt.threshold); got != tt.want {
+			t.Errorf("%s: expected %v, got %v", tt.name, tt.want, got)
+		}
+	}
+}
+
+// TestResolveAssumeYes ensures an explicit -y/--yes takes precedence over KUBECTL_MULTI_ASSUME_YES
+func TestResolveAssumeYes(t *testing.T) {
+	tests := []struct {
+		name      string
+		yes       bool
+		flagGiven bool
+		env       string
+		want      bool
+	}{
+		{"nothing set prompts", false, false, "", false},
+		{"flag given", true, true, "", true},
+		{"env var without flag", false, false, "true", true},
+		{"env var set to false", false, false, "0", false},
+		{"explicit --yes=false overrides env var", false, true, "true", false},
+		{"invalid env var is ignored", false, false, "sure", false},
+	}
+	for _, tt := range tests {
+		if got := resolveAssumeYes(tt.yes, tt.flagGiven, tt.env); got != tt.want {
+			t.Errorf("%s: expected %v, got %v", tt.name, tt.want, got)
+		}
+	}
+}
+
+// TestConfirmActionDryRun ensures dry runs skip the prompt, from the command's --dry-run flag or from
+// the kubectl args it passes through, unless --skip-confirmation-for-dry-run=false
+func TestConfirmActionDryRun(t *testing.T) {
+	defer func(v bool) { skipConfirmationForDryRun = v }(skipConfirmationForDryRun)
+
+	tests := []struct {
+		name   string
+		dryRun string
+		skip   bool
+		want   bool
+	}{
+		{"no dry run", "none", true, false},
+		{"client dry run", "client", true, true},
+		{"server dry run", "server", true, true},
+		{"policy disabled", "server", false, false},
+		{"kubectl args", kubectlArgsDryRun([]string{"delete", "pod", "web", "--dry-run=server"}), true, true},
+		{"bare kubectl flag", kubectlArgsDryRun([]string{"drain", "node1", "--dry-run"}), true, true},
+		{"kubectl args without dry run", kubectlArgsDryRun([]string{"drain", "node1"}), true, false},
+		{"kubectl args with none", kubectlArgsDryRun([]string{"delete", "pod", "web", "--dry-run", "none"}), true, false},
+	}
+	for _, tt := range tests {
+		skipConfirmationForDryRun = tt.skip
+		if got := dryRunSkipsConfirmation(tt.dryRun); got != tt.want {
+			t.Errorf("%s: expected %v, got %v", tt.name, tt.want, got)
+		}
+	}
+
+	// The skipped prompt is only noted when it would have been shown, on stderr
+	skipConfirmationForDryRun = true
+	var ok bool
+	stdout, stderr := captureOutput(t, func() { ok, _ = confirmAction("Delete?", false, "server") })
+	if !ok || stdout != "" || !strings.Contains(stderr, "Not asking for confirmation: nothing changes with --dry-run=server") {
+		t.Errorf("expected the dry run to be confirmed with a note on stderr, got %v, stdout %q, stderr %q", ok, stdout, stderr)
+	}
+	stdout, stderr = captureOutput(t, func() { ok, _ = confirmAction("Delete?", true, "server") })
+	if !ok || stdout != "" || stderr != "" {
+		t.Errorf("expected -y to confirm silently, got %v, stdout %q, stderr %q", ok, stdout, stderr)
+	}
+}
@@ -0,0 +1,91 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"text/tabwriter"
+
+	"kubectl-multi/pkg/cluster"
+	"kubectl-multi/pkg/util"
+)
+
+// clusterCount holds the number of matching resources found in a single cluster
+type clusterCount struct {
+	Context string
+	Count   int
+	Err     error
+}
+
+// countAcrossClusters runs a `kubectl get -o name` query built by buildArgs against every
+// cluster and counts the returned names. The queries are bounded by --timeout and --timeout-total.
+func countAcrossClusters(clusters []cluster.ClusterInfo, kubeconfig string, buildArgs func(context string) []string) []clusterCount {
+	ctx, cancel := totalContext()
+	defer cancel()
+
+	var counts []clusterCount
+	for _, c := range clusters {
+		output, err := queryCluster(ctx, c, buildArgs(c.Context), kubeconfig)
+		if err != nil {
+			counts = append(counts, clusterCount{Context: c.Context, Err: err})
+			continue
+		}
+		counts = append(counts, clusterCount{Context: c.Context, Count: countNames(output)})
+	}
+	return counts
+}
+
+// countNames counts the non-empty lines of `kubectl get -o name` output
+func countNames(output string) int {
+	n := 0
+	for _, line := range strings.Split(output, "\n") {
+		if strings.TrimSpace(line) != "" {
+			n++
+		}
+	}
+	return n
+}
+
+// totalCount sums the counts of all clusters that were counted successfully
+func totalCount(counts []clusterCount) int {
+	total := 0
+	for _, c := range counts {
+		if c.Err == nil {
+			total += c.Count
+		}
+	}
+	return total
+}
+
+// formatCountSummary renders counts as a single line, e.g. "3 in wds1, 0 in wds2; total 3"
+func formatCountSummary(counts []clusterCount) string {
+	var parts []string
+	for _, c := range counts {
+		if c.Err != nil {
+			parts = append(parts, fmt.Sprintf("? in %s", c.Context))
+			continue
+		}
+		parts = append(parts, fmt.Sprintf("%d in %s", c.Count, c.Context))
+	}
+	return fmt.Sprintf("%s; total %d", strings.Join(parts, ", "), totalCount(counts))
+}
+
+// printCountTable prints the per-cluster counts followed by the fleet total
+func printCountTable(counts []clusterCount) {
+	tw := tabwriter.NewWriter(util.GetOutputStream(), 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "CLUSTER\tCOUNT\n")
+	for _, c := range counts {
+		if c.Err != nil {
+			fmt.Fprintf(tw, "%s\t%s\n", c.Context, "<error>")
+			continue
+		}
+		fmt.Fprintf(tw, "%s\t%d\n", c.Context, c.Count)
+	}
+	fmt.Fprintf(tw, "TOTAL\t%d\n", totalCount(counts))
+	tw.Flush()
+
+	for _, c := range counts {
+		if c.Err != nil {
+			fmt.Printf("Warning: failed to count resources in cluster %s: %v\n", c.Context, c.Err)
+		}
+	}
+}
@@ -0,0 +1,149 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+
+	"kubectl-multi/pkg/cluster"
+	"kubectl-multi/pkg/util"
+
+	"github.com/spf13/cobra"
+)
+
+func newCreateCommand() *cobra.Command {
+	var filename string
+	var recursive bool
+	var dryRun string
+	var validate bool
+	var ignoreExists bool
+
+	cmd := &cobra.Command{
+		Use:   "create -f FILENAME",
+		Short: "Create a resource from a file or from stdin across managed clusters",
+		Long: `Create a resource from a file across all managed clusters.
+The manifests are first validated against every cluster with a server-side dry run,
+and nothing is created unless all clusters accept them.`,
+		Example: `# Create a deployment in all managed clusters
+kubectl multi create -f deployment.yaml
+
+# Create without validating against each cluster first
+kubectl multi create -f deployment.yaml --validate=false
+
+# Ensure a namespace exists in every cluster, succeeding where it already does
+kubectl multi create -f namespace.yaml --ignore-exists`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if filename == "" {
+				return fmt.Errorf("must specify -f, --filename")
+			}
+			kubeconfig, remoteCtx, _, namespace, allNamespaces := GetGlobalFlags()
+			return handleCreateCommand(filename, recursive, dryRun, validate, ignoreExists, kubeconfig, remoteCtx, namespace, allNamespaces)
+		},
+	}
+
+	cmd.Flags().StringVarP(&filename, "filename", "f", "", "filename, directory, or URL to files to use to create the resource")
+	cmd.Flags().BoolVarP(&recursive, "recursive", "R", false, "process the directory used in -f, --filename recursively")
+	cmd.Flags().StringVar(&dryRun, "dry-run", "none", "must be \"none\", \"server\", or \"client\"")
+	cmd.Flags().BoolVar(&validate, "validate", true, "validate the manifests against every cluster with a server-side dry run before creating")
+	cmd.Flags().BoolVar(&ignoreExists, "ignore-exists", false, "treat resources that already exist in a cluster as success instead of failing the cluster with AlreadyExists")
+
+	return cmd
+}
+
+func handleCreateCommand(filename string, recursive bool, dryRun string, validate, ignoreExists bool, kubeconfig, remoteCtx, namespace string, allNamespaces bool) error {
+	clusters, unreachable, err := discoverClusters(kubeconfig, remoteCtx)
+	if err != nil {
+		return fmt.Errorf("failed to discover clusters: %v", err)
+	}
+	if len(clusters) == 0 {
+		return fmt.Errorf("no clusters discovered")
+	}
+
+	buildArgs := func(c cluster.ClusterInfo) []string {
+		args := []string{"create", "-f", filename, "--context", c.Context}
+		if recursive {
+			args = append(args, "-R")
+		}
+		if namespace != "" {
+			args = append(args, "-n", namespace)
+		}
+		return args
+	}
+
+	opts := fanOutOptions{Namespace: namespaceOption(namespace, allNamespaces), Unreachable: unreachable}
+
+	// A dry run already validates, so only validate before a real create
+	if validate && (dryRun == "none" || dryRun == "") {
+		var accept func(error) bool
+		if ignoreExists {
+			accept = isAlreadyExists
+		}
+		if err := validateOnClusters(clusters, kubeconfig, remoteCtx, opts, buildArgs, accept); err != nil {
+			return err
+		}
+	}
+
+	op := kubectlOp(kubeconfig, func(c cluster.ClusterInfo) []string {
+		args := buildArgs(c)
+		if dryRun != "none" && dryRun != "" {
+			args = append(args, "--dry-run="+dryRun)
+		}
+		return args
+	})
+	existing := &existingClusters{contexts: make(map[string]bool)}
+	if ignoreExists {
+		op = withIgnoreExists(op, existing)
+	}
+	results, err := fanOut(clusters, kubeconfig, remoteCtx, opts, op)
+	if names := existing.names(results); len(names) > 0 {
+		fmt.Printf("Already existing in %d clusters, left unchanged: %s\n", len(names), strings.Join(names, ", "))
+	}
+	// Admission webhooks may warn or reject differently per cluster, so report them side by side
+	if isServerDryRun(dryRun) {
+		fmt.Println()
+		printDryRunSummary(util.GetOutputStream(), newDryRunReports(results))
+		printDryRunMatrix(util.GetOutputStream(), results)
+	}
+	return err
+}
+
+// withIgnoreExists wraps a create op so that a cluster where kubectl only failed because resources already
+// exist succeeds, the other resources having been created. Such clusters are recorded in existing.
+func withIgnoreExists(op clusterOp, existing *existingClusters) clusterOp {
+	return func(ctx context.Context, c cluster.ClusterInfo, out io.Writer) error {
+		err := op(ctx, c, out)
+		if err != nil && isAlreadyExists(err) {
+			fmt.Fprintln(out, "Already exists, left unchanged")
+			existing.add(c.Context)
+			return nil
+		}
+		return err
+	}
+}
+
+// existingClusters records the clusters where create found resources that already exist, from concurrent ops
+type existingClusters struct {
+	mu       sync.Mutex
+	contexts map[string]bool
+}
+
+func (e *existingClusters) add(context string) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.contexts[context] = true
+}
+
+// names returns the display names of the recorded clusters, in the order of the results
+func (e *existingClusters) names(results []clusterResult) []string {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	var names []string
+	for _, r := range results {
+		if e.contexts[r.Context] {
+			names = append(names, r.displayName())
+		}
+	}
+	return names
+}
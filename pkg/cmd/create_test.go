@@ -0,0 +1,56 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"kubectl-multi/pkg/cluster"
+)
+
+// fakeCreateKubectl is a kubectl where the namespace already exists in cluster2, and creating fails
+// outright in cluster3
+const fakeCreateKubectl = `#!/bin/sh
+case "$*" in
+*"--context cluster2"*) echo 'Error from server (AlreadyExists): error when creating "ns.yaml": namespaces "shop" already exists' >&2; exit 1;;
+*"--context cluster3"*) echo 'Error from server (Forbidden): namespaces is forbidden' >&2; exit 1;;
+esac
+echo 'namespace/shop created'
+`
+
+// TestCreateIgnoreExists ensures clusters that already have the resources succeed and are recorded,
+// while other failures still fail
+func TestCreateIgnoreExists(t *testing.T) {
+	installFakeKubectl(t, fakeCreateKubectl)
+
+	existing := &existingClusters{contexts: make(map[string]bool)}
+	op := withIgnoreExists(kubectlOp("", func(c cluster.ClusterInfo) []string {
+		return []string{"create", "-f", "ns.yaml", "--context", c.Context}
+	}), existing)
+
+	var results []clusterResult
+	for _, c := range []cluster.ClusterInfo{{Context: "cluster1"}, {Context: "cluster2", DisplayName: "eu"}, {Context: "cluster3"}} {
+		var out bytes.Buffer
+		result := runOnCluster(context.Background(), c, fanOutOptions{}, op, &out)
+		results = append(results, result)
+		switch c.Context {
+		case "cluster1":
+			if result.Err != nil || !strings.Contains(out.String(), "created") {
+				t.Errorf("expected the namespace to be created in cluster1, got %v: %q", result.Err, out.String())
+			}
+		case "cluster2":
+			if result.Err != nil || !strings.Contains(out.String(), "Already exists, left unchanged") {
+				t.Errorf("expected cluster2 to succeed as already existing, got %v: %q", result.Err, out.String())
+			}
+		case "cluster3":
+			if result.Err == nil || classifyError(result.Err) != categoryForbidden {
+				t.Errorf("expected cluster3 to fail with Forbidden, got %v", result.Err)
+			}
+		}
+	}
+
+	if names := existing.names(results); len(names) != 1 || names[0] != "eu" {
+		t.Errorf("expected only eu to be reported as already existing, got %v", names)
+	}
+}
@@ -1,16 +1,18 @@
 package cmd
 
 import (
+	"context"
 	"fmt"
-	"strings"
-	"bufio"
+	"io"
 	"os"
+	"strings"
+	"time"
 
 	"kubectl-multi/pkg/cluster"
 	"kubectl-multi/pkg/util"
 
 	"github.com/spf13/cobra"
-	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
 )
 
 // Custom help function for delete command
@@ -37,11 +39,53 @@ kubectl multi delete pods -l app=nginx
 # Delete resources from a file across all clusters
 kubectl multi delete -f deployment.yaml
 
+# List the manifest files found under a directory before deleting their resources
+kubectl multi delete -f manifests/ -R --verbose
+
+# Delete the resources of several files across all clusters
+kubectl multi delete -f deployment.yaml -f service.yaml
+
 # Delete all pods in all clusters
 kubectl multi delete pods --all
 
 # Delete with force flag across all clusters
-kubectl multi delete pod nginx --force`
+kubectl multi delete pod nginx --force
+
+# Replace a deployment's controller without disrupting its pods in any cluster
+kubectl multi delete deployment web --orphan
+
+# Wait in every cluster until the dependents of a deployment are gone before it is removed
+kubectl multi delete deployment web --cascade foreground
+
+# Preview how many resources will be deleted in each cluster before confirming
+kubectl multi delete deployment nginx --count
+
+# Only ask for confirmation when more than 10 pods would be deleted in total
+kubectl multi delete pods -l app=test --confirm-threshold 10
+
+# Delete without being prompted, e.g. in CI (or set KUBECTL_MULTI_ASSUME_YES=true)
+kubectl multi delete pods -l app=e2e -n test -y
+
+# Choose which of the pods matching a label to delete in each cluster
+kubectl multi delete pods -l app=test -i
+
+# Delete pods older than 24 hours in the test namespace across all clusters
+kubectl multi delete pods --older-than 24h -n test
+
+# Tear down the deployments, services and configmaps of an app in all clusters
+kubectl multi delete --types=deploy,svc,cm -l app=nginx
+
+# Wait for finalizers in all clusters concurrently, giving up on a cluster after 2 minutes
+kubectl multi delete namespace team-a --parallel 5 --timeout 2m
+
+# Keep a re-appliable copy of every deleted configmap under ./backup/<context>/<namespace>/
+kubectl multi delete cm -l app=legacy --backup-dir ./backup
+
+# Delete exactly the failed jobs found by get, each in its own cluster
+kubectl multi get jobs -A -o name | grep failed- | kubectl multi delete --from-names - -y
+
+# Delete the configmaps named in a file from every cluster, ignoring those a cluster does not have
+kubectl multi delete configmap --names-file old-configmaps.txt -n shop --ignore-not-found`
 
 	// Multi-cluster usage
 	multiClusterUsage := `kubectl multi delete [TYPE[.VERSION][.GROUP] [NAME | -l label] | TYPE[.VERSION][.GROUP]/NAME ...] [flags]`
@@ -51,24 +95,192 @@ kubectl multi delete pod nginx --force`
 	fmt.Fprintln(cmd.OutOrStdout(), combinedHelp)
 }
 
+// deleteOptions holds the flags of the delete command
+type deleteOptions struct {
+	Filenames    []string
+	Kustomize    string
+	BuildOnce    bool
+	Recursive    bool
+	Verbose      bool
+	DryRun       string
+	Count        bool
+	Interactive  bool
+	OlderThan    time.Duration
+	SinceLastRun bool
+	Selector     string
+	Types        []string
+	Wait         bool
+	NoWait       bool
+	BackupDir    string
+	CheckRBAC    bool
+	FromNames    string
+	NamesFile    string
+
+	IgnoreNotFound    bool
+	Orphan            bool
+	Force             bool
+	Cascade           string
+	PropagationPolicy string
+	ProtectNamespaces []string
+	ForceProtected    bool
+	// ConfirmThreshold is the number of resources above which confirmation is asked, negative to always ask
+	ConfirmThreshold int
+	// Yes skips the confirmation; RunE also sets it from $KUBECTL_MULTI_ASSUME_YES and the dry-run policy
+	Yes bool
+}
+
+// setFlags returns those of the given flags that are set, spelled as on the command line. -A is the
+// global --all-namespaces flag, which is not part of the options.
+func (o deleteOptions) setFlags(allNamespaces bool, flags ...string) []string {
+	set := map[string]bool{
+		"-f":               len(o.Filenames) > 0,
+		"-k":               o.Kustomize != "",
+		"-l":               o.Selector != "",
+		"-A":               allNamespaces,
+		"-y":               o.Yes,
+		"--types":          len(o.Types) > 0,
+		"--older-than":     o.OlderThan != 0,
+		"--since-last-run": o.SinceLastRun,
+		"--backup-dir":     o.BackupDir != "",
+		"--check-rbac":     o.CheckRBAC,
+		"--from-names":     o.FromNames != "",
+		"--names-file":     o.NamesFile != "",
+	}
+	var names []string
+	for _, flag := range flags {
+		if set[flag] {
+			names = append(names, flag)
+		}
+	}
+	return names
+}
+
+// age returns the age filter of --older-than and --since-last-run
+func (o deleteOptions) age() ageFilter {
+	return ageFilter{OlderThan: o.OlderThan, SinceLastRun: o.SinceLastRun}
+}
+
+// selectArgs returns the arguments of the kubectl get listing the resources the delete selects in a
+// cluster, in the same namespaces as the delete itself, e.g. to count or back them up
+func (o deleteOptions) selectArgs(resourceType, resourceName, namespace string, allNamespaces bool, outputFormat, context string) []string {
+	var args []string
+	manifest := len(o.Filenames) > 0 || o.Kustomize != ""
+	if manifest {
+		args = append([]string{"get"}, manifestArgs(o.Filenames, o.Kustomize)...)
+		args = append(args, "-o", outputFormat, "--ignore-not-found", "--context", context)
+		if o.Recursive {
+			args = append(args, "-R")
+		}
+	} else {
+		args = []string{"get", resourceType}
+		if resourceName != "" {
+			args = append(args, resourceName)
+		}
+		args = append(args, "-o", outputFormat, "--ignore-not-found", "--context", context)
+		if o.Selector != "" {
+			args = append(args, "-l", o.Selector)
+		}
+	}
+	return append(args, deleteNamespaceArgs(manifest, namespace, allNamespaces)...)
+}
+
+// namesSourceConflicts are the flags selecting resources that cannot be combined with --from-names or --names-file
+var namesSourceConflicts = []string{"-f", "-k", "--types", "-l", "-A", "--older-than", "--since-last-run", "--backup-dir", "--check-rbac"}
+
 func newDeleteCommand() *cobra.Command {
-	var filename string
-	var recursive bool
-	var dryRun string
+	var opts deleteOptions
 
 	cmd := &cobra.Command{
 		Use:   "delete [TYPE[.VERSION][.GROUP] [NAME | -l label] | TYPE[.VERSION][.GROUP]/NAME ...]",
 		Short: "Delete resources across all managed clusters",
 		RunE: func(cmd *cobra.Command, args []string) error {
 
+			if opts.NoWait {
+				opts.Wait = false
+			}
 			kubeconfig, remoteCtx, _, namespace, allNamespaces := GetGlobalFlags()
-			return handleDeleteCommand(args, filename, recursive, dryRun, kubeconfig, remoteCtx, namespace, allNamespaces)
+			if opts.ForceProtected {
+				opts.ProtectNamespaces = nil
+			}
+			cascade, warning, err := resolveCascade(opts.Cascade, opts.PropagationPolicy, opts.Orphan)
+			if err != nil {
+				return err
+			}
+			opts.Cascade = cascade
+			if warning != "" {
+				fmt.Println(warning)
+			}
+			if cascade == "orphan" && opts.Force {
+				return fmt.Errorf("--orphan cannot be combined with --force, which removes the resources immediately")
+			}
+			if cascade == "orphan" {
+				fmt.Println("Note: with --orphan, the dependents of the deleted resources (e.g. the pods of a Deployment) keep running in every cluster")
+			}
+			if opts.Interactive {
+				if set := opts.setFlags(allNamespaces, "--from-names", "--names-file", "--types", "--older-than", "--since-last-run", "--backup-dir", "-y"); len(set) > 0 {
+					return fmt.Errorf("--interactive cannot be combined with %s", strings.Join(set, ", "))
+				}
+				if !util.IsTerminal(os.Stdin) {
+					return fmt.Errorf("--interactive requires a terminal to choose the resources to delete")
+				}
+			}
+			if opts.FromNames != "" {
+				set := opts.setFlags(allNamespaces, append(namesSourceConflicts, "--names-file")...)
+				if len(args) != 0 {
+					set = append([]string{"a resource type"}, set...)
+				}
+				if len(set) > 0 {
+					return fmt.Errorf("--from-names cannot be combined with %s", strings.Join(set, ", "))
+				}
+				return handleDeleteFromNames(opts.FromNames, opts.DryRun, assumeYes(cmd, opts.Yes), opts.Wait, opts.IgnoreNotFound, cascade, opts.Force, opts.ProtectNamespaces, kubeconfig, remoteCtx)
+			}
+			if opts.NamesFile != "" {
+				if len(args) != 1 {
+					return fmt.Errorf("--names-file requires a single resource type")
+				}
+				if set := opts.setFlags(allNamespaces, namesSourceConflicts...); len(set) > 0 {
+					return fmt.Errorf("--names-file cannot be combined with %s", strings.Join(set, ", "))
+				}
+				return handleDeleteNamesFile(args[0], opts.NamesFile, opts.DryRun, assumeYes(cmd, opts.Yes), opts.Wait, opts.IgnoreNotFound, cascade, opts.Force, opts.ProtectNamespaces, kubeconfig, remoteCtx, namespace)
+			}
+			filenames, kustomize, cleanup, err := resolveManifestSource(opts.Filenames, opts.Kustomize, opts.BuildOnce, kubeconfig)
+			if err != nil {
+				return err
+			}
+			defer cleanup()
+			opts.Filenames, opts.Kustomize = filenames, kustomize
+			opts.Yes = assumeYes(cmd, opts.Yes)
+			return handleDeleteCommand(args, opts, kubeconfig, remoteCtx, namespace, allNamespaces)
 		},
 	}
 
-	cmd.Flags().StringVarP(&filename, "filename", "f", "", "filename, directory, or URL to files to use to delete the resource")
-	cmd.Flags().BoolVarP(&recursive, "recursive", "R", false, "process the directory used in -f, --filename recursively")
-	cmd.Flags().StringVar(&dryRun, "dry-run", "none", "must be \"none\", \"server\", or \"client\"")
+	cmd.Flags().StringArrayVarP(&opts.Filenames, "filename", "f", nil, "filename, directory, or URL to files to use to delete the resource, can be repeated")
+	cmd.Flags().StringVarP(&opts.Kustomize, "kustomize", "k", "", "process a kustomization directory")
+	cmd.Flags().BoolVar(&opts.BuildOnce, "build-once", true, "build the kustomization once and delete the result from every cluster instead of building it per cluster")
+	cmd.Flags().BoolVarP(&opts.Recursive, "recursive", "R", false, "process the directory used in -f, --filename recursively")
+	cmd.Flags().BoolVar(&opts.Verbose, "verbose", false, "list the manifest files found in the directories of -f before deleting, e.g. to check what -R matched")
+	cmd.Flags().StringVar(&opts.DryRun, "dry-run", "none", "must be \"none\", \"server\", or \"client\"")
+	cmd.Flags().BoolVar(&opts.Count, "count", false, "show the number of matching resources per cluster in the confirmation prompt")
+	cmd.Flags().BoolVarP(&opts.Interactive, "interactive", "i", false, "list the matching resources of every cluster and choose which of them to delete, instead of confirming them all (requires a terminal)")
+	cmd.Flags().BoolVarP(&opts.Yes, "yes", "y", false, "delete without prompting for confirmation (default from $"+assumeYesEnv+")")
+	cmd.Flags().IntVar(&opts.ConfirmThreshold, "confirm-threshold", -1, "only ask for confirmation when more than this many resources would be deleted across all clusters, negative to always ask")
+	cmd.Flags().DurationVar(&opts.OlderThan, "older-than", 0, "only delete resources whose creationTimestamp is older than this duration (e.g. 24h, 30m)")
+	cmd.Flags().BoolVar(&opts.SinceLastRun, "since-last-run", false, "only delete resources created since the last successful run of the same delete command and selector")
+	cmd.Flags().StringVarP(&opts.Selector, "selector", "l", "", "selector (label query) to filter on")
+	cmd.Flags().StringSliceVar(&opts.Types, "types", nil, "comma-separated resource types to delete in one pass, requires -l (e.g. deploy,svc,cm)")
+	cmd.Flags().BoolVar(&opts.Wait, "wait", true, "wait for resources to be gone before returning; combine with --timeout to bound the wait per cluster")
+	cmd.Flags().BoolVar(&opts.NoWait, "no-wait", false, "return as soon as the resources are marked for deletion (same as --wait=false)")
+	cmd.Flags().BoolVar(&opts.IgnoreNotFound, "ignore-not-found", false, "treat \"resource not found\" as a successful delete on every cluster where it is absent")
+	cmd.Flags().BoolVar(&opts.Orphan, "orphan", false, "delete the resources but leave their dependents running (--cascade=orphan), e.g. to replace a controller without disrupting its pods")
+	cmd.Flags().StringVar(&opts.Cascade, "cascade", "", "how the dependents of the deleted resources are handled in every cluster: background, foreground or orphan (default background)")
+	cmd.Flags().StringVar(&opts.PropagationPolicy, "propagation-policy", "", "older name of --cascade taking the API values Orphan, Background or Foreground; --cascade wins when both are set")
+	cmd.Flags().BoolVar(&opts.Force, "force", false, "immediately remove the resources from the API, bypassing graceful deletion")
+	cmd.Flags().StringVar(&opts.FromNames, "from-names", "", "delete exactly the resources listed one per line as context/namespace/kind/name (the output of get -o name) in this file, - for stdin")
+	cmd.Flags().StringVar(&opts.NamesFile, "names-file", "", "delete the resources of the given type named one per line in this file from every cluster, - for stdin")
+	cmd.Flags().BoolVar(&opts.CheckRBAC, "check-rbac", false, "ask every cluster with kubectl auth can-i whether the resources may be deleted and skip the clusters where they may not")
+	cmd.Flags().StringSliceVar(&opts.ProtectNamespaces, "protect-namespaces", defaultProtectedNamespaces, "namespaces delete refuses to operate in on any cluster")
+	cmd.Flags().BoolVar(&opts.ForceProtected, "force-protected", false, "allow deleting in the namespaces listed in --protect-namespaces")
+	cmd.Flags().StringVar(&opts.BackupDir, "backup-dir", "", "export the YAML of every resource to <dir>/<context>/<namespace>/<kind>-<name>.yaml before deleting it, clusters whose backup fails are not deleted from")
 
 	// Set custom help function
 	cmd.SetHelpFunc(deleteHelpFunc)
@@ -76,18 +288,40 @@ func newDeleteCommand() *cobra.Command {
 	return cmd
 }
 
-func handleDeleteCommand(args []string, filename string, recursive bool, dryRun, kubeconfig, remoteCtx, namespace string, allNamespaces bool) error {
+func handleDeleteCommand(args []string, opts deleteOptions, kubeconfig, remoteCtx, namespace string, allNamespaces bool) error {
+	age := opts.age()
 
 	var isFileProvided bool
 	var resourceName string
 	var resourceType string
 
-	if len(args) != 0 && filename != "" {
+	if len(args) != 0 && (len(opts.Filenames) > 0 || opts.Kustomize != "") {
 		return fmt.Errorf("provide either filename or resource type at a time")
 	}
+	if age.OlderThan < 0 {
+		return fmt.Errorf("--older-than must be a positive duration")
+	}
+	if age.active() && (len(opts.Filenames) > 0 || opts.Kustomize != "") {
+		return fmt.Errorf("--older-than and --since-last-run cannot be used with -f or -k")
+	}
+	if len(opts.Types) > 0 {
+		if len(args) != 0 || len(opts.Filenames) > 0 || opts.Kustomize != "" {
+			return fmt.Errorf("--types cannot be combined with a resource type, -f or -k")
+		}
+		if opts.Selector == "" {
+			return fmt.Errorf("--types requires a -l/--selector")
+		}
+		if age.active() {
+			return fmt.Errorf("--older-than and --since-last-run cannot be used with --types")
+		}
+	}
 
-	if filename != "" {
+	if len(opts.Filenames) > 0 || opts.Kustomize != "" {
 		isFileProvided = true
+	} else if len(opts.Types) > 0 {
+		resourceType = strings.Join(opts.Types, ",")
+	} else if len(args) == 0 {
+		return fmt.Errorf("you must specify the type of resource to delete, -f, -k or --types")
 	} else {
 		isFileProvided = false // in this case reource type is provided.
 		resourceType = args[0]
@@ -97,7 +331,11 @@ func handleDeleteCommand(args []string, filename string, recursive bool, dryRun,
 		}
 	}
 
-	clusters, err := cluster.DiscoverClusters(kubeconfig, remoteCtx)
+	if err := checkProtectedNamespaces(resourceType, resourceName, namespace, allNamespaces, opts.ProtectNamespaces); err != nil {
+		return err
+	}
+
+	clusters, unreachable, err := discoverClusters(kubeconfig, remoteCtx)
 	if err != nil {
 		return fmt.Errorf("failed to discover clusters: %v", err)
 	}
@@ -105,131 +343,410 @@ func handleDeleteCommand(args []string, filename string, recursive bool, dryRun,
 		return fmt.Errorf("no clusters discovered")
 	}
 
-	fmt.Println("Are you sure you want to delete these resources ?")
-	fmt.Println("Type 'yes' to confirm, or anything else to cancel.")
-	reader := bufio.NewReader(os.Stdin)
-	response, err := reader.ReadString('\n')
+	targets, _ := fanOutTargets(clusters, "", itsContext(remoteCtx))
+	if err := checkProtectedContextNamespaces(targets, namespace, allNamespaces, opts.ProtectNamespaces); err != nil {
+		return err
+	}
 
-	if err != nil {
-		return fmt.Errorf("failed to read confirmation: %v", err)
+	var manifest []manifestResource
+	var manifestErr error
+	if isFileProvided {
+		// The files are the same for every cluster, so they are listed once
+		if opts.Verbose && opts.Kustomize == "" {
+			if err := printManifestFiles(util.GetOutputStream(), opts.Filenames, opts.Recursive); err != nil {
+				fmt.Printf("Warning: cannot list the manifest files: %v\n", err)
+			}
+		}
+		manifest, manifestErr = readManifestResources(opts.Filenames, opts.Kustomize, opts.Recursive, kubeconfig)
 	}
 
-	response = strings.TrimSpace(strings.ToLower(response))
-	if response != "yes" {
-		fmt.Println("Deletion cancelled...")
-		return nil
+	// Skip the clusters where the current identity may not delete the resources, before anything is listed
+	var skip map[string]string
+	if opts.CheckRBAC {
+		if manifestErr != nil {
+			return fmt.Errorf("--check-rbac: cannot read the resources to delete: %v", manifestErr)
+		}
+		skip = checkDeletePermissions(targets, deleteRBACResources(resourceType, resourceName, manifest), namespace, allNamespaces, kubeconfig)
+		allowed := targets[:0:0]
+		for _, c := range targets {
+			if _, ok := skip[c.Context]; !ok {
+				allowed = append(allowed, c)
+			}
+		}
+		targets = allowed
 	}
 
-	// Find current context from kubeconfig
-	currentContext := ""
-	{
-		loading := clientcmd.NewDefaultClientConfigLoadingRules()
-		if kubeconfig != "" {
-			loading.ExplicitPath = kubeconfig
+	// --since-last-run selects the resources created since the last successful run of this command
+	var state *runState
+	var statePath, stateKey string
+	runStart := time.Now()
+	if age.SinceLastRun {
+		if statePath, err = runStateFile(); err != nil {
+			return err
+		}
+		if state, err = loadRunState(statePath); err != nil {
+			return err
 		}
-		cfg := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loading, &clientcmd.ConfigOverrides{})
-		rawCfg, err := cfg.RawConfig()
-		if err == nil {
-			currentContext = rawCfg.CurrentContext
+		stateKey = runStateKey("delete "+resourceType+" "+resourceName, opts.Selector, namespace, allNamespaces)
+		age.Since = state.LastRun[stateKey]
+	}
+
+	// Select the resources matching the age filter in every target cluster, they are then deleted by name
+	var aged map[string][]agedResource
+	var agedErrs map[string]error
+	var counts []clusterCount
+	if age.active() {
+		aged = make(map[string][]agedResource)
+		agedErrs = make(map[string]error)
+		listCtx, cancel := totalContext()
+		defer cancel()
+		for _, c := range targets {
+			resources, err := listAgedResources(listCtx, c, resourceType, resourceName, opts.Selector, kubeconfig, namespace, allNamespaces, age)
+			if err != nil {
+				agedErrs[c.Context] = err
+				counts = append(counts, clusterCount{Context: c.Context, Err: err})
+				fmt.Printf("Warning: failed to list %s in cluster %s: %v\n", resourceType, c.Context, err)
+				continue
+			}
+			aged[c.Context] = resources
+			counts = append(counts, clusterCount{Context: c.Context, Count: len(resources)})
+		}
+		fmt.Printf("Will delete %s %s: %s\n", resourceType, age, formatCountSummary(counts))
+	}
+
+	// Show which resources of the manifest exist in each cluster, and will be deleted, before confirming
+	if isFileProvided {
+		if manifestErr != nil {
+			fmt.Printf("Warning: cannot preview the deletion: %v\n", manifestErr)
+		} else {
+			preview := previewManifestDelete(targets, manifest, opts.Filenames, opts.Kustomize, opts.Recursive, kubeconfig, namespace)
+			preview.print(util.GetOutputStream())
+			counts = preview.counts()
+			fmt.Printf("Will delete %s\n", formatCountSummary(counts))
 		}
 	}
 
-	// Identify ITS (control) cluster context
-	itsContext := remoteCtx
+	// getSelected builds the kubectl get arguments listing the resources the delete selects in a cluster
+	getSelected := func(outputFormat, context string) []string {
+		return opts.selectArgs(resourceType, resourceName, namespace, allNamespaces, outputFormat, context)
+	}
+
+	// The user chooses among the matching resources, which are then deleted by name
+	if opts.Interactive {
+		return deleteInteractively(targets, func(context string) []string {
+			return getSelected("json", context)
+		}, opts.DryRun, opts.Wait, opts.IgnoreNotFound, opts.Cascade, opts.Force, opts.ProtectNamespaces, kubeconfig, remoteCtx)
+	}
+
+	// Count the matching resources in every target cluster so the blast radius is visible before confirming
+	if (opts.Count || opts.ConfirmThreshold >= 0) && !age.active() && counts == nil {
+		counts = countAcrossClusters(targets, kubeconfig, func(context string) []string {
+			return getSelected("name", context)
+		})
+		fmt.Printf("Will delete %s\n", formatCountSummary(counts))
+	}
 
-	// Build maps for quick lookup
-	contextToCluster := make(map[string]cluster.ClusterInfo)
-	for _, c := range clusters {
-		contextToCluster[c.Context] = c
+	skipConfirm := !needsConfirmation(counts, opts.ConfirmThreshold)
+	if skipConfirm {
+		fmt.Printf("Not asking for confirmation: %d resources is within --confirm-threshold %d\n", totalCount(counts), opts.ConfirmThreshold)
+	}
+	ok, err := confirmAction("Are you sure you want to delete these resources ?", skipConfirm || opts.Yes, opts.DryRun)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		fmt.Println("Deletion cancelled...")
+		return nil
 	}
 
-	// 1. Run for current context (if present)
-	if cinfo, ok := contextToCluster[currentContext]; ok {
-		var args []string
-		if isFileProvided {
-			args = []string{"delete", "-f", filename, "--context", cinfo.Context}
+	// Export the resources before deleting them, a cluster whose backup failed is left untouched
+	backupErrs := map[string]error{}
+	if opts.BackupDir != "" {
+		if opts.DryRun != "none" && opts.DryRun != "" {
+			fmt.Println("Not backing up resources with --dry-run")
 		} else {
-			args = []string{"delete", resourceType, resourceName, "--context", cinfo.Context}
+			backupErrs = backupDeleted(opts.BackupDir, targets, kubeconfig, getSelected, age, aged)
 		}
-		if recursive {
-			args = append(args, "-R")
+	}
+	withBackup := func(op clusterOp) clusterOp {
+		return func(ctx context.Context, c cluster.ClusterInfo, out io.Writer) error {
+			if err, ok := backupErrs[c.Context]; ok {
+				return err
+			}
+			return op(ctx, c, out)
 		}
-		if dryRun != "none" && dryRun != "" {
-			args = append(args, "--dry-run="+dryRun)
+	}
+
+	fanOpts := fanOutOptions{Namespace: namespaceOption(namespace, allNamespaces), Skip: skip, Unreachable: unreachable}
+	if age.active() {
+		_, err = fanOut(clusters, kubeconfig, remoteCtx, fanOpts, withBackup(func(ctx context.Context, c cluster.ClusterInfo, out io.Writer) error {
+			if err, ok := agedErrs[c.Context]; ok {
+				return err
+			}
+			return deleteAgedResources(ctx, aged[c.Context], resourceType, c.Context, kubeconfig, opts.DryRun, opts.Wait, opts.IgnoreNotFound, opts.Cascade, opts.Force, age, out)
+		}))
+		// Only a complete, real run moves the mark, so failed clusters are retried next time
+		if age.SinceLastRun && err == nil && (opts.DryRun == "none" || opts.DryRun == "") {
+			state.LastRun[stateKey] = runStart
+			if saveErr := state.save(statePath); saveErr != nil {
+				fmt.Printf("Warning: failed to record the run time: %v\n", saveErr)
+			}
 		}
-		if namespace != "" {
-			args = append(args, "-n", namespace)
+		return err
+	}
+	if len(opts.Types) > 0 {
+		_, err = fanOut(clusters, kubeconfig, remoteCtx, fanOpts, withBackup(func(ctx context.Context, c cluster.ClusterInfo, out io.Writer) error {
+			return deleteTypes(ctx, opts.Types, opts.Selector, c.Context, kubeconfig, namespace, allNamespaces, opts.DryRun, opts.Wait, opts.IgnoreNotFound, opts.Cascade, opts.Force, out)
+		}))
+		return err
+	}
+
+	op := kubectlOp(kubeconfig, func(c cluster.ClusterInfo) []string {
+		return buildDeleteArgs(resourceType, resourceName, opts.Selector, opts.Filenames, opts.Kustomize, opts.Recursive, opts.DryRun, opts.Wait, opts.IgnoreNotFound, opts.Cascade, opts.Force, namespace, allNamespaces, c.Context)
+	})
+	if opts.IgnoreNotFound {
+		op = withIgnoreNotFound(op)
+	}
+	_, err = fanOut(clusters, kubeconfig, remoteCtx, fanOpts, withBackup(op))
+	return err
+}
+
+// buildDeleteArgs builds the arguments of `kubectl delete` for a cluster, deleting either the resources
+// of the -f/-k manifests or those of resourceType matching resourceName or selector, in all namespaces with -A
+func buildDeleteArgs(resourceType, resourceName, selector string, filenames []string, kustomize string, recursive bool, dryRun string, wait, ignoreNotFound bool, cascade string, force bool, namespace string, allNamespaces bool, context string) []string {
+	var args []string
+	if len(filenames) > 0 || kustomize != "" {
+		args = append([]string{"delete"}, manifestArgs(filenames, kustomize)...)
+		args = append(args, "--context", context)
+	} else {
+		args = []string{"delete", resourceType}
+		if resourceName != "" {
+			args = append(args, resourceName)
 		}
-		output, err := runKubectl(args, kubeconfig)
-		fmt.Printf("=== Cluster: %s ===\n", cinfo.Context)
-		if err != nil {
-			fmt.Printf("Error: %v\n", err)
-		} else {
-			fmt.Print(output)
+		args = append(args, "--context", context)
+		if selector != "" {
+			args = append(args, "-l", selector)
 		}
-		fmt.Println()
 	}
+	if recursive {
+		args = append(args, "-R")
+	}
+	if dryRun != "none" && dryRun != "" {
+		args = append(args, "--dry-run="+dryRun)
+	}
+	if !wait {
+		args = append(args, "--wait=false")
+	}
+	if ignoreNotFound {
+		args = append(args, "--ignore-not-found")
+	}
+	args = append(args, cascadeArgs(cascade, force)...)
+	args = append(args, deleteNamespaceArgs(len(filenames) > 0 || kustomize != "", namespace, allNamespaces)...)
+	return args
+}
 
-	// 2. Run for KubeStellar clusters (excluding ITS and current)
-	for _, c := range clusters {
-		if c.Context == currentContext || c.Context == itsContext {
-			continue
+// deleteNamespaceArgs returns the namespace flags of the kubectl commands of a delete. -A only applies to
+// the deletes by resource type, the resources of manifests are deleted in their own namespaces.
+func deleteNamespaceArgs(manifest bool, namespace string, allNamespaces bool) []string {
+	if allNamespaces && !manifest {
+		return []string{"-A"}
+	}
+	if namespace != "" {
+		return []string{"-n", namespace}
+	}
+	return nil
+}
+
+// validCascade tells whether s is a value of --cascade, kubectl's lowercase names of the propagation policies
+func validCascade(s string) bool {
+	switch s {
+	case "background", "foreground", "orphan":
+		return true
+	}
+	return false
+}
+
+// resolveCascade validates --cascade, --propagation-policy and --orphan and returns the cascade to pass to
+// kubectl delete in every cluster, empty for kubectl's default. --propagation-policy takes the API names
+// Orphan, Background and Foreground in any case. When it disagrees with --cascade, --cascade wins and a
+// warning is returned; --orphan is an explicit request and disagreeing with it is an error.
+func resolveCascade(cascade, propagationPolicy string, orphan bool) (string, string, error) {
+	if cascade != "" && !validCascade(cascade) {
+		return "", "", fmt.Errorf("invalid --cascade %q, must be one of background, foreground, orphan", cascade)
+	}
+	policy := strings.ToLower(propagationPolicy)
+	if policy != "" && !validCascade(policy) {
+		return "", "", fmt.Errorf("invalid --propagation-policy %q, must be one of Orphan, Background, Foreground", propagationPolicy)
+	}
+
+	var warning string
+	resolved := cascade
+	switch {
+	case resolved == "":
+		resolved = policy
+	case policy != "" && policy != resolved:
+		warning = fmt.Sprintf("Warning: --propagation-policy=%s conflicts with --cascade=%s, using --cascade=%s", propagationPolicy, cascade, cascade)
+	}
+	if orphan {
+		if resolved != "" && resolved != "orphan" {
+			return "", "", fmt.Errorf("--orphan conflicts with the %s cascade requested by --cascade or --propagation-policy", resolved)
 		}
-		var args []string
-		if isFileProvided {
-			args = []string{"delete", "-f", filename, "--context", c.Context}
-		} else {
-			args = []string{"delete", resourceType, resourceName, "--context", c.Context}
+		resolved = "orphan"
+	}
+	return resolved, warning, nil
+}
+
+// cascadeArgs returns the kubectl delete flags of the resolved cascade and --force
+func cascadeArgs(cascade string, force bool) []string {
+	var args []string
+	if cascade != "" {
+		args = append(args, "--cascade="+cascade)
+	}
+	if force {
+		args = append(args, "--force")
+	}
+	return args
+}
+
+// withIgnoreNotFound wraps a delete op run with --ignore-not-found, for which kubectl prints nothing when
+// the resources are absent, so that the clusters without them say so instead of showing an empty section
+func withIgnoreNotFound(op clusterOp) clusterOp {
+	return func(ctx context.Context, c cluster.ClusterInfo, out io.Writer) error {
+		w := &countingWriter{w: out}
+		err := op(ctx, c, w)
+		if err == nil && w.n == 0 {
+			fmt.Fprintln(out, "Not found, nothing to delete")
 		}
-		if recursive {
-			args = append(args, "-R")
+		return err
+	}
+}
+
+// countingWriter counts the bytes written through it
+type countingWriter struct {
+	w io.Writer
+	n int
+}
+
+func (c *countingWriter) Write(p []byte) (int, error) {
+	n, err := c.w.Write(p)
+	c.n += n
+	return n, err
+}
+
+// backupDeleted backs up the resources a delete selects in every cluster. With an age filter, only the
+// resources selected by age are exported.
+func backupDeleted(dir string, targets []cluster.ClusterInfo, kubeconfig string, getSelected func(outputFormat, context string) []string, age ageFilter, aged map[string][]agedResource) map[string]error {
+	listArgs := func(context string) []string {
+		return getSelected("json", context)
+	}
+	if !age.active() {
+		return backupResources(dir, targets, kubeconfig, listArgs, nil)
+	}
+	return backupResources(dir, targets, kubeconfig, listArgs, func(c cluster.ClusterInfo, obj *unstructured.Unstructured) bool {
+		for _, r := range aged[c.Context] {
+			if r.Namespace == obj.GetNamespace() && r.Name == obj.GetName() {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+// listAgedResources fetches the matching resources of a cluster as JSON and returns the ones selected by the age filter.
+// The query is bounded by ctx and the cluster's --timeout.
+func listAgedResources(ctx context.Context, c cluster.ClusterInfo, resourceType, resourceName, selector, kubeconfig, namespace string, allNamespaces bool, age ageFilter) ([]agedResource, error) {
+	args := []string{"get", resourceType}
+	if resourceName != "" {
+		args = append(args, resourceName)
+	}
+	args = append(args, "-o", "json", "--ignore-not-found", "--context", c.Context)
+	if selector != "" {
+		args = append(args, "-l", selector)
+	}
+	if allNamespaces {
+		args = append(args, "-A")
+	} else if namespace != "" {
+		args = append(args, "-n", namespace)
+	}
+
+	output, err := queryCluster(ctx, c, args, kubeconfig)
+	if err != nil {
+		return nil, err
+	}
+	if strings.TrimSpace(output) == "" {
+		return nil, nil
+	}
+	resources, err := parseAgedResources([]byte(output))
+	if err != nil {
+		return nil, err
+	}
+	return age.apply(resources, time.Now()), nil
+}
+
+// deleteAgedResources deletes the given resources by name, one kubectl call per namespace
+func deleteAgedResources(ctx context.Context, resources []agedResource, resourceType, context, kubeconfig, dryRun string, wait, ignoreNotFound bool, cascade string, force bool, age ageFilter, out io.Writer) error {
+	if len(resources) == 0 {
+		fmt.Fprintf(out, "No %s %s found\n", resourceType, age)
+		return nil
+	}
+
+	namespaces, names := groupByNamespace(resources)
+	for _, ns := range namespaces {
+		args := append([]string{"delete", resourceType}, names[ns]...)
+		args = append(args, "--context", context)
+		if ns != "" {
+			args = append(args, "-n", ns)
 		}
 		if dryRun != "none" && dryRun != "" {
 			args = append(args, "--dry-run="+dryRun)
 		}
-		if namespace != "" {
-			args = append(args, "-n", namespace)
+		if !wait {
+			args = append(args, "--wait=false")
 		}
-		output, err := runKubectl(args, kubeconfig)
-		fmt.Printf("=== Cluster: %s ===\n", c.Context)
-		if err != nil {
-			fmt.Printf("Error: %v\n", err)
-		} else {
-			fmt.Print(output)
+		// A resource selected by age may have been deleted since it was listed
+		if ignoreNotFound {
+			args = append(args, "--ignore-not-found")
+		}
+		args = append(args, cascadeArgs(cascade, force)...)
+		if err := runKubectlTo(ctx, args, kubeconfig, out); err != nil {
+			return err
 		}
-		fmt.Println()
-	}
-
-	// 3. Print warning for ITS (control) cluster
-	if cinfo, ok := contextToCluster[itsContext]; ok {
-		fmt.Printf("=== Cluster: %s ===\n", cinfo.Context)
-		fmt.Printf("Cannot perform this operation on ITS (control) cluster: %s\n", cinfo.Context)
-		fmt.Println()
 	}
-
 	return nil
 }
 
-func newExecCommand() *cobra.Command {
-	cmd := &cobra.Command{
-		Use:   "exec POD [-c CONTAINER] -- COMMAND [args...]",
-		Short: "Execute a command in a container across managed clusters",
-		RunE: func(cmd *cobra.Command, args []string) error {
-			return fmt.Errorf("exec command not yet implemented")
-		},
-	}
-	return cmd
-}
+// deleteTypes deletes the resources of each type matching selector, one kubectl call per type,
+// and reports the result of every type. It fails if any of the types could not be deleted.
+func deleteTypes(ctx context.Context, types []string, selector, context, kubeconfig, namespace string, allNamespaces bool, dryRun string, wait, ignoreNotFound bool, cascade string, force bool, out io.Writer) error {
+	var failed []string
+	for _, t := range types {
+		args := []string{"delete", t, "-l", selector, "--context", context}
+		if allNamespaces {
+			args = append(args, "-A")
+		} else if namespace != "" {
+			args = append(args, "-n", namespace)
+		}
+		if dryRun != "none" && dryRun != "" {
+			args = append(args, "--dry-run="+dryRun)
+		}
+		if !wait {
+			args = append(args, "--wait=false")
+		}
+		if ignoreNotFound {
+			args = append(args, "--ignore-not-found")
+		}
+		args = append(args, cascadeArgs(cascade, force)...)
 
-func newCreateCommand() *cobra.Command {
-	cmd := &cobra.Command{
-		Use:   "create -f FILENAME",
-		Short: "Create a resource from a file or from stdin across managed clusters",
-		RunE: func(cmd *cobra.Command, args []string) error {
-			return fmt.Errorf("create command not yet implemented")
-		},
+		fmt.Fprintf(out, "--- %s ---\n", t)
+		if err := runKubectlTo(ctx, args, kubeconfig, out); err != nil {
+			failed = append(failed, t)
+			fmt.Fprintf(out, "Error: %v\n", err)
+		}
 	}
-	return cmd
+	if len(failed) > 0 {
+		return fmt.Errorf("failed to delete %s", strings.Join(failed, ", "))
+	}
+	return nil
 }
 
 func newEditCommand() *cobra.Command {
@@ -243,17 +760,6 @@ func newEditCommand() *cobra.Command {
 	return cmd
 }
 
-func newPatchCommand() *cobra.Command {
-	cmd := &cobra.Command{
-		Use:   "patch [TYPE[.VERSION][.GROUP]/]NAME --patch PATCH",
-		Short: "Update field(s) of a resource across managed clusters",
-		RunE: func(cmd *cobra.Command, args []string) error {
-			return fmt.Errorf("patch command not yet implemented")
-		},
-	}
-	return cmd
-}
-
 func newScaleCommand() *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "scale [TYPE[.VERSION][.GROUP]/]NAME --replicas=COUNT",
@@ -275,14 +781,3 @@ func newPortForwardCommand() *cobra.Command {
 	}
 	return cmd
 }
-
-func newTopCommand() *cobra.Command {
-	cmd := &cobra.Command{
-		Use:   "top [TYPE]",
-		Short: "Display resource (CPU/memory/storage) usage across managed clusters",
-		RunE: func(cmd *cobra.Command, args []string) error {
-			return fmt.Errorf("top command not yet implemented")
-		},
-	}
-	return cmd
-}
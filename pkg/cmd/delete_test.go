@@ -0,0 +1,156 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"kubectl-multi/pkg/cluster"
+)
+
+// fakeDeleteKubectl is a kubectl that only has deployment web in cluster2 and fails with NotFound
+// elsewhere, unless --ignore-not-found is passed
+const fakeDeleteKubectl = `#!/bin/sh
+case "$*" in *"--context cluster2"*) echo 'deployment.apps "web" deleted'; exit 0;; esac
+case "$*" in *--ignore-not-found*) exit 0;; esac
+echo 'Error from server (NotFound): deployments.apps "web" not found' >&2
+exit 1
+`
+
+// TestDeleteIgnoreNotFound ensures a resource present in only one cluster is deleted there, while the
+// clusters without it succeed with --ignore-not-found and fail without it
+func TestDeleteIgnoreNotFound(t *testing.T) {
+	installFakeKubectl(t, fakeDeleteKubectl)
+
+	clusters := []cluster.ClusterInfo{{Context: "cluster1"}, {Context: "cluster2"}, {Context: "cluster3"}}
+	for _, ignoreNotFound := range []bool{true, false} {
+		op := kubectlOp("", func(c cluster.ClusterInfo) []string {
+			return buildDeleteArgs("deployment", "web", "", nil, "", false, "none", true, ignoreNotFound, "", false, "", false, c.Context)
+		})
+		if ignoreNotFound {
+			op = withIgnoreNotFound(op)
+		}
+
+		for _, c := range clusters {
+			var out bytes.Buffer
+			result := runOnCluster(context.Background(), c, fanOutOptions{}, op, &out)
+			switch {
+			case c.Context == "cluster2":
+				if result.Err != nil || !strings.Contains(out.String(), "deleted") {
+					t.Errorf("ignoreNotFound=%v: expected web to be deleted from cluster2, got %v: %q", ignoreNotFound, result.Err, out.String())
+				}
+			case ignoreNotFound:
+				if result.Err != nil || strings.Contains(out.String(), "NotFound") || !strings.Contains(out.String(), "nothing to delete") {
+					t.Errorf("expected %s to succeed quietly, got %v: %q", c.Context, result.Err, out.String())
+				}
+			default:
+				if result.Err == nil || classifyError(result.Err) != categoryNotFound {
+					t.Errorf("expected %s to fail with NotFound without --ignore-not-found, got %v", c.Context, result.Err)
+				}
+			}
+		}
+	}
+}
+
+// TestBuildDeleteArgsCascade ensures --orphan and --force are passed on to kubectl delete
+func TestBuildDeleteArgsCascade(t *testing.T) {
+	args := strings.Join(buildDeleteArgs("deployment", "web", "", nil, "", false, "none", true, false, "orphan", false, "shop", false, "cluster1"), " ")
+	if args != "delete deployment web --context cluster1 --cascade=orphan -n shop" {
+		t.Errorf("unexpected args with --orphan: %s", args)
+	}
+	args = strings.Join(buildDeleteArgs("pod", "web-0", "", nil, "", false, "none", true, false, "", true, "", false, "cluster1"), " ")
+	if args != "delete pod web-0 --context cluster1 --force" {
+		t.Errorf("unexpected args with --force: %s", args)
+	}
+}
+
+// TestResolveCascade ensures --propagation-policy is mapped to a cascade and conflicts with --cascade and --orphan are detected
+func TestResolveCascade(t *testing.T) {
+	for _, tc := range []struct {
+		cascade, policy string
+		orphan          bool
+		want, warning   string
+		err             string
+	}{
+		{"", "", false, "", "", ""},
+		{"foreground", "", false, "foreground", "", ""},
+		{"", "Foreground", false, "foreground", "", ""},
+		{"", "orphan", false, "orphan", "", ""},
+		{"", "", true, "orphan", "", ""},
+		// Agreeing flags are not a conflict
+		{"background", "Background", false, "background", "", ""},
+		{"orphan", "Orphan", true, "orphan", "", ""},
+		{"background", "Foreground", false, "background", "--propagation-policy=Foreground conflicts with --cascade=background", ""},
+		{"", "Background", true, "", "", "--orphan conflicts with the background cascade"},
+		{"foreground", "", true, "", "", "--orphan conflicts with the foreground cascade"},
+		{"Orphan", "", false, "", "", `invalid --cascade "Orphan"`},
+		{"", "Delete", false, "", "", `invalid --propagation-policy "Delete"`},
+	} {
+		got, warning, err := resolveCascade(tc.cascade, tc.policy, tc.orphan)
+		if tc.err != "" {
+			if err == nil || !strings.Contains(err.Error(), tc.err) {
+				t.Errorf("%+v: expected an error containing %q, got %v", tc, tc.err, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%+v: unexpected error: %v", tc, err)
+			continue
+		}
+		if got != tc.want {
+			t.Errorf("%+v: expected cascade %q, got %q", tc, tc.want, got)
+		}
+		if (tc.warning == "") != (warning == "") || !strings.Contains(warning, tc.warning) {
+			t.Errorf("%+v: expected a warning containing %q, got %q", tc, tc.warning, warning)
+		}
+	}
+}
+
+// TestDeleteFlagConflicts ensures the flags that cannot be combined with --interactive, --from-names and
+// --names-file are rejected, naming the flags that were set
+func TestDeleteFlagConflicts(t *testing.T) {
+	for _, tc := range []struct {
+		flags []string
+		err   string
+	}{
+		{[]string{"pods", "-i", "--types", "deploy", "-y"}, "--interactive cannot be combined with --types, -y"},
+		{[]string{"pods", "--from-names", "names.txt", "-l", "app=web"}, "--from-names cannot be combined with a resource type, -l"},
+		{[]string{"--from-names", "names.txt", "--names-file", "other.txt"}, "--from-names cannot be combined with --names-file"},
+		{[]string{"--names-file", "names.txt"}, "--names-file requires a single resource type"},
+		{[]string{"pods", "--names-file", "names.txt", "-k", "overlay", "--check-rbac"}, "--names-file cannot be combined with -k, --check-rbac"},
+	} {
+		cmd := newDeleteCommand()
+		if err := cmd.ParseFlags(tc.flags); err != nil {
+			t.Fatalf("%v: unexpected error: %v", tc.flags, err)
+		}
+		err := cmd.RunE(cmd, cmd.Flags().Args())
+		if err == nil || err.Error() != tc.err {
+			t.Errorf("%v: expected %q, got %v", tc.flags, tc.err, err)
+		}
+	}
+}
+
+// TestDeleteSelectArgsAllNamespaces ensures the resources counted, listed and backed up before a delete
+// with -A are those of all namespaces, like the delete itself, except for manifests
+func TestDeleteSelectArgsAllNamespaces(t *testing.T) {
+	opts := deleteOptions{Selector: "app=web"}
+	args := strings.Join(opts.selectArgs("pods", "", "shop", true, "name", "cluster1"), " ")
+	if args != "get pods -o name --ignore-not-found --context cluster1 -l app=web -A" {
+		t.Errorf("unexpected count args with -A: %s", args)
+	}
+	args = strings.Join(buildDeleteArgs("pods", "", "app=web", nil, "", false, "none", true, false, "", false, "shop", true, "cluster1"), " ")
+	if args != "delete pods --context cluster1 -l app=web -A" {
+		t.Errorf("unexpected delete args with -A: %s", args)
+	}
+	args = strings.Join(opts.selectArgs("pods", "", "shop", false, "name", "cluster1"), " ")
+	if args != "get pods -o name --ignore-not-found --context cluster1 -l app=web -n shop" {
+		t.Errorf("unexpected count args with -n: %s", args)
+	}
+
+	manifest := deleteOptions{Filenames: []string{"app.yaml"}}
+	args = strings.Join(manifest.selectArgs("", "", "", true, "name", "cluster1"), " ")
+	if strings.Contains(args, "-A") {
+		t.Errorf("expected the resources of a manifest to be counted in their own namespaces, got %s", args)
+	}
+}
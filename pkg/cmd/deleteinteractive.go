@@ -0,0 +1,175 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+
+	"kubectl-multi/pkg/cluster"
+)
+
+// deleteInteractively lists the resources the delete selects in every target cluster, lets the user
+// choose which of them to delete and deletes exactly those, each in its own cluster. listArgs builds
+// the kubectl get arguments listing the selected resources of a cluster as JSON.
+func deleteInteractively(targets []cluster.ClusterInfo, listArgs func(context string) []string, dryRun string, wait, ignoreNotFound bool, cascade string, force bool, protectNamespaces []string, kubeconfig, remoteCtx string) error {
+	listCtx, cancel := totalContext()
+	defer cancel()
+
+	var matched []qualifiedName
+	for _, c := range targets {
+		output, err := queryCluster(listCtx, c, listArgs(c.Context), kubeconfig)
+		if err != nil {
+			fmt.Printf("Warning: failed to list the resources to delete in cluster %s: %v\n", c.Display(), err)
+			continue
+		}
+		items, err := parseObjects([]byte(output))
+		if err != nil {
+			fmt.Printf("Warning: failed to list the resources to delete in cluster %s: %v\n", c.Display(), err)
+			continue
+		}
+		for _, item := range items {
+			matched = append(matched, newQualifiedName(clusterObject{Cluster: c.Display(), Context: c.Context, Object: item}))
+		}
+	}
+	if len(matched) == 0 {
+		fmt.Println("No resources to delete")
+		return nil
+	}
+
+	chosen, ok, err := selectResources(os.Stdin, os.Stdout, matched)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		fmt.Println("Deletion cancelled...")
+		return nil
+	}
+	if len(chosen) == 0 {
+		fmt.Println("No resources selected, nothing deleted")
+		return nil
+	}
+
+	var contexts []string
+	names := make(map[string][]qualifiedName)
+	for _, n := range chosen {
+		if err := checkProtectedNamespaces(n.Kind, n.Name, n.Namespace, false, protectNamespaces); err != nil {
+			return fmt.Errorf("%s/%s in %s: %v", n.Kind, n.Name, n.Cluster, err)
+		}
+		if _, ok := names[n.Context]; !ok {
+			contexts = append(contexts, n.Context)
+		}
+		names[n.Context] = append(names[n.Context], n)
+	}
+	selected, err := selectNamedClusters(targets, contexts)
+	if err != nil {
+		return err
+	}
+	var counts []clusterCount
+	for _, ctx := range contexts {
+		counts = append(counts, clusterCount{Context: ctx, Count: len(names[ctx])})
+	}
+	fmt.Printf("Will delete %s\n", formatCountSummary(counts))
+
+	var op clusterOp = func(ctx context.Context, c cluster.ClusterInfo, out io.Writer) error {
+		return deleteNamed(ctx, names[c.Context], c.Context, kubeconfig, dryRun, wait, ignoreNotFound, cascade, force, out)
+	}
+	if ignoreNotFound {
+		op = withIgnoreNotFound(op)
+	}
+	_, err = fanOut(selected, kubeconfig, remoteCtx, fanOutOptions{}, op)
+	return err
+}
+
+// selectResources prints the numbered resources, none selected at first so that nothing is deleted
+// unless chosen, and reads from in the numbers or ranges to toggle until the user is done. It returns
+// the selected resources in their order, and false when the user quits or in ends before they are done.
+func selectResources(in io.Reader, out io.Writer, resources []qualifiedName) ([]qualifiedName, bool, error) {
+	selected := make([]bool, len(resources))
+
+	scanner := bufio.NewScanner(in)
+	for {
+		printSelection(out, resources, selected)
+		fmt.Fprintln(out, "Toggle resources by number or range (e.g. 1,3-5), or type all, none, done to delete the selected ones, or quit:")
+		if !scanner.Scan() {
+			if err := scanner.Err(); err != nil {
+				return nil, false, fmt.Errorf("failed to read the selection: %v", err)
+			}
+			return nil, false, nil
+		}
+
+		switch answer := strings.ToLower(strings.TrimSpace(scanner.Text())); answer {
+		case "done", "d":
+			var chosen []qualifiedName
+			for i, r := range resources {
+				if selected[i] {
+					chosen = append(chosen, r)
+				}
+			}
+			return chosen, true, nil
+		case "quit", "q":
+			return nil, false, nil
+		case "all", "none":
+			for i := range selected {
+				selected[i] = answer == "all"
+			}
+		case "":
+		default:
+			indexes, err := parseToggles(answer, len(resources))
+			if err != nil {
+				fmt.Fprintf(out, "Invalid selection: %v\n", err)
+				continue
+			}
+			for _, i := range indexes {
+				selected[i] = !selected[i]
+			}
+		}
+	}
+}
+
+// printSelection prints the resources with their number and whether they are selected
+func printSelection(out io.Writer, resources []qualifiedName, selected []bool) {
+	count := 0
+	for i, r := range resources {
+		mark := " "
+		if selected[i] {
+			mark = "x"
+			count++
+		}
+		name := r.Kind + "/" + r.Name
+		if r.Namespace != "" {
+			name = r.Namespace + "/" + name
+		}
+		fmt.Fprintf(out, "[%s] %*d  %s  %s\n", mark, len(strconv.Itoa(len(resources))), i+1, r.Cluster, name)
+	}
+	fmt.Fprintf(out, "%d of %d resources selected\n", count, len(resources))
+}
+
+// parseToggles parses comma or space separated numbers and ranges such as "1,3-5" into 0-based indexes
+// of a list of n resources
+func parseToggles(answer string, n int) ([]int, error) {
+	var indexes []int
+	for _, field := range strings.FieldsFunc(answer, func(r rune) bool { return r == ',' || r == ' ' }) {
+		from, to, isRange := strings.Cut(field, "-")
+		first, err := strconv.Atoi(from)
+		if err != nil {
+			return nil, fmt.Errorf("%q is not a number or range", field)
+		}
+		last := first
+		if isRange {
+			if last, err = strconv.Atoi(to); err != nil {
+				return nil, fmt.Errorf("%q is not a number or range", field)
+			}
+		}
+		if first < 1 || last > n || first > last {
+			return nil, fmt.Errorf("%q is not within 1-%d", field, n)
+		}
+		for i := first; i <= last; i++ {
+			indexes = append(indexes, i-1)
+		}
+	}
+	return indexes, nil
+}
@@ -0,0 +1,80 @@
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+)
+
+var interactiveResources = []qualifiedName{
+	{Context: "cluster1", Cluster: "cluster1", Namespace: "shop", Kind: "pod", Name: "web-1"},
+	{Context: "cluster1", Cluster: "cluster1", Namespace: "shop", Kind: "pod", Name: "web-2"},
+	{Context: "cluster2", Cluster: "eu", Namespace: "shop", Kind: "pod", Name: "web-1"},
+	{Context: "cluster2", Cluster: "eu", Kind: "namespace", Name: "shop"},
+}
+
+// TestSelectResources ensures nothing is selected at first, toggles, ranges and all/none change the
+// selection, invalid input is reported without aborting, and quitting or running out of input cancels
+func TestSelectResources(t *testing.T) {
+	for _, tc := range []struct {
+		input string
+		want  string
+		ok    bool
+	}{
+		{"done\n", "", true},
+		{"1,3\ndone\n", "cluster1/web-1,cluster2/web-1", true},
+		{"2-3 4\nd\n", "cluster1/web-2,cluster2/web-1,cluster2/shop", true},
+		{"2\n2\ndone\n", "", true},
+		{"9\n0\nx\n1\ndone\n", "cluster1/web-1", true},
+		{"all\n1\ndone\n", "cluster1/web-2,cluster2/web-1,cluster2/shop", true},
+		{"none\nall\n\ndone\n", "cluster1/web-1,cluster1/web-2,cluster2/web-1,cluster2/shop", true},
+		{"1\nquit\n", "", false},
+		{"1\n", "", false},
+	} {
+		var out bytes.Buffer
+		chosen, ok, err := selectResources(strings.NewReader(tc.input), &out, interactiveResources)
+		if err != nil {
+			t.Fatalf("%q: unexpected error: %v", tc.input, err)
+		}
+		var names []string
+		for _, n := range chosen {
+			names = append(names, n.Context+"/"+n.Name)
+		}
+		if got := strings.Join(names, ","); ok != tc.ok || got != tc.want {
+			t.Errorf("%q: expected %q (%v), got %q (%v)", tc.input, tc.want, tc.ok, got, ok)
+		}
+	}
+}
+
+// TestSelectResourcesOutput ensures the resources are numbered with their cluster and selection mark
+func TestSelectResourcesOutput(t *testing.T) {
+	var out bytes.Buffer
+	if _, _, err := selectResources(strings.NewReader("2\nfoo\nq\n"), &out, interactiveResources); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	for _, want := range []string{
+		"[ ] 1  cluster1  shop/pod/web-1\n",
+		"0 of 4 resources selected\n",
+		"[x] 2  cluster1  shop/pod/web-2\n",
+		"[ ] 4  eu  namespace/shop\n",
+		"1 of 4 resources selected\n",
+		`Invalid selection: "foo" is not a number or range`,
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("expected the output to contain %q, got:\n%s", want, out.String())
+		}
+	}
+}
+
+// TestParseToggles ensures ranges are expanded and numbers outside the list are rejected
+func TestParseToggles(t *testing.T) {
+	indexes, err := parseToggles("1, 3-4", 5)
+	if err != nil || len(indexes) != 3 || indexes[0] != 0 || indexes[1] != 2 || indexes[2] != 3 {
+		t.Errorf("expected [0 2 3], got %v, %v", indexes, err)
+	}
+	for _, answer := range []string{"0", "6", "4-2", "2-9", "-1", "a-b", "1-"} {
+		if _, err := parseToggles(answer, 5); err == nil {
+			t.Errorf("expected an error for %q", answer)
+		}
+	}
+}
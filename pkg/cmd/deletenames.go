@@ -0,0 +1,277 @@
+package cmd
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"kubectl-multi/pkg/cluster"
+)
+
+// parseQualifiedNames reads context/namespace/kind/name lines, as printed by `get -o name`, and groups
+// them by context in the order the contexts first appear. Blank lines and # comments are ignored,
+// duplicates are dropped and any malformed line fails the whole input.
+func parseQualifiedNames(r io.Reader) ([]string, map[string][]qualifiedName, error) {
+	var contexts []string
+	names := make(map[string][]qualifiedName)
+	seen := make(map[qualifiedName]bool)
+
+	scanner := bufio.NewScanner(r)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		parts := strings.Split(line, "/")
+		if len(parts) != 4 {
+			return nil, nil, fmt.Errorf("line %d: expected context/namespace/kind/name, got %q", n, line)
+		}
+		name := qualifiedName{Context: parts[0], Namespace: parts[1], Kind: parts[2], Name: parts[3]}
+		switch {
+		case name.Context == "":
+			return nil, nil, fmt.Errorf("line %d: missing context in %q", n, line)
+		case name.Kind == "":
+			return nil, nil, fmt.Errorf("line %d: missing kind in %q", n, line)
+		case name.Name == "":
+			return nil, nil, fmt.Errorf("line %d: missing name in %q", n, line)
+		}
+		if seen[name] {
+			continue
+		}
+		seen[name] = true
+		if _, ok := names[name.Context]; !ok {
+			contexts = append(contexts, name.Context)
+		}
+		names[name.Context] = append(names[name.Context], name)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, nil, fmt.Errorf("failed to read names: %v", err)
+	}
+	return contexts, names, nil
+}
+
+// handleDeleteFromNames deletes exactly the resources listed in path ("-" for stdin) in the cluster of
+// each line
+func handleDeleteFromNames(path, dryRun string, assumeYes, wait, ignoreNotFound bool, cascade string, force bool, protectNamespaces []string, kubeconfig, remoteCtx string) error {
+	in, err := openNamesInput(path, "--from-names", assumeYes || dryRunSkipsConfirmation(dryRun))
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	contexts, names, err := parseQualifiedNames(in)
+	if err != nil {
+		return err
+	}
+	if len(contexts) == 0 {
+		fmt.Println("No resources to delete")
+		return nil
+	}
+	for _, ctx := range contexts {
+		for _, n := range names[ctx] {
+			if err := checkProtectedNamespaces(n.Kind, n.Name, n.Namespace, false, protectNamespaces); err != nil {
+				return fmt.Errorf("%s/%s in %s: %v", n.Kind, n.Name, ctx, err)
+			}
+		}
+	}
+
+	clusters, _, err := discoverClusters(kubeconfig, remoteCtx)
+	if err != nil {
+		return fmt.Errorf("failed to discover clusters: %v", err)
+	}
+	targets, _ := fanOutTargets(clusters, "", itsContext(remoteCtx))
+	selected, err := selectNamedClusters(targets, contexts)
+	if err != nil {
+		return err
+	}
+
+	var counts []clusterCount
+	for _, ctx := range contexts {
+		counts = append(counts, clusterCount{Context: ctx, Count: len(names[ctx])})
+	}
+	fmt.Printf("Will delete %s\n", formatCountSummary(counts))
+	ok, err := confirmAction("Are you sure you want to delete these resources ?", assumeYes, dryRun)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		fmt.Println("Deletion cancelled...")
+		return nil
+	}
+
+	var op clusterOp = func(ctx context.Context, c cluster.ClusterInfo, out io.Writer) error {
+		return deleteNamed(ctx, names[c.Context], c.Context, kubeconfig, dryRun, wait, ignoreNotFound, cascade, force, out)
+	}
+	if ignoreNotFound {
+		op = withIgnoreNotFound(op)
+	}
+	_, err = fanOut(selected, kubeconfig, remoteCtx, fanOutOptions{}, op)
+	return err
+}
+
+// openNamesInput opens the names file given to flag, "-" for stdin
+func openNamesInput(path, flag string, assumeYes bool) (io.ReadCloser, error) {
+	if path != "-" {
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open %s file: %v", flag, err)
+		}
+		return f, nil
+	}
+	if !assumeYes {
+		// The prompt reads its answer from stdin, which already holds the names
+		return nil, fmt.Errorf("%s - reads the names from stdin, pass -y to confirm the deletion", flag)
+	}
+	return io.NopCloser(os.Stdin), nil
+}
+
+// parseNameList reads resource names, one per line, for --names-file. Blank lines and # comments are
+// ignored, duplicates are dropped and a line that is not a bare name fails the whole input.
+func parseNameList(r io.Reader) ([]string, error) {
+	var names []string
+	seen := make(map[string]bool)
+
+	scanner := bufio.NewScanner(r)
+	for n := 1; scanner.Scan(); n++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if strings.ContainsAny(line, "/ \t") {
+			return nil, fmt.Errorf("line %d: expected a resource name, got %q", n, line)
+		}
+		if seen[line] {
+			continue
+		}
+		seen[line] = true
+		names = append(names, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read names: %v", err)
+	}
+	return names, nil
+}
+
+// handleDeleteNamesFile deletes the resources of resourceType named in path ("-" for stdin) from every
+// cluster, in a single kubectl delete per cluster
+func handleDeleteNamesFile(resourceType, path, dryRun string, assumeYes, wait, ignoreNotFound bool, cascade string, force bool, protectNamespaces []string, kubeconfig, remoteCtx, namespace string) error {
+	in, err := openNamesInput(path, "--names-file", assumeYes || dryRunSkipsConfirmation(dryRun))
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+
+	names, err := parseNameList(in)
+	if err != nil {
+		return err
+	}
+	if len(names) == 0 {
+		fmt.Println("No resources to delete")
+		return nil
+	}
+	for _, name := range names {
+		if err := checkProtectedNamespaces(resourceType, name, namespace, false, protectNamespaces); err != nil {
+			return err
+		}
+	}
+
+	clusters, unreachable, err := discoverClusters(kubeconfig, remoteCtx)
+	if err != nil {
+		return fmt.Errorf("failed to discover clusters: %v", err)
+	}
+	if len(clusters) == 0 {
+		return fmt.Errorf("no clusters discovered")
+	}
+	targets, _ := fanOutTargets(clusters, "", itsContext(remoteCtx))
+	if err := checkProtectedContextNamespaces(targets, namespace, false, protectNamespaces); err != nil {
+		return err
+	}
+
+	fmt.Printf("Will delete %d %s by name from each of %d clusters, %d resources at most\n", len(names), resourceType, len(targets), len(names)*len(targets))
+	if !ignoreNotFound {
+		fmt.Println("Note: clusters missing some of the names will report them as not found, pass --ignore-not-found to ignore them")
+	}
+	ok, err := confirmAction("Are you sure you want to delete these resources ?", assumeYes, dryRun)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		fmt.Println("Deletion cancelled...")
+		return nil
+	}
+
+	op := kubectlOp(kubeconfig, func(c cluster.ClusterInfo) []string {
+		args := buildDeleteArgs(resourceType, "", "", nil, "", false, dryRun, wait, ignoreNotFound, cascade, force, namespace, false, c.Context)
+		// The names follow the type, as in `kubectl delete configmap a b c`
+		return append(append([]string{"delete", resourceType}, names...), args[2:]...)
+	})
+	if ignoreNotFound {
+		op = withIgnoreNotFound(op)
+	}
+	_, err = fanOut(clusters, kubeconfig, remoteCtx, fanOutOptions{Namespace: namespaceOption(namespace, false), Unreachable: unreachable}, op)
+	return err
+}
+
+// selectNamedClusters returns the target clusters of the given contexts, failing on contexts that are
+// not a target so that nothing is deleted from a cluster that was not meant
+func selectNamedClusters(targets []cluster.ClusterInfo, contexts []string) ([]cluster.ClusterInfo, error) {
+	wanted := make(map[string]bool, len(contexts))
+	for _, ctx := range contexts {
+		wanted[ctx] = true
+	}
+	var selected []cluster.ClusterInfo
+	for _, c := range targets {
+		if wanted[c.Context] {
+			selected = append(selected, c)
+			delete(wanted, c.Context)
+		}
+	}
+	if len(wanted) > 0 {
+		unknown := make([]string, 0, len(wanted))
+		for ctx := range wanted {
+			unknown = append(unknown, ctx)
+		}
+		sort.Strings(unknown)
+		return nil, fmt.Errorf("not a managed cluster: %s", strings.Join(unknown, ", "))
+	}
+	return selected, nil
+}
+
+// deleteNamed deletes the given resources of a cluster, one kubectl call per namespace
+func deleteNamed(ctx context.Context, names []qualifiedName, context, kubeconfig, dryRun string, wait, ignoreNotFound bool, cascade string, force bool, out io.Writer) error {
+	byNamespace := make(map[string][]string)
+	for _, n := range names {
+		byNamespace[n.Namespace] = append(byNamespace[n.Namespace], n.Kind+"/"+n.Name)
+	}
+	namespaces := make([]string, 0, len(byNamespace))
+	for ns := range byNamespace {
+		namespaces = append(namespaces, ns)
+	}
+	sort.Strings(namespaces)
+
+	for _, ns := range namespaces {
+		args := append([]string{"delete"}, byNamespace[ns]...)
+		args = append(args, "--context", context)
+		if ns != "" {
+			args = append(args, "-n", ns)
+		}
+		if dryRun != "none" && dryRun != "" {
+			args = append(args, "--dry-run="+dryRun)
+		}
+		if !wait {
+			args = append(args, "--wait=false")
+		}
+		if ignoreNotFound {
+			args = append(args, "--ignore-not-found")
+		}
+		args = append(args, cascadeArgs(cascade, force)...)
+		if err := runKubectlTo(ctx, args, kubeconfig, out); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,124 @@
+package cmd
+
+import (
+	"context"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+
+	"kubectl-multi/pkg/cluster"
+)
+
+// TestParseQualifiedNames ensures the output of get -o name is grouped per context, with cluster-scoped
+// resources, comments and duplicates handled
+func TestParseQualifiedNames(t *testing.T) {
+	input := `cluster1/shop/deployment.apps/web
+# leftovers of the canary
+cluster2/shop/job.batch/migrate
+
+cluster1//node/node-1
+cluster1/shop/deployment.apps/web
+`
+	contexts, names, err := parseQualifiedNames(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(contexts, []string{"cluster1", "cluster2"}) {
+		t.Errorf("unexpected contexts %v", contexts)
+	}
+	want := []qualifiedName{
+		{Context: "cluster1", Namespace: "shop", Kind: "deployment.apps", Name: "web"},
+		{Context: "cluster1", Kind: "node", Name: "node-1"},
+	}
+	if !reflect.DeepEqual(names["cluster1"], want) {
+		t.Errorf("expected %v, got %v", want, names["cluster1"])
+	}
+	if len(names["cluster2"]) != 1 || names["cluster2"][0].Name != "migrate" {
+		t.Errorf("unexpected names for cluster2: %v", names["cluster2"])
+	}
+}
+
+// TestParseNameList ensures names are read one per line without blanks, comments or duplicates
+func TestParseNameList(t *testing.T) {
+	input := `# old configmaps
+app-config
+
+  legacy-config  
+app-config
+`
+	names, err := parseNameList(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(names, []string{"app-config", "legacy-config"}) {
+		t.Errorf("unexpected names %v", names)
+	}
+}
+
+// TestParseNameListMalformed ensures qualified names and lines with spaces are rejected with their line
+func TestParseNameListMalformed(t *testing.T) {
+	for _, line := range []string{"configmap/app-config", "app config", "cluster1/shop/configmap/app"} {
+		_, err := parseNameList(strings.NewReader("ok\n" + line + "\n"))
+		if err == nil || !strings.Contains(err.Error(), "line 2") {
+			t.Errorf("expected a line 2 error for %q, got %v", line, err)
+		}
+	}
+}
+
+// TestParseQualifiedNamesMalformed ensures a malformed line is reported with its number
+func TestParseQualifiedNamesMalformed(t *testing.T) {
+	tests := map[string]string{
+		"deployment.apps/web":                 "expected context/namespace/kind/name",
+		"cluster1/shop/deployment.apps":       "expected context/namespace/kind/name",
+		"cluster1/shop/deployment.apps/web/x": "expected context/namespace/kind/name",
+		"/shop/deployment.apps/web":           "missing context",
+		"cluster1/shop//web":                  "missing kind",
+		"cluster1/shop/deployment.apps/":      "missing name",
+	}
+	for line, want := range tests {
+		_, _, err := parseQualifiedNames(strings.NewReader("cluster1/shop/pod/ok\n" + line + "\n"))
+		if err == nil || !strings.Contains(err.Error(), "line 2") || !strings.Contains(err.Error(), want) {
+			t.Errorf("%q: expected a line 2 error containing %q, got %v", line, want, err)
+		}
+	}
+}
+
+// TestSelectNamedClusters ensures unknown contexts are refused instead of being silently ignored
+func TestSelectNamedClusters(t *testing.T) {
+	targets := []cluster.ClusterInfo{{Context: "cluster1"}, {Context: "cluster2"}, {Context: "cluster3"}}
+	selected, err := selectNamedClusters(targets, []string{"cluster3", "cluster1"})
+	if err != nil || len(selected) != 2 || selected[0].Context != "cluster1" || selected[1].Context != "cluster3" {
+		t.Errorf("expected cluster1 and cluster3, got %v, %v", selected, err)
+	}
+	if _, err := selectNamedClusters(targets, []string{"cluster1", "its1", "gone"}); err == nil || !strings.Contains(err.Error(), "gone, its1") {
+		t.Errorf("expected the unknown contexts to be reported, got %v", err)
+	}
+}
+
+// TestDeleteNamed ensures the resources of a cluster are deleted with one kubectl call per namespace
+func TestDeleteNamed(t *testing.T) {
+	log := filepath.Join(t.TempDir(), "calls")
+	installFakeKubectl(t, "#!/bin/sh\necho \"$*\" >> "+log+"\n")
+
+	names := []qualifiedName{
+		{Context: "cluster1", Namespace: "shop", Kind: "deployment.apps", Name: "web"},
+		{Context: "cluster1", Kind: "node", Name: "node-1"},
+		{Context: "cluster1", Namespace: "shop", Kind: "service", Name: "web"},
+	}
+	if err := deleteNamed(context.Background(), names, "cluster1", "", "none", false, true, "", false, io.Discard); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	data, err := os.ReadFile(log)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := "delete node/node-1 --context cluster1 --wait=false --ignore-not-found\n" +
+		"delete deployment.apps/web service/web --context cluster1 -n shop --wait=false --ignore-not-found\n"
+	if string(data) != want {
+		t.Errorf("expected calls:\n%s\ngot:\n%s", want, data)
+	}
+}
@@ -0,0 +1,306 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+
+	"kubectl-multi/pkg/cluster"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	utilyaml "k8s.io/apimachinery/pkg/util/yaml"
+)
+
+// manifestResource identifies a resource of a manifest
+type manifestResource struct {
+	Group     string
+	Kind      string
+	Namespace string
+	Name      string
+}
+
+// String returns the resource formatted like kubectl -o name, e.g. "deployment.apps/web"
+func (r manifestResource) String() string {
+	kind := strings.ToLower(r.Kind)
+	if r.Group != "" {
+		kind += "." + r.Group
+	}
+	return kind + "/" + r.Name
+}
+
+// matches reports whether an object read from a cluster is the manifest resource. A manifest
+// resource without a namespace matches whatever namespace it was created in.
+func (r manifestResource) matches(obj manifestResource) bool {
+	return r.Group == obj.Group && r.Kind == obj.Kind && r.Name == obj.Name &&
+		(r.Namespace == "" || r.Namespace == obj.Namespace)
+}
+
+// newManifestResource returns the identity of an object
+func newManifestResource(obj *unstructured.Unstructured) manifestResource {
+	return manifestResource{
+		Group:     obj.GroupVersionKind().Group,
+		Kind:      obj.GetKind(),
+		Namespace: obj.GetNamespace(),
+		Name:      obj.GetName(),
+	}
+}
+
+// parseManifestResources returns the resources of a multi-document YAML or JSON manifest.
+// The items of List kinds are expanded and documents without a name are skipped.
+func parseManifestResources(data []byte) ([]manifestResource, error) {
+	objects, err := decodeObjects(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse manifest: %v", err)
+	}
+	var resources []manifestResource
+	for _, obj := range objects {
+		if obj.GetName() == "" || obj.GetKind() == "" {
+			continue
+		}
+		resources = append(resources, newManifestResource(obj))
+	}
+	return resources, nil
+}
+
+// readManifestResources returns the resources of the manifests given with -f (one or more) or -k.
+// Directories are read like kubectl does, their .yaml, .yml and .json files, descending into
+// subdirectories with -R.
+func readManifestResources(filenames []string, kustomize string, recursive bool, kubeconfig string) ([]manifestResource, error) {
+	if kustomize != "" {
+		rendered, err := kustomizeBuild(kustomize, kubeconfig)
+		if err != nil {
+			return nil, err
+		}
+		return parseManifestResources([]byte(rendered))
+	}
+
+	var files []string
+	for _, filename := range filenames {
+		found, err := manifestFiles(filename, recursive)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, found...)
+	}
+
+	var resources []manifestResource
+	for _, f := range files {
+		data, err := os.ReadFile(f)
+		if err != nil {
+			return nil, err
+		}
+		parsed, err := parseManifestResources(data)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %v", f, err)
+		}
+		resources = append(resources, parsed...)
+	}
+	return resources, nil
+}
+
+// manifestFiles returns the manifest files kubectl reads for -f filename: the file itself, or the
+// .yaml, .yml and .json files of a directory, including its subdirectories with -R
+func manifestFiles(filename string, recursive bool) ([]string, error) {
+	if strings.HasPrefix(filename, "http://") || strings.HasPrefix(filename, "https://") || filename == "-" {
+		return nil, fmt.Errorf("cannot preview %s, only local files and directories are supported", filename)
+	}
+
+	info, err := os.Stat(filename)
+	if err != nil {
+		return nil, err
+	}
+	files := []string{filename}
+	if info.IsDir() {
+		files = nil
+		err := filepath.WalkDir(filename, func(path string, d os.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if d.IsDir() {
+				if path != filename && !recursive {
+					return filepath.SkipDir
+				}
+				return nil
+			}
+			switch filepath.Ext(path) {
+			case ".yaml", ".yml", ".json":
+				files = append(files, path)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	return files, nil
+}
+
+// printManifestFiles lists the manifest files found in each directory of filenames, in the order kubectl
+// reads them, so that the set matched by -R can be checked. Plain files, URLs and stdin are not listed.
+func printManifestFiles(out io.Writer, filenames []string, recursive bool) error {
+	for _, filename := range filenames {
+		if strings.HasPrefix(filename, "http://") || strings.HasPrefix(filename, "https://") || filename == "-" {
+			continue
+		}
+		info, err := os.Stat(filename)
+		if err != nil {
+			return err
+		}
+		if !info.IsDir() {
+			continue
+		}
+		files, err := manifestFiles(filename, recursive)
+		if err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "Manifest files in %s (%d):\n", filename, len(files))
+		for _, f := range files {
+			fmt.Fprintf(out, "  %s\n", f)
+		}
+	}
+	return nil
+}
+
+// deletePreview records which resources of a manifest exist in each cluster
+type deletePreview struct {
+	Resources []manifestResource
+	Clusters  []string
+	// Present maps a cluster to whether each resource exists there, in the order of Resources
+	Present map[string][]bool
+	// Errs holds the clusters the check failed for
+	Errs map[string]error
+}
+
+// newDeletePreview matches the manifest resources against the objects found in each cluster
+func newDeletePreview(resources []manifestResource, clusters []string, found map[string][]manifestResource, errs map[string]error) deletePreview {
+	preview := deletePreview{Resources: resources, Clusters: clusters, Present: make(map[string][]bool), Errs: errs}
+	for _, c := range clusters {
+		if errs[c] != nil {
+			continue
+		}
+		present := make([]bool, len(resources))
+		for i, r := range resources {
+			for _, obj := range found[c] {
+				if r.matches(obj) {
+					present[i] = true
+					break
+				}
+			}
+		}
+		preview.Present[c] = present
+	}
+	return preview
+}
+
+// counts returns the number of resources that will be deleted in each cluster
+func (p deletePreview) counts() []clusterCount {
+	var counts []clusterCount
+	for _, c := range p.Clusters {
+		if err := p.Errs[c]; err != nil {
+			counts = append(counts, clusterCount{Context: c, Err: err})
+			continue
+		}
+		n := 0
+		for _, present := range p.Present[c] {
+			if present {
+				n++
+			}
+		}
+		counts = append(counts, clusterCount{Context: c, Count: n})
+	}
+	return counts
+}
+
+// print writes the resource × cluster matrix, marking whether each resource is present (and will be
+// deleted) or already absent. Clusters that could not be checked are marked with "?".
+func (p deletePreview) print(out io.Writer) {
+	tw := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	defer tw.Flush()
+
+	fmt.Fprintf(tw, "NAMESPACE\tRESOURCE\t%s\n", strings.Join(p.Clusters, "\t"))
+	for i, r := range p.Resources {
+		namespace := r.Namespace
+		if namespace == "" {
+			namespace = "-"
+		}
+		marks := make([]string, len(p.Clusters))
+		for j, c := range p.Clusters {
+			switch {
+			case p.Errs[c] != nil:
+				marks[j] = "?"
+			case p.Present[c][i]:
+				marks[j] = "present"
+			default:
+				marks[j] = "absent"
+			}
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\n", namespace, r, strings.Join(marks, "\t"))
+	}
+}
+
+// previewManifestDelete checks which resources of the manifest exist in every target cluster
+func previewManifestDelete(targets []cluster.ClusterInfo, resources []manifestResource, filenames []string, kustomize string, recursive bool, kubeconfig, namespace string) deletePreview {
+	var contexts []string
+	found := make(map[string][]manifestResource)
+	errs := make(map[string]error)
+	for _, c := range targets {
+		contexts = append(contexts, c.Context)
+		args := append([]string{"get"}, manifestArgs(filenames, kustomize)...)
+		args = append(args, "-o", "json", "--ignore-not-found", "--context", c.Context)
+		if recursive {
+			args = append(args, "-R")
+		}
+		if namespace != "" {
+			args = append(args, "-n", namespace)
+		}
+		output, err := runKubectl(args, kubeconfig)
+		if err != nil {
+			errs[c.Context] = fmt.Errorf("%v: %s", err, strings.TrimSpace(output))
+			continue
+		}
+		objects, err := decodeObjects([]byte(output))
+		if err != nil {
+			errs[c.Context] = fmt.Errorf("failed to parse kubectl output: %v", err)
+			continue
+		}
+		for _, obj := range objects {
+			found[c.Context] = append(found[c.Context], newManifestResource(obj))
+		}
+	}
+	return newDeletePreview(resources, contexts, found, errs)
+}
+
+// decodeObjects decodes a stream of YAML or JSON documents, such as a manifest or the output of
+// `kubectl get -f -o json` for several documents, expanding the items of List kinds
+func decodeObjects(data []byte) ([]*unstructured.Unstructured, error) {
+	var objects []*unstructured.Unstructured
+	decoder := utilyaml.NewYAMLOrJSONDecoder(bytes.NewReader(data), 4096)
+	for {
+		var raw map[string]interface{}
+		if err := decoder.Decode(&raw); err != nil {
+			if errors.Is(err, io.EOF) {
+				return objects, nil
+			}
+			return nil, err
+		}
+		if raw == nil {
+			continue
+		}
+		kind, _ := raw["kind"].(string)
+		if !strings.HasSuffix(kind, "List") {
+			objects = append(objects, &unstructured.Unstructured{Object: raw})
+			continue
+		}
+		items, _ := raw["items"].([]interface{})
+		for _, item := range items {
+			if m, ok := item.(map[string]interface{}); ok {
+				objects = append(objects, &unstructured.Unstructured{Object: m})
+			}
+		}
+	}
+}
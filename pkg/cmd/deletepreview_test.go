@@ -0,0 +1,156 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const previewManifest = `apiVersion: apps/v1
+kind: Deployment
+metadata:
+  name: web
+  namespace: shop
+---
+apiVersion: v1
+kind: List
+items:
+- apiVersion: v1
+  kind: Service
+  metadata:
+    name: web
+- apiVersion: v1
+  kind: ConfigMap
+  metadata:
+    name: web-config
+---
+apiVersion: v1
+kind: Pod
+metadata:
+  generateName: job-
+`
+
+// TestParseManifestResources ensures every named document and List item is returned
+func TestParseManifestResources(t *testing.T) {
+	resources, err := parseManifestResources([]byte(previewManifest))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var names []string
+	for _, r := range resources {
+		names = append(names, r.String())
+	}
+	if got, want := strings.Join(names, ","), "deployment.apps/web,service/web,configmap/web-config"; got != want {
+		t.Errorf("expected %s, got %s", want, got)
+	}
+	if resources[0].Namespace != "shop" {
+		t.Errorf("expected the deployment namespace to be kept, got %q", resources[0].Namespace)
+	}
+}
+
+// TestReadManifestResourcesDirectory ensures subdirectories are only read with -R
+func TestReadManifestResourcesDirectory(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "sub"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	write := func(path, name string) {
+		data := "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: " + name + "\n"
+		if err := os.WriteFile(filepath.Join(dir, path), []byte(data), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("a.yaml", "a")
+	write("notes.txt", "ignored")
+	write("sub/b.yml", "b")
+
+	flat, err := readManifestResources([]string{dir}, "", false, "")
+	if err != nil || len(flat) != 1 || flat[0].Name != "a" {
+		t.Errorf("expected only a without -R, got %v, %v", flat, err)
+	}
+	all, err := readManifestResources([]string{dir}, "", true, "")
+	if err != nil || len(all) != 2 {
+		t.Errorf("expected a and b with -R, got %v, %v", all, err)
+	}
+	if _, err := readManifestResources([]string{"https://example.com/app.yaml"}, "", false, ""); err == nil {
+		t.Error("expected URLs not to be previewed")
+	}
+
+	both, err := readManifestResources([]string{filepath.Join(dir, "a.yaml"), filepath.Join(dir, "sub")}, "", false, "")
+	if err != nil || len(both) != 2 || both[0].Name != "a" || both[1].Name != "b" {
+		t.Errorf("expected a and b from two -f, got %v, %v", both, err)
+	}
+}
+
+// TestPrintManifestFiles ensures the files of a directory are listed once, with its subdirectories only with -R
+func TestPrintManifestFiles(t *testing.T) {
+	dir := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(dir, "sub"), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	for _, f := range []string{"a.yaml", "notes.txt", "sub/b.json"} {
+		if err := os.WriteFile(filepath.Join(dir, f), []byte("{}"), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	single := filepath.Join(dir, "a.yaml")
+
+	var out bytes.Buffer
+	if err := printManifestFiles(&out, []string{dir, single, "-"}, true); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := "Manifest files in " + dir + " (2):\n  " + single + "\n  " + filepath.Join(dir, "sub", "b.json") + "\n"
+	if out.String() != want {
+		t.Errorf("expected %q, got %q", want, out.String())
+	}
+
+	out.Reset()
+	if err := printManifestFiles(&out, []string{dir}, false); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.Contains(out.String(), "b.json") || !strings.Contains(out.String(), "(1)") {
+		t.Errorf("expected only a.yaml without -R, got %q", out.String())
+	}
+
+	if err := printManifestFiles(&out, []string{filepath.Join(dir, "missing")}, true); err == nil {
+		t.Error("expected an error for a missing directory")
+	}
+}
+
+// TestDeletePreview ensures the matrix marks present and absent resources per cluster and the counts match
+func TestDeletePreview(t *testing.T) {
+	resources, err := parseManifestResources([]byte(previewManifest))
+	if err != nil {
+		t.Fatal(err)
+	}
+	found := map[string][]manifestResource{
+		"cluster1": {
+			{Group: "apps", Kind: "Deployment", Namespace: "shop", Name: "web"},
+			{Kind: "Service", Namespace: "default", Name: "web"},
+		},
+		"cluster2": {
+			{Group: "apps", Kind: "Deployment", Namespace: "other", Name: "web"},
+		},
+	}
+	errs := map[string]error{"cluster3": errors.New("forbidden")}
+	preview := newDeletePreview(resources, []string{"cluster1", "cluster2", "cluster3"}, found, errs)
+
+	var buf bytes.Buffer
+	preview.print(&buf)
+	want := `NAMESPACE  RESOURCE              cluster1  cluster2  cluster3
+shop       deployment.apps/web   present   absent    ?
+-          service/web           present   absent    ?
+-          configmap/web-config  absent    absent    ?
+`
+	if buf.String() != want {
+		t.Errorf("expected:\n%s\ngot:\n%s", want, buf.String())
+	}
+
+	counts := preview.counts()
+	if counts[0].Count != 2 || counts[1].Count != 0 || counts[2].Err == nil {
+		t.Errorf("unexpected counts: %+v", counts)
+	}
+}
@@ -9,7 +9,6 @@ import (
 
 	"github.com/spf13/cobra"
 
-	"kubectl-multi/pkg/cluster"
 	"kubectl-multi/pkg/util"
 )
 
@@ -93,7 +92,7 @@ kubectl multi describe nodes`,
 }
 
 func handleDescribeCommand(args []string, selector string, showEvents bool, chunkSize int, kubeconfig, remoteCtx, namespace string, allNamespaces bool) error {
-	clusters, err := cluster.DiscoverClusters(kubeconfig, remoteCtx)
+	clusters, _, err := discoverClusters(kubeconfig, remoteCtx)
 	if err != nil {
 		return fmt.Errorf("failed to discover clusters: %v", err)
 	}
@@ -115,13 +114,13 @@ func handleDescribeCommand(args []string, selector string, showEvents bool, chun
 	// Track if any cluster had successful output
 	anyOutput := false
 
-	for _, clusterInfo := range clusters {
+	for i, clusterInfo := range clusters {
 		if clusterInfo.Client == nil {
 			fmt.Printf("Warning: skipping cluster %s (no client available)\n", clusterInfo.Name)
 			continue
 		}
 
-		fmt.Printf("=== Cluster: %s (Context: %s) ===\n", clusterInfo.Name, clusterInfo.Context)
+		fmt.Println(clusterBanner(clusterInfo, i+1, len(clusters)))
 
 		// Build kubectl describe command
 		kubectlArgs := buildDescribeArgs(args, selector, showEvents, chunkSize, namespace, allNamespaces, clusterInfo.Name)
@@ -191,7 +190,7 @@ func buildDescribeArgs(args []string, selector string, showEvents bool, chunkSiz
 // executeKubectlDescribe executes kubectl describe command for a specific cluster
 func executeKubectlDescribe(args []string, kubeconfig, clusterName string) (string, error) {
 	// Create the command
-	cmd := exec.Command("kubectl", args...)
+	cmd := exec.Command(kubectlBinary(args), args...)
 
 	// Set environment variables
 	cmd.Env = os.Environ()
@@ -0,0 +1,72 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"kubectl-multi/pkg/cluster"
+
+	"github.com/spf13/cobra"
+	"sigs.k8s.io/yaml"
+)
+
+func newDiscoverCommand() *cobra.Command {
+	var outputFormat string
+
+	cmd := &cobra.Command{
+		Use:   "discover -o json",
+		Short: "Print the discovered clusters as JSON or YAML for other tools",
+		Long: `Print the result of discovery for dashboards and other tools: the context, name, API server
+URL, role, labels and reachability of every cluster, including the clusters that could not be
+reached. Every reachable cluster is checked by asking its API server for its version.
+
+The output is a ` + cluster.DiscoveryReportKind + ` object whose apiVersion (` + cluster.DiscoveryAPIVersion + `) only changes
+when a field is removed or changes meaning, so tools can depend on it.`,
+		Example: `# Print the discovered clusters as JSON
+kubectl multi discover -o json
+
+# List the contexts of the unreachable clusters
+kubectl multi discover -o json | jq -r '.clusters[] | select(.reachable | not) | .context'`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			kubeconfig, remoteCtx, _, _, _ := GetGlobalFlags()
+			return handleDiscoverCommand(outputFormat, kubeconfig, remoteCtx)
+		},
+	}
+
+	cmd.Flags().StringVarP(&outputFormat, "output", "o", "json", "output format (json|yaml)")
+	return cmd
+}
+
+func handleDiscoverCommand(outputFormat, kubeconfig, remoteCtx string) error {
+	if outputFormat != "json" && outputFormat != "yaml" {
+		return fmt.Errorf("unsupported output format %q, must be json or yaml", outputFormat)
+	}
+
+	clusters, unreachable, err := discoverClusters(kubeconfig, remoteCtx)
+	if err != nil {
+		return fmt.Errorf("failed to discover clusters: %v", err)
+	}
+
+	report := cluster.NewDiscoveryReport(append(clusters, unreachable...), cluster.KubeconfigServers(kubeconfig), func(c cluster.ClusterInfo) (string, error) {
+		v, err := cluster.ServerVersion(c)
+		if err != nil {
+			return "", err
+		}
+		return "v" + v.String(), nil
+	})
+
+	var data []byte
+	if outputFormat == "yaml" {
+		data, err = yaml.Marshal(report)
+	} else {
+		data, err = json.MarshalIndent(report, "", "  ")
+		data = append(data, '\n')
+	}
+	if err != nil {
+		return err
+	}
+	_, err = os.Stdout.Write(data)
+	return err
+}
@@ -0,0 +1,180 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+)
+
+// isServerDryRun reports whether a --dry-run value asks the API servers for a dry run,
+// which runs the admission webhooks of every cluster
+func isServerDryRun(dryRun string) bool {
+	return dryRun == "server"
+}
+
+// dryRunReport is the outcome of a server-side dry run in a single cluster
+type dryRunReport struct {
+	Cluster string
+	// Result is accepted, rejected or skipped
+	Result string
+	// Warnings are the warnings returned by the API server and its admission webhooks
+	Warnings []string
+	// Rejection is the reason the dry run failed, such as a webhook denying the request
+	Rejection string
+}
+
+// parseDryRunWarnings returns the "Warning:" lines kubectl prints for the warnings of the API server
+func parseDryRunWarnings(output string) []string {
+	var warnings []string
+	for _, line := range strings.Split(output, "\n") {
+		if w, ok := strings.CutPrefix(strings.TrimSpace(line), "Warning:"); ok {
+			warnings = append(warnings, strings.TrimSpace(w))
+		}
+	}
+	return warnings
+}
+
+// dryRunRejection returns the line explaining why a dry run failed, preferring admission webhook denials
+func dryRunRejection(output string, err error) string {
+	var serverErr string
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimSpace(line)
+		if strings.Contains(line, "denied the request") {
+			return line
+		}
+		if serverErr == "" && strings.HasPrefix(line, "Error from server") {
+			serverErr = line
+		}
+	}
+	if serverErr != "" {
+		return serverErr
+	}
+	return err.Error()
+}
+
+// newDryRunReports builds the per-cluster reports of a server-side dry run from the fan-out results
+func newDryRunReports(results []clusterResult) []dryRunReport {
+	var reports []dryRunReport
+	for _, r := range results {
+		report := dryRunReport{Cluster: r.displayName(), Result: "accepted", Warnings: parseDryRunWarnings(r.Output)}
+		switch {
+		case r.Skipped != "":
+			report.Result = "skipped"
+		case r.Err != nil:
+			report.Result = "rejected"
+			report.Rejection = dryRunRejection(r.Output, r.Err)
+		}
+		reports = append(reports, report)
+	}
+	return reports
+}
+
+// printDryRunSummary prints a table of the dry run result of every cluster, followed by the warnings
+// and rejections of each cluster, so a cluster whose policies would block the real operation stands out
+func printDryRunSummary(out io.Writer, reports []dryRunReport) {
+	if len(reports) == 0 {
+		return
+	}
+	fmt.Fprintln(out, "Server dry run:")
+	tw := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "CLUSTER\tRESULT\tWARNINGS\n")
+	for _, r := range reports {
+		fmt.Fprintf(tw, "%s\t%s\t%d\n", r.Cluster, r.Result, len(r.Warnings))
+	}
+	tw.Flush()
+
+	var warnings, rejections []string
+	for _, r := range reports {
+		for _, w := range r.Warnings {
+			warnings = append(warnings, fmt.Sprintf("  %s: %s", r.Cluster, w))
+		}
+		if r.Rejection != "" {
+			rejections = append(rejections, fmt.Sprintf("  %s: %s", r.Cluster, r.Rejection))
+		}
+	}
+	if len(warnings) > 0 {
+		fmt.Fprintf(out, "\nWarnings:\n%s\n", strings.Join(warnings, "\n"))
+	}
+	if len(rejections) > 0 {
+		fmt.Fprintf(out, "\nRejected:\n%s\n", strings.Join(rejections, "\n"))
+	}
+}
+
+// dryRunSuffixes end the lines kubectl prints for every resource of a dry run, e.g.
+// "deployment.apps/web configured (server dry run)"
+var dryRunSuffixes = []string{" (server dry run)", " (dry run)"}
+
+// parseDryRunActions returns the resources of a dry run's output in the order they are printed,
+// along with what would happen to each one: created, configured, unchanged, ...
+func parseDryRunActions(output string) ([]string, map[string]string) {
+	var resources []string
+	actions := make(map[string]string)
+	for _, line := range strings.Split(output, "\n") {
+		line = strings.TrimSpace(line)
+		for _, suffix := range dryRunSuffixes {
+			rest, ok := strings.CutSuffix(line, suffix)
+			if !ok {
+				continue
+			}
+			fields := strings.Fields(rest)
+			if len(fields) != 2 {
+				break
+			}
+			if _, seen := actions[fields[0]]; !seen {
+				resources = append(resources, fields[0])
+			}
+			actions[fields[0]] = fields[1]
+			break
+		}
+	}
+	return resources, actions
+}
+
+// printDryRunMatrix prints what a dry run would do to every resource in every cluster, one row per
+// resource and one column per cluster, so the clusters out of sync with the manifests stand out.
+// Resources missing from the output of a cluster are shown as error if the cluster failed.
+func printDryRunMatrix(out io.Writer, results []clusterResult) {
+	var resources []string
+	seen := make(map[string]bool)
+	actions := make([]map[string]string, len(results))
+	for i, r := range results {
+		var order []string
+		order, actions[i] = parseDryRunActions(r.Output)
+		for _, res := range order {
+			if !seen[res] {
+				seen[res] = true
+				resources = append(resources, res)
+			}
+		}
+	}
+	if len(resources) == 0 {
+		return
+	}
+
+	fmt.Fprintln(out, "\nChanges per resource:")
+	tw := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	fmt.Fprint(tw, "RESOURCE")
+	for _, r := range results {
+		fmt.Fprintf(tw, "\t%s", r.displayName())
+	}
+	fmt.Fprintln(tw)
+	for _, res := range resources {
+		fmt.Fprint(tw, res)
+		for i, r := range results {
+			action, ok := actions[i][res]
+			switch {
+			case ok:
+			case r.Skipped != "":
+				action = "skipped"
+			case r.Err != nil:
+				action = "error"
+			default:
+				action = "-"
+			}
+			fmt.Fprintf(tw, "\t%s", action)
+		}
+		fmt.Fprintln(tw)
+	}
+	tw.Flush()
+}
@@ -0,0 +1,101 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// TestNewDryRunReports ensures warnings and webhook denials are attributed to their cluster
+func TestNewDryRunReports(t *testing.T) {
+	results := []clusterResult{
+		{Context: "wds1", Output: "Warning: would violate PodSecurity \"restricted:latest\": runAsNonRoot != true\ndeployment.apps/web created (server dry run)\n"},
+		{Context: "wds2", Output: "Error from server (Forbidden): error when creating \"app.yaml\": admission webhook \"validate.kyverno.svc\" denied the request: image tag latest is not allowed\n", Err: errors.New("exit status 1")},
+		{Context: "wds3", Output: "deployment.apps/web created (server dry run)\n"},
+		{Context: "wds4", Skipped: "server version too old"},
+	}
+
+	reports := newDryRunReports(results)
+	if reports[0].Result != "accepted" || len(reports[0].Warnings) != 1 || !strings.HasPrefix(reports[0].Warnings[0], "would violate PodSecurity") {
+		t.Errorf("unexpected wds1 report: %+v", reports[0])
+	}
+	if reports[1].Result != "rejected" || !strings.Contains(reports[1].Rejection, "denied the request") {
+		t.Errorf("unexpected wds2 report: %+v", reports[1])
+	}
+	if reports[2].Result != "accepted" || len(reports[2].Warnings) != 0 {
+		t.Errorf("unexpected wds3 report: %+v", reports[2])
+	}
+	if reports[3].Result != "skipped" {
+		t.Errorf("unexpected wds4 report: %+v", reports[3])
+	}
+}
+
+// TestDryRunRejectionFallback ensures the error is used when kubectl printed no server error
+func TestDryRunRejectionFallback(t *testing.T) {
+	if got := dryRunRejection("", errors.New("timed out after 30s")); got != "timed out after 30s" {
+		t.Errorf("unexpected rejection %q", got)
+	}
+}
+
+// TestPrintDryRunSummary ensures the summary lists every cluster, then the warnings and rejections
+func TestPrintDryRunSummary(t *testing.T) {
+	reports := []dryRunReport{
+		{Cluster: "wds1", Result: "accepted", Warnings: []string{"deprecated API"}},
+		{Cluster: "wds2", Result: "rejected", Rejection: "admission webhook denied the request"},
+	}
+
+	var buf bytes.Buffer
+	printDryRunSummary(&buf, reports)
+	want := `Server dry run:
+CLUSTER  RESULT    WARNINGS
+wds1     accepted  1
+wds2     rejected  0
+
+Warnings:
+  wds1: deprecated API
+
+Rejected:
+  wds2: admission webhook denied the request
+`
+	if buf.String() != want {
+		t.Errorf("expected:\n%s\ngot:\n%s", want, buf.String())
+	}
+}
+
+// TestPrintDryRunMatrix ensures every resource gets the action of each cluster, and failed or
+// skipped clusters are marked for the resources they did not report
+func TestPrintDryRunMatrix(t *testing.T) {
+	results := []clusterResult{
+		{Context: "wds1", Output: "deployment.apps/web configured (server dry run)\nservice/web unchanged (server dry run)\n"},
+		{Context: "wds2", Output: "deployment.apps/web unchanged (server dry run)\nservice/web unchanged (server dry run)\n"},
+		{Context: "wds3", Output: "deployment.apps/web created (server dry run)\nError from server (Forbidden): error when creating \"app.yaml\": services is forbidden\n", Err: errors.New("exit status 1")},
+		{Context: "wds4", Skipped: "server version too old"},
+	}
+
+	var buf bytes.Buffer
+	printDryRunMatrix(&buf, results)
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	if len(lines) != 4 {
+		t.Fatalf("expected a title, a header and 2 rows, got:\n%s", buf.String())
+	}
+	want := [][]string{
+		{"RESOURCE", "wds1", "wds2", "wds3", "wds4"},
+		{"deployment.apps/web", "configured", "unchanged", "created", "skipped"},
+		{"service/web", "unchanged", "unchanged", "error", "skipped"},
+	}
+	for i, w := range want {
+		if got := strings.Fields(lines[i+1]); strings.Join(got, " ") != strings.Join(w, " ") {
+			t.Errorf("row %d: expected %v, got %v", i, w, got)
+		}
+	}
+}
+
+// TestPrintDryRunMatrixEmpty ensures nothing is printed when no resource line was found
+func TestPrintDryRunMatrixEmpty(t *testing.T) {
+	var buf bytes.Buffer
+	printDryRunMatrix(&buf, []clusterResult{{Context: "wds1", Err: errors.New("connection refused")}})
+	if buf.Len() != 0 {
+		t.Errorf("expected no matrix, got:\n%s", buf.String())
+	}
+}
@@ -0,0 +1,147 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// Error categories used to group cluster failures in the fan-out summary
+const (
+	categoryNotFound    = "NotFound"
+	categoryForbidden   = "Forbidden"
+	categoryTimeout     = "Timeout"
+	categoryUnreachable = "Unreachable"
+	categoryOther       = "Other"
+)
+
+// errorCategories lists the categories in the order they are reported
+var errorCategories = []string{categoryNotFound, categoryForbidden, categoryTimeout, categoryUnreachable, categoryOther}
+
+// kubectlError is returned when kubectl fails, it keeps the exit code and stderr so the failure can be classified
+type kubectlError struct {
+	// ExitCode is kubectl's exit code, or -1 if it did not exit normally
+	ExitCode int
+	Stderr   string
+	Err      error
+}
+
+// newKubectlError wraps the error of a kubectl run with its exit code and stderr
+func newKubectlError(err error, stderr string) error {
+	exitCode := -1
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		exitCode = exitErr.ExitCode()
+	}
+	return &kubectlError{ExitCode: exitCode, Stderr: stderr, Err: err}
+}
+
+func (e *kubectlError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *kubectlError) Unwrap() error {
+	return e.Err
+}
+
+// classifyError returns the category of a cluster failure from kubectl's stderr, or the error message
+// for failures that did not come from kubectl
+func classifyError(err error) string {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return categoryTimeout
+	}
+
+	msg := err.Error()
+	var kerr *kubectlError
+	if errors.As(err, &kerr) {
+		msg = kerr.Stderr
+	}
+	msg = strings.ToLower(msg)
+
+	switch {
+	case strings.Contains(msg, "(notfound)") || strings.Contains(msg, "not found"):
+		return categoryNotFound
+	case strings.Contains(msg, "(forbidden)") || strings.Contains(msg, "forbidden") || strings.Contains(msg, "unauthorized"):
+		return categoryForbidden
+	// checked before the generic timeout match, a dial failure like "dial tcp ...: i/o timeout" means the
+	// server could not be reached rather than that it was too slow to answer
+	case strings.Contains(msg, "unable to connect to the server") || strings.Contains(msg, "dial tcp") ||
+		strings.Contains(msg, "connection refused") || strings.Contains(msg, "no such host") ||
+		strings.Contains(msg, "unreachable") || strings.Contains(msg, "no route to host"):
+		return categoryUnreachable
+	case strings.Contains(msg, "timed out") || strings.Contains(msg, "timeout") || strings.Contains(msg, "deadline exceeded"):
+		return categoryTimeout
+	default:
+		return categoryOther
+	}
+}
+
+// isAlreadyExists tells whether kubectl failed only because resources it was to create already exist.
+// kubectl then reports an AlreadyExists error per existing resource, the other resources being created.
+func isAlreadyExists(err error) bool {
+	var kerr *kubectlError
+	if !errors.As(err, &kerr) || kerr.ExitCode != 1 {
+		return false
+	}
+	found := false
+	for _, line := range strings.Split(kerr.Stderr, "\n") {
+		line = strings.TrimSpace(line)
+		switch {
+		case line == "" || strings.HasPrefix(line, "Warning:"):
+		case strings.Contains(line, "(AlreadyExists)"):
+			found = true
+		default:
+			return false
+		}
+	}
+	return found
+}
+
+// isFieldLabelNotSupported tells whether kubectl failed because the server does not support a field of
+// the --field-selector for the resource, e.g. for most fields of custom resources
+func isFieldLabelNotSupported(err error) bool {
+	var kerr *kubectlError
+	return errors.As(err, &kerr) && strings.Contains(kerr.Stderr, "field label not supported")
+}
+
+// formatErrorCategories counts the failed clusters per category, e.g. "3 Forbidden, 1 Timeout".
+// It returns "" when no cluster failed.
+func formatErrorCategories(results []clusterResult) string {
+	counts := make(map[string]int)
+	for _, r := range results {
+		if r.Err == nil {
+			continue
+		}
+		if r.TimedOut {
+			counts[categoryTimeout]++
+			continue
+		}
+		counts[classifyError(r.Err)]++
+	}
+
+	var parts []string
+	for _, category := range errorCategories {
+		if counts[category] > 0 {
+			parts = append(parts, fmt.Sprintf("%d %s", counts[category], category))
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// errorHints are the remediation hints printed after a cluster failure with --explain-errors, by category
+var errorHints = map[string]string{
+	categoryNotFound:    "the resource does not exist in this cluster; when deleting with -f it may already have been deleted, otherwise check the name and namespace (-n)",
+	categoryForbidden:   "your credentials for this context lack permission; check RBAC with 'kubectl auth can-i --context <context>' or refresh the credentials",
+	categoryTimeout:     "the cluster did not answer in time; retry with a longer --timeout (or --timeout-override for this cluster) and check the API server load",
+	categoryUnreachable: "the API server could not be reached; check the VPN or network, and the server address of the context in your kubeconfig",
+}
+
+// errorHint returns the remediation hint for a cluster failure, or "" when there is none
+func errorHint(err error, timedOut bool) string {
+	if timedOut {
+		return errorHints[categoryTimeout]
+	}
+	return errorHints[classifyError(err)]
+}
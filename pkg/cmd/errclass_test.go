@@ -0,0 +1,119 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"kubectl-multi/pkg/cluster"
+)
+
+// TestClassifyError ensures kubectl failures are grouped by their stderr
+func TestClassifyError(t *testing.T) {
+	exitErr := errors.New("exit status 1")
+	tests := []struct {
+		err  error
+		want string
+	}{
+		{&kubectlError{ExitCode: 1, Stderr: `Error from server (NotFound): deployments.apps "web" not found`, Err: exitErr}, categoryNotFound},
+		{&kubectlError{ExitCode: 1, Stderr: `Error from server (Forbidden): pods is forbidden: User "dev" cannot list resource "pods"`, Err: exitErr}, categoryForbidden},
+		{&kubectlError{ExitCode: 1, Stderr: "error: You must be logged in to the server (Unauthorized)", Err: exitErr}, categoryForbidden},
+		{&kubectlError{ExitCode: 1, Stderr: "Unable to connect to the server: dial tcp 10.0.0.1:6443: connect: connection refused", Err: exitErr}, categoryUnreachable},
+		{&kubectlError{ExitCode: 1, Stderr: "Unable to connect to the server: dial tcp 10.0.0.1:6443: i/o timeout", Err: exitErr}, categoryUnreachable},
+		{errors.New("dial tcp 10.0.0.1:6443: i/o timeout"), categoryUnreachable},
+		{&kubectlError{ExitCode: 1, Stderr: "Error from server (Timeout): the server was unable to return a response in the time allotted", Err: exitErr}, categoryTimeout},
+		{&kubectlError{ExitCode: 1, Stderr: `error: the server doesn't have a resource type "foo"`, Err: exitErr}, categoryOther},
+		{fmt.Errorf("wait failed: %w", context.DeadlineExceeded), categoryTimeout},
+		{errors.New("unreachable after 3 attempts: connection refused"), categoryUnreachable},
+	}
+
+	for _, tt := range tests {
+		if got := classifyError(tt.err); got != tt.want {
+			t.Errorf("classifyError(%v): expected %s, got %s", tt.err, tt.want, got)
+		}
+	}
+}
+
+// TestIsAlreadyExists ensures only failures made entirely of AlreadyExists errors are recognized
+func TestIsAlreadyExists(t *testing.T) {
+	exitErr := errors.New("exit status 1")
+	exists := `Error from server (AlreadyExists): error when creating "app.yaml": deployments.apps "web" already exists`
+	tests := []struct {
+		err  error
+		want bool
+	}{
+		{&kubectlError{ExitCode: 1, Stderr: exists + "\n", Err: exitErr}, true},
+		{&kubectlError{ExitCode: 1, Stderr: exists + "\n" + strings.Replace(exists, "deployments.apps \"web\"", "services \"web\"", 1), Err: exitErr}, true},
+		{&kubectlError{ExitCode: 1, Stderr: "Warning: spec.template: deprecated\n" + exists, Err: exitErr}, true},
+		{&kubectlError{ExitCode: 1, Stderr: exists + "\nError from server (Forbidden): services is forbidden", Err: exitErr}, false},
+		{&kubectlError{ExitCode: 1, Stderr: `Error from server (NotFound): namespaces "shop" not found`, Err: exitErr}, false},
+		{&kubectlError{ExitCode: 1, Stderr: "", Err: exitErr}, false},
+		{&kubectlError{ExitCode: -1, Stderr: exists, Err: exitErr}, false},
+		{errors.New(exists), false},
+	}
+
+	for _, tt := range tests {
+		if got := isAlreadyExists(tt.err); got != tt.want {
+			t.Errorf("isAlreadyExists(%v): expected %v, got %v", tt.err, tt.want, got)
+		}
+	}
+}
+
+// TestFormatErrorCategories ensures failures are counted per category in a fixed order
+func TestFormatErrorCategories(t *testing.T) {
+	forbidden := &kubectlError{ExitCode: 1, Stderr: "Error from server (Forbidden): forbidden", Err: errors.New("exit status 1")}
+	results := []clusterResult{
+		{Context: "wds1"},
+		{Context: "wds2", Err: forbidden},
+		{Context: "wds3", Err: errors.New("timed out after 30s"), TimedOut: true},
+		{Context: "wds4", Err: forbidden},
+		{Context: "wds5", Err: forbidden},
+		{Context: "wds6", Skipped: "namespace does not exist"},
+	}
+
+	if got := formatErrorCategories(results); got != "3 Forbidden, 1 Timeout" {
+		t.Errorf("unexpected categories %q", got)
+	}
+	if got := formatErrorCategories(results[:1]); got != "" {
+		t.Errorf("expected no categories without failures, got %q", got)
+	}
+}
+
+// TestErrorHint ensures every classified category has a hint and unclassified failures have none
+func TestErrorHint(t *testing.T) {
+	forbidden := newKubectlError(errors.New("exit status 1"), `Error from server (Forbidden): pods is forbidden: User "dev" cannot list resource "pods"`)
+	if hint := errorHint(forbidden, false); !strings.Contains(hint, "RBAC") {
+		t.Errorf("expected an RBAC hint, got %q", hint)
+	}
+	unreachable := newKubectlError(errors.New("exit status 1"), "Unable to connect to the server: dial tcp: no such host")
+	if hint := errorHint(unreachable, false); !strings.Contains(hint, "VPN") {
+		t.Errorf("expected a connectivity hint, got %q", hint)
+	}
+	if hint := errorHint(errors.New("timed out after 30s"), true); !strings.Contains(hint, "--timeout") {
+		t.Errorf("expected a timeout hint, got %q", hint)
+	}
+	if hint := errorHint(errors.New("something odd"), false); hint != "" {
+		t.Errorf("expected no hint for an unclassified failure, got %q", hint)
+	}
+}
+
+// TestRunOnClusterExplainErrors ensures the hint is only printed with --explain-errors
+func TestRunOnClusterExplainErrors(t *testing.T) {
+	defer func(v bool) { explainErrors = v }(explainErrors)
+	op := func(ctx context.Context, c cluster.ClusterInfo, out io.Writer) error {
+		return newKubectlError(errors.New("exit status 1"), "Error from server (NotFound): deployments.apps \"web\" not found")
+	}
+
+	for _, explain := range []bool{false, true} {
+		explainErrors = explain
+		var buf bytes.Buffer
+		runOnCluster(context.Background(), cluster.ClusterInfo{Context: "wds1"}, fanOutOptions{}, op, &buf)
+		if got := strings.Contains(buf.String(), "Hint: the resource does not exist"); got != explain {
+			t.Errorf("--explain-errors=%v: unexpected output %q", explain, buf.String())
+		}
+	}
+}
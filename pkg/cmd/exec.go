@@ -0,0 +1,459 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+
+	"kubectl-multi/pkg/cluster"
+
+	"github.com/spf13/cobra"
+)
+
+// defaultExecConcurrency is how many pods exec --all runs the command in at once
+const defaultExecConcurrency = 5
+
+// podTarget identifies a pod in a cluster
+type podTarget struct {
+	Context string
+	Cluster string
+	Pod     string
+	// Containers and DefaultContainer, the default-container annotation, are used to choose the
+	// container when -c is omitted
+	Containers       []string
+	DefaultContainer string
+}
+
+// label returns the prefix printed before the output of the pod, e.g. "[cluster1/web-1]"
+func (p podTarget) label() string {
+	return fmt.Sprintf("[%s/%s]", p.Cluster, p.Pod)
+}
+
+// podExecResult records the outcome of running the command in a single pod
+type podExecResult struct {
+	Target podTarget
+	Output string
+	Err    error
+}
+
+func newExecCommand() *cobra.Command {
+	var container string
+	var selector string
+	var all bool
+	var stdin bool
+	var tty bool
+	var concurrency int
+	var maxPerCluster int
+	var verbose bool
+	var contextEnv string
+	var podRunningTimeout time.Duration
+
+	cmd := &cobra.Command{
+		Use:   "exec (POD | -l SELECTOR --all) [-c CONTAINER] -- COMMAND [args...]",
+		Short: "Execute a command in a container across managed clusters",
+		Long: `Execute a command in a container across all managed clusters.
+With a pod name, the command runs in that pod in every cluster. With --all, the command runs in
+every running pod matching the -l selector in every cluster and each output line is prefixed
+with [cluster/pod].
+
+Without -c, the container is chosen in every cluster from the pod found there: the one named by the
+kubectl.kubernetes.io/default-container annotation, else the only container. Pods with several
+containers and no annotation use their first container, but fail with --all. --verbose prints the
+container chosen in each cluster.
+
+--context-env NAME sets the environment variable NAME to the context of the cluster in the command,
+by running it through env in the container (env NAME=context COMMAND...), so that scripts in the pods
+can tag their output with their cluster. The container image must provide env.
+
+--pod-running-timeout waits, in every cluster, for the pod to be running before running the command,
+for pods that were just created and start at different speeds. With --all, the pods that are not
+running yet are then included and waited for too. The wait counts against the per-cluster --timeout.
+
+With --all, --max-concurrent-per-cluster caps the pods the command runs in at once in the same cluster,
+for API servers that throttle aggressively, while --concurrency caps them across all clusters. As --all
+runs the command pod by pod, the fan-out flags --pre-hook, --post-hook, --record-event, --fail-fast,
+--max-errors, --timeout-total, --stagger and --count-only cannot be used with it.`,
+		Example: `# Print the hostname of the web-1 pod in every cluster
+kubectl multi exec web-1 -- hostname
+
+# Run a command in every pod labelled app=web in every cluster
+kubectl multi exec -l app=web --all -- cat /etc/hostname
+
+# Let a collection script in every pod know its cluster from $CLUSTER_CONTEXT
+kubectl multi exec -l app=web --all --context-env CLUSTER_CONTEXT -- /scripts/collect.sh
+
+# Run a command in a freshly created pod, waiting up to a minute in every cluster for it to start
+kubectl multi exec web-1 --pod-running-timeout 1m -- cat /ready
+
+# Show which container the command runs in, in every cluster
+kubectl multi exec web-1 --verbose -- env
+
+# Open a shell in a pod of a single cluster
+kubectl multi exec web-1 -it --clusters cluster1 -- sh`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			dash := cmd.ArgsLenAtDash()
+			if dash < 0 || dash == len(args) {
+				return fmt.Errorf("a command must be specified after --")
+			}
+			podArgs, command := args[:dash], args[dash:]
+
+			if contextEnv != "" && !envVarName.MatchString(contextEnv) {
+				return fmt.Errorf("--context-env %q is not a valid environment variable name", contextEnv)
+			}
+			if podRunningTimeout < 0 {
+				return fmt.Errorf("--pod-running-timeout must not be negative")
+			}
+			if podRunningTimeout > 0 && clusterTimeout > 0 && podRunningTimeout >= clusterTimeout {
+				fmt.Printf("Warning: --pod-running-timeout (%s) is not shorter than --timeout (%s), which stops waiting first\n", podRunningTimeout, clusterTimeout)
+			}
+			kubeconfig, remoteCtx, _, namespace, _ := GetGlobalFlags()
+			if all {
+				if stdin || tty {
+					return fmt.Errorf("-i and -t cannot be used with --all, it only runs non-interactive commands")
+				}
+				if selector == "" || len(podArgs) > 0 {
+					return fmt.Errorf("--all requires a -l selector instead of a pod name")
+				}
+				if set := execAllUnsupportedFlags(); len(set) > 0 {
+					return fmt.Errorf("%s cannot be used with --all, which runs the command pod by pod rather than cluster by cluster", strings.Join(set, ", "))
+				}
+				return handleExecAllCommand(selector, container, command, concurrency, maxPerCluster, verbose, contextEnv, podRunningTimeout, kubeconfig, remoteCtx, namespace)
+			}
+
+			if len(podArgs) != 1 {
+				return fmt.Errorf("exactly one pod name must be specified, or use -l with --all")
+			}
+			return handleExecCommand(podArgs[0], container, command, stdin, tty, verbose, contextEnv, podRunningTimeout, kubeconfig, remoteCtx, namespace)
+		},
+	}
+
+	cmd.Flags().StringVarP(&container, "container", "c", "", "container name, defaults to the pod's default container")
+	cmd.Flags().StringVarP(&selector, "selector", "l", "", "selector (label query) of the pods to run the command in, used with --all")
+	cmd.Flags().BoolVar(&all, "all", false, "run the command in every running pod matching the selector in every cluster")
+	cmd.Flags().BoolVarP(&stdin, "stdin", "i", false, "pass stdin to the container, requires a single target cluster")
+	cmd.Flags().BoolVarP(&tty, "tty", "t", false, "stdin is a TTY, requires a single target cluster")
+	cmd.Flags().IntVar(&concurrency, "concurrency", defaultExecConcurrency, "number of pods to run the command in at once with --all")
+	cmd.Flags().IntVar(&maxPerCluster, "max-concurrent-per-cluster", 0, "number of pods of the same cluster to run the command in at once with --all (0 means unlimited)")
+	cmd.Flags().StringVar(&contextEnv, "context-env", "", "set this environment variable to the cluster's context in the command, by running it with env in the container")
+	cmd.Flags().BoolVar(&verbose, "verbose", false, "print the container chosen in every cluster when -c is omitted")
+	cmd.Flags().DurationVar(&podRunningTimeout, "pod-running-timeout", 0, "wait this long in every cluster for the pod to be running before running the command, e.g. 1m (0 does not wait)")
+
+	return cmd
+}
+
+// buildExecArgs builds the kubectl exec arguments for a pod in a cluster
+func buildExecArgs(pod, container, namespace, context string, interactive, tty bool, command []string) []string {
+	args := []string{"exec", pod, "--context", context}
+	if namespace != "" {
+		args = append(args, "-n", namespace)
+	}
+	if container != "" {
+		args = append(args, "-c", container)
+	}
+	if interactive {
+		args = append(args, "-i")
+	}
+	if tty {
+		args = append(args, "-t")
+	}
+	return append(append(args, "--"), command...)
+}
+
+func handleExecCommand(pod, container string, command []string, stdin, tty, verbose bool, contextEnv string, podRunningTimeout time.Duration, kubeconfig, remoteCtx, namespace string) error {
+	clusters, unreachable, err := discoverClusters(kubeconfig, remoteCtx)
+	if err != nil {
+		return fmt.Errorf("failed to discover clusters: %v", err)
+	}
+	if len(clusters) == 0 {
+		return fmt.Errorf("no clusters discovered")
+	}
+
+	// Interactive sessions need the terminal, so they are only possible with one cluster
+	if stdin || tty {
+		targets, _ := fanOutTargets(clusters, currentKubeContext(kubeconfig), itsContext(remoteCtx))
+		if len(targets) != 1 {
+			return fmt.Errorf("-i and -t require a single target cluster, select one with --clusters")
+		}
+		if err := waitPodRunning(context.Background(), pod, namespace, targets[0].Context, kubeconfig, podRunningTimeout); err != nil {
+			return err
+		}
+		resolved, err := execContainer(pod, container, namespace, targets[0], kubeconfig, verbose, os.Stdout)
+		if err != nil {
+			return err
+		}
+		return runKubectlInteractive(buildExecArgs(pod, resolved, namespace, targets[0].Context, stdin, tty, withContextEnv(contextEnv, targets[0].Context, command)), kubeconfig)
+	}
+
+	// The same pod may have different containers in each cluster, so the container is chosen per cluster
+	opts := fanOutOptions{Namespace: namespaceOption(namespace, false), Unreachable: unreachable}
+	_, err = fanOut(clusters, kubeconfig, remoteCtx, opts, func(ctx context.Context, c cluster.ClusterInfo, out io.Writer) error {
+		if err := waitPodRunning(ctx, pod, namespace, c.Context, kubeconfig, podRunningTimeout); err != nil {
+			return err
+		}
+		resolved, err := execContainer(pod, container, namespace, c, kubeconfig, verbose, out)
+		if err != nil {
+			return err
+		}
+		return runKubectlTo(ctx, buildExecArgs(pod, resolved, namespace, c.Context, false, false, withContextEnv(contextEnv, c.Context, command)), kubeconfig, out)
+	})
+	return err
+}
+
+// waitPodRunning waits up to timeout for the pod to be running in the cluster of context, or until ctx is
+// done. It returns at once when timeout is 0.
+func waitPodRunning(ctx context.Context, pod, namespace, context, kubeconfig string, timeout time.Duration) error {
+	if timeout <= 0 {
+		return nil
+	}
+	args := []string{"wait", "pod/" + pod, "--for=jsonpath={.status.phase}=Running", "--timeout=" + timeout.String(), "--context", context}
+	if namespace != "" {
+		args = append(args, "-n", namespace)
+	}
+	var out bytes.Buffer
+	if err := runKubectlTo(ctx, args, kubeconfig, &out); err != nil {
+		if ctx.Err() != nil {
+			return err
+		}
+		return fmt.Errorf("pod %s did not become running within %s: %s", pod, timeout, strings.TrimSpace(out.String()))
+	}
+	return nil
+}
+
+// envVarName matches the names accepted by --context-env
+var envVarName = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// withContextEnv wraps command in `env NAME=context` so that it sees the context of its cluster in the
+// environment variable NAME, or returns it unchanged when name is empty
+func withContextEnv(name, context string, command []string) []string {
+	if name == "" {
+		return command
+	}
+	return append([]string{"env", name + "=" + context}, command...)
+}
+
+// runKubectlInteractive runs kubectl attached to the terminal
+func runKubectlInteractive(args []string, kubeconfig string) error {
+	cmd := exec.Command(kubectlBinary(args), args...)
+	if kubeconfig != "" {
+		cmd.Env = append(os.Environ(), "KUBECONFIG="+kubeconfig)
+	}
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+	return cmd.Run()
+}
+
+// execAllUnsupportedFlags returns the global flags that are set but do not apply to exec --all, as it
+// runs the command in every pod instead of fanning out over the clusters
+func execAllUnsupportedFlags() []string {
+	var set []string
+	for _, f := range []struct {
+		name string
+		set  bool
+	}{
+		{"--pre-hook", preHook != ""},
+		{"--post-hook", postHook != ""},
+		{"--record-event", recordEvent},
+		{"--fail-fast", failFast},
+		{"--max-errors", maxErrors > 0},
+		{"--timeout-total", totalTimeout > 0},
+		{"--stagger", stagger > 0},
+		{"--count-only", countOnly},
+	} {
+		if f.set {
+			set = append(set, f.name)
+		}
+	}
+	return set
+}
+
+func handleExecAllCommand(selector, container string, command []string, concurrency, maxPerCluster int, verbose bool, contextEnv string, podRunningTimeout time.Duration, kubeconfig, remoteCtx, namespace string) error {
+	clusters, unreachable, err := discoverClusters(kubeconfig, remoteCtx)
+	if err != nil {
+		return fmt.Errorf("failed to discover clusters: %v", err)
+	}
+	if len(clusters) == 0 {
+		return fmt.Errorf("no clusters discovered")
+	}
+
+	targets, _ := fanOutTargets(clusters, currentKubeContext(kubeconfig), itsContext(remoteCtx))
+	// When waiting for pods to run, those still starting are included
+	pods := listPodTargets(targets, kubeconfig, selector, namespace, podRunningTimeout > 0)
+	if len(pods) == 0 {
+		fmt.Printf("No running pods matching %q found in any cluster\n", selector)
+		if len(unreachable) == 0 {
+			return nil
+		}
+		multiErr := &cluster.MultiClusterError{Total: len(unreachable)}
+		reportUnreachable(multiErr, unreachable)
+		return multiErr
+	}
+
+	results := execInPods(pods, concurrency, maxPerCluster, func(p podTarget, out io.Writer) error {
+		// Each pod gets the per-cluster --timeout of its cluster, which bounds the wait too
+		ctx := context.Background()
+		if timeout := timeoutFor(cluster.ClusterInfo{Context: p.Context}); timeout > 0 {
+			var cancel context.CancelFunc
+			ctx, cancel = context.WithTimeout(ctx, timeout)
+			defer cancel()
+		}
+		if err := waitPodRunning(ctx, p.Pod, namespace, p.Context, kubeconfig, podRunningTimeout); err != nil {
+			return err
+		}
+
+		resolved := container
+		if resolved == "" {
+			// Without a terminal to notice the wrong container, pods with several containers must say which one
+			var reason string
+			var err error
+			if resolved, reason, err = p.resolveContainer(true); err != nil {
+				return err
+			}
+			if verbose {
+				fmt.Fprintf(out, "Using container %s (%s)\n", resolved, reason)
+			}
+		}
+		return runKubectlTo(ctx, buildExecArgs(p.Pod, resolved, namespace, p.Context, false, false, withContextEnv(contextEnv, p.Context, command)), kubeconfig, out)
+	})
+
+	multiErr := &cluster.MultiClusterError{Total: len(results) + len(unreachable)}
+	failed := 0
+	for _, r := range results {
+		fmt.Print(prefixLines(r.Target.label(), r.Output))
+		if r.Err != nil {
+			fmt.Printf("%s Error: %v\n", r.Target.label(), r.Err)
+			multiErr.Errors = append(multiErr.Errors, cluster.ClusterError{Context: r.Target.Context + "/" + r.Target.Pod, Err: r.Err})
+			failed++
+		}
+	}
+	reportUnreachable(multiErr, unreachable)
+	fmt.Printf("\nSummary: ran in %d pods, %d failed", len(results), failed)
+	if len(unreachable) > 0 {
+		fmt.Printf(", %d clusters unreachable", len(unreachable))
+	}
+	fmt.Println()
+	if len(multiErr.Errors) > 0 {
+		return multiErr
+	}
+	return nil
+}
+
+// reportUnreachable prints the clusters discovery could not reach, whose pods could not be listed, as
+// failed and adds them to multiErr
+func reportUnreachable(multiErr *cluster.MultiClusterError, unreachable []cluster.ClusterInfo) {
+	for _, c := range unreachable {
+		fmt.Printf("[%s] Error: %v\n", c.Display(), c.DiscoveryErr)
+		multiErr.Errors = append(multiErr.Errors, cluster.ClusterError{Context: c.Context, Err: c.DiscoveryErr})
+	}
+}
+
+// listPodTargets enumerates the running pods matching the selector in every cluster, and with starting
+// the pods that may still start running, i.e. those that have not completed
+func listPodTargets(clusters []cluster.ClusterInfo, kubeconfig, selector, namespace string, starting bool) []podTarget {
+	phases := "--field-selector=status.phase=Running"
+	if starting {
+		phases = "--field-selector=status.phase!=Succeeded,status.phase!=Failed"
+	}
+	var pods []podTarget
+	for _, c := range clusters {
+		args := []string{"get", "pods", "-l", selector, phases, "-o", "json", "--context", c.Context}
+		if namespace != "" {
+			args = append(args, "-n", namespace)
+		}
+		output, err := runKubectl(args, kubeconfig)
+		if err != nil {
+			fmt.Printf("Warning: failed to list pods in cluster %s: %v: %s\n", c.Display(), err, strings.TrimSpace(output))
+			continue
+		}
+		found, err := parsePodContainers([]byte(output))
+		if err != nil {
+			fmt.Printf("Warning: failed to list pods in cluster %s: %v\n", c.Display(), err)
+			continue
+		}
+		for _, p := range found {
+			p.Context, p.Cluster = c.Context, c.Display()
+			pods = append(pods, p)
+		}
+	}
+	return pods
+}
+
+// execInPods runs run for every pod with at most concurrency running at once, and at most
+// maxPerCluster in the same cluster, returning the results in the order of pods
+func execInPods(pods []podTarget, concurrency, maxPerCluster int, run func(p podTarget, out io.Writer) error) []podExecResult {
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	results := make([]podExecResult, len(pods))
+	sem := make(chan struct{}, concurrency)
+	perCluster := newClusterSemaphores(maxPerCluster)
+
+	var wg sync.WaitGroup
+	for i, p := range pods {
+		wg.Add(1)
+		go func(i int, p podTarget) {
+			defer wg.Done()
+			// Wait for the cluster first so a throttled cluster does not hold global slots
+			release := perCluster.acquire(p.Context)
+			defer release()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			var out bytes.Buffer
+			err := run(p, &out)
+			results[i] = podExecResult{Target: p, Output: out.String(), Err: err}
+		}(i, p)
+	}
+	wg.Wait()
+	return results
+}
+
+// prefixLines prefixes every line of output with prefix
+func prefixLines(prefix, output string) string {
+	if output == "" {
+		return ""
+	}
+	var b strings.Builder
+	for _, line := range strings.Split(strings.TrimSuffix(output, "\n"), "\n") {
+		fmt.Fprintf(&b, "%s %s\n", prefix, line)
+	}
+	return b.String()
+}
+
+// clusterSemaphores limits how many operations run at once against each cluster, for exec --all,
+// which runs the command in several pods per cluster. A limit of 0 or less means unlimited.
+type clusterSemaphores struct {
+	limit int
+	mu    sync.Mutex
+	sems  map[string]chan struct{}
+}
+
+// newClusterSemaphores returns semaphores allowing limit concurrent operations per cluster
+func newClusterSemaphores(limit int) *clusterSemaphores {
+	return &clusterSemaphores{limit: limit, sems: make(map[string]chan struct{})}
+}
+
+// acquire blocks until an operation can run against the cluster of the given context,
+// and returns the function releasing the slot
+func (s *clusterSemaphores) acquire(context string) func() {
+	if s.limit <= 0 {
+		return func() {}
+	}
+	s.mu.Lock()
+	sem, ok := s.sems[context]
+	if !ok {
+		sem = make(chan struct{}, s.limit)
+		s.sems[context] = sem
+	}
+	s.mu.Unlock()
+
+	sem <- struct{}{}
+	return func() { <-sem }
+}
@@ -0,0 +1,258 @@
+package cmd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"kubectl-multi/pkg/cluster"
+)
+
+// TestPrefixLines ensures every output line is prefixed with the pod label
+func TestPrefixLines(t *testing.T) {
+	label := podTarget{Cluster: "cluster1", Pod: "web-1"}.label()
+	if got := prefixLines(label, "a\nb\n"); got != "[cluster1/web-1] a\n[cluster1/web-1] b\n" {
+		t.Errorf("unexpected output %q", got)
+	}
+	if got := prefixLines(label, ""); got != "" {
+		t.Errorf("expected no output for an empty command output, got %q", got)
+	}
+}
+
+// TestExecInPodsConcurrency ensures the command runs in every pod, at most concurrency at once,
+// and per-pod errors are collected in pod order
+func TestExecInPodsConcurrency(t *testing.T) {
+	var pods []podTarget
+	for i := 0; i < 8; i++ {
+		pods = append(pods, podTarget{Context: "cluster1", Cluster: "cluster1", Pod: fmt.Sprintf("web-%d", i)})
+	}
+
+	var mu sync.Mutex
+	running, maxRunning := 0, 0
+	results := execInPods(pods, 3, 0, func(p podTarget, out io.Writer) error {
+		mu.Lock()
+		running++
+		if running > maxRunning {
+			maxRunning = running
+		}
+		mu.Unlock()
+		defer func() {
+			mu.Lock()
+			running--
+			mu.Unlock()
+		}()
+
+		fmt.Fprintln(out, p.Pod)
+		if p.Pod == "web-5" {
+			return errors.New("command terminated with exit code 1")
+		}
+		return nil
+	})
+
+	if maxRunning > 3 {
+		t.Errorf("expected at most 3 concurrent execs, got %d", maxRunning)
+	}
+	for i, r := range results {
+		if r.Target.Pod != pods[i].Pod || r.Output != pods[i].Pod+"\n" {
+			t.Errorf("result %d out of order: %+v", i, r)
+		}
+		if (r.Err != nil) != (r.Target.Pod == "web-5") {
+			t.Errorf("unexpected error for %s: %v", r.Target.Pod, r.Err)
+		}
+	}
+}
+
+// TestExecInPodsPerClusterLimit ensures --max-concurrent-per-cluster caps the execs in each cluster
+// while the global concurrency still lets other clusters run
+func TestExecInPodsPerClusterLimit(t *testing.T) {
+	var pods []podTarget
+	for i := 0; i < 6; i++ {
+		for _, c := range []string{"cluster1", "cluster2"} {
+			pods = append(pods, podTarget{Context: c, Cluster: c, Pod: fmt.Sprintf("web-%d", i)})
+		}
+	}
+
+	var mu sync.Mutex
+	running := make(map[string]int)
+	maxRunning := make(map[string]int)
+	totalRunning, maxTotal := 0, 0
+	execInPods(pods, 8, 2, func(p podTarget, out io.Writer) error {
+		mu.Lock()
+		running[p.Context]++
+		totalRunning++
+		if running[p.Context] > maxRunning[p.Context] {
+			maxRunning[p.Context] = running[p.Context]
+		}
+		if totalRunning > maxTotal {
+			maxTotal = totalRunning
+		}
+		mu.Unlock()
+
+		time.Sleep(5 * time.Millisecond)
+
+		mu.Lock()
+		running[p.Context]--
+		totalRunning--
+		mu.Unlock()
+		return nil
+	})
+
+	for c, n := range maxRunning {
+		if n > 2 {
+			t.Errorf("expected at most 2 concurrent execs in %s, got %d", c, n)
+		}
+	}
+	if maxTotal > 4 {
+		t.Errorf("expected at most 4 concurrent execs across 2 clusters, got %d", maxTotal)
+	}
+}
+
+// TestWithContextEnv ensures the command is run through env with the context only when asked to
+func TestWithContextEnv(t *testing.T) {
+	command := []string{"sh", "-c", "echo $CLUSTER_CONTEXT"}
+	got := withContextEnv("CLUSTER_CONTEXT", "cluster1", command)
+	want := []string{"env", "CLUSTER_CONTEXT=cluster1", "sh", "-c", "echo $CLUSTER_CONTEXT"}
+	if strings.Join(got, " ") != strings.Join(want, " ") {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+	if got := withContextEnv("", "cluster1", command); len(got) != len(command) {
+		t.Errorf("expected the command unchanged, got %v", got)
+	}
+
+	for name, valid := range map[string]bool{"CLUSTER_CONTEXT": true, "_ctx1": true, "1CTX": false, "CLUSTER-CONTEXT": false, "A=B": false} {
+		if envVarName.MatchString(name) != valid {
+			t.Errorf("expected %q valid=%v", name, valid)
+		}
+	}
+}
+
+// fakeWaitKubectl is a kubectl where the pod runs in cluster1, never starts in cluster2 and is slow
+// to start in cluster3
+const fakeWaitKubectl = `#!/bin/sh
+case "$*" in
+*"--context cluster2"*) echo 'error: timed out waiting for the condition on pods/web-1' >&2; exit 1;;
+*"--context cluster3"*) exec sleep 5;;
+esac
+echo 'pod/web-1 condition met'
+`
+
+// TestWaitPodRunning ensures the wait is skipped without a timeout, and reports the clusters where the
+// pod never ran or where --timeout stopped the wait
+func TestWaitPodRunning(t *testing.T) {
+	installFakeKubectl(t, fakeWaitKubectl)
+
+	if err := waitPodRunning(context.Background(), "web-1", "", "cluster2", "", 0); err != nil {
+		t.Errorf("expected no wait without a timeout, got %v", err)
+	}
+	if err := waitPodRunning(context.Background(), "web-1", "", "cluster1", "", time.Minute); err != nil {
+		t.Errorf("expected the pod to be running in cluster1, got %v", err)
+	}
+	err := waitPodRunning(context.Background(), "web-1", "", "cluster2", "", time.Minute)
+	if err == nil || !strings.Contains(err.Error(), "did not become running within 1m0s") || !strings.Contains(err.Error(), "timed out waiting") {
+		t.Errorf("expected cluster2 to report the pod never ran, got %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+	if err := waitPodRunning(ctx, "web-1", "", "cluster3", "", time.Minute); err == nil || strings.Contains(err.Error(), "did not become running") {
+		t.Errorf("expected the wait in cluster3 to be stopped by the cluster timeout, got %v", err)
+	}
+}
+
+// TestClusterSemaphores ensures slots are limited per context and released for reuse
+func TestClusterSemaphores(t *testing.T) {
+	sems := newClusterSemaphores(1)
+	release := sems.acquire("wds1")
+
+	// Another cluster is not affected by wds1 being busy
+	done := make(chan struct{})
+	go func() {
+		sems.acquire("wds2")()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("expected wds2 not to wait for wds1")
+	}
+
+	acquired := make(chan struct{})
+	go func() {
+		sems.acquire("wds1")()
+		close(acquired)
+	}()
+	select {
+	case <-acquired:
+		t.Fatal("expected the second wds1 operation to wait for the first")
+	case <-time.After(20 * time.Millisecond):
+	}
+	release()
+	select {
+	case <-acquired:
+	case <-time.After(time.Second):
+		t.Fatal("expected the second wds1 operation to run once the slot was released")
+	}
+
+	// No limit never blocks
+	unlimited := newClusterSemaphores(0)
+	unlimited.acquire("wds1")
+	unlimited.acquire("wds1")()
+}
+
+// fakeExecAllKubectl is a kubectl where every cluster runs the pod web-1 with a single container
+const fakeExecAllKubectl = `#!/bin/sh
+case "$1" in
+get) echo '{"kind":"PodList","items":[{"metadata":{"name":"web-1"},"spec":{"containers":[{"name":"app"}]}}]}';;
+exec) echo hello;;
+esac
+`
+
+// TestExecAllUnreachable ensures exec --all reports the clusters discovery could not reach as failed,
+// next to the pods of the others
+func TestExecAllUnreachable(t *testing.T) {
+	installFakeKubectl(t, fakeExecAllKubectl)
+	kubeconfig := useFakeContexts(t, "cluster1", "cluster2")
+	path, err := reachabilityCacheFile()
+	if err != nil {
+		t.Fatal(err)
+	}
+	cache, _ := cluster.LoadReachabilityCache(path, time.Minute)
+	cache.Record("cluster2", errors.New("dial tcp 10.0.0.2:6443: connect: connection refused"))
+	if err := cache.Save(); err != nil {
+		t.Fatal(err)
+	}
+
+	stdout, _ := captureOutput(t, func() {
+		err = handleExecAllCommand("app=web", "", []string{"hostname"}, 5, 0, false, "", 0, kubeconfig, "", "")
+	})
+	if err == nil {
+		t.Error("expected the unreachable cluster to fail exec --all")
+	}
+	for _, want := range []string{"[cluster1/web-1] hello", "[cluster2] Error: ", "connection refused", "Summary: ran in 1 pods, 0 failed, 1 clusters unreachable"} {
+		if !strings.Contains(stdout, want) {
+			t.Errorf("expected the output to contain %q, got:\n%s", want, stdout)
+		}
+	}
+}
+
+// TestExecAllUnsupportedFlags ensures exec --all rejects the fan-out flags it does not apply
+func TestExecAllUnsupportedFlags(t *testing.T) {
+	defer func(pre string, count bool, total time.Duration) {
+		preHook, countOnly, totalTimeout = pre, count, total
+	}(preHook, countOnly, totalTimeout)
+	preHook, countOnly, totalTimeout = "true", true, time.Minute
+
+	cmd := newExecCommand()
+	if err := cmd.ParseFlags([]string{"-l", "app=web", "--all", "--", "hostname"}); err != nil {
+		t.Fatal(err)
+	}
+	err := cmd.RunE(cmd, cmd.Flags().Args())
+	if err == nil || !strings.Contains(err.Error(), "--pre-hook, --timeout-total, --count-only cannot be used with --all") {
+		t.Errorf("expected the fan-out flags to be rejected, got %v", err)
+	}
+}
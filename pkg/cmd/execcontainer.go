@@ -0,0 +1,118 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"kubectl-multi/pkg/cluster"
+)
+
+// defaultContainerAnnotation names the container kubectl exec and logs use when -c is omitted
+const defaultContainerAnnotation = "kubectl.kubernetes.io/default-container"
+
+// containerPod is the part of a pod needed to choose the container to exec into
+type containerPod struct {
+	Metadata struct {
+		Name        string            `json:"name"`
+		Annotations map[string]string `json:"annotations"`
+	} `json:"metadata"`
+	Spec struct {
+		Containers []struct {
+			Name string `json:"name"`
+		} `json:"containers"`
+	} `json:"spec"`
+}
+
+// parsePodContainers returns the pods of `kubectl get pods -o json` output, which is either a List or
+// a single pod, with their containers and default-container annotation
+func parsePodContainers(data []byte) ([]podTarget, error) {
+	var obj struct {
+		containerPod
+		Kind  string         `json:"kind"`
+		Items []containerPod `json:"items"`
+	}
+	if err := json.Unmarshal(data, &obj); err != nil {
+		return nil, fmt.Errorf("failed to parse kubectl output: %v", err)
+	}
+
+	pods := []containerPod{obj.containerPod}
+	if strings.HasSuffix(obj.Kind, "List") {
+		pods = obj.Items
+	}
+
+	var targets []podTarget
+	for _, p := range pods {
+		if p.Metadata.Name == "" {
+			continue
+		}
+		t := podTarget{Pod: p.Metadata.Name, DefaultContainer: p.Metadata.Annotations[defaultContainerAnnotation]}
+		for _, c := range p.Spec.Containers {
+			t.Containers = append(t.Containers, c.Name)
+		}
+		targets = append(targets, t)
+	}
+	return targets, nil
+}
+
+// resolveContainer returns the container of the pod to exec into when -c is omitted, and why it was
+// chosen: the container named by the default-container annotation, else the only container. A pod with
+// several containers and no usable annotation gets its first container, as kubectl does, unless strict
+// is set, in which case it is an error.
+func (p podTarget) resolveContainer(strict bool) (string, string, error) {
+	if len(p.Containers) == 0 {
+		return "", "", fmt.Errorf("pod %s has no containers", p.Pod)
+	}
+	if p.DefaultContainer != "" {
+		for _, c := range p.Containers {
+			if c == p.DefaultContainer {
+				return c, defaultContainerAnnotation + " annotation", nil
+			}
+		}
+	}
+	if len(p.Containers) == 1 {
+		return p.Containers[0], "only container", nil
+	}
+
+	reason := "no " + defaultContainerAnnotation + " annotation"
+	if p.DefaultContainer != "" {
+		reason = fmt.Sprintf("%s annotation names missing container %q", defaultContainerAnnotation, p.DefaultContainer)
+	}
+	if strict {
+		return "", "", fmt.Errorf("pod %s has %d containers (%s) and %s, select one with -c",
+			p.Pod, len(p.Containers), strings.Join(p.Containers, ", "), reason)
+	}
+	return p.Containers[0], fmt.Sprintf("first of %d containers, %s", len(p.Containers), reason), nil
+}
+
+// execContainer returns the container to exec into in the pod of a cluster: container if it is set,
+// else the one resolved from the pod in that cluster, which is reported to out when verbose is set
+func execContainer(pod, container, namespace string, c cluster.ClusterInfo, kubeconfig string, verbose bool, out io.Writer) (string, error) {
+	if container != "" {
+		return container, nil
+	}
+	args := []string{"get", "pod", pod, "-o", "json", "--context", c.Context}
+	if namespace != "" {
+		args = append(args, "-n", namespace)
+	}
+	output, err := runKubectl(args, kubeconfig)
+	if err != nil {
+		return "", fmt.Errorf("failed to get pod %s: %v: %s", pod, err, strings.TrimSpace(output))
+	}
+	pods, err := parsePodContainers([]byte(output))
+	if err != nil {
+		return "", err
+	}
+	if len(pods) != 1 {
+		return "", fmt.Errorf("pod %s not found", pod)
+	}
+	resolved, reason, err := pods[0].resolveContainer(false)
+	if err != nil {
+		return "", err
+	}
+	if verbose {
+		fmt.Fprintf(out, "Using container %s in cluster %s (%s)\n", resolved, c.Display(), reason)
+	}
+	return resolved, nil
+}
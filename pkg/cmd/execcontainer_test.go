@@ -0,0 +1,72 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestParsePodContainers ensures pod lists and single pods yield the pod names, containers and
+// default-container annotation
+func TestParsePodContainers(t *testing.T) {
+	list := `{"kind": "List", "items": [
+		{"metadata": {"name": "web-1", "annotations": {"kubectl.kubernetes.io/default-container": "app"}},
+		 "spec": {"containers": [{"name": "istio-proxy"}, {"name": "app"}]}},
+		{"metadata": {"name": "web-2"}, "spec": {"containers": [{"name": "app"}]}}
+	]}`
+	pods, err := parsePodContainers([]byte(list))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(pods) != 2 || pods[0].Pod != "web-1" || pods[1].Pod != "web-2" {
+		t.Fatalf("unexpected pods: %+v", pods)
+	}
+	if pods[0].DefaultContainer != "app" || strings.Join(pods[0].Containers, ",") != "istio-proxy,app" {
+		t.Errorf("unexpected containers of web-1: %+v", pods[0])
+	}
+
+	pods, err = parsePodContainers([]byte(`{"kind": "Pod", "metadata": {"name": "web-1"}, "spec": {"containers": [{"name": "app"}]}}`))
+	if err != nil || len(pods) != 1 || pods[0].Pod != "web-1" {
+		t.Errorf("unexpected single pod: %+v, %v", pods, err)
+	}
+
+	if _, err := parsePodContainers([]byte("not json")); err == nil {
+		t.Error("expected an error for invalid output")
+	}
+}
+
+// TestResolveContainer ensures the annotation wins, a single container is used as is, and pods with
+// several containers and no usable annotation use the first one unless strict
+func TestResolveContainer(t *testing.T) {
+	tests := []struct {
+		name      string
+		pod       podTarget
+		strict    bool
+		container string
+		wantErr   bool
+	}{
+		{"annotation", podTarget{Pod: "web", Containers: []string{"proxy", "app"}, DefaultContainer: "app"}, true, "app", false},
+		{"only container", podTarget{Pod: "web", Containers: []string{"app"}}, true, "app", false},
+		{"stale annotation with one container", podTarget{Pod: "web", Containers: []string{"app"}, DefaultContainer: "old"}, true, "app", false},
+		{"several containers", podTarget{Pod: "web", Containers: []string{"proxy", "app"}}, false, "proxy", false},
+		{"several containers strict", podTarget{Pod: "web", Containers: []string{"proxy", "app"}}, true, "", true},
+		{"stale annotation strict", podTarget{Pod: "web", Containers: []string{"proxy", "app"}, DefaultContainer: "old"}, true, "", true},
+		{"no containers", podTarget{Pod: "web"}, false, "", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			container, reason, err := tt.pod.resolveContainer(tt.strict)
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			if container != tt.container {
+				t.Errorf("expected container %q, got %q", tt.container, container)
+			}
+			if err == nil && reason == "" {
+				t.Error("expected the reason the container was chosen")
+			}
+			if tt.wantErr && len(tt.pod.Containers) > 1 && !strings.Contains(err.Error(), "proxy, app") {
+				t.Errorf("expected the error to list the containers, got %v", err)
+			}
+		})
+	}
+}
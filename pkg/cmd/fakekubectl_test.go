@@ -0,0 +1,24 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+)
+
+// installFakeKubectl makes the shell script the kubectl binary the commands run until the test ends.
+// The test is skipped on Windows, where the script cannot run.
+func installFakeKubectl(t *testing.T, script string) {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("uses a shell script as kubectl")
+	}
+	path := filepath.Join(t.TempDir(), "kubectl")
+	if err := os.WriteFile(path, []byte(script), 0o755); err != nil {
+		t.Fatal(err)
+	}
+	previous := kubectlPath
+	kubectlPath = path
+	t.Cleanup(func() { kubectlPath = previous })
+}
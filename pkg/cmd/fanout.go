@@ -0,0 +1,656 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"kubectl-multi/pkg/cluster"
+
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/clientcmd"
+)
+
+// clusterOp runs an operation against a single cluster, writing its output to out.
+// It should stop when ctx is cancelled.
+type clusterOp func(ctx context.Context, c cluster.ClusterInfo, out io.Writer) error
+
+// fanOutOptions holds per-command settings for a fan-out
+type fanOutOptions struct {
+	// MinServerVersion skips clusters whose API server is older than this version (e.g. "1.22")
+	MinServerVersion string
+	// Namespace is the namespace the operation targets, checked with --validate-namespace.
+	// Leave it empty for cluster-scoped and all-namespaces operations, and when -n is not given.
+	Namespace string
+	// Stream, when set, receives the result of every cluster as soon as it is known instead of the
+	// cluster's banner and output being printed. Messages and the summary then go to stderr.
+	// It is called concurrently with --parallel.
+	Stream func(r clusterResult)
+	// Skip maps the contexts of clusters the operation must not run on to the reason they are skipped
+	Skip map[string]string
+	// NoHooks runs the operation without the --pre-hook and --post-hook commands and --record-event,
+	// e.g. to validate the manifests before the operation itself
+	NoHooks bool
+	// Unreachable are the clusters discovery could not reach, reported as failed after the others
+	Unreachable []cluster.ClusterInfo
+}
+
+// namespaceOption returns the namespace to set in fanOutOptions for a command's -n/-A flags. Without
+// -n it is empty, each cluster using the namespace of its context.
+func namespaceOption(namespace string, allNamespaces bool) string {
+	if allNamespaces {
+		return ""
+	}
+	return namespace
+}
+
+// clusterResult records the outcome of a clusterOp on a single cluster
+type clusterResult struct {
+	Context string
+	// DisplayName is the name shown for the cluster in the summary
+	DisplayName string
+	Output      string
+	Err         error
+	// Skipped holds the reason the operation was not run on the cluster, if any
+	Skipped string
+	// TimedOut is set when the operation was stopped by --timeout
+	TimedOut bool
+}
+
+// currentKubeContext returns the current context of the kubeconfig, or "" if it cannot be loaded
+func currentKubeContext(kubeconfig string) string {
+	loading := clientcmd.NewDefaultClientConfigLoadingRules()
+	if kubeconfig != "" {
+		loading.ExplicitPath = kubeconfig
+	}
+	cfg := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loading, &clientcmd.ConfigOverrides{})
+	rawCfg, err := cfg.RawConfig()
+	if err != nil {
+		return ""
+	}
+	return rawCfg.CurrentContext
+}
+
+// fanOutTargets returns the clusters an operation runs on, the current context first followed by the
+// remaining KubeStellar clusters. The ITS (control) cluster is returned separately as it is never targeted.
+func fanOutTargets(clusters []cluster.ClusterInfo, currentContext, itsContext string) ([]cluster.ClusterInfo, *cluster.ClusterInfo) {
+	var targets []cluster.ClusterInfo
+	var its *cluster.ClusterInfo
+
+	for i, c := range clusters {
+		if c.Context == itsContext {
+			its = &clusters[i]
+			continue
+		}
+		if c.Context == currentContext {
+			targets = append([]cluster.ClusterInfo{c}, targets...)
+			continue
+		}
+		targets = append(targets, c)
+	}
+	return targets, its
+}
+
+// failFastSkipReason is the skip reason of clusters not processed because of --fail-fast
+const failFastSkipReason = "not run because another cluster failed (--fail-fast)"
+
+// maxFailures returns how many clusters may fail before the rest of a fan-out is aborted, 0 meaning unlimited.
+// --fail-fast is the same as --max-errors 1.
+func maxFailures() int {
+	if failFast {
+		return 1
+	}
+	return maxErrors
+}
+
+// abortSkipReason is the skip reason of clusters not processed because the failure limit was reached
+func abortSkipReason() string {
+	if failFast {
+		return failFastSkipReason
+	}
+	return fmt.Sprintf("not run because %d clusters failed (--max-errors)", maxErrors)
+}
+
+// totalTimeoutSkipReason is the skip reason of clusters not started before --timeout-total expired
+func totalTimeoutSkipReason() string {
+	return fmt.Sprintf("not run because --timeout-total (%s) was exceeded", totalTimeout)
+}
+
+// stopSkipReason returns why the clusters not yet started are skipped once ctx is done:
+// either --timeout-total expired or the failure limit was reached
+func stopSkipReason(ctx context.Context) string {
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return totalTimeoutSkipReason()
+	}
+	return abortSkipReason()
+}
+
+// printAbortMessage tells the user the fan-out stopped early
+func printAbortMessage(out io.Writer) {
+	if failFast {
+		fmt.Fprintf(out, "Stopped after the first cluster failure (--fail-fast)\n\n")
+		return
+	}
+	fmt.Fprintf(out, "Stopped after %d cluster failures (--max-errors)\n\n", maxErrors)
+}
+
+// messageStream returns where a fan-out prints its messages and summary: stdout, or stderr when
+// the results are streamed so stdout only holds the stream
+func messageStream(opts fanOutOptions) io.Writer {
+	if opts.Stream != nil {
+		return os.Stderr
+	}
+	return os.Stdout
+}
+
+// fanOut runs op on every target cluster in --context-order, printing a banner and the output of each one,
+// followed by the ITS (control) cluster warning and a summary of the results
+func fanOut(clusters []cluster.ClusterInfo, kubeconfig, remoteCtx string, opts fanOutOptions, op clusterOp) ([]clusterResult, error) {
+	if err := validateContextOrder(contextOrder); err != nil {
+		return nil, err
+	}
+	if err := validateHookFailMode(hookFailMode); err != nil {
+		return nil, err
+	}
+	if err := validateStagger(stagger, parallelism); err != nil {
+		return nil, err
+	}
+	current := ""
+	if contextOrder == contextOrderCurrentFirst {
+		current = currentKubeContext(kubeconfig)
+	}
+	targets, its := fanOutTargets(clusters, current, itsContext(remoteCtx))
+	if contextOrder == contextOrderKubeconfig {
+		orderTargets(targets, contextOrder, kubeconfigContextOrder(kubeconfig))
+	} else {
+		orderTargets(targets, contextOrder, nil)
+	}
+
+	// With --count-only the results are only counted, so the output of every cluster is dropped and the
+	// messages go to stderr, leaving the counts alone on stdout
+	if countOnly {
+		opts.Stream = func(clusterResult) {}
+	}
+	start := time.Now()
+
+	// --timeout-total bounds the whole fan-out, the per-cluster --timeout is derived from the same context
+	ctx, cancel := totalContext()
+	defer cancel()
+
+	progress := newProgressReporter(len(targets))
+	var results []clusterResult
+	if parallelism > 1 {
+		results = fanOutParallel(ctx, cancel, targets, opts, op, progress)
+	} else {
+		results = fanOutSequential(ctx, targets, opts, op, progress)
+	}
+	recordReachability(results)
+	if err := serverVersionCache.Save(); err != nil {
+		fmt.Printf("Warning: %v\n", err)
+	}
+	messages := messageStream(opts)
+	if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		printTotalTimeoutMessage(messages, results)
+	}
+
+	for _, c := range opts.Unreachable {
+		result := clusterResult{Context: c.Context, DisplayName: c.Display(), Err: c.DiscoveryErr}
+		results = append(results, result)
+		if opts.Stream != nil {
+			opts.Stream(result)
+		}
+	}
+
+	// Results are reported in the --sort-clusters order rather than the order clusters completed in, so
+	// two runs of the same command print the same summary
+	sortResults(results, sortClustersBy, append(append([]cluster.ClusterInfo{}, clusters...), opts.Unreachable...))
+
+	printITSNotice(messages, its, len(targets))
+
+	if countOnly {
+		printCountOnlySummary(os.Stdout, results, time.Since(start))
+	} else {
+		printFanOutSummary(messages, results)
+	}
+	return results, fanOutError(results)
+}
+
+// sortResults orders results in place by context name, or with --sort-clusters discovery in the order
+// of the discovered clusters, whatever order they completed in. Ties keep the context name order.
+func sortResults(results []clusterResult, by string, discovered []cluster.ClusterInfo) {
+	rank := make(map[string]int)
+	if by == "discovery" {
+		for i, c := range discovered {
+			if _, ok := rank[c.Context]; !ok {
+				rank[c.Context] = i
+			}
+		}
+	}
+	sort.SliceStable(results, func(i, j int) bool {
+		ri, iok := rank[results[i].Context]
+		rj, jok := rank[results[j].Context]
+		if iok != jok {
+			return iok
+		}
+		if ri != rj {
+			return ri < rj
+		}
+		return results[i].Context < results[j].Context
+	})
+}
+
+// printITSNotice tells the user the operation was not performed on the ITS (control) cluster, if any.
+// The ITS cluster is never a target, --no-its-warning only hides the notice.
+func printITSNotice(out io.Writer, its *cluster.ClusterInfo, total int) {
+	if its == nil || noITSWarning {
+		return
+	}
+	fmt.Fprintln(out, clusterBanner(*its, 0, total))
+	fmt.Fprintf(out, "Cannot perform this operation on ITS (control) cluster: %s\n", its.Display())
+	fmt.Fprintln(out)
+}
+
+// fanOutSequential runs op on one cluster at a time, streaming each cluster's output live under
+// its banner. Output is only buffered when the progress indicator shares the terminal.
+// With --fail-fast or --max-errors, the remaining clusters are skipped once the failure limit is reached,
+// and with --timeout-total once ctx expires. With --stagger, it waits between a cluster the operation
+// ran on and the next one.
+func fanOutSequential(ctx context.Context, targets []cluster.ClusterInfo, opts fanOutOptions, op clusterOp, progress *progressReporter) []clusterResult {
+	var results []clusterResult
+	limit := maxFailures()
+	failures := 0
+	stopped := false
+	ran := false
+	for i, c := range targets {
+		if ran && !stopped && ctx.Err() == nil {
+			waitStagger(ctx, stagger, c, messageStream(opts))
+		}
+		if stopped || ctx.Err() != nil {
+			result := clusterResult{Context: c.Context, DisplayName: c.Display(), Skipped: abortSkipReason()}
+			if !stopped {
+				result.Skipped = stopSkipReason(ctx)
+			}
+			results = append(results, result)
+			if opts.Stream != nil {
+				opts.Stream(result)
+			}
+			continue
+		}
+
+		var result clusterResult
+		if opts.Stream != nil {
+			progress.start(c.Display())
+			result = runOnCluster(ctx, c, opts, op, io.Discard)
+			progress.finish()
+			opts.Stream(result)
+		} else if !progress.enabled {
+			fmt.Println(clusterBanner(c, i+1, len(targets)))
+			result = runOnCluster(ctx, c, opts, op, os.Stdout)
+			fmt.Println()
+		} else {
+			var out bytes.Buffer
+			progress.start(c.Display())
+			result = runOnCluster(ctx, c, opts, op, &out)
+			progress.finish()
+			fmt.Println(clusterBanner(c, i+1, len(targets)))
+			fmt.Print(out.String())
+			fmt.Println()
+		}
+		results = append(results, result)
+		ran = result.Skipped == ""
+
+		if result.Err != nil {
+			failures++
+			if limit > 0 && failures >= limit {
+				stopped = true
+				printAbortMessage(messageStream(opts))
+			}
+		}
+	}
+	return results
+}
+
+// validateStagger checks --stagger, which only applies when clusters are processed one at a time
+func validateStagger(stagger time.Duration, parallelism int) error {
+	if stagger < 0 {
+		return fmt.Errorf("--stagger must not be negative")
+	}
+	if stagger > 0 && parallelism > 1 {
+		return fmt.Errorf("--stagger cannot be used with --parallel greater than 1")
+	}
+	return nil
+}
+
+// waitStagger waits d before the operation starts on the next cluster, telling the user so, or until
+// ctx is done
+func waitStagger(ctx context.Context, d time.Duration, next cluster.ClusterInfo, out io.Writer) {
+	if d <= 0 {
+		return
+	}
+	fmt.Fprintf(out, "Waiting %s before cluster %s (--stagger), press Ctrl+C to stop here\n\n", d, next.Display())
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+	case <-ctx.Done():
+	}
+}
+
+// fanOutParallel runs op on up to --parallel clusters at once. Each cluster's output is buffered
+// and printed in target order once all clusters are done, so outputs never interleave.
+// With --fail-fast or --max-errors, reaching the failure limit cancels ctx, stopping running commands
+// and skipping the rest. When --timeout-total expires, ctx is done and the same happens.
+func fanOutParallel(ctx context.Context, cancel context.CancelFunc, targets []cluster.ClusterInfo, opts fanOutOptions, op clusterOp, progress *progressReporter) []clusterResult {
+	results := make([]clusterResult, len(targets))
+	outputs := make([]bytes.Buffer, len(targets))
+	sem := make(chan struct{}, parallelism)
+
+	limit := maxFailures()
+	failures := 0
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	for i, c := range targets {
+		wg.Add(1)
+		go func(i int, c cluster.ClusterInfo) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			if ctx.Err() != nil {
+				results[i] = clusterResult{Context: c.Context, DisplayName: c.Display(), Skipped: stopSkipReason(ctx)}
+				if opts.Stream != nil {
+					opts.Stream(results[i])
+				}
+				return
+			}
+
+			progress.start(c.Display())
+			result := runOnCluster(ctx, c, opts, op, &outputs[i])
+			progress.finish()
+
+			if limit > 0 && result.Err != nil {
+				mu.Lock()
+				if errors.Is(ctx.Err(), context.Canceled) {
+					// Interrupted because the failure limit was reached by other clusters
+					result.Err = nil
+					result.Skipped = abortSkipReason()
+				} else if failures++; failures >= limit {
+					cancel()
+				}
+				mu.Unlock()
+			}
+			results[i] = result
+			if opts.Stream != nil {
+				opts.Stream(result)
+			}
+		}(i, c)
+	}
+	wg.Wait()
+
+	// Streamed results were already handed over as they completed
+	if opts.Stream == nil {
+		for i, c := range targets {
+			if results[i].Skipped == abortSkipReason() || results[i].Skipped == totalTimeoutSkipReason() {
+				continue
+			}
+			fmt.Println(clusterBanner(c, i+1, len(targets)))
+			fmt.Print(outputs[i].String())
+			fmt.Println()
+		}
+	}
+	if errors.Is(ctx.Err(), context.Canceled) {
+		printAbortMessage(messageStream(opts))
+	}
+	return results
+}
+
+// clusterTimeoutOverrides maps contexts to the per-cluster timeout set for them with --timeout-override
+var clusterTimeoutOverrides map[string]time.Duration
+
+// timeoutFor returns the per-cluster timeout of c: its --timeout-override if any, otherwise --timeout
+func timeoutFor(c cluster.ClusterInfo) time.Duration {
+	if d, ok := clusterTimeoutOverrides[c.Context]; ok {
+		return d
+	}
+	return clusterTimeout
+}
+
+// totalContext returns a context bounded by --timeout-total, if set, for an operation across all clusters
+func totalContext() (context.Context, context.CancelFunc) {
+	if totalTimeout > 0 {
+		return context.WithTimeout(context.Background(), totalTimeout)
+	}
+	return context.WithCancel(context.Background())
+}
+
+// queryCluster runs a kubectl query against a single cluster and returns its stdout, for the listings
+// made outside of a fan-out. Like an operation of the fan-out it is stopped when ctx is done or after
+// the cluster's --timeout, so a cluster that does not answer cannot hang the command.
+func queryCluster(ctx context.Context, c cluster.ClusterInfo, args []string, kubeconfig string) (string, error) {
+	if timeout := timeoutFor(c); timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	var stdout bytes.Buffer
+	if err := runKubectlStdout(ctx, args, kubeconfig, &stdout); err != nil {
+		if ctx.Err() != nil {
+			return "", fmt.Errorf("timed out: %w", ctx.Err())
+		}
+		var kerr *kubectlError
+		if errors.As(err, &kerr) {
+			return "", fmt.Errorf("%w: %s", err, strings.TrimSpace(kerr.Stderr))
+		}
+		return "", err
+	}
+	return stdout.String(), nil
+}
+
+// runOnCluster runs the preflight checks and op, with its hooks, on a single cluster, writing its
+// output to out while also capturing it in the result
+func runOnCluster(ctx context.Context, c cluster.ClusterInfo, opts fanOutOptions, op clusterOp, out io.Writer) clusterResult {
+	result := clusterResult{Context: c.Context, DisplayName: c.Display()}
+	if reason := preflightSkipReason(c, opts); reason != "" {
+		fmt.Fprintf(out, "Skipped: %s\n", reason)
+		result.Skipped = reason
+		return result
+	}
+
+	opCtx := ctx
+	timeout := timeoutFor(c)
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		opCtx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+
+	var captured bytes.Buffer
+	if !opts.NoHooks {
+		op = withRecordEvent(withHooks(op), opts.Namespace)
+	}
+	result.Err = op(opCtx, c, io.MultiWriter(out, &captured))
+	result.Output = captured.String()
+	if result.Err != nil && errors.Is(opCtx.Err(), context.DeadlineExceeded) {
+		result.TimedOut = true
+		if errors.Is(ctx.Err(), context.DeadlineExceeded) {
+			result.Err = fmt.Errorf("stopped by --timeout-total after %s", totalTimeout)
+		} else {
+			result.Err = fmt.Errorf("timed out after %s", timeout)
+		}
+	}
+	if result.Err != nil {
+		fmt.Fprintf(out, "Error: %v\n", result.Err)
+		if hint := errorHint(result.Err, result.TimedOut); explainErrors && hint != "" {
+			fmt.Fprintf(out, "Hint: %s\n", hint)
+		}
+	}
+	return result
+}
+
+// preflightSkipReason checks whether a cluster meets the requirements of the fan-out and
+// returns the reason to skip it, or "" if the operation can run
+func preflightSkipReason(c cluster.ClusterInfo, opts fanOutOptions) string {
+	if reason, ok := opts.Skip[c.Context]; ok {
+		return reason
+	}
+	if opts.MinServerVersion != "" {
+		ok, v, err := serverVersionCache.MeetsMinServerVersion(c, opts.MinServerVersion)
+		if err != nil {
+			fmt.Printf("Warning: could not verify server version of cluster %s: %v\n", c.Context, err)
+		} else if !ok {
+			return fmt.Sprintf("server version %s is older than the required %s", v, opts.MinServerVersion)
+		}
+	}
+	if validateNamespace && opts.Namespace != "" {
+		exists, err := namespaceExists(c, opts.Namespace)
+		if err != nil {
+			fmt.Printf("Warning: could not verify namespace %s in cluster %s: %v\n", opts.Namespace, c.Context, err)
+		} else if !exists {
+			return fmt.Sprintf("namespace %q does not exist", opts.Namespace)
+		}
+	}
+	return ""
+}
+
+// namespaceExists reports whether a namespace exists in the cluster, it is a variable so tests can replace it
+var namespaceExists = func(c cluster.ClusterInfo, namespace string) (bool, error) {
+	if c.Client == nil {
+		return false, fmt.Errorf("no client available for cluster %s", c.Name)
+	}
+	_, err := c.Client.CoreV1().Namespaces().Get(context.TODO(), namespace, metav1.GetOptions{})
+	if apierrors.IsNotFound(err) {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// kubectlOp returns a clusterOp that runs kubectl with the args built for each cluster
+func kubectlOp(kubeconfig string, buildArgs func(c cluster.ClusterInfo) []string) clusterOp {
+	return func(ctx context.Context, c cluster.ClusterInfo, out io.Writer) error {
+		return runKubectlTo(ctx, buildArgs(c), kubeconfig, out)
+	}
+}
+
+// displayName returns the name shown for the cluster of a result
+func (r clusterResult) displayName() string {
+	if r.DisplayName != "" {
+		return r.DisplayName
+	}
+	return r.Context
+}
+
+// failedClusters returns the display names of the clusters where the operation failed without timing out
+func failedClusters(results []clusterResult) []string {
+	var failed []string
+	for _, r := range results {
+		if r.Err != nil && !r.TimedOut {
+			failed = append(failed, r.displayName())
+		}
+	}
+	return failed
+}
+
+// timedOutClusters returns the display names of the clusters where the operation timed out
+func timedOutClusters(results []clusterResult) []string {
+	var timedOut []string
+	for _, r := range results {
+		if r.TimedOut {
+			timedOut = append(timedOut, r.displayName())
+		}
+	}
+	return timedOut
+}
+
+// skippedClusters returns the display names of the clusters the operation was not run on
+func skippedClusters(results []clusterResult) []string {
+	var skipped []string
+	for _, r := range results {
+		if r.Skipped != "" {
+			skipped = append(skipped, r.displayName())
+		}
+	}
+	return skipped
+}
+
+// completedClusters returns the display names of the clusters where the operation succeeded
+func completedClusters(results []clusterResult) []string {
+	var completed []string
+	for _, r := range results {
+		if r.Err == nil && r.Skipped == "" {
+			completed = append(completed, r.displayName())
+		}
+	}
+	return completed
+}
+
+// printTotalTimeoutMessage tells the user --timeout-total expired and which clusters completed before it
+func printTotalTimeoutMessage(out io.Writer, results []clusterResult) {
+	completed := completedClusters(results)
+	if len(completed) == 0 {
+		fmt.Fprintf(out, "Stopped after --timeout-total (%s), no cluster completed\n\n", totalTimeout)
+		return
+	}
+	fmt.Fprintf(out, "Stopped after --timeout-total (%s), completed on: %s\n\n", totalTimeout, strings.Join(completed, ", "))
+}
+
+// printFanOutSummary prints how many clusters succeeded and which ones failed or were skipped
+func printFanOutSummary(out io.Writer, results []clusterResult) {
+	if len(results) == 0 {
+		return
+	}
+	failed := failedClusters(results)
+	timedOut := timedOutClusters(results)
+	skipped := skippedClusters(results)
+	succeeded := len(results) - len(failed) - len(timedOut) - len(skipped)
+
+	summary := fmt.Sprintf("Summary: %d succeeded, %d failed", succeeded, len(failed))
+	if len(failed) > 0 {
+		summary += fmt.Sprintf(" (%s)", strings.Join(failed, ", "))
+	}
+	if len(timedOut) > 0 {
+		summary += fmt.Sprintf(", %d timed out (%s)", len(timedOut), strings.Join(timedOut, ", "))
+	}
+	if len(skipped) > 0 {
+		summary += fmt.Sprintf(", %d skipped (%s)", len(skipped), strings.Join(skipped, ", "))
+	}
+	fmt.Fprintln(out, summary)
+	if categories := formatErrorCategories(results); categories != "" {
+		fmt.Fprintf(out, "Errors: %s\n", categories)
+	}
+}
+
+// printCountOnlySummary prints the --count-only line: how many clusters succeeded, failed, timed out
+// and were skipped, and how long the whole operation took, as key=value pairs for scripts
+func printCountOnlySummary(out io.Writer, results []clusterResult, elapsed time.Duration) {
+	failed := len(failedClusters(results))
+	timedOut := len(timedOutClusters(results))
+	skipped := len(skippedClusters(results))
+	succeeded := len(results) - failed - timedOut - skipped
+	fmt.Fprintf(out, "succeeded=%d failed=%d timed_out=%d skipped=%d total=%d duration=%s\n",
+		succeeded, failed, timedOut, skipped, len(results), elapsed.Round(time.Millisecond))
+}
+
+// fanOutError aggregates the per-cluster failures into a *cluster.MultiClusterError
+func fanOutError(results []clusterResult) error {
+	multiErr := &cluster.MultiClusterError{Total: len(results)}
+	for _, r := range results {
+		if r.Err != nil {
+			multiErr.Errors = append(multiErr.Errors, cluster.ClusterError{Context: r.Context, Err: r.Err})
+		}
+	}
+	if len(multiErr.Errors) == 0 {
+		return nil
+	}
+	return multiErr
+}
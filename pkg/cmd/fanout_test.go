@@ -0,0 +1,592 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"kubectl-multi/pkg/cluster"
+)
+
+// TestFanOutTargets ensures the current context is first and the ITS cluster is never targeted
+func TestFanOutTargets(t *testing.T) {
+	clusters := []cluster.ClusterInfo{{Context: "wds1"}, {Context: "its1"}, {Context: "wds2"}}
+
+	targets, its := fanOutTargets(clusters, "wds2", "its1")
+	if its == nil || its.Context != "its1" {
+		t.Fatalf("expected its1 to be returned as the ITS cluster, got %v", its)
+	}
+	if len(targets) != 2 || targets[0].Context != "wds2" || targets[1].Context != "wds1" {
+		t.Errorf("unexpected targets: %v", targets)
+	}
+}
+
+// TestFanOutErrorNoFailures ensures no error is returned when every cluster succeeded
+func TestFanOutErrorNoFailures(t *testing.T) {
+	results := []clusterResult{{Context: "wds1"}, {Context: "wds2", Skipped: "too old"}}
+	if err := fanOutError(results); err != nil {
+		t.Errorf("expected no error, got %v", err)
+	}
+}
+
+// TestFanOutErrorMultiClusterError ensures per-cluster failures can be extracted with errors.As
+func TestFanOutErrorMultiClusterError(t *testing.T) {
+	errForbidden := errors.New("forbidden")
+	results := []clusterResult{
+		{Context: "wds1"},
+		{Context: "wds2", Err: errForbidden},
+		{Context: "wds3", Err: errors.New("connection refused")},
+	}
+
+	err := fanOutError(results)
+	var multiErr *cluster.MultiClusterError
+	if !errors.As(err, &multiErr) {
+		t.Fatalf("expected a *cluster.MultiClusterError, got %T", err)
+	}
+	if multiErr.Total != 3 || len(multiErr.Errors) != 2 {
+		t.Errorf("expected 2 of 3 clusters to fail, got %d of %d", len(multiErr.Errors), multiErr.Total)
+	}
+	if got := multiErr.ErrorFor("wds2"); got != errForbidden {
+		t.Errorf("expected the wds2 error to be %v, got %v", errForbidden, got)
+	}
+	if !errors.Is(err, errForbidden) {
+		t.Error("expected errors.Is to match a per-cluster error")
+	}
+	want := "operation failed on 2 of 3 clusters: wds2, wds3"
+	if err.Error() != want {
+		t.Errorf("expected %q, got %q", want, err.Error())
+	}
+}
+
+// TestPreflightValidateNamespace ensures clusters without the target namespace are skipped with --validate-namespace
+func TestPreflightValidateNamespace(t *testing.T) {
+	defer func(v bool, f func(cluster.ClusterInfo, string) (bool, error)) {
+		validateNamespace, namespaceExists = v, f
+	}(validateNamespace, namespaceExists)
+	validateNamespace = true
+	namespaceExists = func(c cluster.ClusterInfo, namespace string) (bool, error) {
+		return c.Context == "wds1" && namespace == "myns", nil
+	}
+
+	withNS := cluster.ClusterInfo{Context: "wds1"}
+	withoutNS := cluster.ClusterInfo{Context: "wds2"}
+	opts := fanOutOptions{Namespace: "myns"}
+
+	if reason := preflightSkipReason(withNS, opts); reason != "" {
+		t.Errorf("expected wds1 not to be skipped, got %q", reason)
+	}
+	if reason := preflightSkipReason(withoutNS, opts); reason == "" {
+		t.Error("expected wds2 to be skipped")
+	}
+	if reason := preflightSkipReason(withoutNS, fanOutOptions{}); reason != "" {
+		t.Errorf("expected no validation without a namespace, got %q", reason)
+	}
+}
+
+// TestFanOutMinServerVersion ensures the clusters whose API server is older than the minimum version
+// of a fan-out are skipped, using the cached server versions
+func TestFanOutMinServerVersion(t *testing.T) {
+	defer func(c *cluster.ServerVersionCache) { serverVersionCache = c }(serverVersionCache)
+	var err error
+	if serverVersionCache, err = cluster.LoadServerVersionCache(filepath.Join(t.TempDir(), "versions.json"), time.Hour); err != nil {
+		t.Fatal(err)
+	}
+	clusters := []cluster.ClusterInfo{{Context: "wds1"}, {Context: "wds2"}}
+	serverVersionCache.Record(clusters[0], "v1.29.3-eks-adc7111")
+	serverVersionCache.Record(clusters[1], "v1.21.14+k3s1")
+
+	if reason := preflightSkipReason(clusters[1], fanOutOptions{MinServerVersion: "1.22"}); !strings.Contains(reason, "server version 1.21.14 is older than the required 1.22") {
+		t.Errorf("expected wds2 to be skipped for its version, got %q", reason)
+	}
+
+	var ran []string
+	op := func(ctx context.Context, c cluster.ClusterInfo, out io.Writer) error {
+		ran = append(ran, c.Context)
+		return nil
+	}
+	results, err := fanOut(clusters, "", "", fanOutOptions{MinServerVersion: "1.22", Stream: func(r clusterResult) {}}, op)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if strings.Join(ran, ",") != "wds1" {
+		t.Errorf("expected the operation to only run on wds1, ran on %v", ran)
+	}
+	if len(results) != 2 || results[1].Skipped == "" {
+		t.Errorf("expected wds2 to be reported as skipped, got %+v", results)
+	}
+}
+
+// TestFanOutUnreachable ensures the clusters discovery could not reach are reported as failed, in the
+// --sort-clusters order, without the operation running on them
+func TestFanOutUnreachable(t *testing.T) {
+	defer func(s string) { sortClustersBy = s }(sortClustersBy)
+	sortClustersBy = "name"
+
+	var ran []string
+	op := func(ctx context.Context, c cluster.ClusterInfo, out io.Writer) error {
+		ran = append(ran, c.Context)
+		return nil
+	}
+	unreachable := []cluster.ClusterInfo{{Context: "wds1", DiscoveryErr: errors.New("connection refused")}}
+	results, err := fanOut([]cluster.ClusterInfo{{Context: "wds2"}}, "", "", fanOutOptions{Unreachable: unreachable, Stream: func(r clusterResult) {}}, op)
+	if err == nil {
+		t.Errorf("expected the unreachable cluster to fail the fan-out")
+	}
+	if strings.Join(ran, ",") != "wds2" {
+		t.Errorf("expected the operation to only run on wds2, ran on %v", ran)
+	}
+	if len(results) != 2 || results[0].Context != "wds1" || results[0].Err == nil || results[1].Err != nil {
+		t.Errorf("expected wds1 to be reported as failed before wds2, got %+v", results)
+	}
+}
+
+// TestFanOutParallelKeepsTargetOrder ensures parallel results are returned in target order with their output captured
+func TestFanOutParallelKeepsTargetOrder(t *testing.T) {
+	defer func(n int) { parallelism = n }(parallelism)
+	parallelism = 3
+
+	targets := []cluster.ClusterInfo{{Context: "wds1"}, {Context: "wds2"}, {Context: "wds3"}, {Context: "wds4"}}
+	op := func(ctx context.Context, c cluster.ClusterInfo, out io.Writer) error {
+		fmt.Fprintf(out, "hello from %s\n", c.Context)
+		if c.Context == "wds3" {
+			return errors.New("boom")
+		}
+		return nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	results := fanOutParallel(ctx, cancel, targets, fanOutOptions{}, op, &progressReporter{out: io.Discard})
+	if len(results) != len(targets) {
+		t.Fatalf("expected %d results, got %d", len(targets), len(results))
+	}
+	for i, r := range results {
+		if r.Context != targets[i].Context {
+			t.Errorf("result %d: expected %s, got %s", i, targets[i].Context, r.Context)
+		}
+		if want := "hello from " + targets[i].Context + "\n"; r.Output != want {
+			t.Errorf("result %d: expected output %q, got %q", i, want, r.Output)
+		}
+	}
+	if results[2].Err == nil {
+		t.Error("expected wds3 to fail")
+	}
+}
+
+// TestFanOutSequentialFailFast ensures the clusters after the first failure are skipped with --fail-fast
+func TestFanOutSequentialFailFast(t *testing.T) {
+	defer func(v bool) { failFast = v }(failFast)
+	failFast = true
+
+	var ran []string
+	targets := []cluster.ClusterInfo{{Context: "wds1"}, {Context: "wds2"}, {Context: "wds3"}}
+	op := func(ctx context.Context, c cluster.ClusterInfo, out io.Writer) error {
+		ran = append(ran, c.Context)
+		if c.Context == "wds2" {
+			return errors.New("boom")
+		}
+		return nil
+	}
+
+	results := fanOutSequential(context.Background(), targets, fanOutOptions{}, op, &progressReporter{out: io.Discard})
+	if len(ran) != 2 {
+		t.Errorf("expected only wds1 and wds2 to run, ran %v", ran)
+	}
+	if results[2].Skipped != failFastSkipReason {
+		t.Errorf("expected wds3 to be skipped due to --fail-fast, got %+v", results[2])
+	}
+}
+
+// TestFanOutParallelFailFast ensures a failure cancels the shared context and skips clusters not yet started
+func TestFanOutParallelFailFast(t *testing.T) {
+	defer func(v bool, n int) { failFast, parallelism = v, n }(failFast, parallelism)
+	failFast = true
+	parallelism = 2
+
+	targets := []cluster.ClusterInfo{{Context: "wds1"}, {Context: "wds2"}, {Context: "wds3"}, {Context: "wds4"}}
+	op := func(ctx context.Context, c cluster.ClusterInfo, out io.Writer) error {
+		if c.Context == "wds1" {
+			return errors.New("boom")
+		}
+		// Block until another cluster's failure cancels the fan-out
+		<-ctx.Done()
+		return ctx.Err()
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	results := fanOutParallel(ctx, cancel, targets, fanOutOptions{}, op, &progressReporter{out: io.Discard})
+
+	failed := 0
+	for _, r := range results {
+		if r.Err != nil {
+			failed++
+			if r.Context != "wds1" {
+				t.Errorf("expected only wds1 to fail, %s failed with %v", r.Context, r.Err)
+			}
+		} else if r.Skipped != failFastSkipReason {
+			t.Errorf("expected %s to be skipped due to --fail-fast, got %+v", r.Context, r)
+		}
+	}
+	if failed != 1 {
+		t.Errorf("expected exactly 1 failure, got %d", failed)
+	}
+}
+
+// TestCountAcrossClustersTimeout ensures the counts made outside of a fan-out are bounded by --timeout
+// and --timeout-total rather than waiting for a cluster that does not answer
+func TestCountAcrossClustersTimeout(t *testing.T) {
+	installFakeKubectl(t, "#!/bin/sh\nexec sleep 10\n")
+	defer func(c, total time.Duration) { clusterTimeout, totalTimeout = c, total }(clusterTimeout, totalTimeout)
+	clusters := []cluster.ClusterInfo{{Context: "wds1"}, {Context: "wds2"}}
+	args := func(context string) []string { return []string{"get", "pods", "-o", "name", "--context", context} }
+
+	for _, tt := range []struct {
+		name           string
+		cluster, total time.Duration
+	}{
+		{"timeout", 50 * time.Millisecond, 0},
+		{"timeout-total", time.Minute, 50 * time.Millisecond},
+	} {
+		clusterTimeout, totalTimeout = tt.cluster, tt.total
+		start := time.Now()
+		counts := countAcrossClusters(clusters, "", args)
+		if elapsed := time.Since(start); elapsed > 5*time.Second {
+			t.Errorf("%s: expected the counts to stop early, took %s", tt.name, elapsed)
+		}
+		for _, c := range counts {
+			if c.Err == nil || classifyError(c.Err) != categoryTimeout {
+				t.Errorf("%s: expected %s to time out, got %+v", tt.name, c.Context, c)
+			}
+		}
+	}
+}
+
+// TestRunOnClusterTimeout ensures an operation exceeding --timeout is stopped and reported as timed out
+func TestRunOnClusterTimeout(t *testing.T) {
+	defer func(d time.Duration) { clusterTimeout = d }(clusterTimeout)
+	clusterTimeout = 10 * time.Millisecond
+
+	op := func(ctx context.Context, c cluster.ClusterInfo, out io.Writer) error {
+		<-ctx.Done()
+		return ctx.Err()
+	}
+
+	result := runOnCluster(context.Background(), cluster.ClusterInfo{Context: "wds1"}, fanOutOptions{}, op, io.Discard)
+	if !result.TimedOut || result.Err == nil {
+		t.Fatalf("expected wds1 to time out, got %+v", result)
+	}
+	if got := timedOutClusters([]clusterResult{result}); len(got) != 1 || got[0] != "wds1" {
+		t.Errorf("expected wds1 to be reported as timed out, got %v", got)
+	}
+	if got := failedClusters([]clusterResult{result}); len(got) != 0 {
+		t.Errorf("expected timed out clusters not to be listed as failed, got %v", got)
+	}
+}
+
+// TestFanOutSequentialMaxErrors ensures the fan-out stops once --max-errors clusters have failed
+func TestFanOutSequentialMaxErrors(t *testing.T) {
+	defer func(n int) { maxErrors = n }(maxErrors)
+	maxErrors = 2
+
+	var ran []string
+	targets := []cluster.ClusterInfo{{Context: "wds1"}, {Context: "wds2"}, {Context: "wds3"}, {Context: "wds4"}}
+	op := func(ctx context.Context, c cluster.ClusterInfo, out io.Writer) error {
+		ran = append(ran, c.Context)
+		if c.Context != "wds2" {
+			return errors.New("boom")
+		}
+		return nil
+	}
+
+	results := fanOutSequential(context.Background(), targets, fanOutOptions{}, op, &progressReporter{out: io.Discard})
+	if len(ran) != 3 {
+		t.Errorf("expected wds1 to wds3 to run, ran %v", ran)
+	}
+	if len(failedClusters(results)) != 2 {
+		t.Errorf("expected 2 failures, got %v", failedClusters(results))
+	}
+	if results[3].Skipped != abortSkipReason() {
+		t.Errorf("expected wds4 to be skipped due to --max-errors, got %+v", results[3])
+	}
+}
+
+// TestFanOutParallelMaxErrors ensures the shared context is only cancelled once --max-errors clusters have failed
+func TestFanOutParallelMaxErrors(t *testing.T) {
+	defer func(n, p int) { maxErrors, parallelism = n, p }(maxErrors, parallelism)
+	maxErrors = 2
+	parallelism = 2
+
+	targets := []cluster.ClusterInfo{{Context: "wds1"}, {Context: "wds2"}, {Context: "wds3"}}
+	op := func(ctx context.Context, c cluster.ClusterInfo, out io.Writer) error {
+		return errors.New("boom")
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	results := fanOutParallel(ctx, cancel, targets, fanOutOptions{}, op, &progressReporter{out: io.Discard})
+
+	if got := failedClusters(results); len(got) != 2 {
+		t.Errorf("expected exactly 2 failures, got %v", got)
+	}
+	if got := skippedClusters(results); len(got) != 1 {
+		t.Errorf("expected 1 skipped cluster, got %v", got)
+	}
+}
+
+// slowOp returns an operation that completes immediately on the fast clusters and blocks
+// until the context is done on the others
+func slowOp(fast ...string) clusterOp {
+	return func(ctx context.Context, c cluster.ClusterInfo, out io.Writer) error {
+		for _, f := range fast {
+			if c.Context == f {
+				return nil
+			}
+		}
+		<-ctx.Done()
+		return ctx.Err()
+	}
+}
+
+// TestFanOutSequentialTotalTimeout ensures the slow cluster is stopped and the remaining ones skipped
+// once --timeout-total expires
+func TestFanOutSequentialTotalTimeout(t *testing.T) {
+	defer func(d time.Duration) { totalTimeout = d }(totalTimeout)
+	totalTimeout = 20 * time.Millisecond
+
+	ctx, cancel := context.WithTimeout(context.Background(), totalTimeout)
+	defer cancel()
+	targets := []cluster.ClusterInfo{{Context: "wds1"}, {Context: "wds2"}, {Context: "wds3"}}
+	results := fanOutSequential(ctx, targets, fanOutOptions{}, slowOp("wds1"), &progressReporter{out: io.Discard})
+
+	if got := completedClusters(results); len(got) != 1 || got[0] != "wds1" {
+		t.Errorf("expected only wds1 to complete, got %v", got)
+	}
+	if !results[1].TimedOut || results[1].Err == nil || !strings.Contains(results[1].Err.Error(), "--timeout-total") {
+		t.Errorf("expected wds2 to be stopped by --timeout-total, got %+v", results[1])
+	}
+	if results[2].Skipped != totalTimeoutSkipReason() {
+		t.Errorf("expected wds3 to be skipped due to --timeout-total, got %+v", results[2])
+	}
+}
+
+// TestFanOutParallelTotalTimeout ensures running clusters are cancelled by --timeout-total while the
+// clusters that completed before the deadline keep their result
+func TestFanOutParallelTotalTimeout(t *testing.T) {
+	defer func(d time.Duration, p int) { totalTimeout, parallelism = d, p }(totalTimeout, parallelism)
+	totalTimeout = 20 * time.Millisecond
+	parallelism = 2
+
+	ctx, cancel := context.WithTimeout(context.Background(), totalTimeout)
+	defer cancel()
+	targets := []cluster.ClusterInfo{{Context: "wds1"}, {Context: "wds2"}, {Context: "wds3"}, {Context: "wds4"}}
+	results := fanOutParallel(ctx, cancel, targets, fanOutOptions{}, slowOp("wds1", "wds3"), &progressReporter{out: io.Discard})
+
+	if got := timedOutClusters(results); len(got) == 0 {
+		t.Errorf("expected at least one cluster to be stopped by --timeout-total, got %+v", results)
+	}
+	for _, r := range results {
+		if r.Context == "wds2" && !r.TimedOut {
+			t.Errorf("expected wds2 to time out, got %+v", r)
+		}
+		if r.Err == nil && r.Skipped == "" && r.Context != "wds1" && r.Context != "wds3" {
+			t.Errorf("expected %s not to complete, got %+v", r.Context, r)
+		}
+	}
+	if results[0].Err != nil || results[0].Skipped != "" {
+		t.Errorf("expected wds1 to complete before the deadline, got %+v", results[0])
+	}
+}
+
+// TestRunOnClusterTotalTimeoutPrecedence ensures the earlier of --timeout and --timeout-total stops a cluster
+func TestRunOnClusterTotalTimeoutPrecedence(t *testing.T) {
+	defer func(c, total time.Duration) { clusterTimeout, totalTimeout = c, total }(clusterTimeout, totalTimeout)
+	clusterTimeout = time.Minute
+	totalTimeout = 10 * time.Millisecond
+
+	ctx, cancel := context.WithTimeout(context.Background(), totalTimeout)
+	defer cancel()
+	result := runOnCluster(ctx, cluster.ClusterInfo{Context: "wds1"}, fanOutOptions{}, slowOp(), io.Discard)
+	if !result.TimedOut || result.Err == nil || !strings.Contains(result.Err.Error(), "--timeout-total") {
+		t.Errorf("expected wds1 to be stopped by --timeout-total, got %+v", result)
+	}
+}
+
+// TestRunOnClusterTimeoutOverride ensures a --timeout-override applies to its cluster only
+func TestRunOnClusterTimeoutOverride(t *testing.T) {
+	defer func(d time.Duration, o map[string]time.Duration) {
+		clusterTimeout, clusterTimeoutOverrides = d, o
+	}(clusterTimeout, clusterTimeoutOverrides)
+	clusterTimeout = time.Minute
+	clusterTimeoutOverrides = map[string]time.Duration{"slow": 10 * time.Millisecond}
+
+	if got := timeoutFor(cluster.ClusterInfo{Context: "wds1"}); got != time.Minute {
+		t.Errorf("expected wds1 to use --timeout, got %s", got)
+	}
+
+	result := runOnCluster(context.Background(), cluster.ClusterInfo{Context: "slow"}, fanOutOptions{}, slowOp(), io.Discard)
+	if !result.TimedOut || result.Err == nil || result.Err.Error() != "timed out after 10ms" {
+		t.Errorf("expected slow to time out after its override, got %+v", result)
+	}
+}
+
+// TestFanOutSequentialStagger ensures --stagger waits between the clusters the operation ran on,
+// but not before the first one nor after a skipped one
+func TestFanOutSequentialStagger(t *testing.T) {
+	defer func(d time.Duration) { stagger = d }(stagger)
+	stagger = 30 * time.Millisecond
+
+	var started []time.Time
+	targets := []cluster.ClusterInfo{{Context: "wds1"}, {Context: "wds2"}, {Context: "wds3"}, {Context: "wds4"}}
+	op := func(ctx context.Context, c cluster.ClusterInfo, out io.Writer) error {
+		started = append(started, time.Now())
+		return nil
+	}
+	opts := fanOutOptions{Skip: map[string]string{"wds3": "not needed"}, Stream: func(r clusterResult) {}}
+
+	begin := time.Now()
+	results := fanOutSequential(context.Background(), targets, opts, op, &progressReporter{out: io.Discard})
+	if len(results) != 4 || len(started) != 3 {
+		t.Fatalf("expected 3 clusters to run, got %d results and %d runs", len(results), len(started))
+	}
+	if d := started[0].Sub(begin); d >= stagger {
+		t.Errorf("expected the first cluster to start right away, waited %s", d)
+	}
+	if d := started[1].Sub(started[0]); d < stagger {
+		t.Errorf("expected wds2 to wait for --stagger, waited %s", d)
+	}
+	if d := started[2].Sub(started[1]); d < stagger || d >= 2*stagger {
+		t.Errorf("expected one wait between wds2 and wds4 as wds3 was skipped, waited %s", d)
+	}
+}
+
+// TestWaitStaggerCancelled ensures the wait ends when the fan-out is stopped
+func TestWaitStaggerCancelled(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	var out bytes.Buffer
+	begin := time.Now()
+	waitStagger(ctx, time.Hour, cluster.ClusterInfo{Context: "wds2"}, &out)
+	if time.Since(begin) > time.Second {
+		t.Error("expected the wait to stop when the context is done")
+	}
+	if !strings.Contains(out.String(), "Waiting 1h0m0s before cluster wds2") {
+		t.Errorf("expected a notice naming the next cluster, got %q", out.String())
+	}
+}
+
+// TestValidateStagger ensures --stagger is rejected when negative or with parallel fan-outs
+func TestValidateStagger(t *testing.T) {
+	if err := validateStagger(time.Minute, 1); err != nil {
+		t.Errorf("unexpected error: %v", err)
+	}
+	if err := validateStagger(-time.Second, 1); err == nil {
+		t.Error("expected a negative --stagger to be rejected")
+	}
+	if err := validateStagger(time.Minute, 4); err == nil {
+		t.Error("expected --stagger to be rejected with --parallel 4")
+	}
+}
+
+// TestPrintITSNotice ensures --no-its-warning hides the ITS notice
+func TestPrintITSNotice(t *testing.T) {
+	defer func(v bool) { noITSWarning = v }(noITSWarning)
+	its := &cluster.ClusterInfo{Context: "its1"}
+
+	var out bytes.Buffer
+	printITSNotice(&out, its, 2)
+	if !strings.Contains(out.String(), "Cannot perform this operation on ITS (control) cluster: its1") {
+		t.Errorf("expected the ITS notice, got %q", out.String())
+	}
+
+	out.Reset()
+	noITSWarning = true
+	printITSNotice(&out, its, 2)
+	printITSNotice(&out, nil, 2)
+	if out.Len() != 0 {
+		t.Errorf("expected no notice with --no-its-warning, got %q", out.String())
+	}
+}
+
+// TestPrintCountOnlySummary ensures every outcome is counted once, timed out clusters apart from failed ones
+func TestPrintCountOnlySummary(t *testing.T) {
+	results := []clusterResult{
+		{Context: "cluster1"},
+		{Context: "cluster2", Err: errors.New("forbidden")},
+		{Context: "cluster3", Err: errors.New("timed out after 1s"), TimedOut: true},
+		{Context: "cluster4", Skipped: "namespace shop not found"},
+		{Context: "cluster5"},
+	}
+	var out bytes.Buffer
+	printCountOnlySummary(&out, results, 1234567*time.Microsecond)
+	want := "succeeded=2 failed=1 timed_out=1 skipped=1 total=5 duration=1.235s\n"
+	if out.String() != want {
+		t.Errorf("expected %q, got %q", want, out.String())
+	}
+}
+
+// TestSortResults ensures results completed out of order are reported by context name, or in discovery
+// order with --sort-clusters discovery
+func TestSortResults(t *testing.T) {
+	discovered := []cluster.ClusterInfo{{Context: "edge-b"}, {Context: "edge-a"}, {Context: "edge-c"}}
+	for _, tc := range []struct {
+		by   string
+		want string
+	}{
+		{"name", "edge-a,edge-b,edge-c,edge-d"},
+		{"discovery", "edge-b,edge-a,edge-c,edge-d"},
+	} {
+		results := []clusterResult{{Context: "edge-c"}, {Context: "edge-d"}, {Context: "edge-a"}, {Context: "edge-b"}}
+		sortResults(results, tc.by, discovered)
+		var got []string
+		for _, r := range results {
+			got = append(got, r.Context)
+		}
+		if strings.Join(got, ",") != tc.want {
+			t.Errorf("%s: expected %s, got %v", tc.by, tc.want, got)
+		}
+	}
+}
+
+// TestFanOutParallelStableSummary ensures the summary of a parallel fan-out is the same whatever order
+// the clusters complete in
+func TestFanOutParallelStableSummary(t *testing.T) {
+	defer func(n int, s string) { parallelism, sortClustersBy = n, s }(parallelism, sortClustersBy)
+	parallelism = 4
+	sortClustersBy = "name"
+
+	clusters := []cluster.ClusterInfo{{Context: "wds1"}, {Context: "wds2"}, {Context: "wds3"}, {Context: "wds4"}}
+	var summaries []string
+	for _, delays := range [][]time.Duration{{40, 30, 20, 10}, {10, 20, 30, 40}, {30, 10, 40, 20}} {
+		op := func(ctx context.Context, c cluster.ClusterInfo, out io.Writer) error {
+			// The clusters complete in the order of their delays
+			time.Sleep(delays[c.Context[3]-'1'] * time.Millisecond)
+			if c.Context != "wds1" {
+				return errors.New("boom")
+			}
+			return nil
+		}
+		var order []string
+		opts := fanOutOptions{Stream: func(r clusterResult) {}}
+		results, _ := fanOut(clusters, "", "its1", opts, op)
+		for _, r := range results {
+			order = append(order, r.Context)
+		}
+		var summary bytes.Buffer
+		printFanOutSummary(&summary, results)
+		summaries = append(summaries, strings.Join(order, ",")+" "+summary.String())
+	}
+	want := "wds1,wds2,wds3,wds4 Summary: 1 succeeded, 3 failed (wds2, wds3, wds4)\nErrors: 3 Other\n"
+	for i, s := range summaries {
+		if s != want {
+			t.Errorf("run %d: expected %q, got %q", i, want, s)
+		}
+	}
+}
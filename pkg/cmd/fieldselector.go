@@ -0,0 +1,68 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"strings"
+
+	"kubectl-multi/pkg/cluster"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/fields"
+)
+
+// fetchObjectsWithFieldSelector runs `kubectl get -o json --field-selector` in every cluster like
+// fetchObjects. Servers that do not support a field of the selector for the resource, as for most
+// fields of custom resources, are asked for the whole list instead, which is filtered client-side.
+// With verbose, which of the two happened in each cluster is written to notes.
+func fetchObjectsWithFieldSelector(clusters []cluster.ClusterInfo, kubeconfig, resourceType, resourceName, selector, namespace string, allNamespaces bool, chunkSize int64, fieldSelector fields.Selector, verbose bool, notes io.Writer) []clusterObject {
+	var objects []clusterObject
+	for _, c := range clusters {
+		args := append(buildKubectlGetArgs(resourceType, resourceName, "json", selector, namespace, allNamespaces, c.Context, chunkSize), "--ignore-not-found")
+		output, err := runKubectl(append(args, "--field-selector="+fieldSelector.String()), kubeconfig)
+		clientSide := false
+		if err != nil && isFieldLabelNotSupported(err) {
+			clientSide = true
+			output, err = runKubectl(args, kubeconfig)
+		}
+		if err != nil {
+			fmt.Printf("Warning: failed to get %s in cluster %s: %v: %s\n", resourceType, c.Display(), err, strings.TrimSpace(output))
+			continue
+		}
+		items, err := parseObjects([]byte(output))
+		if err != nil {
+			fmt.Printf("Warning: failed to get %s in cluster %s: %v\n", resourceType, c.Display(), err)
+			continue
+		}
+
+		if verbose {
+			if clientSide {
+				fmt.Fprintf(notes, "Cluster %s: the server does not support the field selector, filtered client-side\n", c.Display())
+			} else {
+				fmt.Fprintf(notes, "Cluster %s: field selector applied by the server\n", c.Display())
+			}
+		}
+		for _, item := range items {
+			if clientSide && !matchesFieldSelector(item, fieldSelector) {
+				continue
+			}
+			objects = append(objects, clusterObject{Cluster: c.Display(), Context: c.Context, Object: item})
+		}
+	}
+	return objects
+}
+
+// matchesFieldSelector evaluates a field selector against an object, reading each field of its
+// requirements as a dotted path such as status.phase. Missing fields are empty, like on the server.
+func matchesFieldSelector(obj *unstructured.Unstructured, selector fields.Selector) bool {
+	set := fields.Set{}
+	for _, r := range selector.Requirements() {
+		value, found, err := unstructured.NestedFieldNoCopy(obj.Object, strings.Split(r.Field, ".")...)
+		if err != nil || !found || value == nil {
+			set[r.Field] = ""
+			continue
+		}
+		set[r.Field] = fmt.Sprint(value)
+	}
+	return selector.Matches(set)
+}
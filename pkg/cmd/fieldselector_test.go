@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"kubectl-multi/pkg/cluster"
+
+	"k8s.io/apimachinery/pkg/fields"
+)
+
+// fakeFieldSelectorKubectl is a kubectl listing a running and a pending pod in every cluster. The
+// server of cluster1 applies the field selector, the one of cluster2 does not support it.
+const fakeFieldSelectorKubectl = `#!/bin/sh
+running='{"kind":"Pod","metadata":{"name":"web-1"},"status":{"phase":"Running"}}'
+pending='{"kind":"Pod","metadata":{"name":"web-2"},"status":{"phase":"Pending"}}'
+case "$*" in
+*cluster2*--field-selector*|*--field-selector*cluster2*) echo 'Error from server (BadRequest): Unable to find "/v1, Resource=pods" that match label selector "", field selector "status.phase=Running": field label not supported: status.phase' >&2; exit 1;;
+*--field-selector*) echo "{\"kind\":\"List\",\"items\":[$running]}";;
+*) echo "{\"kind\":\"List\",\"items\":[$running,$pending]}";;
+esac
+`
+
+// TestFetchObjectsWithFieldSelector ensures the selector is applied by the servers supporting it and
+// client-side by the others, and that --verbose reports which path was used in every cluster
+func TestFetchObjectsWithFieldSelector(t *testing.T) {
+	installFakeKubectl(t, fakeFieldSelectorKubectl)
+
+	selector := fields.ParseSelectorOrDie("status.phase=Running")
+	clusters := []cluster.ClusterInfo{{Name: "cluster1", Context: "cluster1"}, {Name: "cluster2", Context: "cluster2"}}
+	var notes bytes.Buffer
+	objects := fetchObjectsWithFieldSelector(clusters, "", "pods", "", "", "", false, 0, selector, true, &notes)
+
+	var got []string
+	for _, o := range objects {
+		got = append(got, o.Context+"/"+o.Object.GetName())
+	}
+	if strings.Join(got, ",") != "cluster1/web-1,cluster2/web-1" {
+		t.Errorf("expected the running pod of both clusters, got %v", got)
+	}
+	for _, want := range []string{"Cluster cluster1: field selector applied by the server", "Cluster cluster2: the server does not support the field selector, filtered client-side"} {
+		if !strings.Contains(notes.String(), want) {
+			t.Errorf("expected %q in the notes, got:\n%s", want, notes.String())
+		}
+	}
+
+	notes.Reset()
+	fetchObjectsWithFieldSelector(clusters, "", "pods", "", "", "", false, 0, selector, false, &notes)
+	if notes.Len() != 0 {
+		t.Errorf("expected no notes without --verbose, got:\n%s", notes.String())
+	}
+}
+
+// TestMatchesFieldSelector ensures the fields are read as dotted paths, a missing field being empty
+func TestMatchesFieldSelector(t *testing.T) {
+	pod := filterTestPod("cluster1", "web-1", "Running", 0, "True").Object
+	for _, tc := range []struct {
+		selector string
+		want     bool
+	}{
+		{"status.phase=Running", true},
+		{"status.phase==Running", true},
+		{"status.phase!=Running", false},
+		{"metadata.name=web-1,status.phase=Running", true},
+		{"metadata.name=web-2,status.phase=Running", false},
+		{"spec.nodeName=", true},
+		{"spec.nodeName!=", false},
+	} {
+		if got := matchesFieldSelector(pod, fields.ParseSelectorOrDie(tc.selector)); got != tc.want {
+			t.Errorf("%s: expected %v, got %v", tc.selector, tc.want, got)
+		}
+	}
+}
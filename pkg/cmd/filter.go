@@ -0,0 +1,134 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"k8s.io/client-go/util/jsonpath"
+)
+
+// objectFilter is a --filter predicate, PATH=VALUE or PATH!=VALUE, checked against the JSON of every
+// fetched object once all clusters have answered
+type objectFilter struct {
+	expr   string
+	path   *jsonpath.JSONPath
+	value  string
+	negate bool
+}
+
+// parseFilters compiles the --filter expressions, which must all match for an object to be kept
+func parseFilters(exprs []string) ([]objectFilter, error) {
+	var filters []objectFilter
+	for _, expr := range exprs {
+		f, err := parseFilter(expr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid --filter %q: %v", expr, err)
+		}
+		filters = append(filters, f)
+	}
+	return filters, nil
+}
+
+// parseFilter compiles a PATH=VALUE or PATH!=VALUE expression. The path is split at the first = outside
+// of braces, brackets, parentheses and quotes, so that JSONPath filters such as
+// {.status.conditions[?(@.type=="Ready")].status}=True keep their own comparison.
+func parseFilter(expr string) (objectFilter, error) {
+	depth := 0
+	var quote rune
+	for i, r := range expr {
+		switch {
+		case quote != 0:
+			if r == quote {
+				quote = 0
+			}
+		case r == '"' || r == '\'':
+			quote = r
+		case strings.ContainsRune("{[(", r):
+			depth++
+		case strings.ContainsRune("}])", r):
+			depth--
+		case r == '=' && depth == 0:
+			f := objectFilter{expr: expr, value: expr[i+1:]}
+			path := expr[:i]
+			if strings.HasSuffix(path, "!") {
+				f.negate = true
+				path = strings.TrimSuffix(path, "!")
+			}
+			if strings.TrimSpace(path) == "" {
+				return objectFilter{}, fmt.Errorf("missing the JSONPath before =")
+			}
+			jp, err := parseFieldPath(path)
+			if err != nil {
+				return objectFilter{}, err
+			}
+			f.path = jp
+			return f, nil
+		}
+	}
+	return objectFilter{}, fmt.Errorf("expected PATH=VALUE or PATH!=VALUE")
+}
+
+// matches tells whether one of the values of the path in obj is the filter value, or none is with !=.
+// Values are compared as text, numbers and booleans as JSON writes them, and a missing field is empty.
+func (f objectFilter) matches(obj map[string]interface{}) bool {
+	found := false
+	for _, v := range filterValues(f.path, obj) {
+		if v == f.value {
+			found = true
+			break
+		}
+	}
+	return found != f.negate
+}
+
+// filterValues returns the values of the path in obj as text, or a single empty value when obj does
+// not have the field
+func filterValues(jp *jsonpath.JSONPath, obj map[string]interface{}) []string {
+	results, err := jp.FindResults(obj)
+	if err != nil {
+		return []string{""}
+	}
+	var values []string
+	for _, result := range results {
+		for _, v := range result {
+			if !v.IsValid() || !v.CanInterface() {
+				continue
+			}
+			switch x := v.Interface().(type) {
+			case nil:
+				values = append(values, "")
+			case string:
+				values = append(values, x)
+			default:
+				data, _ := json.Marshal(x)
+				values = append(values, string(data))
+			}
+		}
+	}
+	if len(values) == 0 {
+		return []string{""}
+	}
+	return values
+}
+
+// filterObjects returns the objects of all clusters that match every filter, in their order
+func filterObjects(objects []clusterObject, filters []objectFilter) []clusterObject {
+	if len(filters) == 0 {
+		return objects
+	}
+	var kept []clusterObject
+	for _, o := range objects {
+		match := true
+		for _, f := range filters {
+			if !f.matches(o.Object.Object) {
+				match = false
+				break
+			}
+		}
+		if match {
+			kept = append(kept, o)
+		}
+	}
+	return kept
+}
@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// filterTestPod returns a pod object of a cluster with a phase, a restart count and conditions
+func filterTestPod(cluster, name, phase string, restarts int64, ready string) clusterObject {
+	obj := map[string]interface{}{
+		"kind":     "Pod",
+		"metadata": map[string]interface{}{"name": name, "labels": map[string]interface{}{"app": "web"}},
+		"status": map[string]interface{}{
+			"phase": phase,
+			"conditions": []interface{}{
+				map[string]interface{}{"type": "Initialized", "status": "True"},
+				map[string]interface{}{"type": "Ready", "status": ready},
+			},
+			"containerStatuses": []interface{}{
+				map[string]interface{}{"name": "app", "restartCount": restarts},
+			},
+		},
+	}
+	return clusterObject{Cluster: cluster, Object: &unstructured.Unstructured{Object: obj}}
+}
+
+// TestFilterObjects ensures the predicates compare the values of the path as text, a missing field
+// being empty, and that several filters must all match
+func TestFilterObjects(t *testing.T) {
+	objects := []clusterObject{
+		filterTestPod("cluster1", "web-1", "Running", 0, "True"),
+		filterTestPod("cluster1", "web-2", "Pending", 0, "False"),
+		filterTestPod("cluster2", "web-3", "Running", 3, "False"),
+	}
+	for _, tc := range []struct {
+		filters []string
+		want    string
+	}{
+		{[]string{".status.phase=Running"}, "web-1,web-3"},
+		{[]string{"status.phase!=Running"}, "web-2"},
+		{[]string{"{.status.phase}=Running", ".status.containerStatuses[0].restartCount=0"}, "web-1"},
+		{[]string{`{.status.conditions[?(@.type=="Ready")].status}=False`}, "web-2,web-3"},
+		// Any of the values of a path matching is enough
+		{[]string{".status.conditions[*].status=True"}, "web-1,web-2,web-3"},
+		{[]string{".metadata.labels.app=web"}, "web-1,web-2,web-3"},
+		// A missing field only matches an empty value
+		{[]string{".spec.nodeName="}, "web-1,web-2,web-3"},
+		{[]string{".spec.nodeName!="}, ""},
+		{[]string{".status.phase=running"}, ""},
+	} {
+		filters, err := parseFilters(tc.filters)
+		if err != nil {
+			t.Fatalf("%v: unexpected error: %v", tc.filters, err)
+		}
+		var names []string
+		for _, o := range filterObjects(objects, filters) {
+			names = append(names, o.Object.GetName())
+		}
+		if got := strings.Join(names, ","); got != tc.want {
+			t.Errorf("%v: expected %q, got %q", tc.filters, tc.want, got)
+		}
+	}
+}
+
+// TestParseFilterErrors ensures expressions without a comparison or with a broken path are rejected
+func TestParseFilterErrors(t *testing.T) {
+	for _, expr := range []string{".status.phase", "=Running", "!=Running", "{.status.phase=Running", `{.status.conditions[?(@.type=="Ready")].status}`} {
+		if _, err := parseFilters([]string{expr}); err == nil {
+			t.Errorf("expected an error for %q", expr)
+		}
+	}
+}
@@ -1,20 +1,21 @@
 package cmd
 
 import (
-	"bytes"
 	"context"
 	"fmt"
+	"io"
 	"os"
-	"os/exec"
 	"strings"
-	"text/tabwriter"
+	"text/template"
 	"time"
 
 	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/fields"
 	"k8s.io/apimachinery/pkg/util/duration"
-	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/client-go/util/jsonpath"
 
 	"kubectl-multi/pkg/cluster"
 	"kubectl-multi/pkg/util"
@@ -71,12 +72,40 @@ kubectl multi get jobs
 	fmt.Fprintln(cmd.OutOrStdout(), combinedHelp)
 }
 
+// getOptions holds the flags of the get command
+type getOptions struct {
+	OutputFormat string
+	Selector     string
+	ShowLabels   bool
+	Watch        bool
+	WatchOnly    bool
+	Count        bool
+	ShowKind     bool
+	NoHeaders    bool
+	Problems     bool
+	NameTemplate string
+	ServerPrint  bool
+	ShowOwner    bool
+	ShowManagers bool
+	ChunkSize    int64
+	Subresource  string
+	SortBy       string
+	Columns      string
+	// LabelColumns are the label keys kubectl adds as columns with -L
+	LabelColumns []string
+	// Filters are the PATH=VALUE predicates the fetched objects must all match
+	Filters []string
+	// FieldSelector is tried server-side and evaluated client-side by the servers not supporting it
+	FieldSelector string
+	// Verbose reports per cluster whether the field selector was applied server-side or client-side
+	Verbose bool
+
+	// OutputTemplate is executed once per cluster with its Context and Items
+	OutputTemplate string
+}
+
 func newGetCommand() *cobra.Command {
-	var outputFormat string
-	var selector string
-	var showLabels bool
-	var watch bool
-	var watchOnly bool
+	var opts getOptions
 
 	cmd := &cobra.Command{
 		Use:   "get [TYPE[.VERSION][.GROUP] [NAME | -l label] | TYPE[.VERSION][.GROUP]/NAME ...]",
@@ -112,6 +141,57 @@ kubectl multi get pods -o json
 
 # Get deployments in YAML format
 kubectl multi get deployments -o yaml
+
+# Stream one JSON object per cluster as soon as each cluster completes
+kubectl multi get pods -A -o jsonl --parallel 10
+
+# Count pods per cluster instead of listing them
+kubectl multi get pods -A --count
+
+# List all resources in one table with CLUSTER and KIND columns
+kubectl multi get all -A --show-kind
+
+# Show which workload owns every pod
+kubectl multi get pods -A --show-owner
+
+# Show which field managers own the fields of a deployment in every cluster
+kubectl multi get deployment web -n shop --show-managers
+
+# List the broken pods of every cluster
+kubectl multi get pods -A --problems
+
+# Watch the pod events of every cluster without the initial list
+kubectl multi get pods -A --watch-only
+
+# List pods of every cluster in one wide table without headers, for scripting
+kubectl multi get pods -A -o wide --no-headers
+
+# List certificates with the printer columns of their CRD
+kubectl multi get certificates -A --server-print
+
+# List deployments as context/namespace/kind/name, one per line
+kubectl multi get deployments -A -o name
+
+# Get the scale subresource of a deployment in every cluster
+kubectl multi get deployment web --subresource=scale
+
+# Print a CSV of the deployments of every cluster
+kubectl multi get deployments -A --output-template '{{if eq .Index 0}}context,namespace,name{{"\n"}}{{end}}{{range .Items}}{{$.Context}},{{.metadata.namespace}},{{.metadata.name}}{{"\n"}}{{end}}'
+
+# List the pods of every cluster, oldest first
+kubectl multi get pods -A --sort-by=.metadata.creationTimestamp
+
+# Export the deployments of every cluster to a spreadsheet
+kubectl multi get deployments -A -o csv --columns 'name,namespace,REPLICAS:.spec.replicas,IMAGE:.spec.template.spec.containers[*].image' > fleet.csv
+
+# Add the app and version labels of every pod as columns
+kubectl multi get pods -A -L app,version
+
+# List the pods of every cluster that are running on a given node pool
+kubectl multi get pods -A --filter .status.phase=Running --filter '.spec.nodeSelector.pool=gpu'
+
+# List pod names prefixed with their cluster only
+kubectl multi get pods -o name --name-template '{{.Cluster}}:{{.Name}}'
 `,
 		RunE: func(cmd *cobra.Command, args []string) error {
 			if len(args) == 0 {
@@ -119,15 +199,32 @@ kubectl multi get deployments -o yaml
 			}
 
 			kubeconfig, remoteCtx, _, namespace, allNamespaces := GetGlobalFlags()
-			return handleGetCommand(args, outputFormat, selector, showLabels, watch, watchOnly, kubeconfig, remoteCtx, namespace, allNamespaces)
+			return handleGetCommand(args, opts, kubeconfig, remoteCtx, namespace, allNamespaces)
 		},
 	}
 
-	cmd.Flags().StringVarP(&outputFormat, "output", "o", "", "output format (json|yaml|wide|name|custom-columns=...|custom-columns-file=...|go-template=...|go-template-file=...|jsonpath=...|jsonpath-file=...)")
-	cmd.Flags().StringVarP(&selector, "selector", "l", "", "selector (label query) to filter on")
-	cmd.Flags().BoolVar(&showLabels, "show-labels", false, "show all labels as the last column")
-	cmd.Flags().BoolVarP(&watch, "watch", "w", false, "watch for changes to the requested object(s)")
-	cmd.Flags().BoolVar(&watchOnly, "watch-only", false, "watch for changes to the requested object(s), without listing/getting first")
+	cmd.Flags().StringVarP(&opts.OutputFormat, "output", "o", "", "output format (json|jsonl|yaml|wide|name|csv|custom-columns=...|custom-columns-file=...|go-template=...|go-template-file=...|jsonpath=...|jsonpath-file=...)")
+	cmd.Flags().StringVarP(&opts.Selector, "selector", "l", "", "selector (label query) to filter on")
+	cmd.Flags().BoolVar(&opts.ShowLabels, "show-labels", false, "show all labels as the last column")
+	cmd.Flags().StringSliceVarP(&opts.LabelColumns, "label-columns", "L", nil, "comma-separated labels to show as columns, passed to kubectl in every cluster (e.g. -L app,version), with the default output or -o wide")
+	cmd.Flags().BoolVarP(&opts.Watch, "watch", "w", false, "watch for changes to the requested object(s)")
+	cmd.Flags().BoolVar(&opts.WatchOnly, "watch-only", false, "watch for changes to the requested object(s), without listing/getting first")
+	cmd.Flags().BoolVar(&opts.ShowKind, "show-kind", false, "print every resource in a single CLUSTER/KIND/NAMESPACE/NAME/AGE table, useful for mixed kinds such as 'get all'")
+	cmd.Flags().BoolVar(&opts.ShowOwner, "show-owner", false, "add an OWNER column with the top-level owner of every resource (e.g. the Deployment of a Pod), in the --show-kind table")
+	cmd.Flags().BoolVar(&opts.ShowManagers, "show-managers", false, "print the field managers of every resource with the fields they own, from managedFields, to debug server-side apply conflicts")
+	cmd.Flags().BoolVar(&opts.Problems, "problems", false, "only list pods that are not running or completed (CrashLoopBackOff, Pending, ImagePullBackOff, ...) with a per-cluster count")
+	cmd.Flags().BoolVar(&opts.NoHeaders, "no-headers", false, "don't print headers in table output")
+	cmd.Flags().BoolVar(&opts.ServerPrint, "server-print", false, "use the table rendered by each API server, keeping the printer columns of CRDs (use -o wide for the extra columns)")
+	cmd.Flags().StringVar(&opts.NameTemplate, "name-template", "", "Go template for -o name, with the fields .Context, .Cluster, .Namespace, .Kind and .Name (default \""+defaultNameTemplate+"\")")
+	cmd.Flags().StringVar(&opts.OutputTemplate, "output-template", "", "Go template executed once per cluster with .Index, .Context, .Cluster and .Items (the resources as JSON objects), to format the results of all clusters at will")
+	cmd.Flags().Int64Var(&opts.ChunkSize, "chunk-size", defaultChunkSize, "return large lists in chunks rather than all at once; pass 0 to disable")
+	cmd.Flags().StringVar(&opts.Subresource, "subresource", "", "if specified, gets the subresource of the requested object; one of "+strings.Join(subresources, "|"))
+	cmd.Flags().StringVar(&opts.SortBy, "sort-by", "", "JSONPath of the field to sort the resources of all clusters by, together rather than per cluster (e.g. .metadata.creationTimestamp), with the default output, --show-kind, --show-owner or -o name")
+	cmd.Flags().StringArrayVar(&opts.Filters, "filter", nil, "only show the resources whose JSONPath field has a value, as PATH=VALUE or PATH!=VALUE (e.g. .status.phase=Running), checked after fetching from all clusters; can be repeated, all must match")
+	cmd.Flags().StringVar(&opts.FieldSelector, "field-selector", "", "selector (field query) to filter on, e.g. status.phase=Running; clusters whose server does not support a field for the resource are filtered client-side")
+	cmd.Flags().BoolVar(&opts.Verbose, "verbose", false, "print for every cluster whether --field-selector was applied by the server or client-side")
+	cmd.Flags().StringVar(&opts.Columns, "columns", "", "comma-separated columns of -o csv after CLUSTER: context, namespace, kind, name, created or HEADER:JSONPATH (default \""+defaultCSVColumns+"\")")
+	cmd.Flags().BoolVar(&opts.Count, "count", false, "print the number of matching resources per cluster and a fleet total instead of the resources")
 
 	// Set custom help function
 	cmd.SetHelpFunc(getHelpFunc)
@@ -135,29 +232,276 @@ kubectl multi get deployments -o yaml
 	return cmd
 }
 
-func handleGetCommand(args []string, outputFormat, selector string, showLabels, watch, watchOnly bool, kubeconfig, remoteCtx, namespace string, allNamespaces bool) error {
+func handleGetCommand(args []string, opts getOptions, kubeconfig, remoteCtx, namespace string, allNamespaces bool) error {
+	outputFormat, selector, showLabels := opts.OutputFormat, opts.Selector, opts.ShowLabels
 	resourceType := args[0]
 	resourceName := ""
 	if len(args) > 1 {
 		resourceName = args[1]
 	}
 
-	// For watch operations, we don't support multi-cluster watch yet
-	if watch || watchOnly {
-		return fmt.Errorf("watch operations are not supported in multi-cluster mode")
+	if opts.Watch || opts.WatchOnly {
+		if outputFormat == "jsonl" || outputFormat == "name" || outputFormat == "csv" || opts.Count || opts.Problems || opts.ShowKind || opts.ShowOwner || opts.ShowManagers || opts.ServerPrint || opts.OutputTemplate != "" || opts.Subresource != "" {
+			return fmt.Errorf("--watch and --watch-only only support the default output and -o wide|json|yaml|custom-columns|go-template|jsonpath")
+		}
 	}
 
-	clusters, err := cluster.DiscoverClusters(kubeconfig, remoteCtx)
+	if opts.Subresource != "" {
+		if err := validateSubresource(opts.Subresource); err != nil {
+			return err
+		}
+		if opts.Count || opts.Problems || opts.ShowKind || opts.ShowOwner || opts.ShowManagers || opts.ServerPrint || outputFormat == "name" || outputFormat == "jsonl" || outputFormat == "csv" {
+			return fmt.Errorf("--subresource can only be used with the default output or -o wide|json|yaml|custom-columns|go-template|jsonpath")
+		}
+	}
+
+	// The output template is compiled before any cluster is queried
+	var outputTemplate *template.Template
+	if opts.OutputTemplate != "" {
+		if outputFormat != "" || opts.Count || opts.Problems || opts.ShowKind || opts.ShowOwner || opts.ShowManagers || opts.ServerPrint || opts.Subresource != "" {
+			return fmt.Errorf("--output-template cannot be combined with -o or other output options")
+		}
+		tmpl, err := parseOutputTemplate(opts.OutputTemplate)
+		if err != nil {
+			return err
+		}
+		outputTemplate = tmpl
+	}
+
+	// The sort field is compiled before any cluster is queried
+	var sortBy *jsonpath.JSONPath
+	if opts.SortBy != "" {
+		if (outputFormat != "" && outputFormat != "name" && outputFormat != "csv") || opts.Watch || opts.WatchOnly || opts.Count || opts.Problems || opts.ShowManagers || opts.ServerPrint || opts.OutputTemplate != "" || opts.Subresource != "" {
+			return fmt.Errorf("--sort-by can only be used with the default output, --show-kind, --show-owner, -o name or -o csv")
+		}
+		jp, err := parseSortBy(opts.SortBy)
+		if err != nil {
+			return err
+		}
+		sortBy = jp
+	}
+
+	// The filters are compiled before any cluster is queried
+	var filters []objectFilter
+	if len(opts.Filters) > 0 {
+		if (outputFormat != "" && outputFormat != "name" && outputFormat != "csv") || opts.Watch || opts.WatchOnly || opts.Count || opts.Problems || opts.ShowManagers || opts.ServerPrint || opts.Subresource != "" || len(opts.LabelColumns) > 0 {
+			return fmt.Errorf("--filter can only be used with the default output, --show-kind, --show-owner, --sort-by, --output-template, -o name or -o csv")
+		}
+		parsed, err := parseFilters(opts.Filters)
+		if err != nil {
+			return err
+		}
+		filters = parsed
+	}
+
+	// The field selector is parsed before any cluster is queried, to evaluate it client-side if needed
+	var fieldSelector fields.Selector
+	if opts.FieldSelector != "" {
+		if (outputFormat != "" && outputFormat != "name" && outputFormat != "csv") || opts.Watch || opts.WatchOnly || opts.Count || opts.Problems || opts.ShowManagers || opts.ServerPrint || opts.Subresource != "" || len(opts.LabelColumns) > 0 {
+			return fmt.Errorf("--field-selector can only be used with the default output, --show-kind, --show-owner, --sort-by, --output-template, -o name or -o csv")
+		}
+		parsed, err := fields.ParseSelector(opts.FieldSelector)
+		if err != nil {
+			return fmt.Errorf("invalid --field-selector: %v", err)
+		}
+		fieldSelector = parsed
+	} else if opts.Verbose {
+		return fmt.Errorf("--verbose can only be used with --field-selector")
+	}
+
+	// fetch gets the resources of the clusters as JSON, keeping those matching the selectors and filters
+	fetch := func(clusters []cluster.ClusterInfo) []clusterObject {
+		if fieldSelector != nil {
+			return filterObjects(fetchObjectsWithFieldSelector(clusters, kubeconfig, resourceType, resourceName, selector, namespace, allNamespaces, opts.ChunkSize, fieldSelector, opts.Verbose, os.Stderr), filters)
+		}
+		return filterObjects(fetchObjects(clusters, kubeconfig, resourceType, resourceName, selector, namespace, allNamespaces, opts.ChunkSize), filters)
+	}
+
+	// The CSV columns are checked before any cluster is queried
+	var csvColumns []csvColumn
+	if outputFormat == "csv" {
+		spec := opts.Columns
+		if spec == "" {
+			spec = defaultCSVColumns
+		}
+		columns, err := parseCSVColumns(spec)
+		if err != nil {
+			return err
+		}
+		csvColumns = columns
+	} else if opts.Columns != "" {
+		return fmt.Errorf("--columns can only be used with -o csv")
+	}
+
+	if len(opts.LabelColumns) > 0 {
+		if (outputFormat != "" && outputFormat != "wide") || opts.Watch || opts.WatchOnly || opts.Count || opts.Problems || opts.ShowKind || opts.ShowOwner || opts.ShowManagers || opts.ServerPrint || opts.OutputTemplate != "" || opts.Subresource != "" || opts.SortBy != "" {
+			return fmt.Errorf("-L, --label-columns can only be used with the default output or -o wide")
+		}
+	}
+
+	clusters, unreachable, err := discoverClusters(kubeconfig, remoteCtx)
 	if err != nil {
 		return fmt.Errorf("failed to discover clusters: %v", err)
 	}
 
+	// Watches run in every cluster at once and their events are merged as they arrive
+	if opts.Watch || opts.WatchOnly {
+		return handleGetWatch(clusters, resourceType, resourceName, outputFormat, selector, namespace, allNamespaces, opts.WatchOnly, kubeconfig, remoteCtx)
+	}
+
+	// The output template formats the resources of every cluster
+	if outputTemplate != nil {
+		targets, _ := fanOutTargets(clusters, "", itsContext(remoteCtx))
+		objects := fetch(targets)
+		return executeOutputTemplate(util.GetOutputStream(), outputTemplate, targets, objects)
+	}
+
+	// Count mode only reports how many resources match in each cluster
+	if opts.Count {
+		counts := countAcrossClusters(clusters, kubeconfig, func(context string) []string {
+			return append(buildKubectlGetArgs(resourceType, resourceName, "name", selector, namespace, allNamespaces, context, opts.ChunkSize), "--ignore-not-found")
+		})
+		printCountTable(counts)
+		return nil
+	}
+
+	var out io.Writer = util.GetOutputStream()
+	if opts.NoHeaders {
+		out = &headerSkipper{w: out}
+	}
+
+	// Problems mode only lists pods that are not healthy, filtered client-side
+	if opts.Problems {
+		switch strings.ToLower(resourceType) {
+		case "pods", "pod", "po":
+		default:
+			return fmt.Errorf("--problems can only be used with pods")
+		}
+		if outputFormat != "" || resourceName != "" {
+			return fmt.Errorf("--problems cannot be used with -o or a pod name")
+		}
+		pods := fetchPods(clusters, kubeconfig, selector, namespace, allNamespaces)
+		printProblemPods(newTableWriter(out, clusters), clusters, problemPods(pods), allNamespaces, time.Now())
+		return nil
+	}
+
+	// Managers mode summarizes the managedFields of every resource, one row per field manager
+	if opts.ShowManagers {
+		if outputFormat != "" || opts.ShowKind || opts.ShowOwner {
+			return fmt.Errorf("--show-managers cannot be used with -o, --show-kind or --show-owner")
+		}
+		// kubectl leaves managedFields out of -o json unless asked for them
+		objects := fetchObjects(clusters, kubeconfig, resourceType, resourceName, selector, namespace, allNamespaces, opts.ChunkSize, "--show-managed-fields")
+		printManagersTable(newTableWriter(out, clusters), objects, time.Now())
+		return nil
+	}
+
+	// Sorted output merges the resources of every cluster before ordering them, so it is rendered from
+	// their JSON in the --show-kind table, as names or as CSV
+	if sortBy != nil {
+		objects := fetch(clusters)
+		sortObjects(objects, sortBy)
+		switch outputFormat {
+		case "name":
+			return printQualifiedNames(util.GetOutputStream(), objects, opts.NameTemplate)
+		case "csv":
+			return writeCSV(util.GetOutputStream(), csvColumns, objects, opts.NoHeaders)
+		}
+		if opts.ShowOwner {
+			resolveOwners(objects, newOwnerResolver(kubectlOwnerFetcher(kubeconfig)))
+		}
+		printKindTable(newTableWriter(out, clusters), objects, time.Now())
+		return nil
+	}
+
+	// Show-kind mode renders every resource in one table with the same columns whatever its kind
+	// --show-owner uses the same table, with the top-level owner of every resource, and so do --filter
+	// and --field-selector as the filtered resources are only known from their JSON
+	if opts.ShowKind || opts.ShowOwner || ((len(filters) > 0 || fieldSelector != nil) && outputFormat == "") {
+		if outputFormat != "" {
+			return fmt.Errorf("--show-kind and --show-owner cannot be used with -o")
+		}
+		objects := fetch(clusters)
+		if opts.ShowOwner {
+			resolveOwners(objects, newOwnerResolver(kubectlOwnerFetcher(kubeconfig)))
+		}
+		printKindTable(newTableWriter(out, clusters), objects, time.Now())
+		return nil
+	}
+
+	// Name output is qualified with the context so every line identifies a resource in a single cluster
+	if outputFormat == "name" {
+		return printQualifiedNames(util.GetOutputStream(), fetch(clusters), opts.NameTemplate)
+	}
+	if opts.NameTemplate != "" {
+		return fmt.Errorf("--name-template can only be used with -o name")
+	}
+
+	// CSV output has one row per resource of every cluster, rendered from their JSON
+	if outputFormat == "csv" {
+		return writeCSV(util.GetOutputStream(), csvColumns, fetch(clusters), opts.NoHeaders)
+	}
+
+	// Subresources are only known to kubectl, which renders them for every cluster
+	if opts.Subresource != "" {
+		return handleGetSubresource(clusters, unreachable, resourceName, resourceType, outputFormat, selector, namespace, allNamespaces, opts)
+	}
+
+	// Server-side tables hold the exact kubectl columns, they are merged with a CLUSTER column
+	if opts.ServerPrint {
+		if outputFormat != "" && outputFormat != "wide" {
+			return fmt.Errorf("--server-print can only be used with the default output or -o wide")
+		}
+		tables := fetchServerTables(clusters, resourceType, resourceName, selector, namespace, allNamespaces)
+		mergeServerTables(newTableWriter(util.GetOutputStream(), clusters), tables, allNamespaces, outputFormat == "wide", opts.NoHeaders)
+		return nil
+	}
+
+	// Label columns are added by kubectl in every cluster, and the tables merged by column name
+	if len(opts.LabelColumns) > 0 {
+		tables := fetchTablesWithAges(clusters, kubeconfig, func(context string) []string {
+			return buildLabelColumnsGetArgs(resourceType, resourceName, outputFormat, selector, namespace, allNamespaces, context, opts)
+		}, func(context string) []string {
+			return buildKubectlGetArgs(resourceType, resourceName, "json", selector, namespace, allNamespaces, context, opts.ChunkSize)
+		})
+		mergeTables(newTableWriter(util.GetOutputStream(), clusters), tables, opts.NoHeaders, time.Now())
+		return nil
+	}
+
+	// Table output formats are merged into a single table with one header and a CLUSTER column. The
+	// ages of -o wide are computed from the objects' JSON, as kubectl computes them at a different
+	// moment in every cluster.
+	if isTableOutput(outputFormat) {
+		buildArgs := func(context string) []string {
+			return buildKubectlGetArgs(resourceType, resourceName, outputFormat, selector, namespace, allNamespaces, context, opts.ChunkSize)
+		}
+		var tables []clusterTable
+		if outputFormat == "wide" {
+			tables = fetchTablesWithAges(clusters, kubeconfig, buildArgs, func(context string) []string {
+				return buildKubectlGetArgs(resourceType, resourceName, "json", selector, namespace, allNamespaces, context, opts.ChunkSize)
+			})
+		} else {
+			tables = fetchTables(clusters, kubeconfig, buildArgs)
+		}
+		mergeTables(newTableWriter(util.GetOutputStream(), clusters), tables, opts.NoHeaders, time.Now())
+		return nil
+	}
+
+	// JSON lines are streamed, one line per cluster as soon as it completes
+	if outputFormat == "jsonl" {
+		return handleGetJSONLines(clusters, unreachable, resourceName, resourceType, selector, namespace, allNamespaces, opts.ChunkSize)
+	}
+
 	// If output format is provided use custom output format handler instead of default table format
 	if outputFormat != "" {
-		return handleGetWithOutputFormat(clusters, resourceName, resourceType, outputFormat, selector, namespace, allNamespaces)
+		return handleGetWithOutputFormat(clusters, unreachable, resourceName, resourceType, outputFormat, selector, namespace, allNamespaces, opts.ChunkSize)
+	}
+
+	if opts.NoHeaders && strings.ToLower(resourceType) == "all" {
+		return fmt.Errorf("--no-headers cannot be used with 'get all', use --show-kind for a single table")
 	}
 
-	tw := tabwriter.NewWriter(util.GetOutputStream(), 0, 0, 2, ' ', 0)
+	tw := newTableWriter(out, clusters)
 	defer tw.Flush()
 
 	// Handle different resource types
@@ -180,11 +524,11 @@ func handleGetCommand(args []string, outputFormat, selector string, showLabels,
 	case "networkpolicies", "networkpolicy", "np":
 		return handleNetworkPoliciesGet(tw, clusters, resourceName, selector, showLabels, outputFormat, namespace, allNamespaces)
 	case "all":
-		return handleAllGet(tw, clusters, resourceName, selector, showLabels, outputFormat, namespace, allNamespaces)
+		return handleAllGet(tw, clusters, resourceName, selector, showLabels, outputFormat, namespace, allNamespaces, opts.ChunkSize)
 	case "nodes", "node", "no":
 		return handleNodesGet(tw, clusters, resourceName, selector, showLabels, outputFormat)
 	case "pods", "pod", "po":
-		return handlePodsGet(tw, clusters, resourceName, selector, showLabels, outputFormat, namespace, allNamespaces)
+		return handlePodsGet(tw, clusters, resourceName, selector, showLabels, outputFormat, namespace, allNamespaces, opts.ChunkSize)
 	case "services", "service", "svc":
 		return handleServicesGet(tw, clusters, resourceName, selector, showLabels, outputFormat, namespace, allNamespaces)
 	case "deployments", "deployment", "deploy":
@@ -216,7 +560,7 @@ func handleGetCommand(args []string, outputFormat, selector string, showLabels,
 	}
 }
 
-func handleServiceAccountsGet(tw *tabwriter.Writer, clusters []cluster.ClusterInfo, resourceName, selector string, showLabels bool, outputFormat, namespace string, allNamespaces bool) error {
+func handleServiceAccountsGet(tw tableWriter, clusters []cluster.ClusterInfo, resourceName, selector string, showLabels bool, outputFormat, namespace string, allNamespaces bool) error {
 	isHeaderPrint := false
 
 	for _, clusterInfo := range clusters {
@@ -224,7 +568,7 @@ func handleServiceAccountsGet(tw *tabwriter.Writer, clusters []cluster.ClusterIn
 			continue
 		}
 
-		targetNS := cluster.GetTargetNamespace(namespace)
+		targetNS := cluster.TargetNamespace(clusterInfo, namespace)
 		if allNamespaces {
 			targetNS = ""
 		}
@@ -300,7 +644,7 @@ func handleServiceAccountsGet(tw *tabwriter.Writer, clusters []cluster.ClusterIn
 	return nil
 }
 
-func handleEndpointsGet(tw *tabwriter.Writer, clusters []cluster.ClusterInfo, resourceName, selector string, showLabels bool, outputFormat, namespace string, allNamespaces bool) error {
+func handleEndpointsGet(tw tableWriter, clusters []cluster.ClusterInfo, resourceName, selector string, showLabels bool, outputFormat, namespace string, allNamespaces bool) error {
 	isHeaderPrint := false
 
 	for _, clusterInfo := range clusters {
@@ -308,7 +652,7 @@ func handleEndpointsGet(tw *tabwriter.Writer, clusters []cluster.ClusterInfo, re
 			continue
 		}
 
-		targetNS := cluster.GetTargetNamespace(namespace)
+		targetNS := cluster.TargetNamespace(clusterInfo, namespace)
 		if allNamespaces {
 			targetNS = ""
 		}
@@ -398,7 +742,7 @@ func handleEndpointsGet(tw *tabwriter.Writer, clusters []cluster.ClusterInfo, re
 	return nil
 }
 
-func handleResourceQuotasGet(tw *tabwriter.Writer, clusters []cluster.ClusterInfo, resourceName, selector string, showLabels bool, outputFormat, namespace string, allNamespaces bool) error {
+func handleResourceQuotasGet(tw tableWriter, clusters []cluster.ClusterInfo, resourceName, selector string, showLabels bool, outputFormat, namespace string, allNamespaces bool) error {
 	isHeaderPrint := false
 
 	for _, clusterInfo := range clusters {
@@ -406,7 +750,7 @@ func handleResourceQuotasGet(tw *tabwriter.Writer, clusters []cluster.ClusterInf
 			continue
 		}
 
-		targetNS := cluster.GetTargetNamespace(namespace)
+		targetNS := cluster.TargetNamespace(clusterInfo, namespace)
 		if allNamespaces {
 			targetNS = ""
 		}
@@ -524,7 +868,7 @@ func handleResourceQuotasGet(tw *tabwriter.Writer, clusters []cluster.ClusterInf
 	return nil
 }
 
-func handleLimitRangesGet(tw *tabwriter.Writer, clusters []cluster.ClusterInfo, resourceName, selector string, showLabels bool, outputFormat, namespace string, allNamespaces bool) error {
+func handleLimitRangesGet(tw tableWriter, clusters []cluster.ClusterInfo, resourceName, selector string, showLabels bool, outputFormat, namespace string, allNamespaces bool) error {
 	isHeaderPrint := false
 
 	for _, clusterInfo := range clusters {
@@ -532,7 +876,7 @@ func handleLimitRangesGet(tw *tabwriter.Writer, clusters []cluster.ClusterInfo,
 			continue
 		}
 
-		targetNS := cluster.GetTargetNamespace(namespace)
+		targetNS := cluster.TargetNamespace(clusterInfo, namespace)
 		if allNamespaces {
 			targetNS = ""
 		}
@@ -607,7 +951,7 @@ func handleLimitRangesGet(tw *tabwriter.Writer, clusters []cluster.ClusterInfo,
 	return nil
 }
 
-func handleIngressesGet(tw *tabwriter.Writer, clusters []cluster.ClusterInfo, resourceName, selector string, showLabels bool, outputFormat, namespace string, allNamespaces bool) error {
+func handleIngressesGet(tw tableWriter, clusters []cluster.ClusterInfo, resourceName, selector string, showLabels bool, outputFormat, namespace string, allNamespaces bool) error {
 	isHeaderPrint := false
 
 	for _, clusterInfo := range clusters {
@@ -615,7 +959,7 @@ func handleIngressesGet(tw *tabwriter.Writer, clusters []cluster.ClusterInfo, re
 			continue
 		}
 
-		targetNS := cluster.GetTargetNamespace(namespace)
+		targetNS := cluster.TargetNamespace(clusterInfo, namespace)
 		if allNamespaces {
 			targetNS = ""
 		}
@@ -746,7 +1090,7 @@ func handleIngressesGet(tw *tabwriter.Writer, clusters []cluster.ClusterInfo, re
 	return nil
 }
 
-func handleJobsGet(tw *tabwriter.Writer, clusters []cluster.ClusterInfo, resourceName, selector string, showLabels bool, outputFormat, namespace string, allNamespaces bool) error {
+func handleJobsGet(tw tableWriter, clusters []cluster.ClusterInfo, resourceName, selector string, showLabels bool, outputFormat, namespace string, allNamespaces bool) error {
 	isHeaderPrint := false
 
 	for _, clusterInfo := range clusters {
@@ -754,7 +1098,7 @@ func handleJobsGet(tw *tabwriter.Writer, clusters []cluster.ClusterInfo, resourc
 			continue
 		}
 
-		targetNS := cluster.GetTargetNamespace(namespace)
+		targetNS := cluster.TargetNamespace(clusterInfo, namespace)
 		if allNamespaces {
 			targetNS = ""
 		}
@@ -850,106 +1194,106 @@ func handleJobsGet(tw *tabwriter.Writer, clusters []cluster.ClusterInfo, resourc
 	return nil
 }
 
-func handleAllGet(tw *tabwriter.Writer, clusters []cluster.ClusterInfo, resourceName, selector string, showLabels bool, outputFormat, namespace string, allNamespaces bool) error {
+func handleAllGet(tw tableWriter, clusters []cluster.ClusterInfo, resourceName, selector string, showLabels bool, outputFormat, namespace string, allNamespaces bool, chunkSize int64) error {
 	fmt.Println("==> Pods")
-	if err := handlePodsGet(tw, clusters, resourceName, selector, showLabels, outputFormat, namespace, allNamespaces); err != nil {
+	if err := handlePodsGet(tw, clusters, resourceName, selector, showLabels, outputFormat, namespace, allNamespaces, chunkSize); err != nil {
 		return err
 	}
 	tw.Flush()
 
 	fmt.Println("\n==> Services")
-	tw = tabwriter.NewWriter(util.GetOutputStream(), 0, 0, 2, ' ', 0)
+	tw = newTableWriter(util.GetOutputStream(), clusters)
 	if err := handleServicesGet(tw, clusters, resourceName, selector, showLabels, outputFormat, namespace, allNamespaces); err != nil {
 		return err
 	}
 	tw.Flush()
 
 	fmt.Println("\n==> Deployments")
-	tw = tabwriter.NewWriter(util.GetOutputStream(), 0, 0, 2, ' ', 0)
+	tw = newTableWriter(util.GetOutputStream(), clusters)
 	if err := handleDeploymentsGet(tw, clusters, resourceName, selector, showLabels, outputFormat, namespace, allNamespaces); err != nil {
 		return err
 	}
 	tw.Flush()
 
 	fmt.Println("\n==> Jobs")
-	tw = tabwriter.NewWriter(util.GetOutputStream(), 0, 0, 2, ' ', 0)
+	tw = newTableWriter(util.GetOutputStream(), clusters)
 	if err := handleJobsGet(tw, clusters, resourceName, selector, showLabels, outputFormat, namespace, allNamespaces); err != nil {
 		return err
 	}
 	tw.Flush()
 
 	fmt.Println("\n==> CronJobs")
-	tw = tabwriter.NewWriter(util.GetOutputStream(), 0, 0, 2, ' ', 0)
+	tw = newTableWriter(util.GetOutputStream(), clusters)
 	if err := handleCronJobsGet(tw, clusters, resourceName, selector, showLabels, outputFormat, namespace, allNamespaces); err != nil {
 		return err
 	}
 	tw.Flush()
 
 	fmt.Println("\n==> Nodes")
-	tw = tabwriter.NewWriter(util.GetOutputStream(), 0, 0, 2, ' ', 0)
+	tw = newTableWriter(util.GetOutputStream(), clusters)
 	if err := handleNodesGet(tw, clusters, resourceName, selector, showLabels, outputFormat); err != nil {
 		return err
 	}
 	tw.Flush()
 
 	fmt.Println("\n==> ReplicaSets")
-	tw = tabwriter.NewWriter(util.GetOutputStream(), 0, 0, 2, ' ', 0)
+	tw = newTableWriter(util.GetOutputStream(), clusters)
 	if err := handleReplicaSetsGet(tw, clusters, resourceName, selector, showLabels, outputFormat, namespace, allNamespaces); err != nil {
 		return err
 	}
 	tw.Flush()
 
 	fmt.Println("\n==> DaemonSets")
-	tw = tabwriter.NewWriter(util.GetOutputStream(), 0, 0, 2, ' ', 0)
+	tw = newTableWriter(util.GetOutputStream(), clusters)
 	if err := handleDaemonSetsGet(tw, clusters, resourceName, selector, showLabels, outputFormat, namespace, allNamespaces); err != nil {
 		return err
 	}
 	tw.Flush()
 
 	fmt.Println("\n==> Namespaces")
-	tw = tabwriter.NewWriter(util.GetOutputStream(), 0, 0, 2, ' ', 0)
+	tw = newTableWriter(util.GetOutputStream(), clusters)
 	if err := handleNamespacesGet(tw, clusters, resourceName, selector, showLabels, outputFormat); err != nil {
 		return err
 	}
 	tw.Flush()
 
 	fmt.Println("\n==> ConfigMaps")
-	tw = tabwriter.NewWriter(util.GetOutputStream(), 0, 0, 2, ' ', 0)
+	tw = newTableWriter(util.GetOutputStream(), clusters)
 	if err := handleConfigMapsGet(tw, clusters, resourceName, selector, showLabels, outputFormat, namespace, allNamespaces); err != nil {
 		return err
 	}
 	tw.Flush()
 
 	fmt.Println("\n==> StatefulSets")
-	tw = tabwriter.NewWriter(util.GetOutputStream(), 0, 0, 2, ' ', 0)
+	tw = newTableWriter(util.GetOutputStream(), clusters)
 	if err := handleStatefulSetsGet(tw, clusters, resourceName, selector, showLabels, outputFormat, namespace, allNamespaces); err != nil {
 		return err
 	}
 	tw.Flush()
 
 	fmt.Println("\n==> Secrets")
-	tw = tabwriter.NewWriter(util.GetOutputStream(), 0, 0, 2, ' ', 0)
+	tw = newTableWriter(util.GetOutputStream(), clusters)
 	if err := handleSecretsGet(tw, clusters, resourceName, selector, showLabels, outputFormat, namespace, allNamespaces); err != nil {
 		return err
 	}
 	tw.Flush()
 
 	fmt.Println("\n==> PersistentVolumes")
-	tw = tabwriter.NewWriter(util.GetOutputStream(), 0, 0, 2, ' ', 0)
+	tw = newTableWriter(util.GetOutputStream(), clusters)
 	if err := handlePVGet(tw, clusters, resourceName, selector, showLabels, outputFormat); err != nil {
 		return err
 	}
 	tw.Flush()
 
 	fmt.Println("\n==> PersistentVolumeClaims")
-	tw = tabwriter.NewWriter(util.GetOutputStream(), 0, 0, 2, ' ', 0)
+	tw = newTableWriter(util.GetOutputStream(), clusters)
 	if err := handlePVCGet(tw, clusters, resourceName, selector, showLabels, outputFormat, namespace, allNamespaces); err != nil {
 		return err
 	}
 	tw.Flush()
 
 	fmt.Println("\n==> Roles")
-	tw = tabwriter.NewWriter(util.GetOutputStream(), 0, 0, 2, ' ', 0)
+	tw = newTableWriter(util.GetOutputStream(), clusters)
 	if err := handleRolesGet(tw, clusters, resourceName, selector, showLabels, outputFormat, namespace, allNamespaces); err != nil {
 		return err
 	}
@@ -957,7 +1301,7 @@ func handleAllGet(tw *tabwriter.Writer, clusters []cluster.ClusterInfo, resource
 
 	return nil
 }
-func handleNodesGet(tw *tabwriter.Writer, clusters []cluster.ClusterInfo, resourceName, selector string, showLabels bool, outputFormat string) error {
+func handleNodesGet(tw tableWriter, clusters []cluster.ClusterInfo, resourceName, selector string, showLabels bool, outputFormat string) error {
 	// Print header only once at the top
 	if showLabels {
 		fmt.Fprintf(tw, "CLUSTER\tNAME\tSTATUS\tROLES\tAGE\tVERSION\tLABELS\n")
@@ -1001,7 +1345,30 @@ func handleNodesGet(tw *tabwriter.Writer, clusters []cluster.ClusterInfo, resour
 	return nil
 }
 
-func handlePodsGet(tw *tabwriter.Writer, clusters []cluster.ClusterInfo, resourceName, selector string, showLabels bool, outputFormat, namespace string, allNamespaces bool) error {
+// listPodsChunked lists pods chunkSize at a time, following the continue token of every page, and
+// merges the pages into a single list. A chunkSize of 0 lists all pods at once.
+func listPodsChunked(ctx context.Context, list func(context.Context, metav1.ListOptions) (*corev1.PodList, error), selector string, chunkSize int64) (*corev1.PodList, error) {
+	opts := metav1.ListOptions{LabelSelector: selector, Limit: chunkSize}
+	var pods *corev1.PodList
+	for {
+		page, err := list(ctx, opts)
+		if err != nil {
+			return nil, err
+		}
+		if pods == nil {
+			pods = page
+		} else {
+			pods.Items = append(pods.Items, page.Items...)
+		}
+		if page.Continue == "" {
+			pods.Continue = ""
+			return pods, nil
+		}
+		opts.Continue = page.Continue
+	}
+}
+
+func handlePodsGet(tw tableWriter, clusters []cluster.ClusterInfo, resourceName, selector string, showLabels bool, outputFormat, namespace string, allNamespaces bool, chunkSize int64) error {
 	isHeaderPrint := false
 
 	for _, clusterInfo := range clusters {
@@ -1009,14 +1376,12 @@ func handlePodsGet(tw *tabwriter.Writer, clusters []cluster.ClusterInfo, resourc
 			continue
 		}
 
-		targetNS := cluster.GetTargetNamespace(namespace)
+		targetNS := cluster.TargetNamespace(clusterInfo, namespace)
 		if allNamespaces {
 			targetNS = ""
 		}
 
-		pods, err := clusterInfo.Client.CoreV1().Pods(targetNS).List(context.TODO(), metav1.ListOptions{
-			LabelSelector: selector,
-		})
+		pods, err := listPodsChunked(context.TODO(), clusterInfo.Client.CoreV1().Pods(targetNS).List, selector, chunkSize)
 		if err != nil {
 			fmt.Printf("Warning: failed to list pods in cluster %s: %v\n", clusterInfo.Name, err)
 			continue
@@ -1086,7 +1451,7 @@ func handlePodsGet(tw *tabwriter.Writer, clusters []cluster.ClusterInfo, resourc
 	return nil
 }
 
-func handleServicesGet(tw *tabwriter.Writer, clusters []cluster.ClusterInfo, resourceName, selector string, showLabels bool, outputFormat, namespace string, allNamespaces bool) error {
+func handleServicesGet(tw tableWriter, clusters []cluster.ClusterInfo, resourceName, selector string, showLabels bool, outputFormat, namespace string, allNamespaces bool) error {
 	isHeaderPrint := false
 
 	for _, clusterInfo := range clusters {
@@ -1094,7 +1459,7 @@ func handleServicesGet(tw *tabwriter.Writer, clusters []cluster.ClusterInfo, res
 			continue
 		}
 
-		targetNS := cluster.GetTargetNamespace(namespace)
+		targetNS := cluster.TargetNamespace(clusterInfo, namespace)
 		if allNamespaces {
 			targetNS = ""
 		}
@@ -1174,7 +1539,7 @@ func handleServicesGet(tw *tabwriter.Writer, clusters []cluster.ClusterInfo, res
 	return nil
 }
 
-func handleDeploymentsGet(tw *tabwriter.Writer, clusters []cluster.ClusterInfo, resourceName, selector string, showLabels bool, outputFormat, namespace string, allNamespaces bool) error {
+func handleDeploymentsGet(tw tableWriter, clusters []cluster.ClusterInfo, resourceName, selector string, showLabels bool, outputFormat, namespace string, allNamespaces bool) error {
 	isHeaderPrint := false
 
 	for _, clusterInfo := range clusters {
@@ -1182,7 +1547,7 @@ func handleDeploymentsGet(tw *tabwriter.Writer, clusters []cluster.ClusterInfo,
 			continue
 		}
 
-		targetNS := cluster.GetTargetNamespace(namespace)
+		targetNS := cluster.TargetNamespace(clusterInfo, namespace)
 		if allNamespaces {
 			targetNS = ""
 		}
@@ -1264,7 +1629,7 @@ func handleDeploymentsGet(tw *tabwriter.Writer, clusters []cluster.ClusterInfo,
 	return nil
 }
 
-func handleNamespacesGet(tw *tabwriter.Writer, clusters []cluster.ClusterInfo, resourceName, selector string, showLabels bool, outputFormat string) error {
+func handleNamespacesGet(tw tableWriter, clusters []cluster.ClusterInfo, resourceName, selector string, showLabels bool, outputFormat string) error {
 	// Print header only once at the top
 	if showLabels {
 		fmt.Fprintf(tw, "CLUSTER\tNAME\tSTATUS\tAGE\tLABELS\n")
@@ -1306,7 +1671,7 @@ func handleNamespacesGet(tw *tabwriter.Writer, clusters []cluster.ClusterInfo, r
 	return nil
 }
 
-func handleConfigMapsGet(tw *tabwriter.Writer, clusters []cluster.ClusterInfo, resourceName, selector string, showLabels bool, outputFormat, namespace string, allNamespaces bool) error {
+func handleConfigMapsGet(tw tableWriter, clusters []cluster.ClusterInfo, resourceName, selector string, showLabels bool, outputFormat, namespace string, allNamespaces bool) error {
 	isHeaderPrint := false
 
 	for _, clusterInfo := range clusters {
@@ -1314,7 +1679,7 @@ func handleConfigMapsGet(tw *tabwriter.Writer, clusters []cluster.ClusterInfo, r
 			continue
 		}
 
-		targetNS := cluster.GetTargetNamespace(namespace)
+		targetNS := cluster.TargetNamespace(clusterInfo, namespace)
 		if allNamespaces {
 			targetNS = ""
 		}
@@ -1389,7 +1754,7 @@ func handleConfigMapsGet(tw *tabwriter.Writer, clusters []cluster.ClusterInfo, r
 	return nil
 }
 
-func handleSecretsGet(tw *tabwriter.Writer, clusters []cluster.ClusterInfo, resourceName, selector string, showLabels bool, outputFormat, namespace string, allNamespaces bool) error {
+func handleSecretsGet(tw tableWriter, clusters []cluster.ClusterInfo, resourceName, selector string, showLabels bool, outputFormat, namespace string, allNamespaces bool) error {
 	isHeaderPrint := false
 
 	for _, clusterInfo := range clusters {
@@ -1397,7 +1762,7 @@ func handleSecretsGet(tw *tabwriter.Writer, clusters []cluster.ClusterInfo, reso
 			continue
 		}
 
-		targetNS := cluster.GetTargetNamespace(namespace)
+		targetNS := cluster.TargetNamespace(clusterInfo, namespace)
 		if allNamespaces {
 			targetNS = ""
 		}
@@ -1474,7 +1839,7 @@ func handleSecretsGet(tw *tabwriter.Writer, clusters []cluster.ClusterInfo, reso
 	return nil
 }
 
-func handlePVGet(tw *tabwriter.Writer, clusters []cluster.ClusterInfo, resourceName, selector string, showLabels bool, outputFormat string) error {
+func handlePVGet(tw tableWriter, clusters []cluster.ClusterInfo, resourceName, selector string, showLabels bool, outputFormat string) error {
 	isHeaderPrint := false
 
 	for _, clusterInfo := range clusters {
@@ -1531,7 +1896,7 @@ func handlePVGet(tw *tabwriter.Writer, clusters []cluster.ClusterInfo, resourceN
 	return nil
 }
 
-func handlePVCGet(tw *tabwriter.Writer, clusters []cluster.ClusterInfo, resourceName, selector string, showLabels bool, outputFormat, namespace string, allNamespaces bool) error {
+func handlePVCGet(tw tableWriter, clusters []cluster.ClusterInfo, resourceName, selector string, showLabels bool, outputFormat, namespace string, allNamespaces bool) error {
 	isHeaderPrint := false
 
 	for _, clusterInfo := range clusters {
@@ -1539,7 +1904,7 @@ func handlePVCGet(tw *tabwriter.Writer, clusters []cluster.ClusterInfo, resource
 			continue
 		}
 
-		targetNS := cluster.GetTargetNamespace(namespace)
+		targetNS := cluster.TargetNamespace(clusterInfo, namespace)
 		if allNamespaces {
 			targetNS = ""
 		}
@@ -1618,7 +1983,7 @@ func handlePVCGet(tw *tabwriter.Writer, clusters []cluster.ClusterInfo, resource
 	return nil
 }
 
-func handleGenericGet(tw *tabwriter.Writer, clusters []cluster.ClusterInfo, resourceType, resourceName, selector string, showLabels bool, outputFormat, namespace string, allNamespaces bool) error {
+func handleGenericGet(tw tableWriter, clusters []cluster.ClusterInfo, resourceType, resourceName, selector string, showLabels bool, outputFormat, namespace string, allNamespaces bool) error {
 	isHeaderPrint := false
 
 	for _, clusterInfo := range clusters {
@@ -1633,7 +1998,7 @@ func handleGenericGet(tw *tabwriter.Writer, clusters []cluster.ClusterInfo, reso
 			continue
 		}
 
-		targetNS := cluster.GetTargetNamespace(namespace)
+		targetNS := cluster.TargetNamespace(clusterInfo, namespace)
 		var list *unstructured.UnstructuredList
 
 		if isNamespaced && !allNamespaces && targetNS != "" {
@@ -1714,7 +2079,7 @@ func handleGenericGet(tw *tabwriter.Writer, clusters []cluster.ClusterInfo, reso
 	return nil
 }
 
-func handleReplicaSetsGet(tw *tabwriter.Writer, clusters []cluster.ClusterInfo, resourceName, selector string, showLabels bool, outputFormat, namespace string, allNamespaces bool) error {
+func handleReplicaSetsGet(tw tableWriter, clusters []cluster.ClusterInfo, resourceName, selector string, showLabels bool, outputFormat, namespace string, allNamespaces bool) error {
 	isHeaderPrint := false
 
 	for _, clusterInfo := range clusters {
@@ -1722,7 +2087,7 @@ func handleReplicaSetsGet(tw *tabwriter.Writer, clusters []cluster.ClusterInfo,
 			continue
 		}
 
-		targetNS := cluster.GetTargetNamespace(namespace)
+		targetNS := cluster.TargetNamespace(clusterInfo, namespace)
 		if allNamespaces {
 			targetNS = ""
 		}
@@ -1802,7 +2167,7 @@ func handleReplicaSetsGet(tw *tabwriter.Writer, clusters []cluster.ClusterInfo,
 	return nil
 }
 
-func handleStatefulSetsGet(tw *tabwriter.Writer, clusters []cluster.ClusterInfo, resourceName, selector string, showLabels bool, outputFormat, namespace string, allNamespaces bool) error {
+func handleStatefulSetsGet(tw tableWriter, clusters []cluster.ClusterInfo, resourceName, selector string, showLabels bool, outputFormat, namespace string, allNamespaces bool) error {
 	isHeaderPrint := false
 
 	for _, clusterInfo := range clusters {
@@ -1810,7 +2175,7 @@ func handleStatefulSetsGet(tw *tabwriter.Writer, clusters []cluster.ClusterInfo,
 			continue
 		}
 
-		targetNS := cluster.GetTargetNamespace(namespace)
+		targetNS := cluster.TargetNamespace(clusterInfo, namespace)
 		if allNamespaces {
 			targetNS = ""
 		}
@@ -1889,7 +2254,7 @@ func handleStatefulSetsGet(tw *tabwriter.Writer, clusters []cluster.ClusterInfo,
 	return nil
 }
 
-func handleDaemonSetsGet(tw *tabwriter.Writer, clusters []cluster.ClusterInfo, resourceName, selector string, showLabels bool, outputFormat, namespace string, allNamespaces bool) error {
+func handleDaemonSetsGet(tw tableWriter, clusters []cluster.ClusterInfo, resourceName, selector string, showLabels bool, outputFormat, namespace string, allNamespaces bool) error {
 	isHeaderPrint := false
 
 	for _, clusterInfo := range clusters {
@@ -1897,7 +2262,7 @@ func handleDaemonSetsGet(tw *tabwriter.Writer, clusters []cluster.ClusterInfo, r
 			continue
 		}
 
-		targetNS := cluster.GetTargetNamespace(namespace)
+		targetNS := cluster.TargetNamespace(clusterInfo, namespace)
 		if allNamespaces {
 			targetNS = ""
 		}
@@ -1986,7 +2351,7 @@ func handleDaemonSetsGet(tw *tabwriter.Writer, clusters []cluster.ClusterInfo, r
 	return nil
 }
 
-func handleCronJobsGet(tw *tabwriter.Writer, clusters []cluster.ClusterInfo, resourceName, selector string, showLabels bool, outputFormat, namespace string, allNamespaces bool) error {
+func handleCronJobsGet(tw tableWriter, clusters []cluster.ClusterInfo, resourceName, selector string, showLabels bool, outputFormat, namespace string, allNamespaces bool) error {
 	isHeaderPrint := false
 
 	for _, clusterInfo := range clusters {
@@ -1994,7 +2359,7 @@ func handleCronJobsGet(tw *tabwriter.Writer, clusters []cluster.ClusterInfo, res
 			continue
 		}
 
-		targetNS := cluster.GetTargetNamespace(namespace)
+		targetNS := cluster.TargetNamespace(clusterInfo, namespace)
 		if allNamespaces {
 			targetNS = ""
 		}
@@ -2081,7 +2446,7 @@ func handleCronJobsGet(tw *tabwriter.Writer, clusters []cluster.ClusterInfo, res
 	return nil
 }
 
-func handleEventsGet(tw *tabwriter.Writer, clusters []cluster.ClusterInfo, resourceName, selector string, showLabels bool, outputFormat, namespace string, allNamespaces bool) error {
+func handleEventsGet(tw tableWriter, clusters []cluster.ClusterInfo, resourceName, selector string, showLabels bool, outputFormat, namespace string, allNamespaces bool) error {
 	if allNamespaces {
 		if showLabels {
 			fmt.Fprintf(tw, "CLUSTER\tNAMESPACE\tLAST SEEN\tTYPE\tREASON\tOBJECT\tMESSAGE\tLABELS\n")
@@ -2101,7 +2466,7 @@ func handleEventsGet(tw *tabwriter.Writer, clusters []cluster.ClusterInfo, resou
 			continue
 		}
 
-		targetNS := cluster.GetTargetNamespace(namespace)
+		targetNS := cluster.TargetNamespace(clusterInfo, namespace)
 		if allNamespaces {
 			targetNS = ""
 		}
@@ -2155,7 +2520,7 @@ func handleEventsGet(tw *tabwriter.Writer, clusters []cluster.ClusterInfo, resou
 	return nil
 }
 
-func handleNetworkPoliciesGet(tw *tabwriter.Writer, clusters []cluster.ClusterInfo, resourceName, selector string, showLabels bool, outputFormat, namespace string, allNamespaces bool) error {
+func handleNetworkPoliciesGet(tw tableWriter, clusters []cluster.ClusterInfo, resourceName, selector string, showLabels bool, outputFormat, namespace string, allNamespaces bool) error {
 	isHeaderPrint := false
 
 	for _, clusterInfo := range clusters {
@@ -2163,7 +2528,7 @@ func handleNetworkPoliciesGet(tw *tabwriter.Writer, clusters []cluster.ClusterIn
 			continue
 		}
 
-		targetNS := cluster.GetTargetNamespace(namespace)
+		targetNS := cluster.TargetNamespace(clusterInfo, namespace)
 		if allNamespaces {
 			targetNS = ""
 		}
@@ -2254,7 +2619,7 @@ func handleNetworkPoliciesGet(tw *tabwriter.Writer, clusters []cluster.ClusterIn
 	return nil
 }
 
-func handleRolesGet(tw *tabwriter.Writer, clusters []cluster.ClusterInfo, resourceName, selector string, showLabels bool, outputFormat, namespace string, allNamespaces bool) error {
+func handleRolesGet(tw tableWriter, clusters []cluster.ClusterInfo, resourceName, selector string, showLabels bool, outputFormat, namespace string, allNamespaces bool) error {
 	isHeaderPrint := false
 
 	for _, clusterInfo := range clusters {
@@ -2262,7 +2627,7 @@ func handleRolesGet(tw *tabwriter.Writer, clusters []cluster.ClusterInfo, resour
 			continue
 		}
 
-		targetNS := cluster.GetTargetNamespace(namespace)
+		targetNS := cluster.TargetNamespace(clusterInfo, namespace)
 		if allNamespaces {
 			targetNS = ""
 		}
@@ -2335,7 +2700,7 @@ func handleRolesGet(tw *tabwriter.Writer, clusters []cluster.ClusterInfo, resour
 	return nil
 }
 
-func handleStorageClassesGet(tw *tabwriter.Writer, clusters []cluster.ClusterInfo, resourceName, selector string, showLabels bool, outputFormat string) error {
+func handleStorageClassesGet(tw tableWriter, clusters []cluster.ClusterInfo, resourceName, selector string, showLabels bool, outputFormat string) error {
 	isHeaderPrint := false
 
 	for _, clusterInfo := range clusters {
@@ -2407,72 +2772,49 @@ func handleStorageClassesGet(tw *tabwriter.Writer, clusters []cluster.ClusterInf
 }
 
 // handleGetWithOutputFormat handles get command when output format is provided
-func handleGetWithOutputFormat(clusters []cluster.ClusterInfo, resourceName, resourceType, outputFormat, selector string, namespace string, allNamespaces bool) error {
+func handleGetWithOutputFormat(clusters, unreachable []cluster.ClusterInfo, resourceName, resourceType, outputFormat, selector string, namespace string, allNamespaces bool, chunkSize int64) error {
 
-	// Find current context from kubeconfig
-	currentContext := ""
-	{
-		loading := clientcmd.NewDefaultClientConfigLoadingRules()
-		if kubeconfig != "" {
-			loading.ExplicitPath = kubeconfig
-		}
-		cfg := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loading, &clientcmd.ConfigOverrides{})
-		rawCfg, err := cfg.RawConfig()
-		if err == nil {
-			currentContext = rawCfg.CurrentContext
-		}
-	}
-
-	// Identify ITS (control) cluster context
-	itsContext := remoteCtx
+	opts := fanOutOptions{Namespace: namespaceOption(namespace, allNamespaces), Unreachable: unreachable}
+	_, err := fanOut(clusters, kubeconfig, remoteCtx, opts, kubectlOp(kubeconfig, func(c cluster.ClusterInfo) []string {
+		return buildKubectlGetArgs(resourceType, resourceName, outputFormat, selector, namespace, allNamespaces, c.Context, chunkSize)
+	}))
+	return err
+}
 
-	// Build maps for quick lookup
-	contextToCluster := make(map[string]cluster.ClusterInfo)
-	for _, c := range clusters {
-		contextToCluster[c.Context] = c
+// handleGetSubresource gets the subresource of the requested objects with kubectl. Tables are
+// merged with a CLUSTER column, other output formats are printed per cluster.
+func handleGetSubresource(clusters, unreachable []cluster.ClusterInfo, resourceName, resourceType, outputFormat, selector, namespace string, allNamespaces bool, opts getOptions) error {
+	buildArgs := func(context string) []string {
+		args := buildKubectlGetArgs(resourceType, resourceName, outputFormat, selector, namespace, allNamespaces, context, opts.ChunkSize)
+		return append(args, "--subresource="+opts.Subresource)
 	}
-
-	// 1. Run for current context (if present)
-	if cinfo, ok := contextToCluster[currentContext]; ok {
-		kubectlArgs := buildKubectlGetArgs(resourceType, resourceName, outputFormat, selector, namespace, allNamespaces, cinfo.Context)
-		output, err := runKubectlGet(kubectlArgs, kubeconfig)
-		fmt.Printf("=== Cluster: %s ===\n", cinfo.Context)
-		if err != nil {
-			fmt.Printf("Error: %v\n", err)
-		} else {
-			fmt.Print(output)
-		}
-		fmt.Println()
+	if outputFormat == "" || isTableOutput(outputFormat) {
+		mergeTables(newTableWriter(util.GetOutputStream(), clusters), fetchTables(clusters, kubeconfig, buildArgs), opts.NoHeaders, time.Now())
+		return nil
 	}
 
-	// 2. Run for KubeStellar clusters (excluding ITS and current)
-	for _, c := range clusters {
-		if c.Context == currentContext || c.Context == itsContext {
-			continue
-		}
-		kubectlArgs := buildKubectlGetArgs(resourceType, resourceName, outputFormat, selector, namespace, allNamespaces, c.Context)
-		output, err := runKubectlGet(kubectlArgs, kubeconfig)
-		fmt.Printf("=== Cluster: %s ===\n", c.Context)
-		if err != nil {
-			fmt.Printf("Error: %v\n", err)
-		} else {
-			fmt.Print(output)
-		}
-		fmt.Println()
-	}
+	fanOpts := fanOutOptions{Namespace: namespaceOption(namespace, allNamespaces), Unreachable: unreachable}
+	_, err := fanOut(clusters, kubeconfig, remoteCtx, fanOpts, kubectlOp(kubeconfig, func(c cluster.ClusterInfo) []string {
+		return buildArgs(c.Context)
+	}))
+	return err
+}
 
-	// 3. Print warning for ITS (control) cluster
-	if cinfo, ok := contextToCluster[itsContext]; ok {
-		fmt.Printf("=== Cluster: %s ===\n", cinfo.Context)
-		fmt.Printf("Cannot perform this operation on ITS (control) cluster: %s\n", cinfo.Context)
-		fmt.Println()
+// buildLabelColumnsGetArgs builds the kubectl get arguments adding the -L label columns of opts
+func buildLabelColumnsGetArgs(resourceType, resourceName, outputFormat, selector, namespace string, allNamespaces bool, context string, opts getOptions) []string {
+	args := buildKubectlGetArgs(resourceType, resourceName, outputFormat, selector, namespace, allNamespaces, context, opts.ChunkSize)
+	if opts.ShowLabels {
+		args = append(args, "--show-labels")
 	}
-
-	return nil
+	return append(args, "-L", strings.Join(opts.LabelColumns, ","))
 }
 
-// buildKubectlGetArgs builds kubectl get command arguments
-func buildKubectlGetArgs(resourceType, resourceName, outputFormat, selector, namespace string, allNamespaces bool, context string) []string {
+// defaultChunkSize is kubectl's default --chunk-size
+const defaultChunkSize int64 = 500
+
+// buildKubectlGetArgs builds kubectl get command arguments. --chunk-size is only passed when it
+// differs from kubectl's default.
+func buildKubectlGetArgs(resourceType, resourceName, outputFormat, selector, namespace string, allNamespaces bool, context string, chunkSize int64) []string {
 	args := []string{"get", resourceType}
 
 	if resourceName != "" {
@@ -2495,20 +2837,9 @@ func buildKubectlGetArgs(resourceType, resourceName, outputFormat, selector, nam
 
 	args = append(args, "--context", context)
 
-	return args
-}
-
-// runKubectlGet runs a kubectl command with the given args and kubeconfig, returns output and error
-func runKubectlGet(args []string, kubeconfig string) (string, error) {
-	cmd := exec.Command("kubectl", args...)
-	if kubeconfig != "" {
-		cmd.Env = append(os.Environ(), "KUBECONFIG="+kubeconfig)
-	}
-	var stdout, stderr bytes.Buffer
-	cmd.Stdout = &stdout
-	cmd.Stderr = &stderr
-	if err := cmd.Run(); err != nil {
-		return stdout.String() + stderr.String(), err
+	if chunkSize != defaultChunkSize {
+		args = append(args, fmt.Sprintf("--chunk-size=%d", chunkSize))
 	}
-	return stdout.String(), nil
+
+	return args
 }
@@ -0,0 +1,123 @@
+package cmd
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	"k8s.io/client-go/util/jsonpath"
+)
+
+// defaultCSVColumns are the columns of -o csv after CLUSTER when --columns is not given
+const defaultCSVColumns = "namespace,kind,name,created"
+
+// csvColumn is a column of -o csv, a field of the resource or of its cluster
+type csvColumn struct {
+	Header string
+	value  func(o clusterObject) string
+}
+
+// csvBuiltinColumns are the columns --columns accepts by name
+var csvBuiltinColumns = map[string]func(o clusterObject) string{
+	"context":   func(o clusterObject) string { return o.Context },
+	"namespace": func(o clusterObject) string { return o.Object.GetNamespace() },
+	"kind":      func(o clusterObject) string { return o.Object.GetKind() },
+	"name":      func(o clusterObject) string { return o.Object.GetName() },
+	"created": func(o clusterObject) string {
+		if created := o.Object.GetCreationTimestamp(); !created.IsZero() {
+			return created.UTC().Format("2006-01-02T15:04:05Z")
+		}
+		return ""
+	},
+}
+
+// parseCSVColumns parses --columns, a comma-separated list of built-in column names (context,
+// namespace, kind, name and created) and HEADER:JSONPATH columns as with -o custom-columns
+func parseCSVColumns(spec string) ([]csvColumn, error) {
+	var columns []csvColumn
+	for _, entry := range strings.Split(spec, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		header, path, custom := strings.Cut(entry, ":")
+		if !custom {
+			value, ok := csvBuiltinColumns[strings.ToLower(entry)]
+			if !ok {
+				return nil, fmt.Errorf("invalid --columns: unknown column %q, use context, namespace, kind, name, created or HEADER:JSONPATH", entry)
+			}
+			columns = append(columns, csvColumn{Header: strings.ToUpper(entry), value: value})
+			continue
+		}
+		if header == "" || path == "" {
+			return nil, fmt.Errorf("invalid --columns: %q must be HEADER:JSONPATH", entry)
+		}
+		jp, err := parseFieldPath(path)
+		if err != nil {
+			return nil, fmt.Errorf("invalid --columns: %s: %v", header, err)
+		}
+		columns = append(columns, csvColumn{Header: header, value: func(o clusterObject) string {
+			return csvFieldValue(jp, o.Object.Object)
+		}})
+	}
+	if len(columns) == 0 {
+		return nil, fmt.Errorf("invalid --columns: no columns given")
+	}
+	return columns, nil
+}
+
+// csvFieldValue returns the values of the field in obj, separated by commas when the path matches
+// several. Lists and objects are written as JSON and missing fields as an empty string.
+func csvFieldValue(jp *jsonpath.JSONPath, obj map[string]interface{}) string {
+	results, err := jp.FindResults(obj)
+	if err != nil {
+		return ""
+	}
+	var values []string
+	for _, result := range results {
+		for _, v := range result {
+			if !v.IsValid() || !v.CanInterface() {
+				continue
+			}
+			switch x := v.Interface().(type) {
+			case nil:
+			case string:
+				values = append(values, x)
+			case map[string]interface{}, []interface{}:
+				data, _ := json.Marshal(x)
+				values = append(values, string(data))
+			default:
+				values = append(values, fmt.Sprint(x))
+			}
+		}
+	}
+	return strings.Join(values, ",")
+}
+
+// writeCSV writes one row per object with its cluster followed by the columns. Values are quoted as
+// needed, so commas, quotes and newlines in them are kept.
+func writeCSV(out io.Writer, columns []csvColumn, objects []clusterObject, noHeaders bool) error {
+	w := csv.NewWriter(out)
+	if !noHeaders {
+		header := []string{"CLUSTER"}
+		for _, c := range columns {
+			header = append(header, c.Header)
+		}
+		if err := w.Write(header); err != nil {
+			return err
+		}
+	}
+	for _, o := range objects {
+		row := []string{o.Cluster}
+		for _, c := range columns {
+			row = append(row, c.value(o))
+		}
+		if err := w.Write(row); err != nil {
+			return err
+		}
+	}
+	w.Flush()
+	return w.Error()
+}
@@ -0,0 +1,85 @@
+package cmd
+
+import (
+	"bytes"
+	"encoding/csv"
+	"strings"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func newCSVTestObject(cluster string, obj map[string]interface{}) clusterObject {
+	return clusterObject{Cluster: cluster, Context: cluster + "-ctx", Object: &unstructured.Unstructured{Object: obj}}
+}
+
+// TestParseCSVColumns ensures built-in and JSONPath columns are accepted and unknown ones rejected
+func TestParseCSVColumns(t *testing.T) {
+	columns, err := parseCSVColumns("name, context,IMAGE:.spec.containers[*].image")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var headers []string
+	for _, c := range columns {
+		headers = append(headers, c.Header)
+	}
+	if strings.Join(headers, ",") != "NAME,CONTEXT,IMAGE" {
+		t.Errorf("unexpected headers %v", headers)
+	}
+
+	for _, spec := range []string{"uid", "IMAGE:", ":.spec", "BAD:{.spec", ""} {
+		if _, err := parseCSVColumns(spec); err == nil {
+			t.Errorf("expected an error for %q", spec)
+		}
+	}
+}
+
+// TestWriteCSVQuoting ensures values with commas, quotes and newlines survive a round trip and every
+// row starts with its cluster
+func TestWriteCSVQuoting(t *testing.T) {
+	objects := []clusterObject{
+		newCSVTestObject("cluster1", map[string]interface{}{
+			"kind":     "ConfigMap",
+			"metadata": map[string]interface{}{"name": "settings", "namespace": "shop"},
+			"data":     map[string]interface{}{"note": "a, \"quoted\"\nmultiline value"},
+		}),
+		newCSVTestObject("cluster2", map[string]interface{}{
+			"kind":     "Pod",
+			"metadata": map[string]interface{}{"name": "web", "namespace": "shop"},
+			"spec": map[string]interface{}{"containers": []interface{}{
+				map[string]interface{}{"image": "nginx:1.25"},
+				map[string]interface{}{"image": "envoy:1.28"},
+			}},
+		}),
+	}
+	columns, err := parseCSVColumns("kind,name,NOTE:.data.note,IMAGES:.spec.containers[*].image,CONTAINERS:.spec.containers")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	if err := writeCSV(&out, columns, objects, false); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	records, err := csv.NewReader(&out).ReadAll()
+	if err != nil {
+		t.Fatalf("output is not valid CSV: %v\n%s", err, out.String())
+	}
+	if len(records) != 3 || strings.Join(records[0], ",") != "CLUSTER,KIND,NAME,NOTE,IMAGES,CONTAINERS" {
+		t.Fatalf("unexpected records %q", records)
+	}
+	if records[1][0] != "cluster1" || records[1][3] != "a, \"quoted\"\nmultiline value" {
+		t.Errorf("unexpected first row %q", records[1])
+	}
+	if records[2][3] != "" || records[2][4] != "nginx:1.25,envoy:1.28" || records[2][5] != `[{"image":"nginx:1.25"},{"image":"envoy:1.28"}]` {
+		t.Errorf("unexpected second row %q", records[2])
+	}
+
+	out.Reset()
+	if err := writeCSV(&out, columns, objects[:1], true); err != nil {
+		t.Fatal(err)
+	}
+	if strings.HasPrefix(out.String(), "CLUSTER") {
+		t.Errorf("expected no header row, got %q", out.String())
+	}
+}
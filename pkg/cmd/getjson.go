@@ -0,0 +1,124 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"kubectl-multi/pkg/cluster"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/duration"
+)
+
+// clusterObject is an object returned by `kubectl get -o json`, tagged with the cluster it came from
+type clusterObject struct {
+	Cluster string
+	Context string
+	Object  *unstructured.Unstructured
+	// Owner is the top-level owner of the object, only set with --show-owner
+	Owner string
+}
+
+// fetchObjects runs `kubectl get -o json` against every cluster and returns the objects found.
+// Clusters that fail are reported as warnings and left out. extraArgs are passed on to kubectl get.
+func fetchObjects(clusters []cluster.ClusterInfo, kubeconfig, resourceType, resourceName, selector, namespace string, allNamespaces bool, chunkSize int64, extraArgs ...string) []clusterObject {
+	var objects []clusterObject
+	for _, c := range clusters {
+		args := append(buildKubectlGetArgs(resourceType, resourceName, "json", selector, namespace, allNamespaces, c.Context, chunkSize), "--ignore-not-found")
+		args = append(args, extraArgs...)
+		output, err := runKubectl(args, kubeconfig)
+		if err != nil {
+			fmt.Printf("Warning: failed to get %s in cluster %s: %v: %s\n", resourceType, c.Display(), err, strings.TrimSpace(output))
+			continue
+		}
+		items, err := parseObjects([]byte(output))
+		if err != nil {
+			fmt.Printf("Warning: failed to get %s in cluster %s: %v\n", resourceType, c.Display(), err)
+			continue
+		}
+		for _, item := range items {
+			objects = append(objects, clusterObject{Cluster: c.Display(), Context: c.Context, Object: item})
+		}
+	}
+	return objects
+}
+
+// parseObjects decodes `kubectl get -o json` output, which is either a List or a single object
+func parseObjects(data []byte) ([]*unstructured.Unstructured, error) {
+	if strings.TrimSpace(string(data)) == "" {
+		return nil, nil
+	}
+
+	var obj map[string]interface{}
+	if err := json.Unmarshal(data, &obj); err != nil {
+		return nil, fmt.Errorf("failed to parse kubectl output: %v", err)
+	}
+
+	kind, _ := obj["kind"].(string)
+	if !strings.HasSuffix(kind, "List") {
+		return []*unstructured.Unstructured{{Object: obj}}, nil
+	}
+
+	rawItems, _ := obj["items"].([]interface{})
+	var items []*unstructured.Unstructured
+	for _, raw := range rawItems {
+		if item, ok := raw.(map[string]interface{}); ok {
+			items = append(items, &unstructured.Unstructured{Object: item})
+		}
+	}
+	return items, nil
+}
+
+// printKindTable prints objects of any kind as a CLUSTER/KIND/NAMESPACE/NAME/AGE table.
+// The NAMESPACE column is left out when all objects are cluster-scoped, and an OWNER column
+// follows CLUSTER when the owners of the objects were resolved.
+func printKindTable(tw tableWriter, objects []clusterObject, now time.Time) {
+	defer tw.Flush()
+
+	if len(objects) == 0 {
+		fmt.Fprintf(tw, "No resources found.\n")
+		return
+	}
+
+	namespaced, withOwner := false, false
+	for _, o := range objects {
+		if o.Object.GetNamespace() != "" {
+			namespaced = true
+		}
+		if o.Owner != "" {
+			withOwner = true
+		}
+	}
+
+	header := []string{"CLUSTER"}
+	if withOwner {
+		header = append(header, "OWNER")
+	}
+	header = append(header, "KIND")
+	if namespaced {
+		header = append(header, "NAMESPACE")
+	}
+	fmt.Fprintln(tw, strings.Join(append(header, "NAME", "AGE"), "\t"))
+
+	for _, o := range objects {
+		age := "<unknown>"
+		if created := o.Object.GetCreationTimestamp(); !created.IsZero() {
+			age = duration.HumanDuration(now.Sub(created.Time))
+		}
+		row := []string{o.Cluster}
+		if withOwner {
+			row = append(row, o.Owner)
+		}
+		row = append(row, o.Object.GetKind())
+		if namespaced {
+			ns := o.Object.GetNamespace()
+			if ns == "" {
+				ns = "<none>"
+			}
+			row = append(row, ns)
+		}
+		fmt.Fprintln(tw, strings.Join(append(row, o.Object.GetName(), age), "\t"))
+	}
+}
@@ -0,0 +1,80 @@
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+// TestParseObjectsList ensures List output is split into its items, whatever their kind
+func TestParseObjectsList(t *testing.T) {
+	data := []byte(`{"kind": "List", "items": [
+		{"kind": "Pod", "metadata": {"name": "nginx-1", "namespace": "default"}},
+		{"kind": "Service", "metadata": {"name": "nginx", "namespace": "default"}}
+	]}`)
+
+	items, err := parseObjects(data)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if len(items) != 2 || items[0].GetKind() != "Pod" || items[1].GetKind() != "Service" {
+		t.Errorf("unexpected items: %v", items)
+	}
+}
+
+// TestParseObjectsEmpty ensures empty output (from --ignore-not-found) yields no objects
+func TestParseObjectsEmpty(t *testing.T) {
+	items, err := parseObjects([]byte("\n"))
+	if err != nil || len(items) != 0 {
+		t.Errorf("expected no objects and no error, got %v, %v", items, err)
+	}
+}
+
+// TestPrintKindTable ensures mixed kinds share the same columns and cluster-scoped objects are handled
+func TestPrintKindTable(t *testing.T) {
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	data := []byte(`{"kind": "List", "items": [
+		{"kind": "Deployment", "metadata": {"name": "nginx", "namespace": "web", "creationTimestamp": "2024-06-01T10:00:00Z"}},
+		{"kind": "Node", "metadata": {"name": "node-1", "creationTimestamp": "2024-05-30T12:00:00Z"}}
+	]}`)
+	items, err := parseObjects(data)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var out bytes.Buffer
+	printKindTable(newTableWriter(&out, nil), []clusterObject{{Cluster: "wds1", Object: items[0]}, {Cluster: "wds2", Object: items[1]}}, now)
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("expected a header and 2 rows, got %q", out.String())
+	}
+	if fields := strings.Fields(lines[0]); strings.Join(fields, " ") != "CLUSTER KIND NAMESPACE NAME AGE" {
+		t.Errorf("unexpected header: %q", lines[0])
+	}
+	if fields := strings.Fields(lines[1]); strings.Join(fields, " ") != "wds1 Deployment web nginx 120m" {
+		t.Errorf("unexpected deployment row: %q", lines[1])
+	}
+	if fields := strings.Fields(lines[2]); strings.Join(fields, " ") != "wds2 Node <none> node-1 2d" {
+		t.Errorf("unexpected node row: %q", lines[2])
+	}
+}
+
+// TestPrintKindTableClusterScoped ensures the NAMESPACE column is dropped when nothing is namespaced
+func TestPrintKindTableClusterScoped(t *testing.T) {
+	items, err := parseObjects([]byte(`{"kind": "Node", "metadata": {"name": "node-1"}}`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	var out bytes.Buffer
+	printKindTable(newTableWriter(&out, nil), []clusterObject{{Cluster: "wds1", Object: items[0]}}, time.Now())
+
+	if strings.Contains(out.String(), "NAMESPACE") {
+		t.Errorf("expected no NAMESPACE column, got %q", out.String())
+	}
+	if !strings.Contains(out.String(), "<unknown>") {
+		t.Errorf("expected an unknown age for an object without creationTimestamp, got %q", out.String())
+	}
+}
@@ -0,0 +1,99 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"os/exec"
+	"sync"
+
+	"kubectl-multi/pkg/cluster"
+	"kubectl-multi/pkg/util"
+)
+
+// jsonLine is the object printed for every cluster with -o jsonl
+type jsonLine struct {
+	Cluster string                   `json:"cluster"`
+	Context string                   `json:"context"`
+	Items   []map[string]interface{} `json:"items"`
+	Error   string                   `json:"error,omitempty"`
+	Skipped string                   `json:"skipped,omitempty"`
+}
+
+// jsonLinesWriter writes one JSON object per line. Writes are serialized and every line is
+// written at once, so lines from clusters completing together never interleave.
+type jsonLinesWriter struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// newJSONLine converts the result of `kubectl get -o json` in a cluster into its JSON line.
+// Items is empty rather than null when the cluster has no matching resources.
+func newJSONLine(r clusterResult) jsonLine {
+	line := jsonLine{Cluster: r.DisplayName, Context: r.Context}
+	switch {
+	case r.Skipped != "":
+		line.Skipped = r.Skipped
+	case r.Err != nil:
+		line.Error = r.Err.Error()
+	default:
+		items, err := parseObjects([]byte(r.Output))
+		if err != nil {
+			line.Error = err.Error()
+			break
+		}
+		line.Items = make([]map[string]interface{}, 0, len(items))
+		for _, item := range items {
+			line.Items = append(line.Items, item.Object)
+		}
+	}
+	return line
+}
+
+// write prints the JSON line of a cluster result
+func (w *jsonLinesWriter) write(r clusterResult) error {
+	data, err := json.Marshal(newJSONLine(r))
+	if err != nil {
+		return err
+	}
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	_, err = w.w.Write(append(data, '\n'))
+	return err
+}
+
+// handleGetJSONLines runs `kubectl get -o json` in every cluster and prints one JSON line per cluster
+// as soon as it completes, for consumers that process the results of a long fan-out incrementally
+func handleGetJSONLines(clusters, unreachable []cluster.ClusterInfo, resourceName, resourceType, selector, namespace string, allNamespaces bool, chunkSize int64) error {
+	lines := &jsonLinesWriter{w: util.GetOutputStream()}
+	opts := fanOutOptions{
+		Namespace:   namespaceOption(namespace, allNamespaces),
+		Unreachable: unreachable,
+		Stream: func(r clusterResult) {
+			_ = lines.write(r)
+		},
+	}
+	_, err := fanOut(clusters, kubeconfig, remoteCtx, opts, func(ctx context.Context, c cluster.ClusterInfo, out io.Writer) error {
+		return runKubectlStdout(ctx, buildKubectlGetArgs(resourceType, resourceName, "json", selector, namespace, allNamespaces, c.Context, chunkSize), kubeconfig, out)
+	})
+	return err
+}
+
+// runKubectlStdout runs a kubectl command like runKubectlTo, but only writes its stdout to out so
+// warnings printed on stderr cannot corrupt the JSON it outputs
+func runKubectlStdout(ctx context.Context, args []string, kubeconfig string, out io.Writer) error {
+	cmd := exec.CommandContext(ctx, kubectlBinary(args), args...)
+	stopGracefully(cmd, timeoutGrace)
+	if kubeconfig != "" {
+		cmd.Env = append(os.Environ(), "KUBECONFIG="+kubeconfig)
+	}
+	var stderr bytes.Buffer
+	cmd.Stdout = out
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		return newKubectlError(err, stderr.String())
+	}
+	return nil
+}
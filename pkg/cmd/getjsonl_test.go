@@ -0,0 +1,91 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+	"testing"
+
+	"kubectl-multi/pkg/cluster"
+)
+
+// TestNewJSONLine ensures items, errors and skipped clusters are converted into their JSON line
+func TestNewJSONLine(t *testing.T) {
+	ok := newJSONLine(clusterResult{Context: "ctx1", DisplayName: "cluster1", Output: `{"kind":"PodList","items":[{"kind":"Pod","metadata":{"name":"web-1"}}]}`})
+	if ok.Cluster != "cluster1" || ok.Context != "ctx1" || len(ok.Items) != 1 || ok.Error != "" {
+		t.Errorf("unexpected line for a successful cluster: %+v", ok)
+	}
+
+	empty := newJSONLine(clusterResult{Context: "ctx2", Output: `{"kind":"PodList","items":[]}`})
+	if data, _ := json.Marshal(empty); !strings.Contains(string(data), `"items":[]`) {
+		t.Errorf("expected an empty items list, got %s", data)
+	}
+
+	failed := newJSONLine(clusterResult{Context: "ctx3", Err: errors.New("connection refused")})
+	if failed.Error != "connection refused" {
+		t.Errorf("expected the error to be reported, got %+v", failed)
+	}
+
+	skipped := newJSONLine(clusterResult{Context: "ctx4", Skipped: "not run"})
+	if skipped.Skipped != "not run" || skipped.Error != "" {
+		t.Errorf("expected the skip reason to be reported, got %+v", skipped)
+	}
+}
+
+// TestFanOutParallelStreamsJSONLines ensures every cluster is streamed as one complete line
+// while the clusters run concurrently
+func TestFanOutParallelStreamsJSONLines(t *testing.T) {
+	defer func(n int) { parallelism = n }(parallelism)
+	parallelism = 4
+
+	var out bytes.Buffer
+	lines := &jsonLinesWriter{w: &out}
+	var targets []cluster.ClusterInfo
+	for i := 1; i <= 8; i++ {
+		targets = append(targets, cluster.ClusterInfo{Context: fmt.Sprintf("cluster%d", i)})
+	}
+	op := func(ctx context.Context, c cluster.ClusterInfo, out io.Writer) error {
+		if c.Context == "cluster5" {
+			return errors.New("boom")
+		}
+		fmt.Fprintf(out, `{"kind":"List","items":[{"kind":"Pod","metadata":{"name":"%s-pod"}}]}`, c.Context)
+		return nil
+	}
+
+	var mu sync.Mutex
+	streamed := 0
+	opts := fanOutOptions{Stream: func(r clusterResult) {
+		mu.Lock()
+		streamed++
+		mu.Unlock()
+		if err := lines.write(r); err != nil {
+			t.Errorf("unexpected error: %v", err)
+		}
+	}}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	fanOutParallel(ctx, cancel, targets, opts, op, &progressReporter{out: io.Discard})
+
+	if streamed != len(targets) {
+		t.Fatalf("expected %d streamed results, got %d", len(targets), streamed)
+	}
+	seen := make(map[string]bool)
+	for _, raw := range strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n") {
+		var line jsonLine
+		if err := json.Unmarshal([]byte(raw), &line); err != nil {
+			t.Fatalf("line is not a complete JSON object: %q: %v", raw, err)
+		}
+		seen[line.Context] = true
+		if line.Context == "cluster5" && line.Error != "boom" {
+			t.Errorf("expected cluster5 to report its error, got %+v", line)
+		}
+	}
+	if len(seen) != len(targets) {
+		t.Errorf("expected one line per cluster, got %v", seen)
+	}
+}
@@ -0,0 +1,252 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"time"
+
+	"kubectl-multi/pkg/cluster"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/duration"
+)
+
+// clusterTable holds the table printed by `kubectl get` in a single cluster
+type clusterTable struct {
+	Cluster string
+	Output  string
+	// Created holds the creationTimestamp of the listed objects by namespace/name, the namespace being
+	// empty for cluster-scoped objects. It is nil when the AGE column is left as kubectl printed it.
+	Created map[string]time.Time
+}
+
+// isTableOutput reports whether an output format prints a kubectl table that can be merged across clusters
+func isTableOutput(outputFormat string) bool {
+	return outputFormat == "wide" ||
+		strings.HasPrefix(outputFormat, "custom-columns=") ||
+		strings.HasPrefix(outputFormat, "custom-columns-file=")
+}
+
+// fetchTables runs `kubectl get` with a table output format in every cluster
+func fetchTables(clusters []cluster.ClusterInfo, kubeconfig string, buildArgs func(context string) []string) []clusterTable {
+	var tables []clusterTable
+	for _, c := range clusters {
+		output, err := runKubectl(buildArgs(c.Context), kubeconfig)
+		if err != nil {
+			fmt.Printf("Warning: failed to get resources in cluster %s: %v: %s\n", c.Display(), err, strings.TrimSpace(output))
+			continue
+		}
+		tables = append(tables, clusterTable{Cluster: c.Display(), Output: output})
+	}
+	return tables
+}
+
+// fetchTablesWithAges runs `kubectl get` with a table output format in every cluster like fetchTables,
+// and `kubectl get -o json` for the creationTimestamp of the listed objects, so that mergeTables
+// renders the AGE column of every cluster from the same moment. A cluster whose JSON cannot be read
+// keeps the ages kubectl printed.
+func fetchTablesWithAges(clusters []cluster.ClusterInfo, kubeconfig string, buildArgs, buildJSONArgs func(context string) []string) []clusterTable {
+	var tables []clusterTable
+	for _, c := range clusters {
+		output, err := runKubectl(buildArgs(c.Context), kubeconfig)
+		if err != nil {
+			fmt.Printf("Warning: failed to get resources in cluster %s: %v: %s\n", c.Display(), err, strings.TrimSpace(output))
+			continue
+		}
+		table := clusterTable{Cluster: c.Display(), Output: output}
+		if data, err := runKubectl(buildJSONArgs(c.Context), kubeconfig); err == nil {
+			if items, err := parseObjects([]byte(data)); err == nil {
+				table.Created = creationTimes(items)
+			}
+		}
+		tables = append(tables, table)
+	}
+	return tables
+}
+
+// creationTimes returns the creationTimestamp of the objects by namespace/name
+func creationTimes(items []*unstructured.Unstructured) map[string]time.Time {
+	created := make(map[string]time.Time, len(items))
+	for _, item := range items {
+		if t := item.GetCreationTimestamp(); !t.IsZero() {
+			created[item.GetNamespace()+"/"+item.GetName()] = t.Time
+		}
+	}
+	return created
+}
+
+// normalizeAges replaces the AGE cells of the rows of a table with the age of their object at now.
+// Rows are matched to objects by their NAME and NAMESPACE cells, the namespace of a table without a
+// NAMESPACE column being the one of its objects; rows that match no object keep kubectl's age.
+func normalizeAges(header []string, rows [][]string, created map[string]time.Time, now time.Time) {
+	ageCol, nameCol, nsCol := indexOfColumn(header, "AGE"), indexOfColumn(header, "NAME"), indexOfColumn(header, "NAMESPACE")
+	if created == nil || ageCol < 0 || nameCol < 0 {
+		return
+	}
+	byName := created
+	if nsCol < 0 {
+		// Without -A all the objects are in the same namespace, so their names are unique
+		byName = make(map[string]time.Time, len(created))
+		for key, t := range created {
+			byName["/"+key[strings.Index(key, "/")+1:]] = t
+		}
+	}
+	for _, cells := range rows {
+		key := "/" + cells[nameCol]
+		if nsCol >= 0 {
+			key = cells[nsCol] + key
+		}
+		if t, ok := byName[key]; ok {
+			cells[ageCol] = duration.HumanDuration(now.Sub(t))
+		}
+	}
+}
+
+// tableColumnStarts returns the offsets at which the columns of a kubectl table header start.
+// Columns are separated by at least two spaces, so headers such as "NOMINATED NODE" stay whole.
+func tableColumnStarts(header string) []int {
+	starts := []int{0}
+	for i := 2; i < len(header); i++ {
+		if header[i] != ' ' && header[i-1] == ' ' && header[i-2] == ' ' {
+			starts = append(starts, i)
+		}
+	}
+	return starts
+}
+
+// splitTableRow cuts a kubectl table row into cells at the column offsets of its header
+func splitTableRow(row string, starts []int) []string {
+	cells := make([]string, len(starts))
+	for i, start := range starts {
+		if start >= len(row) {
+			break
+		}
+		end := len(row)
+		if i+1 < len(starts) && starts[i+1] < len(row) {
+			end = starts[i+1]
+		}
+		cells[i] = strings.TrimSpace(row[start:end])
+	}
+	return cells
+}
+
+// mergeTables prints the tables of all clusters as a single table with a leading CLUSTER column.
+// The header is printed once, or never with noHeaders, and clusters without rows are left out.
+// When the clusters' tables do not have the same columns, e.g. because of different server versions,
+// the cells are placed by column name and the columns a cluster lacks are left empty. The AGE cells of
+// the tables with creation times are computed at now, so that ages are comparable across clusters.
+func mergeTables(tw tableWriter, tables []clusterTable, noHeaders bool, now time.Time) {
+	defer tw.Flush()
+
+	type parsedTable struct {
+		cluster string
+		header  []string
+		rows    [][]string
+	}
+	var parsed []parsedTable
+	var headers [][]string
+	for _, t := range tables {
+		lines := strings.Split(strings.TrimRight(t.Output, "\n"), "\n")
+		if len(lines) < 2 {
+			// Only a header, or nothing at all when the cluster has no matching resources
+			continue
+		}
+
+		starts := tableColumnStarts(lines[0])
+		p := parsedTable{cluster: t.Cluster, header: splitTableRow(lines[0], starts)}
+		for _, row := range lines[1:] {
+			if strings.TrimSpace(row) == "" {
+				continue
+			}
+			p.rows = append(p.rows, splitTableRow(row, starts))
+		}
+		normalizeAges(p.header, p.rows, t.Created, now)
+		parsed = append(parsed, p)
+		headers = append(headers, p.header)
+	}
+
+	if len(parsed) == 0 {
+		if !noHeaders {
+			fmt.Fprintf(tw, "No resource found.\n")
+		}
+		return
+	}
+
+	columns := mergeTableHeaders(headers)
+	if !noHeaders {
+		fmt.Fprintf(tw, "CLUSTER\t%s\n", strings.Join(columns, "\t"))
+	}
+	for _, p := range parsed {
+		same := strings.Join(p.header, "\t") == strings.Join(columns, "\t")
+		index := make(map[string]int, len(p.header))
+		for i := len(p.header) - 1; i >= 0; i-- {
+			index[p.header[i]] = i
+		}
+		for _, cells := range p.rows {
+			if !same {
+				aligned := make([]string, len(columns))
+				for i, column := range columns {
+					if j, ok := index[column]; ok {
+						aligned[i] = cells[j]
+					}
+				}
+				cells = aligned
+			}
+			fmt.Fprintf(tw, "%s\t%s\n", p.cluster, strings.Join(cells, "\t"))
+		}
+	}
+}
+
+// mergeTableHeaders returns the columns of the merged table: those of the first header, with the
+// columns only other clusters have inserted after the column that precedes them in their own header,
+// so that trailing columns such as the label columns of -L stay last
+func mergeTableHeaders(headers [][]string) []string {
+	merged := append([]string(nil), headers[0]...)
+	for _, header := range headers[1:] {
+		prev := -1
+		for _, column := range header {
+			if i := indexOfColumn(merged, column); i >= 0 {
+				prev = i
+				continue
+			}
+			prev++
+			merged = append(merged[:prev], append([]string{column}, merged[prev:]...)...)
+		}
+	}
+	return merged
+}
+
+// indexOfColumn returns the position of the column in columns, or -1
+func indexOfColumn(columns []string, column string) int {
+	for i, c := range columns {
+		if c == column {
+			return i
+		}
+	}
+	return -1
+}
+
+// headerSkipper is a writer that drops the first line written to it, used to remove the header
+// (or "No resource found." message) of the built-in tables with --no-headers
+type headerSkipper struct {
+	w       io.Writer
+	skipped bool
+}
+
+func (h *headerSkipper) Write(p []byte) (int, error) {
+	n := len(p)
+	if !h.skipped {
+		i := bytes.IndexByte(p, '\n')
+		if i < 0 {
+			return n, nil
+		}
+		h.skipped = true
+		p = p[i+1:]
+	}
+	if _, err := h.w.Write(p); err != nil {
+		return 0, err
+	}
+	return n, nil
+}
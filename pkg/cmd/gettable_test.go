@@ -0,0 +1,197 @@
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+)
+
+var twoClusterTables = []clusterTable{
+	{Cluster: "cluster1", Output: "NAME    READY   STATUS    NOMINATED NODE\nweb-1   1/1     Running   <none>\nweb-2   0/1     Pending   <none>\n"},
+	{Cluster: "cluster2", Output: ""},
+	{Cluster: "cluster3", Output: "NAME        READY   STATUS    NOMINATED NODE\nweb-abcde   1/1     Running   <none>\n"},
+}
+
+// TestMergeTablesSingleHeader ensures the header is printed once and every row gets its cluster
+func TestMergeTablesSingleHeader(t *testing.T) {
+	var buf bytes.Buffer
+	mergeTables(newTableWriter(&buf, nil), twoClusterTables, false, time.Now())
+
+	lines := strings.Split(strings.TrimRight(buf.String(), "\n"), "\n")
+	if len(lines) != 4 {
+		t.Fatalf("expected a header and 3 rows, got:\n%s", buf.String())
+	}
+	if fields := strings.Fields(lines[0]); fields[0] != "CLUSTER" || strings.Count(buf.String(), "READY") != 1 {
+		t.Errorf("expected a single CLUSTER header, got:\n%s", buf.String())
+	}
+	if !strings.Contains(lines[0], "NOMINATED NODE") {
+		t.Errorf("expected multi-word headers to stay whole, got %q", lines[0])
+	}
+	want := [][]string{
+		{"cluster1", "web-1", "1/1", "Running", "<none>"},
+		{"cluster1", "web-2", "0/1", "Pending", "<none>"},
+		{"cluster3", "web-abcde", "1/1", "Running", "<none>"},
+	}
+	for i, w := range want {
+		if got := strings.Fields(lines[i+1]); strings.Join(got, " ") != strings.Join(w, " ") {
+			t.Errorf("row %d: expected %v, got %v", i, w, got)
+		}
+	}
+}
+
+// TestMergeTablesNoHeaders ensures --no-headers prints only the data rows
+func TestMergeTablesNoHeaders(t *testing.T) {
+	var buf bytes.Buffer
+	mergeTables(newTableWriter(&buf, nil), twoClusterTables, true, time.Now())
+
+	lines := strings.Split(strings.TrimRight(buf.String(), "\n"), "\n")
+	if len(lines) != 3 {
+		t.Fatalf("expected 3 rows, got:\n%s", buf.String())
+	}
+	for _, line := range lines {
+		if strings.Contains(line, "NAME") {
+			t.Errorf("expected no header, got %q", line)
+		}
+	}
+}
+
+// TestMergeTablesEmpty ensures clusters without rows never print a stray header
+func TestMergeTablesEmpty(t *testing.T) {
+	tables := []clusterTable{{Cluster: "cluster1", Output: ""}, {Cluster: "cluster2", Output: "NAME   READY\n"}}
+
+	var buf bytes.Buffer
+	mergeTables(newTableWriter(&buf, nil), tables, false, time.Now())
+	if got := strings.TrimSpace(buf.String()); got != "No resource found." {
+		t.Errorf("expected no resources message, got %q", got)
+	}
+
+	buf.Reset()
+	mergeTables(newTableWriter(&buf, nil), tables, true, time.Now())
+	if buf.Len() != 0 {
+		t.Errorf("expected no output with --no-headers, got %q", buf.String())
+	}
+}
+
+// TestMergeTablesLabelColumns ensures the -L columns of two clusters stay aligned under one header,
+// even when a cluster prints an extra column before them
+func TestMergeTablesLabelColumns(t *testing.T) {
+	tables := []clusterTable{
+		{Cluster: "cluster1", Output: "NAME    READY   AGE   APP   VERSION\nweb-1   1/1     5d    web   v2\ndb-0    1/1     9d    db\n"},
+		{Cluster: "cluster2", Output: "NAME        READY   STATUS    AGE   APP   VERSION\nweb-abcde   0/1     Pending   1h    web   v3\n"},
+	}
+
+	var buf bytes.Buffer
+	mergeTables(newTableWriter(&buf, nil), tables, false, time.Now())
+
+	lines := strings.Split(strings.TrimRight(buf.String(), "\n"), "\n")
+	if len(lines) != 4 {
+		t.Fatalf("expected a header and 3 rows, got:\n%s", buf.String())
+	}
+	if got := strings.Join(strings.Fields(lines[0]), " "); got != "CLUSTER NAME READY STATUS AGE APP VERSION" {
+		t.Errorf("unexpected merged header %q", got)
+	}
+	// Every cell starts under its header, whatever the cluster
+	header := lines[0]
+	for i, want := range []map[string]string{
+		{"NAME": "web-1", "AGE": "5d", "APP": "web", "VERSION": "v2"},
+		{"NAME": "db-0", "AGE": "9d", "APP": "db"},
+		{"NAME": "web-abcde", "STATUS": "Pending", "AGE": "1h", "APP": "web", "VERSION": "v3"},
+	} {
+		row := lines[i+1]
+		for column, value := range want {
+			at := strings.Index(header, column)
+			if !strings.HasPrefix(row[at:], value) {
+				t.Errorf("row %d: expected %s under %s, got %q", i, value, column, row)
+			}
+		}
+	}
+	if strings.Contains(lines[2], "v") {
+		t.Errorf("expected db-0 to have an empty VERSION, got %q", lines[2])
+	}
+}
+
+// TestMergeTableHeaders ensures columns missing from the first cluster are inserted where they belong
+func TestMergeTableHeaders(t *testing.T) {
+	got := mergeTableHeaders([][]string{
+		{"NAME", "READY", "AGE", "APP"},
+		{"NAME", "READY", "STATUS", "AGE", "APP"},
+		{"NAME", "RESTARTS", "AGE", "APP", "TIER"},
+	})
+	if want := "NAME RESTARTS READY STATUS AGE APP TIER"; strings.Join(got, " ") != want {
+		t.Errorf("expected %q, got %q", want, strings.Join(got, " "))
+	}
+}
+
+// TestHeaderSkipper ensures only the first line is dropped, even when written in pieces
+func TestHeaderSkipper(t *testing.T) {
+	var buf bytes.Buffer
+	w := &headerSkipper{w: &buf}
+	for _, chunk := range []string{"CLUSTER  NA", "ME\ncluster1  web-1\n", "cluster2  web-2\n"} {
+		if _, err := w.Write([]byte(chunk)); err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+	}
+	if got := buf.String(); got != "cluster1  web-1\ncluster2  web-2\n" {
+		t.Errorf("unexpected output %q", got)
+	}
+}
+
+// TestBuildLabelColumnsGetArgs ensures the label keys are passed to kubectl as a single -L
+func TestBuildLabelColumnsGetArgs(t *testing.T) {
+	opts := getOptions{LabelColumns: []string{"app", "version"}, ShowLabels: true, ChunkSize: defaultChunkSize}
+	got := strings.Join(buildLabelColumnsGetArgs("pods", "", "wide", "", "", true, "cluster1", opts), " ")
+	if want := "get pods -o wide -A --context cluster1 --show-labels -L app,version"; got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+}
+
+// TestMergeTablesNormalizesAges ensures the AGE cells are computed from the creation times at a single
+// moment, whatever kubectl printed in each cluster, and rows without a creation time are left alone
+func TestMergeTablesNormalizesAges(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC)
+	tables := []clusterTable{
+		{
+			Cluster: "cluster1",
+			Output:  "NAMESPACE   NAME    READY   AGE\nshop        web-1   1/1     9m\ndefault     web-1   1/1     2d\nshop        db-0    1/1     5h\n",
+			Created: map[string]time.Time{
+				"shop/web-1":    now.Add(-10 * time.Minute),
+				"default/web-1": now.Add(-49 * time.Hour),
+			},
+		},
+		{
+			Cluster: "cluster2",
+			Output:  "NAMESPACE   NAME    READY   AGE\nshop        web-1   1/1     11m\n",
+			Created: map[string]time.Time{"shop/web-1": now.Add(-10 * time.Minute)},
+		},
+		{
+			Cluster: "cluster3",
+			Output:  "NAMESPACE   NAME    READY   AGE\nshop        web-1   1/1     7m\n",
+		},
+	}
+
+	var buf bytes.Buffer
+	mergeTables(newTableWriter(&buf, nil), tables, false, now)
+	want := `CLUSTER   NAMESPACE  NAME   READY  AGE
+cluster1  shop       web-1  1/1    10m
+cluster1  default    web-1  1/1    2d1h
+cluster1  shop       db-0   1/1    5h
+cluster2  shop       web-1  1/1    10m
+cluster3  shop       web-1  1/1    7m
+`
+	if buf.String() != want {
+		t.Errorf("expected:\n%s\ngot:\n%s", want, buf.String())
+	}
+
+	// Without a NAMESPACE column the rows are matched by name
+	buf.Reset()
+	single := []clusterTable{{
+		Cluster: "cluster1",
+		Output:  "NAME    READY   AGE\nweb-1   1/1     9m\n",
+		Created: map[string]time.Time{"shop/web-1": now.Add(-90 * time.Second)},
+	}}
+	mergeTables(newTableWriter(&buf, nil), single, true, now)
+	if got := strings.Fields(buf.String()); len(got) != 4 || got[3] != "90s" {
+		t.Errorf("expected an age of 90s, got %q", buf.String())
+	}
+}
@@ -0,0 +1,89 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+
+	"kubectl-multi/pkg/cluster"
+	"kubectl-multi/pkg/util"
+)
+
+// handleGetWatch runs `kubectl get --watch` (or --watch-only, without the initial list) against every
+// target cluster at once and merges their events, each line tagged with its cluster, until Ctrl-C
+func handleGetWatch(clusters []cluster.ClusterInfo, resourceType, resourceName, outputFormat, selector, namespace string, allNamespaces, watchOnly bool, kubeconfig, remoteCtx string) error {
+	targets, _ := fanOutTargets(clusters, "", itsContext(remoteCtx))
+	if len(targets) == 0 {
+		return fmt.Errorf("no clusters discovered")
+	}
+
+	// Ctrl-C cancels ctx, which stops every watch
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	fmt.Fprintf(os.Stderr, "Watching %s in %d clusters, press Ctrl-C to stop\n", resourceType, len(targets))
+	watchClusters(ctx, targets, kubeconfig, util.GetOutputStream(), func(context string) []string {
+		args := buildKubectlGetArgs(resourceType, resourceName, outputFormat, selector, namespace, allNamespaces, context, defaultChunkSize)
+		if watchOnly {
+			return append(args, "--watch-only", "--output-watch-events")
+		}
+		return append(args, "--watch", "--output-watch-events")
+	})
+	return nil
+}
+
+// watchClusters runs the watch built by buildArgs in every cluster concurrently until they end or ctx
+// is done. Their output is merged line by line into out, each line prefixed with its cluster.
+func watchClusters(ctx context.Context, targets []cluster.ClusterInfo, kubeconfig string, out io.Writer, buildArgs func(context string) []string) {
+	shared := &syncWriter{w: out}
+	var wg sync.WaitGroup
+	for _, c := range targets {
+		wg.Add(1)
+		go func(c cluster.ClusterInfo) {
+			defer wg.Done()
+			w := &linePrefixWriter{prefix: "[" + c.Display() + "] ", out: shared}
+			err := runKubectlTo(ctx, buildArgs(c.Context), kubeconfig, w)
+			w.Flush()
+			// Watches killed by Ctrl-C are not failures
+			if err != nil && ctx.Err() == nil {
+				fmt.Fprintf(shared, "[%s] Warning: watch stopped: %v\n", c.Display(), err)
+			}
+		}(c)
+	}
+	wg.Wait()
+}
+
+// linePrefixWriter writes complete lines to out, each prefixed with prefix, so that the lines of
+// concurrent writers sharing out never interleave mid-line
+type linePrefixWriter struct {
+	prefix string
+	out    io.Writer
+	buf    bytes.Buffer
+}
+
+func (w *linePrefixWriter) Write(p []byte) (int, error) {
+	w.buf.Write(p)
+	for {
+		i := bytes.IndexByte(w.buf.Bytes(), '\n')
+		if i < 0 {
+			return len(p), nil
+		}
+		line := w.buf.Next(i + 1)
+		if _, err := w.out.Write(append([]byte(w.prefix), line...)); err != nil {
+			return len(p), err
+		}
+	}
+}
+
+// Flush writes the last line if it did not end with a newline
+func (w *linePrefixWriter) Flush() {
+	if w.buf.Len() > 0 {
+		w.out.Write(append([]byte(w.prefix), append(w.buf.Bytes(), '\n')...))
+		w.buf.Reset()
+	}
+}
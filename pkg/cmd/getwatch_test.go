@@ -0,0 +1,49 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"kubectl-multi/pkg/cluster"
+)
+
+// TestLinePrefixWriter ensures only complete lines are written, each with the prefix
+func TestLinePrefixWriter(t *testing.T) {
+	var out bytes.Buffer
+	w := &linePrefixWriter{prefix: "[cluster1] ", out: &out}
+	w.Write([]byte("EVENT  NAME\nADDED  web"))
+	if out.String() != "[cluster1] EVENT  NAME\n" {
+		t.Errorf("expected only the complete line, got %q", out.String())
+	}
+	w.Write([]byte("-1\nMODIFIED  web-1\nDELE"))
+	w.Flush()
+	want := "[cluster1] EVENT  NAME\n[cluster1] ADDED  web-1\n[cluster1] MODIFIED  web-1\n[cluster1] DELE\n"
+	if out.String() != want {
+		t.Errorf("expected:\n%s\ngot:\n%s", want, out.String())
+	}
+}
+
+// TestWatchClusters ensures the watch of every cluster runs and their lines are merged, tagged with the cluster
+func TestWatchClusters(t *testing.T) {
+	installFakeKubectl(t, "#!/bin/sh\ncase \"$*\" in *--watch-only*) ;; *) echo 'missing --watch-only' >&2; exit 1;; esac\n"+
+		"for a in \"$@\"; do ctx=$a; done\necho \"MODIFIED  pod/web-$ctx\"\n")
+
+	var out bytes.Buffer
+	targets := []cluster.ClusterInfo{{Context: "cluster1"}, {Context: "cluster2", DisplayName: "prod"}}
+	watchClusters(context.Background(), targets, "", &out, func(context string) []string {
+		return []string{"get", "pods", "--watch-only", "--context", context}
+	})
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("expected one line per cluster, got:\n%s", out.String())
+	}
+	got := strings.Join(lines, "\n")
+	for _, want := range []string{"[cluster1] MODIFIED  pod/web-cluster1", "[prod] MODIFIED  pod/web-cluster2"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("expected %q in:\n%s", want, got)
+		}
+	}
+}
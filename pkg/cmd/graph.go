@@ -0,0 +1,109 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"kubectl-multi/pkg/cluster"
+	"kubectl-multi/pkg/graph"
+	"kubectl-multi/pkg/util"
+
+	"github.com/spf13/cobra"
+)
+
+// graphOwnedKinds are the kinds fetched to find the objects owned by a workload
+const graphOwnedKinds = "replicasets,pods,jobs,controllerrevisions"
+
+func newGraphCommand() *cobra.Command {
+	var outputFormat string
+
+	cmd := &cobra.Command{
+		Use:   "graph TYPE/NAME",
+		Short: "Show the objects owned by a workload in every managed cluster",
+		Long: `Show the objects owned by a workload in every managed cluster.
+The workload and the ReplicaSets, Pods, Jobs and ControllerRevisions it owns (through their owner
+references) are printed as a tree per cluster, or exported as a single DOT or JSON graph annotated
+with the cluster of every object.`,
+		Example: `# Show how the web deployment is realized in every cluster
+kubectl multi graph deployment/web
+
+# Render the graph of a cronjob with Graphviz
+kubectl multi graph cronjob/backup -n ops -o dot | dot -Tsvg > backup.svg`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			switch outputFormat {
+			case "tree", "dot", "json":
+			default:
+				return fmt.Errorf("invalid output format %q: must be tree, dot or json", outputFormat)
+			}
+			kubeconfig, remoteCtx, _, namespace, _ := GetGlobalFlags()
+			return handleGraphCommand(args[0], outputFormat, kubeconfig, remoteCtx, namespace)
+		},
+	}
+
+	cmd.Flags().StringVarP(&outputFormat, "output", "o", "tree", "output format: tree, dot or json")
+
+	return cmd
+}
+
+func handleGraphCommand(resource, outputFormat, kubeconfig, remoteCtx, namespace string) error {
+	if !strings.Contains(resource, "/") {
+		return fmt.Errorf("resource must be given as TYPE/NAME, e.g. deployment/web")
+	}
+
+	clusters, _, err := discoverClusters(kubeconfig, remoteCtx)
+	if err != nil {
+		return fmt.Errorf("failed to discover clusters: %v", err)
+	}
+	targets, _ := fanOutTargets(clusters, currentKubeContext(kubeconfig), itsContext(remoteCtx))
+
+	var roots []*graph.Node
+	for _, c := range targets {
+		root, err := fetchGraph(c, resource, kubeconfig, namespace)
+		if err != nil {
+			fmt.Printf("Warning: %v\n", err)
+			continue
+		}
+		if root != nil {
+			roots = append(roots, root)
+		}
+	}
+	if len(roots) == 0 {
+		return fmt.Errorf("%s not found in any cluster", resource)
+	}
+
+	out := util.GetOutputStream()
+	switch outputFormat {
+	case "dot":
+		return graph.WriteDOT(out, roots)
+	case "json":
+		return graph.WriteJSON(out, roots)
+	}
+	return graph.WriteTree(out, roots)
+}
+
+// fetchGraph returns the ownership tree of resource in a cluster, or nil if the resource does not exist there
+func fetchGraph(c cluster.ClusterInfo, resource, kubeconfig, namespace string) (*graph.Node, error) {
+	ns := cluster.TargetNamespace(c, namespace)
+	output, err := runKubectl([]string{"get", resource, "-o", "json", "--ignore-not-found", "-n", ns, "--context", c.Context}, kubeconfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get %s in cluster %s: %v: %s", resource, c.Display(), err, strings.TrimSpace(output))
+	}
+	roots, err := parseObjects([]byte(output))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get %s in cluster %s: %v", resource, c.Display(), err)
+	}
+	if len(roots) == 0 {
+		return nil, nil
+	}
+
+	output, err = runKubectl([]string{"get", graphOwnedKinds, "-o", "json", "-n", ns, "--context", c.Context}, kubeconfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list the objects owned by %s in cluster %s: %v: %s", resource, c.Display(), err, strings.TrimSpace(output))
+	}
+	objects, err := parseObjects([]byte(output))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list the objects owned by %s in cluster %s: %v", resource, c.Display(), err)
+	}
+	return graph.Build(c.Display(), roots[0], objects), nil
+}
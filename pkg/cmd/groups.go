@@ -0,0 +1,72 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"text/tabwriter"
+
+	"kubectl-multi/pkg/util"
+
+	"github.com/spf13/cobra"
+)
+
+func newGroupsCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "groups",
+		Short: "Manage named groups of clusters",
+		Long: `Named groups of clusters are defined in ~/.config/kubectl-multi/groups.yaml,
+mapping each group name to a list of contexts:
+
+  prod:
+  - prod-eu
+  - prod-us
+  staging:
+  - staging-eu
+
+Use --group to run any command on the clusters of one or more groups.`,
+	}
+
+	cmd.AddCommand(newGroupsListCommand())
+	return cmd
+}
+
+func newGroupsListCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "list",
+		Short: "List the defined cluster groups",
+		Args:  cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return handleGroupsListCommand()
+		},
+	}
+	return cmd
+}
+
+func handleGroupsListCommand() error {
+	path, err := groupsFilePath()
+	if err != nil {
+		return err
+	}
+	groups, err := loadGroups()
+	if err != nil {
+		return err
+	}
+	if len(groups) == 0 {
+		fmt.Printf("No cluster groups defined in %s\n", path)
+		return nil
+	}
+
+	names := make([]string, 0, len(groups))
+	for name := range groups {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	tw := tabwriter.NewWriter(util.GetOutputStream(), 0, 0, 2, ' ', 0)
+	fmt.Fprintf(tw, "GROUP\tCLUSTERS\n")
+	for _, name := range names {
+		fmt.Fprintf(tw, "%s\t%s\n", name, strings.Join(groups[name], ","))
+	}
+	return tw.Flush()
+}
@@ -0,0 +1,89 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"runtime"
+
+	"kubectl-multi/pkg/cluster"
+)
+
+// Values of --hook-fail-mode
+const (
+	// hookFailAbort fails the cluster when a hook fails, without running its operation after a failed pre-hook
+	hookFailAbort = "abort"
+	// hookFailContinue only warns about failed hooks
+	hookFailContinue = "continue"
+)
+
+// Environment variables set for hooks
+const (
+	hookContextEnv = "KUBECTL_MULTI_CONTEXT"
+	hookClusterEnv = "KUBECTL_MULTI_CLUSTER"
+	// hookErrorEnv holds the error of the cluster's operation for the post-hook, empty if it succeeded
+	hookErrorEnv = "KUBECTL_MULTI_ERROR"
+)
+
+// validateHookFailMode checks the value of --hook-fail-mode
+func validateHookFailMode(mode string) error {
+	if mode != hookFailAbort && mode != hookFailContinue {
+		return fmt.Errorf("invalid --hook-fail-mode %q: must be one of %s|%s", mode, hookFailAbort, hookFailContinue)
+	}
+	return nil
+}
+
+// runHook runs a --pre-hook or --post-hook shell command for a cluster, writing its output to out.
+// The cluster's context and display name, and the extra variables in env, are set in its environment.
+func runHook(ctx context.Context, hook string, c cluster.ClusterInfo, env []string, out io.Writer) error {
+	shell, flag := "sh", "-c"
+	if runtime.GOOS == "windows" {
+		shell, flag = "cmd", "/C"
+	}
+	cmd := exec.CommandContext(ctx, shell, flag, hook)
+	cmd.Env = append(os.Environ(), hookContextEnv+"="+c.Context, hookClusterEnv+"="+c.Display())
+	if kubeconfig != "" {
+		cmd.Env = append(cmd.Env, "KUBECONFIG="+kubeconfig)
+	}
+	cmd.Env = append(cmd.Env, env...)
+	cmd.Stdout = out
+	cmd.Stderr = out
+	return cmd.Run()
+}
+
+// withHooks wraps op with --pre-hook and --post-hook. With --hook-fail-mode=abort, a failed pre-hook
+// fails the cluster without running op and a failed post-hook fails it after op; otherwise failed
+// hooks are only reported.
+func withHooks(op clusterOp) clusterOp {
+	if preHook == "" && postHook == "" {
+		return op
+	}
+	return func(ctx context.Context, c cluster.ClusterInfo, out io.Writer) error {
+		if preHook != "" {
+			if err := runHook(ctx, preHook, c, nil, out); err != nil {
+				if hookFailMode == hookFailAbort {
+					return fmt.Errorf("pre-hook failed: %v", err)
+				}
+				fmt.Fprintf(out, "Warning: pre-hook failed: %v\n", err)
+			}
+		}
+
+		opErr := op(ctx, c, out)
+
+		if postHook != "" {
+			opError := ""
+			if opErr != nil {
+				opError = opErr.Error()
+			}
+			if err := runHook(ctx, postHook, c, []string{hookErrorEnv + "=" + opError}, out); err != nil {
+				if hookFailMode == hookFailAbort && opErr == nil {
+					return fmt.Errorf("post-hook failed: %v", err)
+				}
+				fmt.Fprintf(out, "Warning: post-hook failed: %v\n", err)
+			}
+		}
+		return opErr
+	}
+}
@@ -0,0 +1,80 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"testing"
+
+	"kubectl-multi/pkg/cluster"
+)
+
+// setHooks sets the hook flags for a test and restores them afterwards
+func setHooks(t *testing.T, pre, post, mode string) {
+	oldPre, oldPost, oldMode := preHook, postHook, hookFailMode
+	t.Cleanup(func() { preHook, postHook, hookFailMode = oldPre, oldPost, oldMode })
+	preHook, postHook, hookFailMode = pre, post, mode
+}
+
+// TestHooksSeeCluster ensures hooks run around the operation with the cluster and its result in their environment
+func TestHooksSeeCluster(t *testing.T) {
+	setHooks(t, `echo "pre $KUBECTL_MULTI_CONTEXT $KUBECTL_MULTI_CLUSTER"`, `echo "post $KUBECTL_MULTI_CONTEXT error=$KUBECTL_MULTI_ERROR"`, hookFailAbort)
+
+	var buf bytes.Buffer
+	op := func(ctx context.Context, c cluster.ClusterInfo, out io.Writer) error {
+		fmt.Fprintln(out, "op")
+		return errors.New("boom")
+	}
+	result := runOnCluster(context.Background(), cluster.ClusterInfo{Context: "ctx1", DisplayName: "prod"}, fanOutOptions{}, op, &buf)
+
+	want := "pre ctx1 prod\nop\npost ctx1 error=boom\n"
+	if !strings.HasPrefix(buf.String(), want) {
+		t.Errorf("expected output to start with %q, got %q", want, buf.String())
+	}
+	if result.Err == nil || result.Err.Error() != "boom" {
+		t.Errorf("expected the operation's error to be kept, got %v", result.Err)
+	}
+}
+
+// TestPreHookFailModes ensures a failed pre-hook skips the operation with abort, and only warns with continue
+func TestPreHookFailModes(t *testing.T) {
+	for _, mode := range []string{hookFailAbort, hookFailContinue} {
+		setHooks(t, "exit 3", "", mode)
+		ran := false
+		op := func(ctx context.Context, c cluster.ClusterInfo, out io.Writer) error {
+			ran = true
+			return nil
+		}
+		result := runOnCluster(context.Background(), cluster.ClusterInfo{Context: "ctx1"}, fanOutOptions{}, op, io.Discard)
+
+		if mode == hookFailAbort && (ran || result.Err == nil || !strings.Contains(result.Err.Error(), "pre-hook failed")) {
+			t.Errorf("abort: expected the cluster to fail without running the operation, ran=%v err=%v", ran, result.Err)
+		}
+		if mode == hookFailContinue && (!ran || result.Err != nil) {
+			t.Errorf("continue: expected the operation to run and succeed, ran=%v err=%v", ran, result.Err)
+		}
+	}
+}
+
+// TestPostHookFailure ensures a failed post-hook fails a successful cluster with abort
+func TestPostHookFailure(t *testing.T) {
+	setHooks(t, "", "false", hookFailAbort)
+	op := func(ctx context.Context, c cluster.ClusterInfo, out io.Writer) error { return nil }
+	result := runOnCluster(context.Background(), cluster.ClusterInfo{Context: "ctx1"}, fanOutOptions{}, op, io.Discard)
+	if result.Err == nil || !strings.Contains(result.Err.Error(), "post-hook failed") {
+		t.Errorf("expected the post-hook failure to fail the cluster, got %v", result.Err)
+	}
+}
+
+// TestValidateHookFailMode ensures only abort and continue are accepted
+func TestValidateHookFailMode(t *testing.T) {
+	if validateHookFailMode(hookFailAbort) != nil || validateHookFailMode(hookFailContinue) != nil {
+		t.Errorf("expected abort and continue to be accepted")
+	}
+	if validateHookFailMode("ignore") == nil {
+		t.Errorf("expected an unknown mode to be rejected")
+	}
+}
@@ -0,0 +1,145 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+)
+
+func newITSCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "its",
+		Short: "Run commands on the ITS (control) cluster only",
+		Long: `Fleet commands never run on the ITS (control) cluster. The its commands run only on it, for the
+resources that belong to the control plane, such as BindingPolicies.
+
+The ITS cluster is the --remote-context. When it is not given and the default its1 context does not
+exist, the kubeconfig context serving ManagedCluster resources is used.`,
+	}
+
+	cmd.AddCommand(newITSGetCommand())
+	cmd.AddCommand(newITSApplyCommand())
+	return cmd
+}
+
+func newITSGetCommand() *cobra.Command {
+	var outputFormat, selector string
+	var showLabels bool
+	cmd := &cobra.Command{
+		Use:   "get TYPE[.VERSION][.GROUP] [NAME]",
+		Short: "Get resources of the ITS (control) cluster",
+		Example: `# List the BindingPolicies of the control plane
+kubectl multi its get bindingpolicies
+
+# List the managed clusters with their labels
+kubectl multi its get managedclusters --show-labels`,
+		Args: cobra.RangeArgs(1, 2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			kubeconfig, remoteCtx, _, namespace, allNamespaces := GetGlobalFlags()
+			its, err := resolveITSContext(kubeconfig, remoteCtx, rootCmd.PersistentFlags().Changed("remote-context"))
+			if err != nil {
+				return err
+			}
+			resourceName := ""
+			if len(args) > 1 {
+				resourceName = args[1]
+			}
+			kubectlArgs := buildKubectlGetArgs(args[0], resourceName, outputFormat, selector, namespace, allNamespaces, its, defaultChunkSize)
+			if showLabels {
+				kubectlArgs = append(kubectlArgs, "--show-labels")
+			}
+			return runKubectlTo(context.Background(), kubectlArgs, kubeconfig, os.Stdout)
+		},
+	}
+	cmd.Flags().StringVarP(&outputFormat, "output", "o", "", "output format, as for kubectl get")
+	cmd.Flags().StringVarP(&selector, "selector", "l", "", "selector (label query) to filter on")
+	cmd.Flags().BoolVar(&showLabels, "show-labels", false, "show all labels as the last column")
+	return cmd
+}
+
+func newITSApplyCommand() *cobra.Command {
+	var filenames []string
+	var kustomize, dryRun string
+	var recursive bool
+	cmd := &cobra.Command{
+		Use:   "apply (-f FILENAME | -k DIRECTORY)",
+		Short: "Apply manifests to the ITS (control) cluster",
+		Example: `# Create or update a BindingPolicy on the control plane
+kubectl multi its apply -f bindingpolicy.yaml`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(filenames) == 0 && kustomize == "" {
+				return fmt.Errorf("must specify one of -f or -k")
+			}
+			if len(filenames) > 0 && kustomize != "" {
+				return fmt.Errorf("-f and -k cannot be used together")
+			}
+			kubeconfig, remoteCtx, _, namespace, _ := GetGlobalFlags()
+			its, err := resolveITSContext(kubeconfig, remoteCtx, rootCmd.PersistentFlags().Changed("remote-context"))
+			if err != nil {
+				return err
+			}
+			kubectlArgs := append([]string{"apply"}, manifestArgs(filenames, kustomize)...)
+			kubectlArgs = append(kubectlArgs, "--context", its)
+			if recursive {
+				kubectlArgs = append(kubectlArgs, "-R")
+			}
+			if dryRun != "none" && dryRun != "" {
+				kubectlArgs = append(kubectlArgs, "--dry-run="+dryRun)
+			}
+			if namespace != "" {
+				kubectlArgs = append(kubectlArgs, "-n", namespace)
+			}
+			fmt.Printf("Applying to the ITS (control) cluster %s\n", its)
+			return runKubectlTo(context.Background(), kubectlArgs, kubeconfig, os.Stdout)
+		},
+	}
+	cmd.Flags().StringArrayVarP(&filenames, "filename", "f", nil, "filename, directory, or URL to files to apply, can be repeated")
+	cmd.Flags().StringVarP(&kustomize, "kustomize", "k", "", "process a kustomization directory")
+	cmd.Flags().BoolVarP(&recursive, "recursive", "R", false, "process the directory used in -f, --filename recursively")
+	cmd.Flags().StringVar(&dryRun, "dry-run", "none", "must be \"none\", \"server\", or \"client\"")
+	return cmd
+}
+
+// resolveITSContext returns the context of the ITS (control) cluster: the --remote-context when it was
+// given or exists in the kubeconfig, else the single context serving ManagedCluster resources
+func resolveITSContext(kubeconfig, remoteCtx string, explicit bool) (string, error) {
+	contexts := kubeconfigContextOrder(kubeconfig)
+	if explicit || contains(contexts, remoteCtx) {
+		return remoteCtx, nil
+	}
+	return detectITSContext(remoteCtx, contexts, func(context string) bool {
+		_, err := runKubectl([]string{"get", "managedclusters", "-o", "name", "--request-timeout=5s", "--context", context}, kubeconfig)
+		return err == nil
+	})
+}
+
+// detectITSContext returns the one context for which isITS is true, failing when none or several are
+func detectITSContext(remoteCtx string, contexts []string, isITS func(context string) bool) (string, error) {
+	var found []string
+	for _, c := range contexts {
+		if isITS(c) {
+			found = append(found, c)
+		}
+	}
+	switch len(found) {
+	case 0:
+		return "", fmt.Errorf("context %q not found and no context serves ManagedCluster resources, set the ITS cluster with --remote-context", remoteCtx)
+	case 1:
+		return found[0], nil
+	}
+	return "", fmt.Errorf("context %q not found and several contexts serve ManagedCluster resources (%s), pick one with --remote-context", remoteCtx, strings.Join(found, ", "))
+}
+
+// contains tells whether values holds value
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,26 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestDetectITSContext ensures the single context serving ManagedClusters is picked, and that none or
+// several ask for --remote-context
+func TestDetectITSContext(t *testing.T) {
+	contexts := []string{"kind-kubeflex", "cluster1", "control", "cluster2"}
+	its, err := detectITSContext("its1", contexts, func(context string) bool { return context == "control" })
+	if err != nil || its != "control" {
+		t.Errorf("expected control, got %q, %v", its, err)
+	}
+
+	_, err = detectITSContext("its1", contexts, func(string) bool { return false })
+	if err == nil || !strings.Contains(err.Error(), `context "its1" not found and no context serves ManagedCluster resources`) {
+		t.Errorf("expected an error when no context is an ITS, got %v", err)
+	}
+
+	_, err = detectITSContext("its1", contexts, func(context string) bool { return strings.HasPrefix(context, "c") && context != "control" })
+	if err == nil || !strings.Contains(err.Error(), "(cluster1, cluster2)") {
+		t.Errorf("expected an error naming both candidates, got %v", err)
+	}
+}
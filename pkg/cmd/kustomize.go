@@ -0,0 +1,68 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"kubectl-multi/pkg/util"
+)
+
+// kustomizeBuild renders a kustomization directory with `kubectl kustomize`, it is a variable so tests can replace it
+var kustomizeBuild = func(dir, kubeconfig string) (string, error) {
+	output, err := runKubectl([]string{"kustomize", dir}, kubeconfig)
+	if err != nil {
+		return "", fmt.Errorf("failed to build kustomization %s: %v: %s", dir, err, strings.TrimSpace(output))
+	}
+	return output, nil
+}
+
+// resolveManifestSource checks the -f/-k flags of a command. With buildOnce, the kustomization is rendered
+// once and written to a temporary file that every cluster then uses with -f, instead of each cluster
+// building it again. The returned function removes the temporary file.
+func resolveManifestSource(filenames []string, kustomize string, buildOnce bool, kubeconfig string) ([]string, string, func(), error) {
+	noop := func() {}
+	if len(filenames) > 0 && kustomize != "" {
+		return nil, "", noop, fmt.Errorf("-f and -k cannot be used together")
+	}
+	for _, f := range filenames {
+		if f == "" {
+			return nil, "", noop, fmt.Errorf("-f requires a filename")
+		}
+	}
+	if kustomize == "" || !buildOnce {
+		return filenames, kustomize, noop, nil
+	}
+
+	rendered, err := kustomizeBuild(kustomize, kubeconfig)
+	if err != nil {
+		return nil, "", noop, err
+	}
+	f, err := os.CreateTemp("", util.TempFilePrefix+"kustomize-*.yaml")
+	if err != nil {
+		return nil, "", noop, fmt.Errorf("failed to create temporary file: %v", err)
+	}
+	cleanup := func() { os.Remove(f.Name()) }
+	if _, err := f.WriteString(rendered); err != nil {
+		f.Close()
+		cleanup()
+		return nil, "", noop, fmt.Errorf("failed to write rendered kustomization: %v", err)
+	}
+	if err := f.Close(); err != nil {
+		cleanup()
+		return nil, "", noop, fmt.Errorf("failed to write rendered kustomization: %v", err)
+	}
+	return []string{f.Name()}, "", cleanup, nil
+}
+
+// manifestArgs returns the kubectl flags selecting the manifests, -f FILENAME for every file or -k DIR
+func manifestArgs(filenames []string, kustomize string) []string {
+	if kustomize != "" {
+		return []string{"-k", kustomize}
+	}
+	var args []string
+	for _, f := range filenames {
+		args = append(args, "-f", f)
+	}
+	return args
+}
@@ -0,0 +1,111 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"os"
+	"strings"
+	"testing"
+
+	"kubectl-multi/pkg/cluster"
+	"kubectl-multi/pkg/util"
+)
+
+// TestResolveManifestSourcePrebuild ensures the kustomization is built once and applied from a temporary file
+func TestResolveManifestSourcePrebuild(t *testing.T) {
+	defer func(f func(dir, kubeconfig string) (string, error)) { kustomizeBuild = f }(kustomizeBuild)
+	builds := 0
+	kustomizeBuild = func(dir, kubeconfig string) (string, error) {
+		builds++
+		if dir != "overlays/prod" {
+			t.Errorf("unexpected kustomization dir %q", dir)
+		}
+		return "apiVersion: v1\nkind: ConfigMap\nmetadata:\n  name: app-config\n", nil
+	}
+
+	filenames, kustomize, cleanup, err := resolveManifestSource(nil, "overlays/prod", true, "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if builds != 1 {
+		t.Errorf("expected the kustomization to be built once, built %d times", builds)
+	}
+	if kustomize != "" || len(filenames) != 1 || !strings.Contains(filenames[0], util.TempFilePrefix) {
+		t.Fatalf("expected a temporary manifest instead of -k, got filenames %q kustomize %q", filenames, kustomize)
+	}
+	filename := filenames[0]
+	if args := manifestArgs(filenames, kustomize); args[0] != "-f" || args[1] != filename {
+		t.Errorf("expected -f %s, got %v", filename, args)
+	}
+
+	data, err := os.ReadFile(filename)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if !strings.Contains(string(data), "name: app-config") {
+		t.Errorf("unexpected rendered manifest %q", data)
+	}
+
+	cleanup()
+	if _, err := os.Stat(filename); !os.IsNotExist(err) {
+		t.Errorf("expected the temporary manifest to be removed, got %v", err)
+	}
+}
+
+// TestResolveManifestSourcePassThrough ensures -k is passed to every cluster when not built once
+func TestResolveManifestSourcePassThrough(t *testing.T) {
+	defer func(f func(dir, kubeconfig string) (string, error)) { kustomizeBuild = f }(kustomizeBuild)
+	kustomizeBuild = func(dir, kubeconfig string) (string, error) {
+		t.Error("expected the kustomization not to be built")
+		return "", nil
+	}
+
+	filenames, kustomize, cleanup, err := resolveManifestSource(nil, "overlays/prod", false, "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer cleanup()
+	if args := manifestArgs(filenames, kustomize); args[0] != "-k" || args[1] != "overlays/prod" {
+		t.Errorf("expected -k overlays/prod, got %v", args)
+	}
+
+	if _, _, _, err := resolveManifestSource([]string{"app.yaml"}, "overlays/prod", true, ""); err == nil {
+		t.Error("expected an error when both -f and -k are set")
+	}
+}
+
+// TestMultipleFilenames ensures repeated -f flags are all passed to the kubectl of every cluster
+func TestMultipleFilenames(t *testing.T) {
+	installFakeKubectl(t, "#!/bin/sh\necho \"$*\"\n")
+
+	cmd := newApplyCommand()
+	if err := cmd.ParseFlags([]string{"-f", "ns.yaml", "-f", "app.yaml"}); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	flagged, err := cmd.Flags().GetStringArray("filename")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	filenames, kustomize, cleanup, err := resolveManifestSource(flagged, "", true, "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer cleanup()
+
+	for _, c := range []cluster.ClusterInfo{{Context: "cluster1"}, {Context: "cluster2"}} {
+		var out bytes.Buffer
+		op := kubectlOp("", func(c cluster.ClusterInfo) []string {
+			return buildDeleteArgs("", "", "", filenames, kustomize, false, "none", true, false, "", false, "", false, c.Context)
+		})
+		if result := runOnCluster(context.Background(), c, fanOutOptions{}, op, &out); result.Err != nil {
+			t.Fatalf("unexpected error in %s: %v", c.Context, result.Err)
+		}
+		if want := "delete -f ns.yaml -f app.yaml --context " + c.Context; !strings.Contains(out.String(), want) {
+			t.Errorf("expected %q in %s, got %q", want, c.Context, out.String())
+		}
+	}
+
+	if _, _, _, err := resolveManifestSource([]string{"ns.yaml", "app.yaml"}, "overlays/prod", false, ""); err == nil {
+		t.Error("expected an error when several -f and -k are set")
+	}
+}
@@ -0,0 +1,94 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"kubectl-multi/pkg/cluster"
+)
+
+// tableWriter is where the get handlers write their tab-separated tables
+type tableWriter interface {
+	io.Writer
+	Flush() error
+}
+
+// newTableWriter returns the writer for a table printed to out. With --label-column, the value of
+// the cluster label is inserted after the CLUSTER column of every row.
+func newTableWriter(out io.Writer, clusters []cluster.ClusterInfo) tableWriter {
+	tw := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	if labelColumn == "" {
+		return tw
+	}
+	return &labelColumnWriter{tw: tw, header: strings.ToUpper(labelColumn), values: clusterLabelValues(clusters, labelColumn)}
+}
+
+// clusterLabelValues maps the names under which clusters are printed to the value of their label key,
+// or "<none>" if the cluster does not have the label
+func clusterLabelValues(clusters []cluster.ClusterInfo, key string) map[string]string {
+	values := make(map[string]string)
+	for _, c := range clusters {
+		value, ok := c.Labels[key]
+		if !ok || value == "" {
+			value = "<none>"
+		}
+		values[c.Name] = value
+		values[c.Display()] = value
+	}
+	return values
+}
+
+// labelColumnWriter inserts a cluster label column after the leading CLUSTER column of the rows
+// written to it, before passing them on to the tabwriter
+type labelColumnWriter struct {
+	tw      *tabwriter.Writer
+	header  string
+	values  map[string]string
+	pending []byte
+}
+
+func (w *labelColumnWriter) Write(p []byte) (int, error) {
+	w.pending = append(w.pending, p...)
+	for {
+		i := bytes.IndexByte(w.pending, '\n')
+		if i < 0 {
+			return len(p), nil
+		}
+		line := string(w.pending[:i])
+		w.pending = w.pending[i+1:]
+		if _, err := fmt.Fprintln(w.tw, w.addColumn(line)); err != nil {
+			return 0, err
+		}
+	}
+}
+
+// addColumn inserts the label column into a single row. Lines that are not table rows,
+// such as "No resource found.", are left unchanged.
+func (w *labelColumnWriter) addColumn(line string) string {
+	first, rest, ok := strings.Cut(line, "\t")
+	if !ok {
+		return line
+	}
+	if first == "CLUSTER" {
+		return first + "\t" + w.header + "\t" + rest
+	}
+	value, found := w.values[first]
+	if !found {
+		value = "<none>"
+	}
+	return first + "\t" + value + "\t" + rest
+}
+
+// Flush writes any incomplete last line and flushes the tabwriter
+func (w *labelColumnWriter) Flush() error {
+	if len(w.pending) > 0 {
+		if _, err := io.WriteString(w.tw, w.addColumn(string(w.pending))); err != nil {
+			return err
+		}
+		w.pending = nil
+	}
+	return w.tw.Flush()
+}
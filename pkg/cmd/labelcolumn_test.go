@@ -0,0 +1,58 @@
+package cmd
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+
+	"kubectl-multi/pkg/cluster"
+)
+
+// TestLabelColumnWriter ensures the label value is inserted after the CLUSTER column of every row
+func TestLabelColumnWriter(t *testing.T) {
+	defer func(v string) { labelColumn = v }(labelColumn)
+	labelColumn = "region"
+
+	clusters := []cluster.ClusterInfo{
+		{Name: "cluster1", Labels: map[string]string{"region": "eu-west"}},
+		{Name: "cluster2", DisplayName: "prod-us"},
+	}
+
+	var buf bytes.Buffer
+	tw := newTableWriter(&buf, clusters)
+	fmt.Fprintf(tw, "CLUSTER\tNAME\tSTATUS\n")
+	fmt.Fprintf(tw, "cluster1\tweb-1\tRunning\n")
+	fmt.Fprintf(tw, "prod-us\tweb-")
+	fmt.Fprintf(tw, "2\tPending\n")
+	tw.Flush()
+
+	want := [][]string{
+		{"CLUSTER", "REGION", "NAME", "STATUS"},
+		{"cluster1", "eu-west", "web-1", "Running"},
+		{"prod-us", "<none>", "web-2", "Pending"},
+	}
+	lines := strings.Split(strings.TrimRight(buf.String(), "\n"), "\n")
+	if len(lines) != len(want) {
+		t.Fatalf("expected %d lines, got:\n%s", len(want), buf.String())
+	}
+	for i, w := range want {
+		if got := strings.Fields(lines[i]); strings.Join(got, " ") != strings.Join(w, " ") {
+			t.Errorf("line %d: expected %v, got %v", i, w, got)
+		}
+	}
+}
+
+// TestLabelColumnWriterMessages ensures lines that are not table rows are left unchanged
+func TestLabelColumnWriterMessages(t *testing.T) {
+	defer func(v string) { labelColumn = v }(labelColumn)
+	labelColumn = "region"
+
+	var buf bytes.Buffer
+	tw := newTableWriter(&buf, nil)
+	fmt.Fprintf(tw, "No resource found.\n")
+	tw.Flush()
+	if got := buf.String(); got != "No resource found.\n" {
+		t.Errorf("unexpected output %q", got)
+	}
+}
@@ -114,7 +114,7 @@ kubectl multi logs nginx-pod --timestamps`,
 }
 
 func handleLogsCommand(podPattern string, follow, previous bool, container, since, sinceTime string, timestamps bool, tail, limitBytes int64, kubeconfig, remoteCtx, namespace string, allNamespaces bool) error {
-	clusters, err := cluster.DiscoverClusters(kubeconfig, remoteCtx)
+	clusters, _, err := discoverClusters(kubeconfig, remoteCtx)
 	if err != nil {
 		return fmt.Errorf("failed to discover clusters: %v", err)
 	}
@@ -134,13 +134,13 @@ func handleLogsCommand(podPattern string, follow, previous bool, container, sinc
 
 	foundAnyPod := false
 
-	for _, clusterInfo := range clusters {
+	for i, clusterInfo := range clusters {
 		if clusterInfo.Client == nil {
 			fmt.Printf("Warning: skipping cluster %s (no client available)\n", clusterInfo.Name)
 			continue
 		}
 
-		fmt.Printf("=== Cluster: %s (Context: %s) ===\n", clusterInfo.Name, clusterInfo.Context)
+		fmt.Println(clusterBanner(clusterInfo, i+1, len(clusters)))
 
 		// Get matching pods from this cluster
 		matchingPods, err := getMatchingPods(clusterInfo, podPattern, namespace, allNamespaces)
@@ -229,7 +229,7 @@ func buildLogsArgs(podName string, follow, previous bool, container, since, sinc
 
 func executeKubectlLogs(args []string, kubeconfig, clusterName string) (string, error) {
 
-	cmd := exec.Command("kubectl", args...)
+	cmd := exec.Command(kubectlBinary(args), args...)
 
 	cmd.Env = os.Environ()
 	if kubeconfig != "" {
@@ -263,13 +263,9 @@ func executeKubectlLogs(args []string, kubeconfig, clusterName string) (string,
 func getMatchingPods(clusterInfo cluster.ClusterInfo, pattern, namespace string, allNamespaces bool) ([]string, error) {
 	var matchingPods []string
 
-	targetNS := ""
+	targetNS := cluster.TargetNamespace(clusterInfo, namespace)
 	if allNamespaces {
 		targetNS = ""
-	} else if namespace != "" {
-		targetNS = namespace
-	} else {
-		targetNS = "default"
 	}
 
 	pods, err := clusterInfo.Client.CoreV1().Pods(targetNS).List(context.TODO(), metav1.ListOptions{})
@@ -0,0 +1,176 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/duration"
+)
+
+// fieldManager summarizes a managedFields entry: the manager, how it last wrote the object and the
+// fields it owns
+type fieldManager struct {
+	Manager string
+	// Operation is Apply or Update, followed by the subresource written if any (e.g. Update/status)
+	Operation string
+	Time      time.Time
+	// Fields are the owned field paths, sibling fields being grouped as in .spec.{paused,replicas}
+	Fields []string
+}
+
+// parseManagedFields returns the field managers of an object, from its metadata.managedFields
+func parseManagedFields(obj *unstructured.Unstructured) ([]fieldManager, error) {
+	var managers []fieldManager
+	for _, entry := range obj.GetManagedFields() {
+		m := fieldManager{Manager: entry.Manager, Operation: string(entry.Operation)}
+		if entry.Subresource != "" {
+			m.Operation += "/" + entry.Subresource
+		}
+		if entry.Time != nil {
+			m.Time = entry.Time.Time
+		}
+		if entry.FieldsV1 != nil && len(entry.FieldsV1.Raw) > 0 {
+			var fields map[string]interface{}
+			if err := json.Unmarshal(entry.FieldsV1.Raw, &fields); err != nil {
+				return nil, fmt.Errorf("invalid managedFields of %s: %v", entry.Manager, err)
+			}
+			m.Fields = fieldPaths("", fields)
+		}
+		managers = append(managers, m)
+	}
+	return managers, nil
+}
+
+// fieldPaths flattens a FieldsV1 set into the paths of its leaves below prefix. Sibling fields that are
+// leaves are grouped into one path to keep the summary compact.
+func fieldPaths(prefix string, set map[string]interface{}) []string {
+	keys := make([]string, 0, len(set))
+	for k := range set {
+		if k != "." {
+			keys = append(keys, k)
+		}
+	}
+	sort.Strings(keys)
+
+	var leaves, paths []string
+	for _, k := range keys {
+		child, _ := set[k].(map[string]interface{})
+		_, self := child["."]
+		if len(child) == 0 || (self && len(child) == 1) {
+			if strings.HasPrefix(k, "f:") {
+				leaves = append(leaves, strings.TrimPrefix(k, "f:"))
+			} else {
+				paths = append(paths, prefix+fieldSegment(k))
+			}
+			continue
+		}
+		paths = append(paths, fieldPaths(prefix+fieldSegment(k), child)...)
+	}
+
+	switch len(leaves) {
+	case 0:
+		return paths
+	case 1:
+		return append([]string{prefix + "." + leaves[0]}, paths...)
+	}
+	return append([]string{prefix + ".{" + strings.Join(leaves, ",") + "}"}, paths...)
+}
+
+// fieldSegment renders a FieldsV1 key: f:name is a field, k:{...} a list item selected by its keys,
+// v:value a set item and i:N a list item by index
+func fieldSegment(key string) string {
+	switch {
+	case strings.HasPrefix(key, "f:"):
+		return "." + strings.TrimPrefix(key, "f:")
+	case strings.HasPrefix(key, "k:"):
+		var keys map[string]interface{}
+		if err := json.Unmarshal([]byte(strings.TrimPrefix(key, "k:")), &keys); err != nil {
+			return "[" + strings.TrimPrefix(key, "k:") + "]"
+		}
+		names := make([]string, 0, len(keys))
+		for name := range keys {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		selectors := make([]string, 0, len(names))
+		for _, name := range names {
+			selectors = append(selectors, fmt.Sprintf("%s=%v", name, keys[name]))
+		}
+		return "[" + strings.Join(selectors, ",") + "]"
+	case strings.HasPrefix(key, "v:"):
+		var value interface{}
+		if err := json.Unmarshal([]byte(strings.TrimPrefix(key, "v:")), &value); err != nil {
+			return "[" + strings.TrimPrefix(key, "v:") + "]"
+		}
+		return fmt.Sprintf("[%v]", value)
+	case strings.HasPrefix(key, "i:"):
+		return "[" + strings.TrimPrefix(key, "i:") + "]"
+	}
+	return "." + key
+}
+
+// printManagersTable prints one row per field manager of every object, the objects of the same resource
+// in different clusters being next to each other so their managers can be compared
+func printManagersTable(tw tableWriter, objects []clusterObject, now time.Time) {
+	defer tw.Flush()
+
+	if len(objects) == 0 {
+		fmt.Fprintf(tw, "No resources found.\n")
+		return
+	}
+
+	sorted := append([]clusterObject(nil), objects...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		return managedResourceKey(sorted[i]) < managedResourceKey(sorted[j])
+	})
+
+	namespaced := false
+	for _, o := range sorted {
+		if o.Object.GetNamespace() != "" {
+			namespaced = true
+		}
+	}
+	header := []string{"CLUSTER"}
+	if namespaced {
+		header = append(header, "NAMESPACE")
+	}
+	fmt.Fprintln(tw, strings.Join(append(header, "NAME", "MANAGER", "OPERATION", "AGE", "FIELDS"), "\t"))
+
+	for _, o := range sorted {
+		row := []string{o.Cluster}
+		if namespaced {
+			ns := o.Object.GetNamespace()
+			if ns == "" {
+				ns = "<none>"
+			}
+			row = append(row, ns)
+		}
+		row = append(row, strings.ToLower(o.Object.GetKind())+"/"+o.Object.GetName())
+
+		managers, err := parseManagedFields(o.Object)
+		if err != nil {
+			fmt.Fprintln(tw, strings.Join(append(row, "<error>", "", "", err.Error()), "\t"))
+			continue
+		}
+		if len(managers) == 0 {
+			fmt.Fprintln(tw, strings.Join(append(row, "<none>", "", "", ""), "\t"))
+			continue
+		}
+		for _, m := range managers {
+			age := "<unknown>"
+			if !m.Time.IsZero() {
+				age = duration.HumanDuration(now.Sub(m.Time))
+			}
+			fmt.Fprintln(tw, strings.Join(append(row, m.Manager, m.Operation, age, strings.Join(m.Fields, " ")), "\t"))
+		}
+	}
+}
+
+// managedResourceKey identifies the resource of an object independently of its cluster
+func managedResourceKey(o clusterObject) string {
+	return o.Object.GetKind() + "/" + o.Object.GetNamespace() + "/" + o.Object.GetName()
+}
@@ -0,0 +1,149 @@
+package cmd
+
+import (
+	"bytes"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// managedDeployment is a deployment as returned by `kubectl get -o json --show-managed-fields`, applied
+// with server-side apply and scaled by an autoscaler
+const managedDeployment = `{
+  "apiVersion": "apps/v1",
+  "kind": "Deployment",
+  "metadata": {
+    "name": "web",
+    "namespace": "shop",
+    "managedFields": [
+      {
+        "manager": "kubectl",
+        "operation": "Apply",
+        "apiVersion": "apps/v1",
+        "time": "2024-01-01T10:00:00Z",
+        "fieldsType": "FieldsV1",
+        "fieldsV1": {
+          "f:metadata": {"f:labels": {"f:app": {}, "f:tier": {}}},
+          "f:spec": {
+            "f:selector": {},
+            "f:template": {"f:spec": {"f:containers": {"k:{\"name\":\"web\"}": {".": {}, "f:image": {}, "f:name": {}}}}}
+          }
+        }
+      },
+      {
+        "manager": "autoscaler",
+        "operation": "Update",
+        "apiVersion": "apps/v1",
+        "time": "2024-01-01T11:00:00Z",
+        "fieldsType": "FieldsV1",
+        "fieldsV1": {"f:spec": {"f:replicas": {}}}
+      },
+      {
+        "manager": "kube-controller-manager",
+        "operation": "Update",
+        "subresource": "status",
+        "apiVersion": "apps/v1",
+        "fieldsType": "FieldsV1",
+        "fieldsV1": {"f:status": {"f:conditions": {"k:{\"type\":\"Available\"}": {".": {}, "f:status": {}}}, "f:replicas": {}}}
+      }
+    ]
+  }
+}`
+
+// TestParseManagedFields ensures every manager is listed with its operation and a compact list of its fields
+func TestParseManagedFields(t *testing.T) {
+	items, err := parseObjects([]byte(managedDeployment))
+	if err != nil {
+		t.Fatal(err)
+	}
+	managers, err := parseManagedFields(items[0])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := []fieldManager{
+		{
+			Manager:   "kubectl",
+			Operation: "Apply",
+			Time:      time.Date(2024, 1, 1, 10, 0, 0, 0, time.UTC),
+			Fields:    []string{".metadata.labels.{app,tier}", ".spec.selector", ".spec.template.spec.containers[name=web].{image,name}"},
+		},
+		{
+			Manager:   "autoscaler",
+			Operation: "Update",
+			Time:      time.Date(2024, 1, 1, 11, 0, 0, 0, time.UTC),
+			Fields:    []string{".spec.replicas"},
+		},
+		{
+			Manager:   "kube-controller-manager",
+			Operation: "Update/status",
+			Fields:    []string{".status.replicas", ".status.conditions[type=Available].status"},
+		},
+	}
+	if len(managers) != len(want) {
+		t.Fatalf("expected %d managers, got %+v", len(want), managers)
+	}
+	for i := range want {
+		if managers[i].Manager != want[i].Manager || managers[i].Operation != want[i].Operation ||
+			!managers[i].Time.Equal(want[i].Time) || !reflect.DeepEqual(managers[i].Fields, want[i].Fields) {
+			t.Errorf("manager %d: expected %+v, got %+v", i, want[i], managers[i])
+		}
+	}
+}
+
+// TestFieldSegment ensures the FieldsV1 keys are rendered as readable path segments
+func TestFieldSegment(t *testing.T) {
+	tests := map[string]string{
+		`f:replicas`:                     ".replicas",
+		`k:{"port":80,"protocol":"TCP"}`: "[port=80,protocol=TCP]",
+		`v:"example.com/finalizer"`:      "[example.com/finalizer]",
+		`i:2`:                            "[2]",
+		`k:{not json`:                    "[{not json]",
+	}
+	for key, want := range tests {
+		if got := fieldSegment(key); got != want {
+			t.Errorf("fieldSegment(%q) = %q, want %q", key, got, want)
+		}
+	}
+}
+
+// TestPrintManagersTable ensures the same resource of different clusters is printed together, so a
+// manager present in only one cluster stands out
+func TestPrintManagersTable(t *testing.T) {
+	items, err := parseObjects([]byte(managedDeployment))
+	if err != nil {
+		t.Fatal(err)
+	}
+	bare, err := parseObjects([]byte(`{"apiVersion":"v1","kind":"ConfigMap","metadata":{"name":"settings","namespace":"shop"}}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var out bytes.Buffer
+	objects := []clusterObject{
+		{Cluster: "wds1", Object: items[0]},
+		{Cluster: "wds1", Object: bare[0]},
+		{Cluster: "wds2", Object: items[0]},
+	}
+	printManagersTable(newTableWriter(&out, nil), objects, time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC))
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 8 {
+		t.Fatalf("expected a header and 7 rows, got:\n%s", out.String())
+	}
+	if fields := strings.Fields(lines[0]); !reflect.DeepEqual(fields, []string{"CLUSTER", "NAMESPACE", "NAME", "MANAGER", "OPERATION", "AGE", "FIELDS"}) {
+		t.Errorf("unexpected header %q", lines[0])
+	}
+	if fields := strings.Fields(lines[1]); fields[0] != "wds1" || fields[2] != "configmap/settings" || fields[3] != "<none>" {
+		t.Errorf("expected the configmap without managers first, got %q", lines[1])
+	}
+	for i, cluster := range []string{"wds1", "wds1", "wds1", "wds2", "wds2", "wds2"} {
+		if fields := strings.Fields(lines[i+2]); fields[0] != cluster || fields[2] != "deployment/web" {
+			t.Errorf("row %d: expected deployment/web of %s, got %q", i+2, cluster, lines[i+2])
+		}
+	}
+	if !strings.Contains(lines[3], "autoscaler") || !strings.Contains(lines[3], "60m") || !strings.Contains(lines[3], ".spec.replicas") {
+		t.Errorf("unexpected autoscaler row %q", lines[3])
+	}
+}
@@ -401,7 +401,7 @@ func handlePodsGetMulti(tw *tabwriter.Writer, clusters []MultiGetClusterInfo, re
 	for _, c := range clusters {
 		infos = append(infos, toClusterInfo(c))
 	}
-	return handlePodsGet(tw, infos, resourceName, selector, showLabels, outputFormat, namespace, allNamespaces)
+	return handlePodsGet(tw, infos, resourceName, selector, showLabels, outputFormat, namespace, allNamespaces, defaultChunkSize)
 }
 
 func handleServicesGetMulti(tw *tabwriter.Writer, clusters []MultiGetClusterInfo, resourceName, selector string, showLabels bool, outputFormat, namespace string, allNamespaces bool) error {
@@ -0,0 +1,56 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"text/template"
+)
+
+// defaultNameTemplate prints cross-cluster names as context/namespace/kind/name. The namespace is
+// empty for cluster-scoped resources, so the name always has four parts.
+const defaultNameTemplate = "{{.Context}}/{{.Namespace}}/{{.Kind}}/{{.Name}}"
+
+// qualifiedName holds the fields available to --name-template
+type qualifiedName struct {
+	Context   string
+	Cluster   string
+	Namespace string
+	Kind      string
+	Name      string
+}
+
+// newQualifiedName returns the fields of an object. Kind is formatted like the kubectl -o name prefix,
+// the lowercase kind followed by the API group, e.g. "pod" or "deployment.apps".
+func newQualifiedName(o clusterObject) qualifiedName {
+	kind := strings.ToLower(o.Object.GetKind())
+	if group := o.Object.GroupVersionKind().Group; group != "" {
+		kind += "." + group
+	}
+	return qualifiedName{
+		Context:   o.Context,
+		Cluster:   o.Cluster,
+		Namespace: o.Object.GetNamespace(),
+		Kind:      kind,
+		Name:      o.Object.GetName(),
+	}
+}
+
+// printQualifiedNames prints one line per object formatted with the name template
+func printQualifiedNames(out io.Writer, objects []clusterObject, nameTemplate string) error {
+	if nameTemplate == "" {
+		nameTemplate = defaultNameTemplate
+	}
+	tmpl, err := template.New("name").Option("missingkey=error").Parse(nameTemplate)
+	if err != nil {
+		return fmt.Errorf("invalid --name-template: %v", err)
+	}
+
+	for _, o := range objects {
+		if err := tmpl.Execute(out, newQualifiedName(o)); err != nil {
+			return fmt.Errorf("invalid --name-template: %v", err)
+		}
+		fmt.Fprintln(out)
+	}
+	return nil
+}
@@ -0,0 +1,58 @@
+package cmd
+
+import (
+	"bytes"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func nameTestObjects() []clusterObject {
+	return []clusterObject{
+		{Cluster: "prod", Context: "cluster1", Object: &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "apps/v1", "kind": "Deployment",
+			"metadata": map[string]interface{}{"name": "web", "namespace": "shop"},
+		}}},
+		{Cluster: "cluster2", Context: "cluster2", Object: &unstructured.Unstructured{Object: map[string]interface{}{
+			"apiVersion": "v1", "kind": "Node",
+			"metadata": map[string]interface{}{"name": "node-1"},
+		}}},
+	}
+}
+
+// TestPrintQualifiedNamesDefault ensures names include the context, and the group of non-core kinds
+func TestPrintQualifiedNamesDefault(t *testing.T) {
+	var buf bytes.Buffer
+	if err := printQualifiedNames(&buf, nameTestObjects(), ""); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := "cluster1/shop/deployment.apps/web\ncluster2//node/node-1\n"
+	if buf.String() != want {
+		t.Errorf("expected:\n%s\ngot:\n%s", want, buf.String())
+	}
+}
+
+// TestPrintQualifiedNamesTemplate ensures --name-template controls the format
+func TestPrintQualifiedNamesTemplate(t *testing.T) {
+	var buf bytes.Buffer
+	if err := printQualifiedNames(&buf, nameTestObjects(), "{{.Cluster}}:{{.Kind}}/{{.Name}}"); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	want := "prod:deployment.apps/web\ncluster2:node/node-1\n"
+	if buf.String() != want {
+		t.Errorf("expected:\n%s\ngot:\n%s", want, buf.String())
+	}
+}
+
+// TestPrintQualifiedNamesInvalidTemplate ensures unknown fields are reported instead of printing garbage
+func TestPrintQualifiedNamesInvalidTemplate(t *testing.T) {
+	var buf bytes.Buffer
+	if err := printQualifiedNames(&buf, nameTestObjects(), "{{.Uid}}"); err == nil {
+		t.Error("expected an error for an unknown field")
+	}
+	if err := printQualifiedNames(&buf, nameTestObjects(), "{{.Name"); err == nil {
+		t.Error("expected an error for a malformed template")
+	}
+}
@@ -0,0 +1,96 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+
+	"kubectl-multi/pkg/cluster"
+
+	"k8s.io/client-go/tools/clientcmd"
+	"sigs.k8s.io/yaml"
+)
+
+// Values of --context-order
+const (
+	contextOrderCurrentFirst = "current-first"
+	contextOrderName         = "name"
+	contextOrderKubeconfig   = "kubeconfig"
+)
+
+// validateContextOrder checks the value of --context-order
+func validateContextOrder(order string) error {
+	switch order {
+	case contextOrderCurrentFirst, contextOrderName, contextOrderKubeconfig:
+		return nil
+	}
+	return fmt.Errorf("invalid --context-order value %q: must be one of kubeconfig|name|current-first", order)
+}
+
+// orderTargets sorts the fan-out targets in place by context name, or by the position of their context
+// in kubeconfigOrder. Contexts missing from kubeconfigOrder keep their relative order after the others.
+// current-first targets are already ordered by fanOutTargets.
+func orderTargets(targets []cluster.ClusterInfo, order string, kubeconfigOrder []string) {
+	switch order {
+	case contextOrderName:
+		sort.SliceStable(targets, func(i, j int) bool {
+			return targets[i].Context < targets[j].Context
+		})
+	case contextOrderKubeconfig:
+		position := make(map[string]int, len(kubeconfigOrder))
+		for i, name := range kubeconfigOrder {
+			position[name] = i
+		}
+		rank := func(c cluster.ClusterInfo) int {
+			if p, ok := position[c.Context]; ok {
+				return p
+			}
+			return len(kubeconfigOrder)
+		}
+		sort.SliceStable(targets, func(i, j int) bool {
+			return rank(targets[i]) < rank(targets[j])
+		})
+	}
+}
+
+// kubeconfigContextOrder returns the contexts in the order they are listed in the kubeconfig files.
+// clientcmd merges contexts into a map, so the files are read directly.
+func kubeconfigContextOrder(kubeconfig string) []string {
+	loading := clientcmd.NewDefaultClientConfigLoadingRules()
+	if kubeconfig != "" {
+		loading.ExplicitPath = kubeconfig
+	}
+
+	var names []string
+	seen := make(map[string]bool)
+	for _, path := range loading.GetLoadingPrecedence() {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			continue
+		}
+		for _, name := range parseContextOrder(data) {
+			if !seen[name] {
+				seen[name] = true
+				names = append(names, name)
+			}
+		}
+	}
+	return names
+}
+
+// parseContextOrder returns the context names of a kubeconfig file in the order they are listed
+func parseContextOrder(data []byte) []string {
+	var cfg struct {
+		Contexts []struct {
+			Name string `json:"name"`
+		} `json:"contexts"`
+	}
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return nil
+	}
+	var names []string
+	for _, c := range cfg.Contexts {
+		names = append(names, c.Name)
+	}
+	return names
+}
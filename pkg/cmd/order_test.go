@@ -0,0 +1,80 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"kubectl-multi/pkg/cluster"
+)
+
+func targetContexts(targets []cluster.ClusterInfo) []string {
+	var contexts []string
+	for _, t := range targets {
+		contexts = append(contexts, t.Context)
+	}
+	return contexts
+}
+
+// TestContextOrderCurrentFirst ensures the default order keeps the current context first
+func TestContextOrderCurrentFirst(t *testing.T) {
+	clusters := []cluster.ClusterInfo{{Context: "a"}, {Context: "b"}, {Context: "c"}}
+	targets, _ := fanOutTargets(clusters, "c", "")
+	orderTargets(targets, contextOrderCurrentFirst, nil)
+
+	if got, want := targetContexts(targets), []string{"c", "a", "b"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+}
+
+// TestContextOrderName ensures the name order sorts by context name
+func TestContextOrderName(t *testing.T) {
+	targets := []cluster.ClusterInfo{{Context: "prod"}, {Context: "dev"}, {Context: "staging"}}
+	orderTargets(targets, contextOrderName, nil)
+
+	if got, want := targetContexts(targets), []string{"dev", "prod", "staging"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+}
+
+// TestContextOrderKubeconfig ensures the kubeconfig order follows the file, with unknown contexts last
+func TestContextOrderKubeconfig(t *testing.T) {
+	kubeconfig := filepath.Join(t.TempDir(), "config")
+	data := `apiVersion: v1
+kind: Config
+contexts:
+- name: staging
+  context: {cluster: staging}
+- name: prod
+  context: {cluster: prod}
+- name: dev
+  context: {cluster: dev}
+`
+	if err := os.WriteFile(kubeconfig, []byte(data), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	order := kubeconfigContextOrder(kubeconfig)
+	if want := []string{"staging", "prod", "dev"}; !reflect.DeepEqual(order, want) {
+		t.Fatalf("expected kubeconfig order %v, got %v", want, order)
+	}
+
+	targets := []cluster.ClusterInfo{{Context: "extra"}, {Context: "dev"}, {Context: "prod"}, {Context: "staging"}}
+	orderTargets(targets, contextOrderKubeconfig, order)
+	if got, want := targetContexts(targets), []string{"staging", "prod", "dev", "extra"}; !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+}
+
+// TestValidateContextOrder ensures unknown --context-order values are rejected
+func TestValidateContextOrder(t *testing.T) {
+	for _, order := range []string{contextOrderCurrentFirst, contextOrderName, contextOrderKubeconfig} {
+		if err := validateContextOrder(order); err != nil {
+			t.Errorf("expected %q to be valid, got %v", order, err)
+		}
+	}
+	if err := validateContextOrder("random"); err == nil {
+		t.Error("expected an error for an unknown order")
+	}
+}
@@ -0,0 +1,45 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"text/template"
+
+	"kubectl-multi/pkg/cluster"
+)
+
+// templateClusterData is the data --output-template is executed with, once per cluster
+type templateClusterData struct {
+	// Index is the position of the cluster, 0 for the first one, e.g. to print a header once
+	Index   int
+	Context string
+	Cluster string
+	// Items are the resources of the cluster as decoded JSON objects
+	Items []interface{}
+}
+
+// parseOutputTemplate compiles --output-template, so that a broken template fails before any cluster
+// is queried
+func parseOutputTemplate(text string) (*template.Template, error) {
+	tmpl, err := template.New("output").Parse(text)
+	if err != nil {
+		return nil, fmt.Errorf("invalid --output-template: %v", err)
+	}
+	return tmpl, nil
+}
+
+// executeOutputTemplate executes tmpl for every cluster in order with the objects found in it.
+// Clusters without objects are included with no items.
+func executeOutputTemplate(out io.Writer, tmpl *template.Template, clusters []cluster.ClusterInfo, objects []clusterObject) error {
+	items := make(map[string][]interface{})
+	for _, o := range objects {
+		items[o.Context] = append(items[o.Context], o.Object.Object)
+	}
+	for i, c := range clusters {
+		data := templateClusterData{Index: i, Context: c.Context, Cluster: c.Display(), Items: items[c.Context]}
+		if err := tmpl.Execute(out, data); err != nil {
+			return fmt.Errorf("failed to execute --output-template for cluster %s: %v", c.Display(), err)
+		}
+	}
+	return nil
+}
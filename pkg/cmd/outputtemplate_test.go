@@ -0,0 +1,46 @@
+package cmd
+
+import (
+	"bytes"
+	"testing"
+
+	"kubectl-multi/pkg/cluster"
+)
+
+// TestExecuteOutputTemplate ensures a CSV template gets the items of every cluster, clusters without
+// items included, and can print its header once
+func TestExecuteOutputTemplate(t *testing.T) {
+	tmpl, err := parseOutputTemplate(`{{if eq .Index 0}}cluster,kind,name{{"\n"}}{{end}}{{range .Items}}{{$.Cluster}},{{.kind}},{{.metadata.name}}{{"\n"}}{{else}}{{.Cluster}},,{{"\n"}}{{end}}`)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	clusters := []cluster.ClusterInfo{{Context: "cluster1", DisplayName: "prod"}, {Context: "cluster2"}, {Context: "cluster3"}}
+	objects := nameTestObjects()
+
+	var out bytes.Buffer
+	if err := executeOutputTemplate(&out, tmpl, clusters, objects); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := "cluster,kind,name\nprod,Deployment,web\ncluster2,Node,node-1\ncluster3,,\n"
+	if out.String() != want {
+		t.Errorf("expected:\n%s\ngot:\n%s", want, out.String())
+	}
+}
+
+// TestOutputTemplateErrors ensures a template that does not compile is rejected before running, and
+// that execution errors name the cluster
+func TestOutputTemplateErrors(t *testing.T) {
+	if _, err := parseOutputTemplate("{{range .Items}"); err == nil {
+		t.Error("expected a malformed template to be rejected")
+	}
+
+	tmpl, err := parseOutputTemplate("{{.Missing}}")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	var out bytes.Buffer
+	if err := executeOutputTemplate(&out, tmpl, []cluster.ClusterInfo{{Context: "cluster1"}}, nil); err == nil {
+		t.Error("expected an error for an unknown field")
+	}
+}
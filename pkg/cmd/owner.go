@@ -0,0 +1,108 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// maxOwnerDepth bounds the walk up the owner references, in case of cycles
+const maxOwnerDepth = 10
+
+// ownerFetcher returns the object an owner reference points to in a cluster, or nil if it does not exist
+type ownerFetcher func(context, namespace string, ref metav1.OwnerReference) (*unstructured.Unstructured, error)
+
+// ownerResolver finds the top-level owner of objects by walking their owner references,
+// caching the owners it fetched
+type ownerResolver struct {
+	fetch ownerFetcher
+	cache map[string]*unstructured.Unstructured
+}
+
+func newOwnerResolver(fetch ownerFetcher) *ownerResolver {
+	return &ownerResolver{fetch: fetch, cache: make(map[string]*unstructured.Unstructured)}
+}
+
+// kubectlOwnerFetcher fetches owners with `kubectl get -o json`
+func kubectlOwnerFetcher(kubeconfig string) ownerFetcher {
+	return func(context, namespace string, ref metav1.OwnerReference) (*unstructured.Unstructured, error) {
+		resource := strings.ToLower(ref.Kind)
+		if gv, err := schema.ParseGroupVersion(ref.APIVersion); err == nil && gv.Group != "" {
+			resource += "." + gv.Group
+		}
+		args := []string{"get", resource + "/" + ref.Name, "-o", "json", "--ignore-not-found", "--context", context}
+		if namespace != "" {
+			args = append(args, "-n", namespace)
+		}
+		output, err := runKubectl(args, kubeconfig)
+		if err != nil {
+			return nil, fmt.Errorf("%v: %s", err, strings.TrimSpace(output))
+		}
+		objects, err := parseObjects([]byte(output))
+		if err != nil || len(objects) == 0 {
+			return nil, err
+		}
+		return objects[0], nil
+	}
+}
+
+// primaryOwner returns the owner reference to follow, the controller if there is one, and the
+// number of other owners
+func primaryOwner(refs []metav1.OwnerReference) (metav1.OwnerReference, int) {
+	for _, ref := range refs {
+		if ref.Controller != nil && *ref.Controller {
+			return ref, len(refs) - 1
+		}
+	}
+	return refs[0], len(refs) - 1
+}
+
+// topOwner returns the top-level owner of an object as KIND/NAME, with the number of other owners
+// of the last step as " (+N)", or "<none>" if the object has no owner. When an owner cannot be
+// fetched, the walk stops at it.
+func (r *ownerResolver) topOwner(context string, obj *unstructured.Unstructured) string {
+	owner := "<none>"
+	current := obj
+	for depth := 0; depth < maxOwnerDepth; depth++ {
+		refs := current.GetOwnerReferences()
+		if len(refs) == 0 {
+			break
+		}
+		ref, others := primaryOwner(refs)
+		owner = ref.Kind + "/" + ref.Name
+		if others > 0 {
+			owner += fmt.Sprintf(" (+%d)", others)
+		}
+
+		next := r.get(context, current.GetNamespace(), ref)
+		if next == nil {
+			break
+		}
+		current = next
+	}
+	return owner
+}
+
+// get returns the object of an owner reference, fetching it once per cluster
+func (r *ownerResolver) get(context, namespace string, ref metav1.OwnerReference) *unstructured.Unstructured {
+	key := strings.Join([]string{context, namespace, ref.APIVersion, ref.Kind, ref.Name}, "/")
+	if obj, ok := r.cache[key]; ok {
+		return obj
+	}
+	obj, err := r.fetch(context, namespace, ref)
+	if err != nil {
+		obj = nil
+	}
+	r.cache[key] = obj
+	return obj
+}
+
+// resolveOwners sets the top-level owner of every object
+func resolveOwners(objects []clusterObject, resolver *ownerResolver) {
+	for i := range objects {
+		objects[i].Owner = resolver.topOwner(objects[i].Context, objects[i].Object)
+	}
+}
@@ -0,0 +1,88 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func ownedObject(kind, name string, owners ...metav1.OwnerReference) *unstructured.Unstructured {
+	obj := &unstructured.Unstructured{}
+	obj.SetKind(kind)
+	obj.SetName(name)
+	obj.SetNamespace("default")
+	obj.SetOwnerReferences(owners)
+	return obj
+}
+
+func ownerRef(kind, name string, controller bool) metav1.OwnerReference {
+	return metav1.OwnerReference{APIVersion: "apps/v1", Kind: kind, Name: name, Controller: &controller}
+}
+
+// fakeOwnerFetcher serves owners from a map and counts the fetches
+func fakeOwnerFetcher(owners map[string]*unstructured.Unstructured, fetches *int) ownerFetcher {
+	return func(context, namespace string, ref metav1.OwnerReference) (*unstructured.Unstructured, error) {
+		*fetches++
+		if ref.Name == "broken" {
+			return nil, errors.New("forbidden")
+		}
+		return owners[ref.Kind+"/"+ref.Name], nil
+	}
+}
+
+// TestTopOwner ensures the walk follows controllers up to the top-level owner and handles
+// absent, multiple and unreachable owners
+func TestTopOwner(t *testing.T) {
+	owners := map[string]*unstructured.Unstructured{
+		"ReplicaSet/web-7d9f": ownedObject("ReplicaSet", "web-7d9f", ownerRef("Deployment", "web", true)),
+		"Deployment/web":      ownedObject("Deployment", "web"),
+	}
+	fetches := 0
+	resolver := newOwnerResolver(fakeOwnerFetcher(owners, &fetches))
+
+	tests := []struct {
+		name string
+		obj  *unstructured.Unstructured
+		want string
+	}{
+		{"walks to the deployment", ownedObject("Pod", "web-7d9f-x", ownerRef("ReplicaSet", "web-7d9f", true)), "Deployment/web"},
+		{"no owner", ownedObject("Pod", "standalone"), "<none>"},
+		{"deleted owner", ownedObject("Pod", "orphan", ownerRef("ReplicaSet", "gone", true)), "ReplicaSet/gone"},
+		{"unreachable owner", ownedObject("Pod", "denied", ownerRef("ReplicaSet", "broken", true)), "ReplicaSet/broken"},
+		{"controller among several owners", ownedObject("ConfigMap", "shared",
+			ownerRef("Deployment", "other", false), ownerRef("ReplicaSet", "web-7d9f", true)), "Deployment/web"},
+		{"several owners without a controller", ownedObject("ConfigMap", "loose",
+			ownerRef("Deployment", "a", false), ownerRef("Deployment", "b", false)), "Deployment/a (+1)"},
+	}
+	for _, tt := range tests {
+		if got := resolver.topOwner("cluster1", tt.obj); got != tt.want {
+			t.Errorf("%s: expected %q, got %q", tt.name, tt.want, got)
+		}
+	}
+
+	before := fetches
+	resolver.topOwner("cluster1", ownedObject("Pod", "web-7d9f-y", ownerRef("ReplicaSet", "web-7d9f", true)))
+	if fetches != before {
+		t.Errorf("expected cached owners not to be fetched again, got %d more fetches", fetches-before)
+	}
+}
+
+// TestPrintKindTableOwner ensures the OWNER column follows CLUSTER once owners are resolved
+func TestPrintKindTableOwner(t *testing.T) {
+	objects := []clusterObject{
+		{Cluster: "wds1", Context: "wds1", Object: ownedObject("Pod", "web-1")},
+	}
+	objects[0].Owner = "Deployment/web"
+
+	var out bytes.Buffer
+	printKindTable(newTableWriter(&out, nil), objects, time.Now())
+	lines := strings.Split(out.String(), "\n")
+	if !strings.HasPrefix(lines[0], "CLUSTER  OWNER") || !strings.Contains(lines[1], "Deployment/web") {
+		t.Errorf("unexpected table:\n%s", out.String())
+	}
+}
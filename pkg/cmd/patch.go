@@ -0,0 +1,124 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"kubectl-multi/pkg/cluster"
+	"kubectl-multi/pkg/util"
+
+	"github.com/spf13/cobra"
+)
+
+// patchTypes are the values accepted by patch --type
+var patchTypes = []string{"strategic", "merge", "json"}
+
+// subresources are the subresources get and patch accept with --subresource
+var subresources = []string{"status", "scale"}
+
+// isOneOf reports whether value is one of allowed
+func isOneOf(value string, allowed []string) bool {
+	for _, a := range allowed {
+		if value == a {
+			return true
+		}
+	}
+	return false
+}
+
+// validateSubresource checks that --subresource names a subresource kubectl supports
+func validateSubresource(subresource string) error {
+	if subresource == "" || isOneOf(subresource, subresources) {
+		return nil
+	}
+	return fmt.Errorf("invalid --subresource %q: must be one of %s", subresource, strings.Join(subresources, "|"))
+}
+
+func newPatchCommand() *cobra.Command {
+	var patch string
+	var patchFile string
+	var patchType string
+	var subresource string
+	var dryRun string
+
+	cmd := &cobra.Command{
+		Use:   "patch (TYPE NAME | TYPE/NAME) (-p PATCH | --patch-file FILE)",
+		Short: "Update field(s) of a resource across managed clusters",
+		Long: `Update field(s) of a resource in every managed cluster using a strategic merge patch, a JSON merge
+patch, or a JSON patch. With --subresource, the status or scale subresource of the resource is
+patched instead (requires kubectl v1.24+).`,
+		Example: `# Add a label to a deployment in all managed clusters
+kubectl multi patch deployment web -p '{"metadata":{"labels":{"tier":"frontend"}}}'
+
+# Scale a deployment through its scale subresource in all managed clusters
+kubectl multi patch deployment web --subresource=scale --type=merge -p '{"spec":{"replicas":3}}'
+
+# Apply a JSON patch read from a file
+kubectl multi patch deployment/web --type=json --patch-file patch.json`,
+		Args: cobra.RangeArgs(1, 2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if (patch == "") == (patchFile == "") {
+				return fmt.Errorf("must specify exactly one of -p, --patch or --patch-file")
+			}
+			if !isOneOf(patchType, patchTypes) {
+				return fmt.Errorf("invalid --type %q: must be one of %s", patchType, strings.Join(patchTypes, "|"))
+			}
+			if err := validateSubresource(subresource); err != nil {
+				return err
+			}
+			kubeconfig, remoteCtx, _, namespace, _ := GetGlobalFlags()
+			return handlePatchCommand(args, patch, patchFile, patchType, subresource, dryRun, kubeconfig, remoteCtx, namespace)
+		},
+	}
+
+	cmd.Flags().StringVarP(&patch, "patch", "p", "", "the patch to be applied to the resource JSON file")
+	cmd.Flags().StringVar(&patchFile, "patch-file", "", "a file containing a patch to be applied to the resource")
+	cmd.Flags().StringVar(&patchType, "type", "strategic", "the type of patch being provided; one of "+strings.Join(patchTypes, "|"))
+	cmd.Flags().StringVar(&subresource, "subresource", "", "if specified, patch will operate on the subresource of the requested object; one of "+strings.Join(subresources, "|"))
+	cmd.Flags().StringVar(&dryRun, "dry-run", "none", "must be \"none\", \"server\", or \"client\"")
+
+	return cmd
+}
+
+func handlePatchCommand(resource []string, patch, patchFile, patchType, subresource, dryRun, kubeconfig, remoteCtx, namespace string) error {
+	clusters, unreachable, err := discoverClusters(kubeconfig, remoteCtx)
+	if err != nil {
+		return fmt.Errorf("failed to discover clusters: %v", err)
+	}
+	if len(clusters) == 0 {
+		return fmt.Errorf("no clusters discovered")
+	}
+
+	opts := fanOutOptions{Namespace: namespaceOption(namespace, false), Unreachable: unreachable}
+	results, err := fanOut(clusters, kubeconfig, remoteCtx, opts, kubectlOp(kubeconfig, func(c cluster.ClusterInfo) []string {
+		return buildPatchArgs(resource, patch, patchFile, patchType, subresource, namespace, dryRun, c.Context)
+	}))
+	// Admission webhooks may warn or reject differently per cluster, so report them side by side
+	if isServerDryRun(dryRun) {
+		fmt.Println()
+		printDryRunSummary(util.GetOutputStream(), newDryRunReports(results))
+		printDryRunMatrix(util.GetOutputStream(), results)
+	}
+	return err
+}
+
+// buildPatchArgs builds the arguments of `kubectl patch` for a cluster
+func buildPatchArgs(resource []string, patch, patchFile, patchType, subresource, namespace, dryRun, context string) []string {
+	args := append([]string{"patch"}, resource...)
+	if patchFile != "" {
+		args = append(args, "--patch-file", patchFile)
+	} else {
+		args = append(args, "-p", patch)
+	}
+	args = append(args, "--type", patchType)
+	if subresource != "" {
+		args = append(args, "--subresource="+subresource)
+	}
+	if namespace != "" {
+		args = append(args, "-n", namespace)
+	}
+	if dryRun != "none" && dryRun != "" {
+		args = append(args, "--dry-run="+dryRun)
+	}
+	return append(args, "--context", context)
+}
@@ -0,0 +1,74 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+)
+
+// TestValidateSubresource ensures only the subresources kubectl supports are accepted
+func TestValidateSubresource(t *testing.T) {
+	for _, s := range []string{"", "status", "scale"} {
+		if err := validateSubresource(s); err != nil {
+			t.Errorf("expected %q to be accepted, got %v", s, err)
+		}
+	}
+	for _, s := range []string{"log", "Scale", "exec"} {
+		if err := validateSubresource(s); err == nil {
+			t.Errorf("expected %q to be rejected", s)
+		}
+	}
+}
+
+// TestBuildPatchArgs ensures the patch, its type and the subresource are passed to kubectl
+func TestBuildPatchArgs(t *testing.T) {
+	args := strings.Join(buildPatchArgs([]string{"deployment", "web"}, `{"spec":{"replicas":3}}`, "", "merge", "scale", "prod", "none", "cluster1"), " ")
+	want := `patch deployment web -p {"spec":{"replicas":3}} --type merge --subresource=scale -n prod --context cluster1`
+	if args != want {
+		t.Errorf("expected %q, got %q", want, args)
+	}
+
+	args = strings.Join(buildPatchArgs([]string{"deployment/web"}, "", "patch.json", "json", "", "", "server", "cluster2"), " ")
+	want = `patch deployment/web --patch-file patch.json --type json --dry-run=server --context cluster2`
+	if args != want {
+		t.Errorf("expected %q, got %q", want, args)
+	}
+}
+
+// fakePatchKubectl is a kubectl patching the deployment in a server dry run, which an admission
+// webhook of cluster2 rejects
+const fakePatchKubectl = `#!/bin/sh
+case "$*" in
+*"--context cluster2"*) echo 'Error from server (Forbidden): admission webhook "policy.example.com" denied the request: replicas must be at most 2' >&2; exit 1;;
+esac
+echo 'deployment.apps/web patched (server dry run)'
+`
+
+// TestPatchServerDryRunSummary ensures a server dry run of patch is followed by the per-cluster summary
+// and the changes per resource, as for apply and create
+func TestPatchServerDryRunSummary(t *testing.T) {
+	installFakeKubectl(t, fakePatchKubectl)
+	kubeconfig := useFakeContexts(t, "cluster1", "cluster2")
+
+	var err error
+	stdout, _ := captureOutput(t, func() {
+		err = handlePatchCommand([]string{"deployment", "web"}, `{"spec":{"replicas":3}}`, "", "merge", "", "server", kubeconfig, "", "")
+	})
+	if err == nil {
+		t.Error("expected cluster2 to fail the patch")
+	}
+	for _, want := range []string{"Rejected:", "replicas must be at most 2", "Changes per resource:", "deployment.apps/web"} {
+		if !strings.Contains(stdout, want) {
+			t.Errorf("expected the output to contain %q, got:\n%s", want, stdout)
+		}
+	}
+	if !strings.Contains(stdout, "patched") || !strings.Contains(stdout, "error") {
+		t.Errorf("expected web to be patched in cluster1 and an error in cluster2, got:\n%s", stdout)
+	}
+
+	stdout, _ = captureOutput(t, func() {
+		err = handlePatchCommand([]string{"deployment", "web"}, `{"spec":{"replicas":3}}`, "", "merge", "", "none", kubeconfig, "", "")
+	})
+	if strings.Contains(stdout, "Changes per resource:") {
+		t.Errorf("expected no dry run summary without --dry-run=server, got:\n%s", stdout)
+	}
+}
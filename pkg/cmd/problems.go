@@ -0,0 +1,101 @@
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"kubectl-multi/pkg/cluster"
+	"kubectl-multi/pkg/util"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/util/duration"
+)
+
+// podStatusReason returns the status of a pod the way `kubectl get pods` shows it, e.g. CrashLoopBackOff,
+// ImagePullBackOff, Init:Error or Terminating, falling back to the pod phase
+func podStatusReason(pod *corev1.Pod) string {
+	reason := string(pod.Status.Phase)
+	if pod.Status.Reason != "" {
+		reason = pod.Status.Reason
+	}
+
+	for _, s := range pod.Status.InitContainerStatuses {
+		if s.State.Terminated != nil && s.State.Terminated.ExitCode == 0 {
+			continue
+		}
+		if s.State.Terminated != nil && s.State.Terminated.Reason != "" {
+			return "Init:" + s.State.Terminated.Reason
+		}
+		if s.State.Waiting != nil && s.State.Waiting.Reason != "" && s.State.Waiting.Reason != "PodInitializing" {
+			return "Init:" + s.State.Waiting.Reason
+		}
+	}
+
+	for _, s := range pod.Status.ContainerStatuses {
+		if s.State.Waiting != nil && s.State.Waiting.Reason != "" {
+			reason = s.State.Waiting.Reason
+		} else if s.State.Terminated != nil && s.State.Terminated.Reason != "" {
+			reason = s.State.Terminated.Reason
+		}
+	}
+
+	if pod.DeletionTimestamp != nil {
+		return "Terminating"
+	}
+	return reason
+}
+
+// isProblemPod reports whether a pod is not in a healthy Running, Succeeded or Completed state
+func isProblemPod(pod *corev1.Pod) bool {
+	switch podStatusReason(pod) {
+	case string(corev1.PodRunning), string(corev1.PodSucceeded), "Completed":
+		return false
+	}
+	return true
+}
+
+// problemPods returns the pods that are not running or completed
+func problemPods(pods []clusterPod) []clusterPod {
+	var problems []clusterPod
+	for _, p := range pods {
+		if isProblemPod(p.Pod) {
+			problems = append(problems, p)
+		}
+	}
+	return problems
+}
+
+// printProblemPods prints the problem pods with their status reason, followed by a per-cluster count
+func printProblemPods(tw tableWriter, clusters []cluster.ClusterInfo, problems []clusterPod, allNamespaces bool, now time.Time) {
+	if len(problems) == 0 {
+		fmt.Fprintf(tw, "No problem pods found.\n")
+		tw.Flush()
+		return
+	}
+
+	if allNamespaces {
+		fmt.Fprintf(tw, "CLUSTER\tNAMESPACE\tNAME\tREADY\tSTATUS\tRESTARTS\tAGE\n")
+	} else {
+		fmt.Fprintf(tw, "CLUSTER\tNAME\tREADY\tSTATUS\tRESTARTS\tAGE\n")
+	}
+	for _, p := range problems {
+		ready := fmt.Sprintf("%d/%d", util.GetPodReadyContainers(p.Pod), len(p.Pod.Spec.Containers))
+		age := duration.HumanDuration(now.Sub(p.Pod.CreationTimestamp.Time))
+		if allNamespaces {
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%d\t%s\n", p.Cluster, p.Pod.Namespace, p.Pod.Name, ready, podStatusReason(p.Pod), util.GetPodRestarts(p.Pod), age)
+		} else {
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%d\t%s\n", p.Cluster, p.Pod.Name, ready, podStatusReason(p.Pod), util.GetPodRestarts(p.Pod), age)
+		}
+	}
+	tw.Flush()
+
+	perCluster := make(map[string]int)
+	for _, p := range problems {
+		perCluster[p.Cluster]++
+	}
+	var counts []clusterCount
+	for _, c := range clusters {
+		counts = append(counts, clusterCount{Context: c.Display(), Count: perCluster[c.Display()]})
+	}
+	fmt.Printf("\nProblem pods: %s\n", formatCountSummary(counts))
+}
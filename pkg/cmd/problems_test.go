@@ -0,0 +1,94 @@
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"kubectl-multi/pkg/cluster"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// problemTestPod returns a pod in the given phase whose only container has the given state
+func problemTestPod(name string, phase corev1.PodPhase, state corev1.ContainerState) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+		Spec:       corev1.PodSpec{Containers: []corev1.Container{{Name: "app"}}},
+		Status: corev1.PodStatus{
+			Phase:             phase,
+			ContainerStatuses: []corev1.ContainerStatus{{Name: "app", State: state}},
+		},
+	}
+}
+
+// TestPodStatusReason ensures the status matches what kubectl shows for common failures
+func TestPodStatusReason(t *testing.T) {
+	running := corev1.ContainerState{Running: &corev1.ContainerStateRunning{}}
+	tests := []struct {
+		pod     *corev1.Pod
+		want    string
+		problem bool
+	}{
+		{problemTestPod("ok", corev1.PodRunning, running), "Running", false},
+		{problemTestPod("done", corev1.PodSucceeded, corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{Reason: "Completed"}}), "Completed", false},
+		{problemTestPod("crash", corev1.PodRunning, corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}}), "CrashLoopBackOff", true},
+		{problemTestPod("image", corev1.PodPending, corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "ImagePullBackOff"}}), "ImagePullBackOff", true},
+		{problemTestPod("pending", corev1.PodPending, corev1.ContainerState{}), "Pending", true},
+		{problemTestPod("oom", corev1.PodFailed, corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{Reason: "OOMKilled", ExitCode: 137}}), "OOMKilled", true},
+	}
+
+	evicted := problemTestPod("evicted", corev1.PodFailed, corev1.ContainerState{})
+	evicted.Status.Reason = "Evicted"
+	tests = append(tests, struct {
+		pod     *corev1.Pod
+		want    string
+		problem bool
+	}{evicted, "Evicted", true})
+
+	initFailing := problemTestPod("init", corev1.PodPending, corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "PodInitializing"}})
+	initFailing.Status.InitContainerStatuses = []corev1.ContainerStatus{{Name: "migrate", State: corev1.ContainerState{Terminated: &corev1.ContainerStateTerminated{Reason: "Error", ExitCode: 1}}}}
+	tests = append(tests, struct {
+		pod     *corev1.Pod
+		want    string
+		problem bool
+	}{initFailing, "Init:Error", true})
+
+	for _, tt := range tests {
+		if got := podStatusReason(tt.pod); got != tt.want {
+			t.Errorf("%s: expected status %s, got %s", tt.pod.Name, tt.want, got)
+		}
+		if got := isProblemPod(tt.pod); got != tt.problem {
+			t.Errorf("%s: expected problem %v, got %v", tt.pod.Name, tt.problem, got)
+		}
+	}
+}
+
+// TestPrintProblemPods ensures only problem pods are listed with their cluster and reason
+func TestPrintProblemPods(t *testing.T) {
+	pods := []clusterPod{
+		{Cluster: "cluster1", Pod: problemTestPod("web-1", corev1.PodRunning, corev1.ContainerState{Running: &corev1.ContainerStateRunning{}})},
+		{Cluster: "cluster1", Pod: problemTestPod("web-2", corev1.PodRunning, corev1.ContainerState{Waiting: &corev1.ContainerStateWaiting{Reason: "CrashLoopBackOff"}})},
+		{Cluster: "cluster2", Pod: problemTestPod("web-3", corev1.PodPending, corev1.ContainerState{})},
+	}
+	clusters := []cluster.ClusterInfo{{Context: "cluster1"}, {Context: "cluster2"}, {Context: "cluster3"}}
+
+	problems := problemPods(pods)
+	if len(problems) != 2 {
+		t.Fatalf("expected 2 problem pods, got %d", len(problems))
+	}
+
+	var buf bytes.Buffer
+	printProblemPods(newTableWriter(&buf, nil), clusters, problems, false, time.Now())
+	out := buf.String()
+	if strings.Contains(out, "web-1") {
+		t.Errorf("expected the running pod not to be listed:\n%s", out)
+	}
+	for _, want := range []string{"cluster1  web-2", "CrashLoopBackOff", "cluster2  web-3", "Pending"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected %q in output:\n%s", want, out)
+		}
+	}
+}
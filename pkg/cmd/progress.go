@@ -0,0 +1,58 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sync"
+
+	"kubectl-multi/pkg/util"
+)
+
+// progressReporter renders a single-line "[n/total] processing <context>..." indicator.
+// It writes to stderr so stdout only carries the results.
+type progressReporter struct {
+	mu      sync.Mutex
+	out     io.Writer
+	total   int
+	done    int
+	enabled bool
+}
+
+// newProgressReporter returns a reporter for total clusters, enabled by --progress when stdout and stderr
+// are terminals
+func newProgressReporter(total int) *progressReporter {
+	return &progressReporter{
+		out:     os.Stderr,
+		total:   total,
+		enabled: showProgress && progressTerminal(os.Stdout, os.Stderr),
+	}
+}
+
+// progressTerminal tells whether the indicator can be shown. It is written to stderr, but the results
+// printed to stdout are only shown on the same terminal when stdout is one too, not when they are piped
+// or redirected for a script.
+func progressTerminal(stdout, stderr *os.File) bool {
+	return util.IsTerminal(stdout) && util.IsTerminal(stderr)
+}
+
+// start shows that the operation on context is in progress
+func (p *progressReporter) start(context string) {
+	if !p.enabled {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	fmt.Fprintf(p.out, "\r\033[K[%d/%d] processing %s...", p.done+1, p.total, context)
+}
+
+// finish marks the current cluster as done and clears the indicator so results can be printed
+func (p *progressReporter) finish() {
+	if !p.enabled {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.done++
+	fmt.Fprint(p.out, "\r\033[K")
+}
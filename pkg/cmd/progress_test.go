@@ -0,0 +1,67 @@
+package cmd
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"kubectl-multi/pkg/util"
+)
+
+// TestProgressReporter ensures the indicator counts clusters and is cleared after each one
+func TestProgressReporter(t *testing.T) {
+	var out bytes.Buffer
+	p := &progressReporter{out: &out, total: 2, enabled: true}
+
+	p.start("wds1")
+	p.finish()
+	p.start("wds2")
+
+	got := out.String()
+	if !strings.Contains(got, "[1/2] processing wds1...") || !strings.Contains(got, "[2/2] processing wds2...") {
+		t.Errorf("unexpected progress output: %q", got)
+	}
+}
+
+// TestProgressReporterDisabled ensures nothing is written when progress is disabled
+func TestProgressReporterDisabled(t *testing.T) {
+	var out bytes.Buffer
+	p := &progressReporter{out: &out, total: 1}
+
+	p.start("wds1")
+	p.finish()
+
+	if out.Len() != 0 {
+		t.Errorf("expected no output, got %q", out.String())
+	}
+}
+
+// TestProgressTerminal ensures the indicator is only shown when stdout is a terminal as well as stderr
+func TestProgressTerminal(t *testing.T) {
+	// The null device is a character device, like a terminal
+	tty, err := os.OpenFile(os.DevNull, os.O_WRONLY, 0)
+	if err != nil {
+		t.Skipf("cannot open %s: %v", os.DevNull, err)
+	}
+	defer tty.Close()
+	if !util.IsTerminal(tty) {
+		t.Skipf("%s is not a character device", os.DevNull)
+	}
+	file, err := os.Create(filepath.Join(t.TempDir(), "out"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer file.Close()
+
+	if !progressTerminal(tty, tty) {
+		t.Error("expected progress when stdout and stderr are terminals")
+	}
+	if progressTerminal(file, tty) {
+		t.Error("expected no progress when stdout is redirected")
+	}
+	if progressTerminal(tty, file) {
+		t.Error("expected no progress when stderr is redirected")
+	}
+}
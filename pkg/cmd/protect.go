@@ -0,0 +1,57 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"kubectl-multi/pkg/cluster"
+)
+
+// defaultProtectedNamespaces are the namespaces delete refuses to operate in without --force-protected
+var defaultProtectedNamespaces = []string{"kube-system", "kube-public"}
+
+// checkProtectedNamespaces returns an error if a delete would operate in, or delete, one of the protected
+// namespaces. All namespaces (-A) include the protected ones.
+func checkProtectedNamespaces(resourceType, resourceName, namespace string, allNamespaces bool, protected []string) error {
+	if len(protected) == 0 {
+		return nil
+	}
+	if allNamespaces {
+		return fmt.Errorf("refusing to delete across all namespaces, which include the protected namespaces %s; pass --force-protected to proceed", strings.Join(protected, ", "))
+	}
+
+	// Without a namespace, each cluster's context namespace is checked by checkProtectedContextNamespaces
+	for _, ns := range protected {
+		if namespace != "" && ns == namespace {
+			return fmt.Errorf("refusing to delete in protected namespace %q on all clusters; pass --force-protected to proceed", ns)
+		}
+	}
+
+	// Deleting the protected namespace itself
+	switch strings.ToLower(resourceType) {
+	case "namespaces", "namespace", "ns":
+		for _, ns := range protected {
+			if ns == resourceName {
+				return fmt.Errorf("refusing to delete protected namespace %q on all clusters; pass --force-protected to proceed", ns)
+			}
+		}
+	}
+	return nil
+}
+
+// checkProtectedContextNamespaces returns an error if a delete given no namespace would operate in a
+// protected namespace because the kubeconfig context of one of the targets pins it
+func checkProtectedContextNamespaces(targets []cluster.ClusterInfo, namespace string, allNamespaces bool, protected []string) error {
+	if namespace != "" || allNamespaces {
+		return nil
+	}
+	for _, c := range targets {
+		target := cluster.TargetNamespace(c, "")
+		for _, ns := range protected {
+			if ns == target {
+				return fmt.Errorf("refusing to delete in protected namespace %q, the namespace of the context of cluster %s; pass -n or --force-protected to proceed", ns, c.Display())
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,57 @@
+package cmd
+
+import (
+	"testing"
+
+	"kubectl-multi/pkg/cluster"
+)
+
+// TestCheckProtectedNamespaces ensures deletes in, or of, protected namespaces are refused
+func TestCheckProtectedNamespaces(t *testing.T) {
+	tests := []struct {
+		name          string
+		resourceType  string
+		resourceName  string
+		namespace     string
+		allNamespaces bool
+		protected     []string
+		wantErr       bool
+	}{
+		{name: "protected namespace", resourceType: "pods", namespace: "kube-system", protected: defaultProtectedNamespaces, wantErr: true},
+		{name: "other protected namespace", resourceType: "cm", namespace: "kube-public", protected: defaultProtectedNamespaces, wantErr: true},
+		{name: "regular namespace", resourceType: "pods", namespace: "production", protected: defaultProtectedNamespaces},
+		{name: "default namespace", resourceType: "pods", protected: defaultProtectedNamespaces},
+		{name: "all namespaces", resourceType: "pods", allNamespaces: true, protected: defaultProtectedNamespaces, wantErr: true},
+		{name: "protected namespace itself", resourceType: "namespace", resourceName: "kube-system", protected: defaultProtectedNamespaces, wantErr: true},
+		{name: "regular namespace itself", resourceType: "ns", resourceName: "e2e-1234", protected: defaultProtectedNamespaces},
+		{name: "custom list", resourceType: "pods", namespace: "monitoring", protected: []string{"monitoring"}, wantErr: true},
+		{name: "protection disabled", resourceType: "pods", namespace: "kube-system", protected: nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkProtectedNamespaces(tt.resourceType, tt.resourceName, tt.namespace, tt.allNamespaces, tt.protected)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("expected error %v, got %v", tt.wantErr, err)
+			}
+		})
+	}
+}
+
+// TestCheckProtectedContextNamespaces ensures a delete given no namespace is refused when the context
+// of a target pins a protected namespace, and allowed with an explicit namespace
+func TestCheckProtectedContextNamespaces(t *testing.T) {
+	targets := []cluster.ClusterInfo{{Context: "cluster1"}, {Context: "cluster2", Namespace: "kube-system"}}
+	if err := checkProtectedContextNamespaces(targets, "", false, defaultProtectedNamespaces); err == nil {
+		t.Error("expected the kube-system context namespace of cluster2 to be refused")
+	}
+	if err := checkProtectedContextNamespaces(targets, "production", false, defaultProtectedNamespaces); err != nil {
+		t.Errorf("expected an explicit namespace to be allowed, got %v", err)
+	}
+	if err := checkProtectedContextNamespaces(targets[:1], "", false, defaultProtectedNamespaces); err != nil {
+		t.Errorf("expected the default namespace to be allowed, got %v", err)
+	}
+	if err := checkProtectedContextNamespaces(targets, "", false, nil); err != nil {
+		t.Errorf("expected no error with protection disabled, got %v", err)
+	}
+}
@@ -0,0 +1,178 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"kubectl-multi/pkg/util"
+
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// defaultQuotaWarnAt is the percentage of a quota limit above which a namespace is flagged
+const defaultQuotaWarnAt = 80
+
+func newQuotaCommand() *cobra.Command {
+	var warnAt int
+
+	cmd := &cobra.Command{
+		Use:   "quota",
+		Short: "Summarize the ResourceQuota usage of every namespace across managed clusters",
+		Long: `Print the used and hard values of the ResourceQuotas of every managed cluster in one table, one
+row per quota resource with CLUSTER and NAMESPACE columns. Resources using --warn-at percent of
+their limit or more are flagged with WARNING, and the namespaces concerned are listed at the end.`,
+		Example: `# Show the quota usage of every namespace of every cluster
+kubectl multi quota
+
+# Flag quotas from 90% of their limit, in one namespace
+kubectl multi quota -n team-a --warn-at 90`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if warnAt < 1 || warnAt > 100 {
+				return fmt.Errorf("--warn-at must be a percentage between 1 and 100")
+			}
+			kubeconfig, remoteCtx, _, namespace, allNamespaces := GetGlobalFlags()
+			return handleQuotaCommand(warnAt, kubeconfig, remoteCtx, namespace, allNamespaces)
+		},
+	}
+
+	cmd.Flags().IntVar(&warnAt, "warn-at", defaultQuotaWarnAt, "flag the quota resources using at least this percentage of their limit")
+
+	return cmd
+}
+
+func handleQuotaCommand(warnAt int, kubeconfig, remoteCtx, namespace string, allNamespaces bool) error {
+	clusters, _, err := discoverClusters(kubeconfig, remoteCtx)
+	if err != nil {
+		return fmt.Errorf("failed to discover clusters: %v", err)
+	}
+	if len(clusters) == 0 {
+		return fmt.Errorf("no clusters discovered")
+	}
+
+	// Quotas are a fleet-wide view, so look at every namespace unless one is given
+	if namespace == "" {
+		allNamespaces = true
+	}
+
+	targets, _ := fanOutTargets(clusters, "", itsContext(remoteCtx))
+	var quotas []clusterQuota
+	for _, o := range fetchObjects(targets, kubeconfig, "resourcequotas", "", "", namespace, allNamespaces, defaultChunkSize) {
+		quota := &corev1.ResourceQuota{}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(o.Object.Object, quota); err != nil {
+			fmt.Printf("Warning: failed to read resourcequota %s in cluster %s: %v\n", o.Object.GetName(), o.Cluster, err)
+			continue
+		}
+		quotas = append(quotas, clusterQuota{Cluster: o.Cluster, Quota: quota})
+	}
+	out := util.GetOutputStream()
+	flagged := printQuotaTable(newTableWriter(out, clusters), quotaRows(quotas), warnAt)
+	if len(flagged) > 0 {
+		fmt.Fprintf(out, "\n%d namespaces use %d%% or more of a quota limit: %s\n", len(flagged), warnAt, strings.Join(flagged, ", "))
+	}
+	return nil
+}
+
+// clusterQuota is a ResourceQuota tagged with its cluster
+type clusterQuota struct {
+	Cluster string
+	Quota   *corev1.ResourceQuota
+}
+
+// quotaRow is the usage of one resource of a quota
+type quotaRow struct {
+	Cluster   string
+	Namespace string
+	Quota     string
+	Resource  string
+	Used      string
+	Hard      string
+	// Percent is the used share of the hard limit, or -1 when the limit is 0
+	Percent float64
+}
+
+// quotaRows returns one row per resource of every quota, from the used and hard values of its status.
+// Quotas keep their cluster order, sorted by namespace and name, and resources are sorted by name.
+func quotaRows(quotas []clusterQuota) []quotaRow {
+	clusterOrder := make(map[string]int)
+	for _, q := range quotas {
+		if _, ok := clusterOrder[q.Cluster]; !ok {
+			clusterOrder[q.Cluster] = len(clusterOrder)
+		}
+	}
+	sorted := append([]clusterQuota(nil), quotas...)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		a, b := sorted[i], sorted[j]
+		if a.Cluster != b.Cluster {
+			return clusterOrder[a.Cluster] < clusterOrder[b.Cluster]
+		}
+		if a.Quota.Namespace != b.Quota.Namespace {
+			return a.Quota.Namespace < b.Quota.Namespace
+		}
+		return a.Quota.Name < b.Quota.Name
+	})
+
+	var rows []quotaRow
+	for _, q := range sorted {
+		names := make([]string, 0, len(q.Quota.Status.Hard))
+		for name := range q.Quota.Status.Hard {
+			names = append(names, string(name))
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			hard := q.Quota.Status.Hard[corev1.ResourceName(name)]
+			used, ok := q.Quota.Status.Used[corev1.ResourceName(name)]
+			row := quotaRow{
+				Cluster:   q.Cluster,
+				Namespace: q.Quota.Namespace,
+				Quota:     q.Quota.Name,
+				Resource:  name,
+				Used:      "0",
+				Hard:      hard.String(),
+				Percent:   -1,
+			}
+			if ok {
+				row.Used = used.String()
+			}
+			if h := hard.AsApproximateFloat64(); h > 0 {
+				row.Percent = used.AsApproximateFloat64() / h * 100
+			}
+			rows = append(rows, row)
+		}
+	}
+	return rows
+}
+
+// printQuotaTable prints the quota rows, flagging those using at least warnAt percent of their limit,
+// and returns the cluster/namespace of the flagged rows
+func printQuotaTable(tw tableWriter, rows []quotaRow, warnAt int) []string {
+	defer tw.Flush()
+
+	if len(rows) == 0 {
+		fmt.Fprintf(tw, "No resource quotas found.\n")
+		return nil
+	}
+
+	fmt.Fprintln(tw, "CLUSTER\tNAMESPACE\tQUOTA\tRESOURCE\tUSED\tHARD\tUSED%\tSTATUS")
+	var flagged []string
+	seen := make(map[string]bool)
+	for _, r := range rows {
+		percent, marker := "-", ""
+		if r.Percent >= 0 {
+			percent = fmt.Sprintf("%.0f%%", r.Percent)
+			if r.Percent >= float64(warnAt) {
+				marker = "WARNING"
+				if ns := r.Cluster + "/" + r.Namespace; !seen[ns] {
+					seen[ns] = true
+					flagged = append(flagged, ns)
+				}
+			}
+		}
+		fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\t%s\t%s\t%s\n", r.Cluster, r.Namespace, r.Quota, r.Resource, r.Used, r.Hard, percent, marker)
+	}
+	return flagged
+}
@@ -0,0 +1,75 @@
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"text/tabwriter"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func newTestQuota(namespace, name string, hard, used map[string]string) *corev1.ResourceQuota {
+	q := &corev1.ResourceQuota{ObjectMeta: metav1.ObjectMeta{Namespace: namespace, Name: name}}
+	q.Status.Hard = corev1.ResourceList{}
+	q.Status.Used = corev1.ResourceList{}
+	for k, v := range hard {
+		q.Status.Hard[corev1.ResourceName(k)] = resource.MustParse(v)
+	}
+	for k, v := range used {
+		q.Status.Used[corev1.ResourceName(k)] = resource.MustParse(v)
+	}
+	return q
+}
+
+// TestQuotaRows ensures every quota resource gets a row with its used share of the limit, in cluster
+// order and then by namespace, quota and resource
+func TestQuotaRows(t *testing.T) {
+	rows := quotaRows([]clusterQuota{
+		{Cluster: "cluster2", Quota: newTestQuota("shop", "compute", map[string]string{"pods": "10"}, map[string]string{"pods": "5"})},
+		{Cluster: "cluster1", Quota: newTestQuota("team-b", "compute", map[string]string{"requests.cpu": "2"}, map[string]string{"requests.cpu": "1800m"})},
+		{Cluster: "cluster1", Quota: newTestQuota("team-a", "compute", map[string]string{"requests.memory": "4Gi", "pods": "0"}, nil)},
+	})
+
+	var got []string
+	for _, r := range rows {
+		got = append(got, r.Cluster+"/"+r.Namespace+"/"+r.Resource)
+	}
+	want := "cluster2/shop/pods cluster1/team-a/pods cluster1/team-a/requests.memory cluster1/team-b/requests.cpu"
+	if strings.Join(got, " ") != want {
+		t.Fatalf("expected rows %s, got %s", want, strings.Join(got, " "))
+	}
+	if rows[0].Percent != 50 {
+		t.Errorf("expected 50%% of pods used, got %v", rows[0].Percent)
+	}
+	if rows[1].Percent != -1 {
+		t.Errorf("expected no percentage for a zero limit, got %v", rows[1].Percent)
+	}
+	if rows[2].Used != "0" || rows[2].Percent != 0 {
+		t.Errorf("expected nothing used without a used value, got %+v", rows[2])
+	}
+	if rows[3].Percent != 90 {
+		t.Errorf("expected 90%% of requests.cpu used, got %v", rows[3].Percent)
+	}
+}
+
+// TestPrintQuotaTable ensures resources at or above the threshold are flagged and their namespaces returned once
+func TestPrintQuotaTable(t *testing.T) {
+	rows := []quotaRow{
+		{Cluster: "cluster1", Namespace: "team-a", Quota: "compute", Resource: "pods", Used: "8", Hard: "10", Percent: 80},
+		{Cluster: "cluster1", Namespace: "team-a", Quota: "compute", Resource: "requests.cpu", Used: "2", Hard: "2", Percent: 100},
+		{Cluster: "cluster2", Namespace: "team-a", Quota: "compute", Resource: "pods", Used: "1", Hard: "10", Percent: 10},
+	}
+	var out bytes.Buffer
+	flagged := printQuotaTable(tabwriter.NewWriter(&out, 0, 0, 2, ' ', 0), rows, 80)
+
+	if strings.Join(flagged, ",") != "cluster1/team-a" {
+		t.Errorf("expected cluster1/team-a to be flagged once, got %v", flagged)
+	}
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if len(lines) != 4 || !strings.HasSuffix(strings.Join(strings.Fields(lines[1]), " "), "80% WARNING") || strings.Contains(lines[3], "WARNING") {
+		t.Errorf("unexpected table:\n%s", out.String())
+	}
+}
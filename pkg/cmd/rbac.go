@@ -0,0 +1,85 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"kubectl-multi/pkg/cluster"
+)
+
+// canI asks a cluster with `kubectl auth can-i --quiet` whether the current identity may perform verb on
+// resource. It is a variable so tests can replace it.
+var canI = func(verb, resource, namespace string, allNamespaces bool, context, kubeconfig string) (bool, error) {
+	args := []string{"auth", "can-i", verb, resource, "--quiet", "--context", context}
+	if allNamespaces {
+		args = append(args, "-A")
+	} else if namespace != "" {
+		args = append(args, "-n", namespace)
+	}
+	output, err := runKubectl(args, kubeconfig)
+	if err == nil {
+		return true, nil
+	}
+	// With --quiet, a denied request exits 1 without printing anything
+	var kerr *kubectlError
+	if errors.As(err, &kerr) && strings.TrimSpace(kerr.Stderr) == "" {
+		return false, nil
+	}
+	return false, fmt.Errorf("%v: %s", err, strings.TrimSpace(output))
+}
+
+// checkDeletePermissions asks every target cluster whether the current identity may delete each of
+// resources and returns the reason to skip the clusters where it may not, or where it could not be checked
+func checkDeletePermissions(targets []cluster.ClusterInfo, resources []string, namespace string, allNamespaces bool, kubeconfig string) map[string]string {
+	skip := make(map[string]string)
+	for _, c := range targets {
+		var denied []string
+		for _, resource := range resources {
+			ok, err := canI("delete", resource, namespace, allNamespaces, c.Context, kubeconfig)
+			if err != nil {
+				skip[c.Context] = fmt.Sprintf("could not check permission to delete %s: %v", resource, err)
+				break
+			}
+			if !ok {
+				denied = append(denied, resource)
+			}
+		}
+		if _, failed := skip[c.Context]; !failed && len(denied) > 0 {
+			skip[c.Context] = "not allowed to delete " + strings.Join(denied, ", ")
+		}
+		if reason, ok := skip[c.Context]; ok {
+			fmt.Printf("Warning: skipping cluster %s: %s\n", c.Display(), reason)
+		}
+	}
+	return skip
+}
+
+// deleteRBACResources returns the resources checked with --check-rbac before a delete: the types given
+// on the command line, or the kinds of the -f/-k manifests
+func deleteRBACResources(resourceType, resourceName string, manifest []manifestResource) []string {
+	if manifest == nil {
+		var resources []string
+		for _, t := range strings.Split(resourceType, ",") {
+			if resourceName != "" && !strings.Contains(resourceType, ",") {
+				t += "/" + resourceName
+			}
+			resources = append(resources, t)
+		}
+		return resources
+	}
+
+	var resources []string
+	seen := make(map[string]bool)
+	for _, r := range manifest {
+		kind := strings.ToLower(r.Kind)
+		if r.Group != "" {
+			kind += "." + r.Group
+		}
+		if !seen[kind] {
+			seen[kind] = true
+			resources = append(resources, kind)
+		}
+	}
+	return resources
+}
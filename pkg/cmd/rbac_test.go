@@ -0,0 +1,86 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"reflect"
+	"strings"
+	"testing"
+
+	"kubectl-multi/pkg/cluster"
+)
+
+// TestCheckDeletePermissions ensures clusters denying or failing the can-i check are skipped with the reason
+func TestCheckDeletePermissions(t *testing.T) {
+	defer func(f func(verb, resource, namespace string, allNamespaces bool, context, kubeconfig string) (bool, error)) {
+		canI = f
+	}(canI)
+	canI = func(verb, resource, namespace string, allNamespaces bool, context, kubeconfig string) (bool, error) {
+		if verb != "delete" || namespace != "shop" {
+			t.Errorf("unexpected check %s %s -n %s", verb, resource, namespace)
+		}
+		switch {
+		case context == "readonly":
+			return false, nil
+		case context == "partial" && resource == "services":
+			return false, nil
+		case context == "broken":
+			return false, errors.New("connection refused")
+		}
+		return true, nil
+	}
+
+	targets := []cluster.ClusterInfo{{Context: "admin"}, {Context: "readonly"}, {Context: "partial"}, {Context: "broken"}}
+	skip := checkDeletePermissions(targets, []string{"deployments", "services"}, "shop", false, "")
+
+	if _, ok := skip["admin"]; ok {
+		t.Errorf("admin should not be skipped: %q", skip["admin"])
+	}
+	if got := skip["readonly"]; got != "not allowed to delete deployments, services" {
+		t.Errorf("unexpected reason for readonly: %q", got)
+	}
+	if got := skip["partial"]; got != "not allowed to delete services" {
+		t.Errorf("unexpected reason for partial: %q", got)
+	}
+	if got := skip["broken"]; !strings.Contains(got, "could not check") || !strings.Contains(got, "connection refused") {
+		t.Errorf("unexpected reason for broken: %q", got)
+	}
+
+	// A skipped cluster never runs the operation
+	var out bytes.Buffer
+	result := runOnCluster(context.Background(), targets[1], fanOutOptions{Skip: skip}, func(ctx context.Context, c cluster.ClusterInfo, out io.Writer) error {
+		t.Errorf("operation ran on skipped cluster %s", c.Context)
+		return nil
+	}, &out)
+	if result.Skipped != skip["readonly"] {
+		t.Errorf("expected readonly to be skipped, got %+v", result)
+	}
+}
+
+// TestDeleteRBACResources ensures the checked resources come from the arguments or the manifest kinds
+func TestDeleteRBACResources(t *testing.T) {
+	tests := []struct {
+		resourceType, resourceName string
+		manifest                   []manifestResource
+		want                       []string
+	}{
+		{resourceType: "deployment", resourceName: "web", want: []string{"deployment/web"}},
+		{resourceType: "pods", want: []string{"pods"}},
+		{resourceType: "deploy,svc,cm", want: []string{"deploy", "svc", "cm"}},
+		{
+			manifest: []manifestResource{
+				{Group: "apps", Kind: "Deployment", Name: "web"},
+				{Kind: "Service", Name: "web"},
+				{Group: "apps", Kind: "Deployment", Name: "api"},
+			},
+			want: []string{"deployment.apps", "service"},
+		},
+	}
+	for _, tt := range tests {
+		if got := deleteRBACResources(tt.resourceType, tt.resourceName, tt.manifest); !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("deleteRBACResources(%q, %q, %v) = %v, want %v", tt.resourceType, tt.resourceName, tt.manifest, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,188 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"kubectl-multi/pkg/cluster"
+
+	"github.com/spf13/cobra"
+)
+
+func newReconcileCommand() *cobra.Command {
+	var filenames []string
+	var kustomize string
+	var buildOnce bool
+	var recursive bool
+	var serverSide bool
+	var showDiff bool
+
+	cmd := &cobra.Command{
+		Use:   "reconcile (-f FILENAME | -k DIRECTORY)",
+		Short: "Re-apply a configuration only to the managed clusters where it drifted",
+		Long: `Compare a configuration with every managed cluster using a server-side kubectl diff, and apply it
+only to the clusters where it differs. Clusters that are already in sync are left untouched, and the
+clusters that were reconciled and those that were already in sync are listed at the end.`,
+		Example: `# Re-apply an app to the clusters where it drifted
+kubectl multi reconcile -f app.yaml
+
+# Show what drifted in each cluster before re-applying a kustomization with server-side apply
+kubectl multi reconcile -k overlays/prod --server-side --show-diff`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			kubeconfig, remoteCtx, _, namespace, _ := GetGlobalFlags()
+			filenames, kustomize, cleanup, err := resolveManifestSource(filenames, kustomize, buildOnce, kubeconfig)
+			if err != nil {
+				return err
+			}
+			defer cleanup()
+			if len(filenames) == 0 && kustomize == "" {
+				return fmt.Errorf("reconcile requires -f or -k")
+			}
+			return handleReconcileCommand(filenames, kustomize, recursive, serverSide, showDiff, kubeconfig, remoteCtx, namespace)
+		},
+	}
+
+	cmd.Flags().StringArrayVarP(&filenames, "filename", "f", nil, "filename, directory, or URL to files to reconcile, can be repeated")
+	cmd.Flags().StringVarP(&kustomize, "kustomize", "k", "", "process a kustomization directory")
+	cmd.Flags().BoolVar(&buildOnce, "build-once", true, "build the kustomization once and use the result for every cluster instead of building it per cluster")
+	cmd.Flags().BoolVarP(&recursive, "recursive", "R", false, "process the directory used in -f, --filename recursively")
+	cmd.Flags().BoolVar(&serverSide, "server-side", false, "diff and apply with server-side apply (skips clusters older than v"+serverSideApplyMinVersion+")")
+	cmd.Flags().BoolVar(&showDiff, "show-diff", false, "print the diff of every drifted cluster before applying")
+
+	return cmd
+}
+
+func handleReconcileCommand(filenames []string, kustomize string, recursive, serverSide, showDiff bool, kubeconfig, remoteCtx, namespace string) error {
+	clusters, unreachable, err := discoverClusters(kubeconfig, remoteCtx)
+	if err != nil {
+		return fmt.Errorf("failed to discover clusters: %v", err)
+	}
+	if len(clusters) == 0 {
+		return fmt.Errorf("no clusters discovered")
+	}
+
+	opts := fanOutOptions{Namespace: namespaceOption(namespace, false), Skip: make(map[string]string), Unreachable: unreachable}
+	if serverSide {
+		opts.MinServerVersion = serverSideApplyMinVersion
+	}
+
+	// Diff every cluster first, only the drifted ones are applied to
+	targets, _ := fanOutTargets(clusters, "", itsContext(remoteCtx))
+	var summary reconcileSummary
+	drifted := 0
+	for _, c := range targets {
+		if reason := preflightSkipReason(c, opts); reason != "" {
+			continue
+		}
+		output, err := runKubectl(buildReconcileArgs("diff", filenames, kustomize, recursive, serverSide, namespace, c.Context), kubeconfig)
+		changed, err := diffDrifted(output, err)
+		switch {
+		case err != nil:
+			fmt.Printf("Warning: failed to diff cluster %s: %v\n", c.Display(), err)
+			opts.Skip[c.Context] = fmt.Sprintf("diff failed: %v", err)
+			summary.DiffFailed = append(summary.DiffFailed, c.Display())
+		case changed:
+			drifted++
+			if showDiff {
+				printClusterDiff(os.Stdout, c.Display(), output)
+			}
+		default:
+			opts.Skip[c.Context] = "already in sync"
+			summary.InSync = append(summary.InSync, c.Display())
+		}
+	}
+
+	if drifted > 0 {
+		var results []clusterResult
+		results, err = fanOut(clusters, kubeconfig, remoteCtx, opts, kubectlOp(kubeconfig, func(c cluster.ClusterInfo) []string {
+			return buildReconcileArgs("apply", filenames, kustomize, recursive, serverSide, namespace, c.Context)
+		}))
+		for _, r := range results {
+			switch {
+			case r.Skipped != "":
+			case r.Err != nil:
+				summary.ApplyFailed = append(summary.ApplyFailed, r.displayName())
+			default:
+				summary.Reconciled = append(summary.Reconciled, r.displayName())
+			}
+		}
+	}
+
+	fmt.Println()
+	summary.print(os.Stdout)
+	if err == nil && len(summary.DiffFailed) > 0 {
+		err = fmt.Errorf("failed to diff %d clusters", len(summary.DiffFailed))
+	}
+	return err
+}
+
+// buildReconcileArgs builds the kubectl diff or apply arguments of the manifests for a cluster
+func buildReconcileArgs(verb string, filenames []string, kustomize string, recursive, serverSide bool, namespace, context string) []string {
+	args := append([]string{verb}, manifestArgs(filenames, kustomize)...)
+	args = append(args, "--context", context)
+	if recursive {
+		args = append(args, "-R")
+	}
+	if serverSide {
+		args = append(args, "--server-side")
+	}
+	if namespace != "" {
+		args = append(args, "-n", namespace)
+	}
+	return args
+}
+
+// diffDrifted interprets the result of kubectl diff, which exits 1 when the cluster differs from the
+// manifests and above 1 when the diff itself failed
+func diffDrifted(output string, err error) (bool, error) {
+	if err == nil {
+		return false, nil
+	}
+	var kerr *kubectlError
+	if errors.As(err, &kerr) && kerr.ExitCode == 1 {
+		return true, nil
+	}
+	return false, fmt.Errorf("%v: %s", err, strings.TrimSpace(output))
+}
+
+// printClusterDiff prints the diff of a cluster under its name
+func printClusterDiff(out io.Writer, name, diff string) {
+	fmt.Fprintf(out, "=== Diff for cluster %s ===\n", name)
+	fmt.Fprint(out, diff)
+	if !strings.HasSuffix(diff, "\n") {
+		fmt.Fprintln(out)
+	}
+}
+
+// reconcileSummary holds the outcome of a reconcile per cluster
+type reconcileSummary struct {
+	Reconciled  []string
+	InSync      []string
+	ApplyFailed []string
+	DiffFailed  []string
+}
+
+// print lists the clusters that were reconciled, those already in sync and those that failed
+func (s reconcileSummary) print(out io.Writer) {
+	if len(s.Reconciled) == 0 && len(s.ApplyFailed) == 0 && len(s.DiffFailed) == 0 {
+		fmt.Fprintf(out, "All %d clusters are already in sync, nothing to apply\n", len(s.InSync))
+		return
+	}
+	for _, line := range []struct {
+		label    string
+		clusters []string
+	}{
+		{"Reconciled", s.Reconciled},
+		{"Already in sync", s.InSync},
+		{"Apply failed", s.ApplyFailed},
+		{"Diff failed", s.DiffFailed},
+	} {
+		if len(line.clusters) > 0 {
+			fmt.Fprintf(out, "%s (%d): %s\n", line.label, len(line.clusters), strings.Join(line.clusters, ", "))
+		}
+	}
+}
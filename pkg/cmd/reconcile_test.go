@@ -0,0 +1,50 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+)
+
+// TestDiffDrifted ensures kubectl diff exit code 1 means drift and higher codes are failures
+func TestDiffDrifted(t *testing.T) {
+	if drifted, err := diffDrifted("", nil); drifted || err != nil {
+		t.Errorf("expected no drift for a clean diff, got %v, %v", drifted, err)
+	}
+	if drifted, err := diffDrifted("-  replicas: 3\n+  replicas: 2\n", &kubectlError{ExitCode: 1, Err: errors.New("exit status 1")}); !drifted || err != nil {
+		t.Errorf("expected drift for exit code 1, got %v, %v", drifted, err)
+	}
+	drifted, err := diffDrifted("error: the server doesn't have a resource type", &kubectlError{ExitCode: 2, Err: errors.New("exit status 2")})
+	if drifted || err == nil || !strings.Contains(err.Error(), "doesn't have a resource type") {
+		t.Errorf("expected a failure with kubectl's output for exit code 2, got %v, %v", drifted, err)
+	}
+}
+
+// TestBuildReconcileArgs ensures diff and apply get the same manifests and options
+func TestBuildReconcileArgs(t *testing.T) {
+	got := strings.Join(buildReconcileArgs("diff", []string{"app.yaml"}, "", true, true, "shop", "cluster1"), " ")
+	if want := "diff -f app.yaml --context cluster1 -R --server-side -n shop"; got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+	got = strings.Join(buildReconcileArgs("apply", nil, "overlays/prod", false, false, "", "cluster2"), " ")
+	if want := "apply -k overlays/prod --context cluster2"; got != want {
+		t.Errorf("expected %q, got %q", want, got)
+	}
+}
+
+// TestReconcileSummary ensures reconciled, in-sync and failed clusters are listed separately
+func TestReconcileSummary(t *testing.T) {
+	var out bytes.Buffer
+	reconcileSummary{Reconciled: []string{"cluster1"}, InSync: []string{"cluster2", "cluster3"}, DiffFailed: []string{"cluster4"}}.print(&out)
+	want := "Reconciled (1): cluster1\nAlready in sync (2): cluster2, cluster3\nDiff failed (1): cluster4\n"
+	if out.String() != want {
+		t.Errorf("expected %q, got %q", want, out.String())
+	}
+
+	out.Reset()
+	reconcileSummary{InSync: []string{"cluster1", "cluster2"}}.print(&out)
+	if !strings.Contains(out.String(), "All 2 clusters are already in sync") {
+		t.Errorf("unexpected output %q", out.String())
+	}
+}
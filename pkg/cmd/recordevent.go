@@ -0,0 +1,108 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/user"
+	"strings"
+	"time"
+
+	"kubectl-multi/pkg/cluster"
+	"kubectl-multi/pkg/util"
+
+	"sigs.k8s.io/yaml"
+)
+
+// recordEventReason is the reason of the Events created with --record-event, so they can be listed
+// with kubectl get events --field-selector reason=KubectlMulti
+const recordEventReason = "KubectlMulti"
+
+// operationEvent returns the Event recording that actor ran action against the namespace at now. It
+// is attached to the namespace itself, so it shows in kubectl get events -n NAMESPACE.
+func operationEvent(namespace, action, actor string, now time.Time) map[string]interface{} {
+	timestamp := now.UTC().Format(time.RFC3339)
+	return map[string]interface{}{
+		"apiVersion": "v1",
+		"kind":       "Event",
+		"metadata": map[string]interface{}{
+			"generateName": "kubectl-multi-",
+			"namespace":    namespace,
+			"annotations": map[string]interface{}{
+				"kubectl-multi/actor":  actor,
+				"kubectl-multi/action": action,
+			},
+		},
+		"involvedObject": map[string]interface{}{
+			"apiVersion": "v1",
+			"kind":       "Namespace",
+			"name":       namespace,
+		},
+		"type":               "Normal",
+		"reason":             recordEventReason,
+		"message":            fmt.Sprintf("%s ran: %s", actor, action),
+		"source":             map[string]interface{}{"component": "kubectl-multi"},
+		"reportingComponent": "kubectl-multi",
+		"reportingInstance":  actor,
+		"firstTimestamp":     timestamp,
+		"lastTimestamp":      timestamp,
+		"count":              1,
+	}
+}
+
+// recordAction returns the command line being run, as the user typed it
+func recordAction() string {
+	return strings.TrimSpace(util.InvocationName + " " + strings.Join(os.Args[1:], " "))
+}
+
+// recordActor returns the local user running the plugin
+func recordActor() string {
+	if u, err := user.Current(); err == nil && u.Username != "" {
+		return u.Username
+	}
+	if name := os.Getenv("USER"); name != "" {
+		return name
+	}
+	return "unknown"
+}
+
+// withRecordEvent wraps op so that, with --record-event, an Event recording the operation is created in
+// the namespace of every cluster where op succeeded, the namespace of its context (or default) when
+// the operation was given none.
+// Failing to create the Event, e.g. because it is forbidden, is only reported.
+func withRecordEvent(op clusterOp, namespace string) clusterOp {
+	if !recordEvent {
+		return op
+	}
+	return func(ctx context.Context, c cluster.ClusterInfo, out io.Writer) error {
+		if err := op(ctx, c, out); err != nil {
+			return err
+		}
+		if err := createOperationEvent(ctx, c, cluster.TargetNamespace(c, namespace), out); err != nil {
+			fmt.Fprintf(out, "Warning: could not record the operation as an Event: %v\n", err)
+		}
+		return nil
+	}
+}
+
+// createOperationEvent creates the Event of the operation in a cluster
+func createOperationEvent(ctx context.Context, c cluster.ClusterInfo, namespace string, out io.Writer) error {
+	manifest, err := yaml.Marshal(operationEvent(namespace, recordAction(), recordActor(), time.Now()))
+	if err != nil {
+		return err
+	}
+	f, err := os.CreateTemp("", util.TempFilePrefix+"event-*.yaml")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary file: %v", err)
+	}
+	defer os.Remove(f.Name())
+	if _, err := f.Write(manifest); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to write temporary file: %v", err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to write temporary file: %v", err)
+	}
+	return runKubectlTo(ctx, []string{"create", "-f", f.Name(), "--context", c.Context}, kubeconfig, out)
+}
@@ -0,0 +1,79 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"kubectl-multi/pkg/cluster"
+)
+
+// TestOperationEvent ensures the Event is attached to the namespace and records the action and actor
+func TestOperationEvent(t *testing.T) {
+	now := time.Date(2024, 5, 1, 12, 0, 0, 0, time.FixedZone("CEST", 2*3600))
+	event := operationEvent("shop", "kubectl multi apply -f app.yaml", "alice", now)
+
+	metadata := event["metadata"].(map[string]interface{})
+	involved := event["involvedObject"].(map[string]interface{})
+	if metadata["namespace"] != "shop" || involved["kind"] != "Namespace" || involved["name"] != "shop" {
+		t.Errorf("expected the event to be attached to namespace shop, got %v and %v", metadata, involved)
+	}
+	if event["message"] != "alice ran: kubectl multi apply -f app.yaml" || event["reason"] != recordEventReason {
+		t.Errorf("unexpected message or reason: %v, %v", event["message"], event["reason"])
+	}
+	if event["lastTimestamp"] != "2024-05-01T10:00:00Z" {
+		t.Errorf("expected the time in UTC, got %v", event["lastTimestamp"])
+	}
+}
+
+// fakeEventKubectl is a kubectl that creates events except in cluster2, where it is forbidden, and
+// appends the event manifests it is given to $FAKE_EVENTS
+const fakeEventKubectl = `#!/bin/sh
+case "$*" in
+create*"--context cluster2"*) echo 'Error from server (Forbidden): events is forbidden' >&2; exit 1;;
+create*) cat "$3" >> "$FAKE_EVENTS"; echo 'event/kubectl-multi-x7k2p created';;
+esac
+`
+
+// TestWithRecordEvent ensures an Event is only created after a successful operation, and a forbidden
+// Event is reported without failing the cluster
+func TestWithRecordEvent(t *testing.T) {
+	installFakeKubectl(t, fakeEventKubectl)
+	defer func(v bool) { recordEvent = v }(recordEvent)
+	events := filepath.Join(t.TempDir(), "events")
+	t.Setenv("FAKE_EVENTS", events)
+	recordEvent = true
+
+	succeed := func(ctx context.Context, c cluster.ClusterInfo, out io.Writer) error { return nil }
+	var out bytes.Buffer
+	if err := withRecordEvent(succeed, "shop")(context.Background(), cluster.ClusterInfo{Context: "cluster1"}, &out); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	data, _ := os.ReadFile(events)
+	if !strings.Contains(string(data), "namespace: shop") || !strings.Contains(string(data), "reason: "+recordEventReason) {
+		t.Errorf("expected an event in namespace shop, got:\n%s", data)
+	}
+
+	out.Reset()
+	if err := withRecordEvent(succeed, "")(context.Background(), cluster.ClusterInfo{Context: "cluster2"}, &out); err != nil {
+		t.Errorf("expected a forbidden event not to fail the cluster, got %v", err)
+	}
+	if !strings.Contains(out.String(), "Warning: could not record the operation as an Event") {
+		t.Errorf("expected a warning, got %q", out.String())
+	}
+
+	os.Remove(events)
+	fail := func(ctx context.Context, c cluster.ClusterInfo, out io.Writer) error { return errors.New("boom") }
+	if err := withRecordEvent(fail, "shop")(context.Background(), cluster.ClusterInfo{Context: "cluster1"}, &out); err == nil || err.Error() != "boom" {
+		t.Errorf("expected the operation error, got %v", err)
+	}
+	if _, err := os.Stat(events); err == nil {
+		t.Error("expected no event after a failed operation")
+	}
+}
@@ -0,0 +1,148 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+
+	"kubectl-multi/pkg/cluster"
+	"kubectl-multi/pkg/util"
+
+	"github.com/spf13/cobra"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/yaml"
+)
+
+// clusterSpecificAnnotations are annotations that only make sense in the cluster they were set in
+var clusterSpecificAnnotations = []string{
+	"kubectl.kubernetes.io/last-applied-configuration",
+	"deployment.kubernetes.io/revision",
+}
+
+func newReplicateCommand() *cobra.Command {
+	var from string
+	var yes bool
+
+	cmd := &cobra.Command{
+		Use:   "replicate TYPE/NAME --from CLUSTER",
+		Short: "Copy a resource from one cluster to all other managed clusters",
+		Long: `Copy a resource from one cluster to all other managed clusters.
+The resource is read from the --from cluster, stripped of its cluster-specific fields
+(resourceVersion, uid, creationTimestamp, managedFields and status) and applied to every other cluster.`,
+		Example: `# Mirror a ConfigMap from wds1 to every other cluster
+kubectl multi replicate cm/myconfig --from wds1
+
+# Mirror a Secret of the payments namespace without being prompted
+kubectl multi replicate secret/db-credentials --from wds1 -n payments -y`,
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if from == "" {
+				return fmt.Errorf("--from must name the source cluster")
+			}
+			kubeconfig, remoteCtx, _, namespace, _ := GetGlobalFlags()
+			return handleReplicateCommand(args[0], from, assumeYes(cmd, yes), kubeconfig, remoteCtx, namespace)
+		},
+	}
+
+	cmd.Flags().StringVar(&from, "from", "", "context (or alias) of the cluster to copy the resource from")
+	cmd.Flags().BoolVarP(&yes, "yes", "y", false, "replicate without prompting for confirmation (default from $"+assumeYesEnv+")")
+	_ = cmd.RegisterFlagCompletionFunc("from", completeClusterNames)
+
+	return cmd
+}
+
+func handleReplicateCommand(resource, from string, assumeYes bool, kubeconfig, remoteCtx, namespace string) error {
+	if !strings.Contains(resource, "/") {
+		return fmt.Errorf("resource must be given as TYPE/NAME, e.g. cm/myconfig")
+	}
+
+	clusters, unreachable, err := discoverClusters(kubeconfig, remoteCtx)
+	if err != nil {
+		return fmt.Errorf("failed to discover clusters: %v", err)
+	}
+
+	var source *cluster.ClusterInfo
+	var destinations []cluster.ClusterInfo
+	for i, c := range clusters {
+		if c.Context == from || c.Display() == from {
+			source = &clusters[i]
+			continue
+		}
+		destinations = append(destinations, c)
+	}
+	if source == nil {
+		return fmt.Errorf("source cluster %q not found", from)
+	}
+	if len(destinations) == 0 {
+		return fmt.Errorf("no other clusters to replicate to")
+	}
+
+	args := []string{"get", resource, "-o", "json", "--context", source.Context}
+	if namespace != "" {
+		args = append(args, "-n", namespace)
+	}
+	output, err := runKubectl(args, kubeconfig)
+	if err != nil {
+		return fmt.Errorf("failed to get %s from cluster %s: %v: %s", resource, source.Display(), err, strings.TrimSpace(output))
+	}
+	obj := &unstructured.Unstructured{}
+	if err := obj.UnmarshalJSON([]byte(output)); err != nil {
+		return fmt.Errorf("failed to parse %s from cluster %s: %v", resource, source.Display(), err)
+	}
+	stripClusterFields(obj)
+
+	manifest, err := yaml.Marshal(obj.Object)
+	if err != nil {
+		return fmt.Errorf("failed to render %s: %v", resource, err)
+	}
+	f, err := os.CreateTemp("", util.TempFilePrefix+"replicate-*.yaml")
+	if err != nil {
+		return fmt.Errorf("failed to create temporary file: %v", err)
+	}
+	defer os.Remove(f.Name())
+	if _, err := f.Write(manifest); err != nil {
+		f.Close()
+		return fmt.Errorf("failed to write temporary file: %v", err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("failed to write temporary file: %v", err)
+	}
+
+	ok, err := confirmAction(fmt.Sprintf("Are you sure you want to copy %s from %s to all other managed clusters ?", resource, source.Display()), assumeYes, "")
+	if err != nil {
+		return err
+	}
+	if !ok {
+		fmt.Println("Operation cancelled...")
+		return nil
+	}
+
+	opts := fanOutOptions{Namespace: namespaceOption(namespace, false), Unreachable: unreachable}
+	_, err = fanOut(destinations, kubeconfig, remoteCtx, opts, kubectlOp(kubeconfig, func(c cluster.ClusterInfo) []string {
+		return []string{"apply", "-f", f.Name(), "--context", c.Context}
+	}))
+	return err
+}
+
+// stripClusterFields removes the fields of obj that are set by the cluster it was read from,
+// so it can be applied to another cluster
+func stripClusterFields(obj *unstructured.Unstructured) {
+	obj.SetResourceVersion("")
+	obj.SetUID("")
+	obj.SetCreationTimestamp(metav1.Time{})
+	obj.SetManagedFields(nil)
+	obj.SetGeneration(0)
+	obj.SetSelfLink("")
+	obj.SetOwnerReferences(nil)
+	unstructured.RemoveNestedField(obj.Object, "status")
+
+	annotations := obj.GetAnnotations()
+	for _, a := range clusterSpecificAnnotations {
+		delete(annotations, a)
+	}
+	if len(annotations) == 0 {
+		annotations = nil
+	}
+	obj.SetAnnotations(annotations)
+}
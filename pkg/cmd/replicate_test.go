@@ -0,0 +1,55 @@
+package cmd
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// TestStripClusterFields ensures server-set fields are removed while the content is kept
+func TestStripClusterFields(t *testing.T) {
+	obj := &unstructured.Unstructured{}
+	err := obj.UnmarshalJSON([]byte(`{
+		"apiVersion": "v1",
+		"kind": "ConfigMap",
+		"metadata": {
+			"name": "myconfig",
+			"namespace": "default",
+			"uid": "0b5a0bd4-5b0b-4d89-9b34-1d6f5a1f1c37",
+			"resourceVersion": "12345",
+			"creationTimestamp": "2024-01-01T00:00:00Z",
+			"managedFields": [{"manager": "kubectl-client-side-apply", "operation": "Update"}],
+			"labels": {"app": "web"},
+			"annotations": {
+				"kubectl.kubernetes.io/last-applied-configuration": "{}",
+				"team": "payments"
+			}
+		},
+		"data": {"key": "value"}
+	}`))
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	stripClusterFields(obj)
+
+	if obj.GetUID() != "" || obj.GetResourceVersion() != "" || len(obj.GetManagedFields()) != 0 {
+		t.Errorf("expected uid, resourceVersion and managedFields to be removed, got %v", obj.Object["metadata"])
+	}
+	if _, found, _ := unstructured.NestedFieldNoCopy(obj.Object, "metadata", "creationTimestamp"); found {
+		t.Error("expected creationTimestamp to be removed")
+	}
+	if obj.GetName() != "myconfig" || obj.GetNamespace() != "default" || obj.GetLabels()["app"] != "web" {
+		t.Errorf("expected name, namespace and labels to be kept, got %v", obj.Object["metadata"])
+	}
+	annotations := obj.GetAnnotations()
+	if _, ok := annotations["kubectl.kubernetes.io/last-applied-configuration"]; ok {
+		t.Error("expected the last-applied-configuration annotation to be removed")
+	}
+	if annotations["team"] != "payments" {
+		t.Errorf("expected other annotations to be kept, got %v", annotations)
+	}
+	if data, _, _ := unstructured.NestedStringMap(obj.Object, "data"); data["key"] != "value" {
+		t.Errorf("expected data to be kept, got %v", data)
+	}
+}
@@ -0,0 +1,189 @@
+package cmd
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"kubectl-multi/pkg/cluster"
+
+	"github.com/spf13/cobra"
+)
+
+func newRestoreCommand() *cobra.Command {
+	var fromDir string
+	var toCluster string
+	var dryRun string
+
+	cmd := &cobra.Command{
+		Use:   "restore --from-dir DIR",
+		Short: "Re-apply resources backed up with delete --backup-dir",
+		Long: `Re-apply resources backed up with delete --backup-dir.
+The backup directory holds one directory per context, and the resources of each one are applied
+to the cluster of that context. With --to-cluster, the resources of every context are applied to
+a single cluster instead, e.g. to recover a fleet's workloads on a standby cluster.
+
+Files are applied one at a time and reported per cluster, cluster-scoped resources such as
+namespaces first.`,
+		Example: `# Undo a delete by re-applying every backup to the cluster it came from
+kubectl multi restore --from-dir ./backup
+
+# Recover the resources of all clusters on the dr cluster
+kubectl multi restore --from-dir ./backup --to-cluster dr`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if fromDir == "" {
+				return fmt.Errorf("--from-dir must name the backup directory")
+			}
+			kubeconfig, remoteCtx, _, _, _ := GetGlobalFlags()
+			return handleRestoreCommand(fromDir, toCluster, dryRun, kubeconfig, remoteCtx)
+		},
+	}
+
+	cmd.Flags().StringVar(&fromDir, "from-dir", "", "backup directory written by delete --backup-dir")
+	cmd.Flags().StringVar(&toCluster, "to-cluster", "", "context (or alias) of the cluster to apply all backups to, instead of the cluster each backup came from")
+	cmd.Flags().StringVar(&dryRun, "dry-run", "none", "must be \"none\", \"server\", or \"client\"")
+	_ = cmd.RegisterFlagCompletionFunc("to-cluster", completeClusterNames)
+
+	return cmd
+}
+
+func handleRestoreCommand(fromDir, toCluster, dryRun, kubeconfig, remoteCtx string) error {
+	backups, err := readBackupTree(fromDir)
+	if err != nil {
+		return err
+	}
+	if len(backups) == 0 {
+		return fmt.Errorf("no backups found in %s", fromDir)
+	}
+
+	clusters, _, err := discoverClusters(kubeconfig, remoteCtx)
+	if err != nil {
+		return fmt.Errorf("failed to discover clusters: %v", err)
+	}
+
+	plan, err := planRestore(backups, clusters, toCluster)
+	if err != nil {
+		return err
+	}
+	var targets []cluster.ClusterInfo
+	for _, c := range clusters {
+		if len(plan[c.Context]) > 0 {
+			targets = append(targets, c)
+		}
+	}
+	if len(targets) == 0 {
+		return fmt.Errorf("no cluster matches the backups in %s", fromDir)
+	}
+
+	_, err = fanOut(targets, kubeconfig, remoteCtx, fanOutOptions{}, func(ctx context.Context, c cluster.ClusterInfo, out io.Writer) error {
+		return restoreFiles(ctx, plan[c.Context], fromDir, c.Context, kubeconfig, dryRun, out)
+	})
+	return err
+}
+
+// readBackupTree returns the backup files of every context directory of dir, sorted so that the
+// cluster-scoped resources, kept under "_cluster", come before the namespaced ones
+func readBackupTree(dir string) (map[string][]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read backup directory: %v", err)
+	}
+
+	backups := make(map[string][]string)
+	for _, e := range entries {
+		if !e.IsDir() {
+			continue
+		}
+		var files []string
+		err := filepath.WalkDir(filepath.Join(dir, e.Name()), func(path string, d os.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			if !d.IsDir() && (strings.HasSuffix(path, ".yaml") || strings.HasSuffix(path, ".yml")) {
+				files = append(files, path)
+			}
+			return nil
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to read backup directory: %v", err)
+		}
+		if len(files) > 0 {
+			sort.Strings(files)
+			backups[e.Name()] = files
+		}
+	}
+	return backups, nil
+}
+
+// planRestore maps the backup files to the contexts they are applied to. Each backup directory goes
+// to the cluster of its context, or every directory to toCluster when it is set. Directories
+// without a matching cluster are reported and left out.
+func planRestore(backups map[string][]string, clusters []cluster.ClusterInfo, toCluster string) (map[string][]string, error) {
+	dirs := make([]string, 0, len(backups))
+	for dir := range backups {
+		dirs = append(dirs, dir)
+	}
+	sort.Strings(dirs)
+
+	plan := make(map[string][]string)
+	if toCluster != "" {
+		for _, c := range clusters {
+			if c.Context == toCluster || c.Display() == toCluster {
+				for _, dir := range dirs {
+					plan[c.Context] = append(plan[c.Context], backups[dir]...)
+				}
+				return plan, nil
+			}
+		}
+		return nil, fmt.Errorf("cluster %q not found", toCluster)
+	}
+
+	for _, dir := range dirs {
+		found := false
+		for _, c := range clusters {
+			if safePathComponent(c.Context) == dir {
+				plan[c.Context] = backups[dir]
+				found = true
+				break
+			}
+		}
+		if !found {
+			fmt.Printf("Warning: no cluster matches the backups of context %s, skipping them\n", dir)
+		}
+	}
+	return plan, nil
+}
+
+// restoreFiles applies the backup files to a cluster one at a time, reporting the result of each
+// file relative to the backup directory. It fails if any file could not be applied.
+func restoreFiles(ctx context.Context, files []string, fromDir, context, kubeconfig, dryRun string, out io.Writer) error {
+	failed := 0
+	for _, file := range files {
+		name, err := filepath.Rel(fromDir, file)
+		if err != nil {
+			name = file
+		}
+
+		args := []string{"apply", "-f", file, "--context", context}
+		if dryRun != "none" && dryRun != "" {
+			args = append(args, "--dry-run="+dryRun)
+		}
+		var result bytes.Buffer
+		if err := runKubectlTo(ctx, args, kubeconfig, &result); err != nil {
+			failed++
+			fmt.Fprintf(out, "%s: Error: %v\n", name, err)
+			continue
+		}
+		fmt.Fprintf(out, "%s: %s\n", name, strings.TrimSpace(result.String()))
+	}
+	if failed > 0 {
+		return fmt.Errorf("failed to restore %d of %d files", failed, len(files))
+	}
+	return nil
+}
@@ -0,0 +1,81 @@
+package cmd
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"kubectl-multi/pkg/cluster"
+)
+
+func writeBackupFiles(t *testing.T, dir string, files ...string) {
+	t.Helper()
+	for _, f := range files {
+		path := filepath.Join(dir, f)
+		if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte("kind: ConfigMap\n"), 0o600); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
+
+// TestReadBackupTree ensures backups are grouped per context with cluster-scoped resources first
+func TestReadBackupTree(t *testing.T) {
+	dir := t.TempDir()
+	writeBackupFiles(t, dir,
+		"cluster1/shop/configmap-settings.yaml",
+		"cluster1/_cluster/namespace-shop.yaml",
+		"cluster2/shop/secret-db.yaml",
+		"cluster2/notes.txt",
+	)
+
+	backups, err := readBackupTree(dir)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := map[string][]string{
+		"cluster1": {
+			filepath.Join(dir, "cluster1/_cluster/namespace-shop.yaml"),
+			filepath.Join(dir, "cluster1/shop/configmap-settings.yaml"),
+		},
+		"cluster2": {filepath.Join(dir, "cluster2/shop/secret-db.yaml")},
+	}
+	if !reflect.DeepEqual(backups, want) {
+		t.Errorf("expected %v, got %v", want, backups)
+	}
+}
+
+// TestPlanRestore ensures backups go back to their own cluster, or all to --to-cluster
+func TestPlanRestore(t *testing.T) {
+	backups := map[string][]string{
+		"cluster1": {"b/cluster1/a.yaml"},
+		"cluster2": {"b/cluster2/b.yaml"},
+		"gone":     {"b/gone/c.yaml"},
+	}
+	clusters := []cluster.ClusterInfo{{Context: "cluster1"}, {Context: "cluster2"}, {Context: "dr-ctx", DisplayName: "dr"}}
+
+	plan, err := planRestore(backups, clusters, "")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want := map[string][]string{"cluster1": {"b/cluster1/a.yaml"}, "cluster2": {"b/cluster2/b.yaml"}}
+	if !reflect.DeepEqual(plan, want) {
+		t.Errorf("expected %v, got %v", want, plan)
+	}
+
+	plan, err = planRestore(backups, clusters, "dr")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	want = map[string][]string{"dr-ctx": {"b/cluster1/a.yaml", "b/cluster2/b.yaml", "b/gone/c.yaml"}}
+	if !reflect.DeepEqual(plan, want) {
+		t.Errorf("expected %v, got %v", want, plan)
+	}
+
+	if _, err := planRestore(backups, clusters, "missing"); err == nil {
+		t.Error("expected an error for an unknown --to-cluster")
+	}
+}
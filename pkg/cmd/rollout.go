@@ -6,7 +6,6 @@ import (
 	"kubectl-multi/pkg/cluster"
 
 	"github.com/spf13/cobra"
-	"k8s.io/client-go/tools/clientcmd"
 )
 
 func newRolloutCommand() *cobra.Command {
@@ -96,7 +95,7 @@ func newRolloutUndoCommand() *cobra.Command {
 }
 
 func handleRolloutSubcommand(subcommand string, extraArgs []string, kubeconfig, remoteCtx string) error {
-	clusters, err := cluster.DiscoverClusters(kubeconfig, remoteCtx)
+	clusters, unreachable, err := discoverClusters(kubeconfig, remoteCtx)
 	if err != nil {
 		return fmt.Errorf("failed to discover clusters: %v", err)
 	}
@@ -104,72 +103,12 @@ func handleRolloutSubcommand(subcommand string, extraArgs []string, kubeconfig,
 		return fmt.Errorf("no clusters discovered")
 	}
 
-	// Find current context from kubeconfig
-	currentContext := ""
-	{
-		loading := clientcmd.NewDefaultClientConfigLoadingRules()
-		if kubeconfig != "" {
-			loading.ExplicitPath = kubeconfig
-		}
-		cfg := clientcmd.NewNonInteractiveDeferredLoadingClientConfig(loading, &clientcmd.ConfigOverrides{})
-		rawCfg, err := cfg.RawConfig()
-		if err == nil {
-			currentContext = rawCfg.CurrentContext
-		}
-	}
-
-	// Identify ITS (control) cluster context
-	itsContext := remoteCtx
-
-	// Build maps for quick lookup
-	contextToCluster := make(map[string]cluster.ClusterInfo)
-	for _, c := range clusters {
-		contextToCluster[c.Context] = c
-	}
-
-	// 1. Run for current context (if present)
-	if cinfo, ok := contextToCluster[currentContext]; ok {
-		args := []string{"rollout", subcommand}
-		if len(extraArgs) > 0 {
-			args = append(args, extraArgs...)
-		}
-		args = append(args, "--context", cinfo.Context)
-		cmdOutput, err := runKubectl(args, kubeconfig)
-		fmt.Printf("=== Cluster: %s ===\n", cinfo.Context)
-		if err != nil {
-			fmt.Printf("Error: %v\n", err)
-		} else {
-			fmt.Print(cmdOutput)
-		}
-		fmt.Println()
-	}
-
-	// 2. Run for KubeStellar clusters (excluding ITS and current)
-	for _, c := range clusters {
-		if c.Context == currentContext || c.Context == itsContext {
-			continue
-		}
+	_, err = fanOut(clusters, kubeconfig, remoteCtx, fanOutOptions{Unreachable: unreachable}, kubectlOp(kubeconfig, func(c cluster.ClusterInfo) []string {
 		args := []string{"rollout", subcommand}
 		if len(extraArgs) > 0 {
 			args = append(args, extraArgs...)
 		}
-		args = append(args, "--context", c.Context)
-		cmdOutput, err := runKubectl(args, kubeconfig)
-		fmt.Printf("=== Cluster: %s ===\n", c.Context)
-		if err != nil {
-			fmt.Printf("Error: %v\n", err)
-		} else {
-			fmt.Print(cmdOutput)
-		}
-		fmt.Println()
-	}
-
-	// 3. Print warning for ITS (control) cluster
-	if cinfo, ok := contextToCluster[itsContext]; ok {
-		fmt.Printf("=== Cluster: %s ===\n", cinfo.Context)
-		fmt.Printf("Cannot perform this operation on ITS (control) cluster: %s\n", cinfo.Context)
-		fmt.Println()
-	}
-
-	return nil
+		return append(args, "--context", c.Context)
+	}))
+	return err
 }
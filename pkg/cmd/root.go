@@ -4,17 +4,55 @@ import (
 	"fmt"
 	"kubectl-multi/pkg/util"
 	"os"
+	"strings"
+	"time"
 
 	"github.com/spf13/cobra"
 	"k8s.io/cli-runtime/pkg/genericclioptions" // Add this import
 )
 
 var (
-	kubeconfig    string
-	remoteCtx     string
-	allClusters   bool
-	namespace     string
-	allNamespaces bool
+	kubeconfig                string
+	remoteCtx                 string
+	allClusters               bool
+	namespace                 string
+	allNamespaces             bool
+	sortClustersBy            string
+	validateNamespace         bool
+	showProgress              bool
+	aliasesFile               string
+	selectedClusters          []string
+	parallelism               int
+	failFast                  bool
+	maxErrors                 int
+	kubectlPath               string
+	contextBinaryFlags        map[string]string
+	clusterTimeout            time.Duration
+	totalTimeout              time.Duration
+	timeoutOverridesFile      string
+	timeoutGrace              time.Duration
+	selectedGroups            []string
+	excludedClusters          []string
+	whereExpr                 string
+	bannerTemplateText        string
+	discoveryRetries          int
+	labelColumn               string
+	clustersFile              string
+	contextOrder              string
+	explainErrors             bool
+	allContexts               bool
+	reachabilityTTL           time.Duration
+	preHook                   string
+	postHook                  string
+	hookFailMode              string
+	displayLabel              string
+	stagger                   time.Duration
+	noITSWarning              bool
+	countOnly                 bool
+	recordEvent               bool
+	skipConfirmationForDryRun bool
+	clientQPS                 float32
+	clientBurst               int
 )
 
 // Custom help function for root command
@@ -85,7 +123,9 @@ const helpTemplate = `{{with (or .Long .Short)}}{{. | trimTrailingWhitespaces}}
 var rootCmd = &cobra.Command{
 	Use:     "kubectl-multi",
 	Version: util.Version,
-	Short:   "Multi-cluster kubectl operations for KubeStellar",
+	// Per-cluster failures are returned as errors, so don't print usage for them
+	SilenceUsage: true,
+	Short:        "Multi-cluster kubectl operations for KubeStellar",
 	Long: `kubectl-multi provides multi-cluster operations for KubeStellar managed clusters.
 It executes kubectl commands across all managed clusters and presents unified output.
 
@@ -119,6 +159,8 @@ kubectl multi install --its its1 --wds wds1`,
 
 // Execute adds all child commands to the root command and sets flags appropriately.
 func Execute() error {
+	util.InvocationName = util.InvocationNameFromBinary(os.Args[0])
+	applyInvocationName(rootCmd, util.InvocationName)
 	rootCmd.SetHelpTemplate(helpTemplate)
 
 	// Set custom help function for root command
@@ -134,6 +176,50 @@ func init() {
 	rootCmd.PersistentFlags().BoolVar(&allClusters, "all-clusters", true, "operate on all managed clusters")
 	rootCmd.PersistentFlags().StringVarP(&namespace, "namespace", "n", "", "target namespace")
 	rootCmd.PersistentFlags().BoolVarP(&allNamespaces, "all-namespaces", "A", false, "list resources across all namespaces")
+	rootCmd.PersistentFlags().StringVar(&sortClustersBy, "sort-clusters", "name", "order in which clusters are processed and printed: name|discovery")
+	rootCmd.PersistentFlags().BoolVar(&validateNamespace, "validate-namespace", false, "skip clusters where the target namespace does not exist instead of failing on them")
+	rootCmd.PersistentFlags().BoolVar(&showProgress, "progress", false, "show a progress indicator on stderr while clusters are processed (only when stdout and stderr are terminals)")
+	rootCmd.PersistentFlags().StringVar(&labelColumn, "label-column", "", "add a column with the value of this cluster label next to the CLUSTER column of tables")
+	rootCmd.PersistentFlags().StringVar(&aliasesFile, "aliases", "", "YAML file mapping context names to friendly names shown in output (realContext: friendlyName)")
+	rootCmd.PersistentFlags().StringVar(&displayLabel, "display-label", "", "show and select clusters by the value of this cluster label (e.g. region) instead of their context; clusters without it keep their context")
+	rootCmd.PersistentFlags().StringSliceVar(&selectedClusters, "clusters", nil, "comma-separated clusters (contexts or aliases) to operate on instead of all managed clusters")
+	rootCmd.PersistentFlags().StringSliceVar(&selectedGroups, "group", nil, "comma-separated cluster groups from ~/.config/kubectl-multi/groups.yaml to operate on")
+	rootCmd.PersistentFlags().StringSliceVar(&excludedClusters, "exclude-clusters", nil, "comma-separated clusters (contexts or aliases) to leave out")
+	rootCmd.PersistentFlags().StringVar(&whereExpr, "where", "", "only operate on the clusters matching this expression of their name, context, role and labels, e.g. 'name =~ prod-.* && region == us-east'")
+	rootCmd.PersistentFlags().StringVar(&clustersFile, "from-file", "", "use the clusters listed in a file written by 'clusters dump' instead of discovering them")
+	rootCmd.PersistentFlags().BoolVar(&allContexts, "all-contexts", false, "operate on every context of the kubeconfig instead of the KubeStellar managed clusters, including WDS and ITS contexts")
+	rootCmd.PersistentFlags().IntVar(&discoveryRetries, "discovery-retries", 0, "check that each managed cluster is reachable during discovery, retrying this many times with backoff (0 disables the check)")
+	rootCmd.PersistentFlags().DurationVar(&reachabilityTTL, "reachability-ttl", 30*time.Second, "remember clusters found unreachable for this long, so commands run shortly after mark them unreachable without probing them again (0 disables the cache)")
+	rootCmd.PersistentFlags().StringVar(&contextOrder, "context-order", contextOrderCurrentFirst, "order in which commands run on the clusters: current-first (the current context, then --sort-clusters order), name or kubeconfig (order of the contexts in the kubeconfig file)")
+	rootCmd.PersistentFlags().IntVar(&parallelism, "parallel", 1, "number of clusters to operate on concurrently; output is buffered per cluster when greater than 1")
+	rootCmd.PersistentFlags().Float32Var(&clientQPS, "qps", 0, "maximum requests per second the plugin's own API clients send to each cluster, e.g. for discovery and --server-print (0 keeps the default of 5; kubectl calls are limited with --parallel instead)")
+	rootCmd.PersistentFlags().IntVar(&clientBurst, "burst", 0, "requests above --qps the plugin's own API clients may send to each cluster in short bursts (0 keeps the default of 10)")
+	rootCmd.PersistentFlags().BoolVar(&failFast, "fail-fast", false, "stop processing the remaining clusters after the first cluster fails")
+	rootCmd.PersistentFlags().DurationVar(&stagger, "stagger", 0, "wait this long between clusters so a bad change can be caught before it reaches them all, combine with --fail-fast for a canary rollout (sequential mode only)")
+	rootCmd.PersistentFlags().IntVar(&maxErrors, "max-errors", 0, "stop processing the remaining clusters once this many clusters have failed (0 means unlimited)")
+	rootCmd.PersistentFlags().DurationVar(&clusterTimeout, "timeout", 0, "maximum time to spend on each cluster, e.g. 30s or 5m (0 means no timeout)")
+	rootCmd.PersistentFlags().StringVar(&timeoutOverridesFile, "timeout-override", "", "YAML file mapping contexts (or aliases) to their own per-cluster timeout instead of --timeout, e.g. 'slow-edge: 5m'")
+	rootCmd.PersistentFlags().DurationVar(&totalTimeout, "timeout-total", 0, "maximum time for the whole operation across all clusters; running clusters are stopped and the rest skipped (0 means no timeout)")
+	rootCmd.PersistentFlags().DurationVar(&timeoutGrace, "timeout-grace", 0, "when a cluster times out, send kubectl SIGTERM and wait this long for it to exit cleanly before killing it (0 kills it at once; not supported on Windows)")
+	rootCmd.PersistentFlags().StringVar(&preHook, "pre-hook", "", "shell command run before the operation on each cluster, with $"+hookContextEnv+" and $"+hookClusterEnv+" set")
+	rootCmd.PersistentFlags().StringVar(&postHook, "post-hook", "", "shell command run after the operation on each cluster, with $"+hookContextEnv+", $"+hookClusterEnv+" and $"+hookErrorEnv+" (empty on success) set")
+	rootCmd.PersistentFlags().StringVar(&hookFailMode, "hook-fail-mode", hookFailAbort, "what a failed hook does: abort (fail the cluster, skipping its operation after a failed pre-hook) or continue (only warn)")
+	rootCmd.PersistentFlags().StringVar(&bannerTemplateText, "banner-template", defaultBannerTemplate, "Go template of the banner printed above the output of each cluster, with the fields .Context, .ClusterName, .Role, .Index and .Total and the functions upper and lower")
+	rootCmd.PersistentFlags().BoolVar(&noITSWarning, "no-its-warning", false, "do not print the notice that the operation is not performed on the ITS (control) cluster, which is still skipped")
+	rootCmd.PersistentFlags().BoolVar(&countOnly, "count-only", false, "print only how many clusters succeeded, failed, timed out and were skipped and how long it took, instead of the output of every cluster; the exit code is still non-zero when a cluster failed")
+	rootCmd.PersistentFlags().BoolVar(&recordEvent, "record-event", false, "create an Event recording the command, the local user and the time in the target namespace (default for cluster-wide commands) of every cluster where the operation succeeded")
+	rootCmd.PersistentFlags().BoolVar(&skipConfirmationForDryRun, "skip-confirmation-for-dry-run", true, "never ask for confirmation when a command runs with --dry-run=client or --dry-run=server, as nothing changes; pass false to be prompted anyway")
+	rootCmd.PersistentFlags().BoolVar(&explainErrors, "explain-errors", false, "print a remediation hint after each cluster failure (RBAC, connectivity, timeouts, missing resources)")
+	rootCmd.PersistentFlags().StringVar(&kubectlPath, "kubectl-path", "kubectl", "kubectl binary used to run commands on the clusters")
+	rootCmd.PersistentFlags().StringToStringVar(&contextBinaryFlags, "context-binary", nil, "binary to use for specific contexts, e.g. prod-ocp=oc (OpenShift clusters default to oc when it is installed)")
+
+	// Defaults of the global flags come from the config file, below the flags given on the command line
+	rootCmd.PersistentPreRunE = loadConfigDefaults
+
+	// Complete cluster and group names for the flags that select clusters
+	_ = rootCmd.RegisterFlagCompletionFunc("clusters", completeClusterNames)
+	_ = rootCmd.RegisterFlagCompletionFunc("exclude-clusters", completeClusterNames)
+	_ = rootCmd.RegisterFlagCompletionFunc("group", completeGroupNames)
 
 	// Add subcommands
 	rootCmd.AddCommand(newGetCommand())
@@ -150,7 +236,25 @@ func init() {
 	rootCmd.AddCommand(newPortForwardCommand())
 	rootCmd.AddCommand(newTopCommand())
 	rootCmd.AddCommand(newRunCommand())
+	rootCmd.AddCommand(newRunRawCommand())
+	rootCmd.AddCommand(newReplicateCommand())
+	rootCmd.AddCommand(newCompareCommand())
+	rootCmd.AddCommand(newReconcileCommand())
+	rootCmd.AddCommand(newRestoreCommand())
+	rootCmd.AddCommand(newGraphCommand())
+	rootCmd.AddCommand(newWatchRestartsCommand())
 	rootCmd.AddCommand(newMultiGetCommand()) // Register multiget
+	rootCmd.AddCommand(newCleanupCommand())
+	rootCmd.AddCommand(newClustersCommand())
+	rootCmd.AddCommand(newDiscoverCommand())
+	rootCmd.AddCommand(newAPIResourcesCommand())
+	rootCmd.AddCommand(newGroupsCommand())
+	rootCmd.AddCommand(newITSCommand())
+	rootCmd.AddCommand(newWDSCommand())
+	rootCmd.AddCommand(newConfigCommand())
+	rootCmd.AddCommand(newUsageCommand())
+	rootCmd.AddCommand(newQuotaCommand())
+	rootCmd.AddCommand(newCompletionCommand())
 	rootCmd.AddCommand(util.VersionCmd)
 
 	// Add the install command - NEW LINE
@@ -162,7 +266,27 @@ func init() {
 	rootCmd.AddCommand(NewInstallCmd(streams))
 }
 
-// GetGlobalFlags returns the global flags that can be used by subcommands
+// applyInvocationName renders the usage line, descriptions and examples of cmd and its subcommands
+// with the name the plugin is run with, so a renamed binary shows examples that work
+func applyInvocationName(cmd *cobra.Command, name string) {
+	if cmd.Annotations == nil {
+		cmd.Annotations = make(map[string]string)
+	}
+	cmd.Annotations[cobra.CommandDisplayNameAnnotation] = name
+
+	var render func(c *cobra.Command)
+	render = func(c *cobra.Command) {
+		c.Long = strings.ReplaceAll(c.Long, util.DefaultInvocationName, name)
+		c.Example = strings.ReplaceAll(c.Example, util.DefaultInvocationName, name)
+		for _, sub := range c.Commands() {
+			render(sub)
+		}
+	}
+	render(cmd)
+}
+
+// GetGlobalFlags returns the global flags that can be used by subcommands. Flags not given on the
+// command line hold their value from the config file, applied by loadConfigDefaults before the command runs.
 func GetGlobalFlags() (string, string, bool, string, bool) {
 	return kubeconfig, remoteCtx, allClusters, namespace, allNamespaces
 }
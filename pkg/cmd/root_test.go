@@ -4,6 +4,10 @@ import (
 	"bytes"
 	"strings"
 	"testing"
+
+	"kubectl-multi/pkg/util"
+
+	"github.com/spf13/cobra"
 )
 
 // TestRootFlags ensures all expected global flags are registered
@@ -89,3 +93,40 @@ func TestRootExecuteInvalidSubcommand(t *testing.T) {
 		t.Errorf("unexpected error message: %v", err)
 	}
 }
+
+// TestInvocationNameFromBinary ensures the binary name is mapped to the command users type
+func TestInvocationNameFromBinary(t *testing.T) {
+	tests := map[string]string{
+		"/usr/local/bin/kubectl-multi": "kubectl multi",
+		"kubectl-fleet.exe":            "kubectl fleet",
+		"kubectl-fleet_ops":            "kubectl fleet-ops",
+		"kubectl-kube-multi":           "kubectl kube multi",
+		"/opt/bin/kmulti":              "kmulti",
+	}
+	for arg0, want := range tests {
+		if got := util.InvocationNameFromBinary(arg0); got != want {
+			t.Errorf("%s: expected %q, got %q", arg0, want, got)
+		}
+	}
+}
+
+// TestApplyInvocationName ensures usage lines and examples use a custom invocation name
+func TestApplyInvocationName(t *testing.T) {
+	root := &cobra.Command{Use: "kubectl-multi"}
+	get := &cobra.Command{
+		Use:     "get TYPE",
+		Long:    "Run kubectl multi get to list resources.",
+		Example: "kubectl multi get pods",
+		Run:     func(cmd *cobra.Command, args []string) {},
+	}
+	root.AddCommand(get)
+
+	applyInvocationName(root, "kubectl fleet")
+
+	if got := get.UseLine(); got != "kubectl fleet get TYPE" {
+		t.Errorf("unexpected usage line %q", got)
+	}
+	if get.Example != "kubectl fleet get pods" || !strings.Contains(get.Long, "kubectl fleet get") {
+		t.Errorf("expected the examples to use the custom name, got %q / %q", get.Example, get.Long)
+	}
+}
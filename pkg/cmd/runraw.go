@@ -0,0 +1,148 @@
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"kubectl-multi/pkg/cluster"
+
+	"github.com/spf13/cobra"
+)
+
+// destructiveSubcommands are kubectl subcommands that run-raw only runs after confirmation
+var destructiveSubcommands = map[string]bool{
+	"delete":   true,
+	"drain":    true,
+	"replace":  true,
+	"cordon":   true,
+	"uncordon": true,
+	"taint":    true,
+	"scale":    true,
+	"patch":    true,
+}
+
+// kubectlValueFlags are the kubectl flags usually given before the subcommand whose value is a separate argument
+var kubectlValueFlags = map[string]bool{
+	"-n": true, "--namespace": true, "--context": true, "--cluster": true, "--user": true, "--kubeconfig": true,
+	"-s": true, "--server": true, "--token": true, "--as": true, "--as-group": true, "--as-uid": true,
+	"--request-timeout": true, "--cache-dir": true, "--certificate-authority": true, "--client-certificate": true,
+	"--client-key": true, "--tls-server-name": true, "-v": true, "--v": true,
+	"-l": true, "--selector": true, "-f": true, "--filename": true, "-o": true, "--output": true,
+}
+
+// kubectlWords returns the arguments of a kubectl command line that are neither flags nor flag values,
+// the subcommand first, up to a -- separating the arguments of the command run in a container
+func kubectlWords(args []string) []string {
+	var words []string
+	for i := 0; i < len(args); i++ {
+		arg := args[i]
+		if arg == "--" {
+			break
+		}
+		if strings.HasPrefix(arg, "-") {
+			if kubectlValueFlags[arg] {
+				i++
+			}
+			continue
+		}
+		words = append(words, arg)
+	}
+	return words
+}
+
+// destructiveCommand returns the destructive kubectl command that the arguments of run-raw run, such as
+// "delete" or "rollout restart", or "" when they are safe to run without confirmation. annotate and
+// label are only destructive with --overwrite, which replaces existing values.
+func destructiveCommand(args []string) string {
+	words := kubectlWords(args)
+	if len(words) == 0 {
+		return ""
+	}
+	switch verb := words[0]; {
+	case destructiveSubcommands[verb]:
+		return verb
+	case verb == "rollout" && len(words) > 1 && (words[1] == "restart" || words[1] == "undo"):
+		return verb + " " + words[1]
+	case verb == "annotate" || verb == "label":
+		for _, arg := range args {
+			if arg == "--" {
+				break
+			}
+			if arg == "--overwrite" || arg == "--overwrite=true" {
+				return verb + " --overwrite"
+			}
+		}
+	}
+	return ""
+}
+
+// runRawArgs returns the kubectl arguments of run-raw for a cluster, with --context inserted before a
+// -- so that it is not passed to the command run in a container
+func runRawArgs(kubectlArgs []string, context string) []string {
+	args := make([]string, 0, len(kubectlArgs)+2)
+	for i, arg := range kubectlArgs {
+		if arg == "--" {
+			args = append(args, "--context", context)
+			return append(args, kubectlArgs[i:]...)
+		}
+		args = append(args, arg)
+	}
+	return append(args, "--context", context)
+}
+
+func newRunRawCommand() *cobra.Command {
+	var yes bool
+
+	cmd := &cobra.Command{
+		Use:   "run-raw [-y] -- KUBECTL_ARGS...",
+		Short: "Run arbitrary kubectl arguments across all managed clusters",
+		Long: `Run arbitrary kubectl arguments across all managed clusters.
+The arguments are passed to kubectl unchanged with --context appended for each cluster.
+This provides multi-cluster support for kubectl subcommands without a dedicated wrapper.`,
+		Example: `# Pause a rollout in all managed clusters
+kubectl multi run-raw -- rollout pause deployment/nginx
+
+# Approve a certificate signing request in all managed clusters
+kubectl multi run-raw -- certificate approve my-csr
+
+# Drain a node without being prompted
+kubectl multi run-raw -y -- drain node1 --ignore-daemonsets`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if len(args) == 0 {
+				return fmt.Errorf("kubectl arguments must be specified after --")
+			}
+			kubeconfig, remoteCtx, _, _, _ := GetGlobalFlags()
+			return handleRunRawCommand(args, assumeYes(cmd, yes), kubeconfig, remoteCtx)
+		},
+	}
+
+	cmd.Flags().BoolVarP(&yes, "yes", "y", false, "run destructive commands without prompting for confirmation (default from $"+assumeYesEnv+")")
+
+	return cmd
+}
+
+func handleRunRawCommand(kubectlArgs []string, assumeYes bool, kubeconfig, remoteCtx string) error {
+	clusters, unreachable, err := discoverClusters(kubeconfig, remoteCtx)
+	if err != nil {
+		return fmt.Errorf("failed to discover clusters: %v", err)
+	}
+	if len(clusters) == 0 {
+		return fmt.Errorf("no clusters discovered")
+	}
+
+	if command := destructiveCommand(kubectlArgs); command != "" {
+		ok, err := confirmAction(fmt.Sprintf("Are you sure you want to run 'kubectl %s' on all managed clusters ?", command), assumeYes, kubectlArgsDryRun(kubectlArgs))
+		if err != nil {
+			return err
+		}
+		if !ok {
+			fmt.Println("Operation cancelled...")
+			return nil
+		}
+	}
+
+	_, err = fanOut(clusters, kubeconfig, remoteCtx, fanOutOptions{Unreachable: unreachable}, kubectlOp(kubeconfig, func(c cluster.ClusterInfo) []string {
+		return runRawArgs(kubectlArgs, c.Context)
+	}))
+	return err
+}
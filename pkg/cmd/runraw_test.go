@@ -0,0 +1,70 @@
+package cmd
+
+import (
+	"strings"
+	"testing"
+
+	"kubectl-multi/pkg/cluster"
+)
+
+// TestDestructiveCommand ensures the subcommand is found after the flags given before it, and that the
+// subcommands changing the clusters require confirmation
+func TestDestructiveCommand(t *testing.T) {
+	for _, tc := range []struct {
+		args string
+		want string
+	}{
+		{"delete pod web", "delete"},
+		{"-n prod delete pod web", "delete"},
+		{"--context=wds1 delete pod web", "delete"},
+		{"--namespace prod --request-timeout 5s drain node1", "drain"},
+		{"-v 6 uncordon node1", "uncordon"},
+		{"scale deployment/web --replicas 0", "scale"},
+		{"-n prod patch deployment web -p {}", "patch"},
+		{"rollout restart deployment/web", "rollout restart"},
+		{"-n prod rollout undo deployment/web", "rollout undo"},
+		{"annotate pod web team=a --overwrite", "annotate --overwrite"},
+		{"label --overwrite=true node node1 zone=b", "label --overwrite"},
+		{"rollout pause deployment/web", ""},
+		{"rollout status deployment/web", ""},
+		{"annotate pod web team=a", ""},
+		{"-n delete get pods", ""},
+		{"exec web -- rm -rf /tmp/x --overwrite", ""},
+		{"get pods", ""},
+		{"--context wds1", ""},
+	} {
+		if got := destructiveCommand(strings.Fields(tc.args)); got != tc.want {
+			t.Errorf("%q: expected %q, got %q", tc.args, tc.want, got)
+		}
+	}
+}
+
+// TestRunRawFanOut ensures run-raw runs the arguments in every cluster with the cluster's context, which
+// is not passed to the command run by exec
+func TestRunRawFanOut(t *testing.T) {
+	installFakeKubectl(t, "#!/bin/sh\necho \"$*\"\n")
+
+	clusters := []cluster.ClusterInfo{{Context: "wds1"}, {Context: "wds2"}}
+	for _, tc := range []struct {
+		args string
+		want string
+	}{
+		{"-n prod get pods", "-n prod get pods --context %s"},
+		{"exec web -- ls -l", "exec web --context %s -- ls -l"},
+	} {
+		results, err := fanOut(clusters, "", "", fanOutOptions{Stream: func(r clusterResult) {}}, kubectlOp("", func(c cluster.ClusterInfo) []string {
+			return runRawArgs(strings.Fields(tc.args), c.Context)
+		}))
+		if err != nil {
+			t.Fatalf("%q: unexpected error: %v", tc.args, err)
+		}
+		if len(results) != len(clusters) {
+			t.Fatalf("%q: expected a result per cluster, got %+v", tc.args, results)
+		}
+		for _, r := range results {
+			if want := strings.Replace(tc.want, "%s", r.Context, 1); strings.TrimSpace(r.Output) != want {
+				t.Errorf("%q: expected %q in %s, got %q", tc.args, want, r.Context, r.Output)
+			}
+		}
+	}
+}
@@ -0,0 +1,159 @@
+package cmd
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"kubectl-multi/pkg/cluster"
+	"kubectl-multi/pkg/util"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// serverTableAccept asks the API server to render the response as a Table
+const serverTableAccept = "application/json;as=Table;v=v1;g=meta.k8s.io,application/json"
+
+// serverTable holds the Table rendered by the API server of a single cluster
+type serverTable struct {
+	Cluster string
+	Table   metav1.Table
+}
+
+// serverTablePath returns the API path listing a resource, or getting it by name
+func serverTablePath(gvr schema.GroupVersionResource, namespaced bool, namespace, name string) string {
+	path := "/apis/" + gvr.Group + "/" + gvr.Version
+	if gvr.Group == "" {
+		path = "/api/" + gvr.Version
+	}
+	if namespaced && namespace != "" {
+		path += "/namespaces/" + namespace
+	}
+	path += "/" + gvr.Resource
+	if name != "" {
+		path += "/" + name
+	}
+	return path
+}
+
+// fetchServerTables requests the server-side Table of the resource from every cluster, which holds
+// the same columns as `kubectl get`, including the printer columns of CRDs
+func fetchServerTables(clusters []cluster.ClusterInfo, resourceType, resourceName, selector, namespace string, allNamespaces bool) []serverTable {
+	var tables []serverTable
+	for _, c := range clusters {
+		if c.Client == nil || c.DiscoveryClient == nil {
+			continue
+		}
+		gvr, namespaced, err := util.DiscoverGVR(c.DiscoveryClient, resourceType)
+		if err != nil {
+			fmt.Printf("Warning: failed to discover resource %s in cluster %s: %v\n", resourceType, c.Display(), err)
+			continue
+		}
+
+		ns := cluster.TargetNamespace(c, namespace)
+		if allNamespaces && resourceName == "" {
+			ns = ""
+		}
+		req := c.Client.CoreV1().RESTClient().Get().
+			AbsPath(serverTablePath(gvr, namespaced, ns, resourceName)).
+			SetHeader("Accept", serverTableAccept).
+			Param("includeObject", "Metadata")
+		if selector != "" {
+			req = req.Param("labelSelector", selector)
+		}
+		raw, err := req.Do(context.TODO()).Raw()
+		if err != nil {
+			fmt.Printf("Warning: failed to get %s in cluster %s: %v\n", resourceType, c.Display(), err)
+			continue
+		}
+
+		var table metav1.Table
+		if err := json.Unmarshal(raw, &table); err != nil || table.Kind != "Table" {
+			fmt.Printf("Warning: cluster %s did not return a server-side table for %s\n", c.Display(), resourceType)
+			continue
+		}
+		tables = append(tables, serverTable{Cluster: c.Display(), Table: table})
+	}
+	return tables
+}
+
+// mergeServerTables prints the server-side tables of all clusters as one table with a leading CLUSTER
+// column, and a NAMESPACE column with allNamespaces. The columns are those of the first table with rows,
+// matched by name in the other clusters; columns with a priority above 0 are only shown with wide.
+func mergeServerTables(tw tableWriter, tables []serverTable, allNamespaces, wide, noHeaders bool) {
+	defer tw.Flush()
+
+	var columns []metav1.TableColumnDefinition
+	for _, t := range tables {
+		if len(t.Table.Rows) == 0 {
+			continue
+		}
+		for _, col := range t.Table.ColumnDefinitions {
+			if wide || col.Priority == 0 {
+				columns = append(columns, col)
+			}
+		}
+		break
+	}
+	if columns == nil {
+		if !noHeaders {
+			fmt.Fprintf(tw, "No resource found.\n")
+		}
+		return
+	}
+
+	if !noHeaders {
+		header := []string{"CLUSTER"}
+		if allNamespaces {
+			header = append(header, "NAMESPACE")
+		}
+		for _, col := range columns {
+			header = append(header, strings.ToUpper(col.Name))
+		}
+		fmt.Fprintln(tw, strings.Join(header, "\t"))
+	}
+
+	for _, t := range tables {
+		index := make(map[string]int)
+		for i, col := range t.Table.ColumnDefinitions {
+			index[col.Name] = i
+		}
+		for _, row := range t.Table.Rows {
+			cells := []string{t.Cluster}
+			if allNamespaces {
+				cells = append(cells, rowNamespace(row))
+			}
+			for _, col := range columns {
+				i, ok := index[col.Name]
+				if !ok || i >= len(row.Cells) {
+					cells = append(cells, "<unknown>")
+					continue
+				}
+				cells = append(cells, formatTableCell(row.Cells[i]))
+			}
+			fmt.Fprintln(tw, strings.Join(cells, "\t"))
+		}
+	}
+}
+
+// rowNamespace returns the namespace of the object of a table row, included as metadata
+func rowNamespace(row metav1.TableRow) string {
+	var meta metav1.PartialObjectMetadata
+	if len(row.Object.Raw) == 0 || json.Unmarshal(row.Object.Raw, &meta) != nil || meta.Namespace == "" {
+		return "<none>"
+	}
+	return meta.Namespace
+}
+
+// formatTableCell prints a table cell the way kubectl does, "<none>" for missing values
+func formatTableCell(cell interface{}) string {
+	if cell == nil {
+		return "<none>"
+	}
+	if s, ok := cell.(string); ok && s == "" {
+		return "<none>"
+	}
+	return fmt.Sprint(cell)
+}
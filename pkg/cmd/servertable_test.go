@@ -0,0 +1,87 @@
+package cmd
+
+import (
+	"bytes"
+	"testing"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+)
+
+// TestServerTablePath ensures core and grouped resources, namespaces and names are mapped to API paths
+func TestServerTablePath(t *testing.T) {
+	pods := schema.GroupVersionResource{Version: "v1", Resource: "pods"}
+	certs := schema.GroupVersionResource{Group: "cert-manager.io", Version: "v1", Resource: "certificates"}
+	nodes := schema.GroupVersionResource{Version: "v1", Resource: "nodes"}
+
+	tests := []struct {
+		got, want string
+	}{
+		{serverTablePath(pods, true, "default", ""), "/api/v1/namespaces/default/pods"},
+		{serverTablePath(pods, true, "", ""), "/api/v1/pods"},
+		{serverTablePath(certs, true, "web", "tls"), "/apis/cert-manager.io/v1/namespaces/web/certificates/tls"},
+		{serverTablePath(nodes, false, "default", "node-1"), "/api/v1/nodes/node-1"},
+	}
+	for _, tt := range tests {
+		if tt.got != tt.want {
+			t.Errorf("expected %s, got %s", tt.want, tt.got)
+		}
+	}
+}
+
+func certificateTable(namespace string, rows ...[]interface{}) metav1.Table {
+	table := metav1.Table{
+		TypeMeta: metav1.TypeMeta{Kind: "Table"},
+		ColumnDefinitions: []metav1.TableColumnDefinition{
+			{Name: "Name", Priority: 0},
+			{Name: "Ready", Priority: 0},
+			{Name: "Secret", Priority: 0},
+			{Name: "Issuer", Priority: 1},
+		},
+	}
+	for _, cells := range rows {
+		table.Rows = append(table.Rows, metav1.TableRow{
+			Cells:  cells,
+			Object: runtime.RawExtension{Raw: []byte(`{"metadata":{"namespace":"` + namespace + `"}}`)},
+		})
+	}
+	return table
+}
+
+// TestMergeServerTables ensures the CRD columns are kept, priority columns need wide, and namespaces
+// are read from the row metadata
+func TestMergeServerTables(t *testing.T) {
+	tables := []serverTable{
+		{Cluster: "cluster1", Table: certificateTable("web", []interface{}{"tls", "True", "tls-secret", "letsencrypt"})},
+		{Cluster: "cluster2", Table: certificateTable("api", []interface{}{"api-tls", "False", nil, "letsencrypt"})},
+	}
+
+	var buf bytes.Buffer
+	mergeServerTables(newTableWriter(&buf, nil), tables, true, false, false)
+	want := `CLUSTER   NAMESPACE  NAME     READY  SECRET
+cluster1  web        tls      True   tls-secret
+cluster2  api        api-tls  False  <none>
+`
+	if buf.String() != want {
+		t.Errorf("expected:\n%s\ngot:\n%s", want, buf.String())
+	}
+
+	buf.Reset()
+	mergeServerTables(newTableWriter(&buf, nil), tables, false, true, true)
+	want = `cluster1  tls      True   tls-secret  letsencrypt
+cluster2  api-tls  False  <none>      letsencrypt
+`
+	if buf.String() != want {
+		t.Errorf("expected wide output without headers:\n%s\ngot:\n%s", want, buf.String())
+	}
+}
+
+// TestMergeServerTablesEmpty ensures an empty result is reported once
+func TestMergeServerTablesEmpty(t *testing.T) {
+	var buf bytes.Buffer
+	mergeServerTables(newTableWriter(&buf, nil), []serverTable{{Cluster: "cluster1", Table: certificateTable("web")}}, false, false, false)
+	if buf.String() != "No resource found.\n" {
+		t.Errorf("unexpected output %q", buf.String())
+	}
+}
@@ -0,0 +1,150 @@
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/client-go/util/jsonpath"
+)
+
+// parseSortBy compiles a --sort-by JSONPath expression
+func parseSortBy(expr string) (*jsonpath.JSONPath, error) {
+	jp, err := parseFieldPath(expr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid --sort-by: %v", err)
+	}
+	return jp, nil
+}
+
+// parseFieldPath compiles the JSONPath of a field. Like kubectl, the braces and the leading dot are
+// optional, e.g. metadata.creationTimestamp or {.status.restartCount}. Missing fields are not errors.
+func parseFieldPath(expr string) (*jsonpath.JSONPath, error) {
+	if !strings.HasPrefix(expr, "{") {
+		if !strings.HasPrefix(expr, ".") {
+			expr = "." + expr
+		}
+		expr = "{" + expr + "}"
+	}
+	jp := jsonpath.New("field").AllowMissingKeys(true)
+	if err := jp.Parse(expr); err != nil {
+		return nil, err
+	}
+	return jp, nil
+}
+
+// sortValue returns the first value of the field in obj, or nil when the object does not have it
+func sortValue(jp *jsonpath.JSONPath, obj map[string]interface{}) interface{} {
+	results, err := jp.FindResults(obj)
+	if err != nil || len(results) == 0 || len(results[0]) == 0 {
+		return nil
+	}
+	v := results[0][0]
+	if !v.IsValid() || !v.CanInterface() {
+		return nil
+	}
+	return v.Interface()
+}
+
+// sortObjects orders the objects of all clusters together by the field. Objects without the field go
+// last, and objects with equal values keep their cluster order.
+func sortObjects(objects []clusterObject, jp *jsonpath.JSONPath) {
+	keys := make([]interface{}, len(objects))
+	order := make([]int, len(objects))
+	for i, o := range objects {
+		keys[i] = sortValue(jp, o.Object.Object)
+		order[i] = i
+	}
+	sort.SliceStable(order, func(i, j int) bool {
+		return compareSortValues(keys[order[i]], keys[order[j]]) < 0
+	})
+
+	sorted := make([]clusterObject, len(objects))
+	for i, j := range order {
+		sorted[i] = objects[j]
+	}
+	copy(objects, sorted)
+}
+
+// compareSortValues compares two field values: numbers numerically, quantities such as "500m" or
+// "1Gi" by their amount and other strings lexically, which also orders timestamps. Values of different
+// types are grouped by type, and missing values go after all others.
+func compareSortValues(a, b interface{}) int {
+	switch {
+	case a == nil && b == nil:
+		return 0
+	case a == nil:
+		return 1
+	case b == nil:
+		return -1
+	}
+
+	if x, ok := sortNumber(a); ok {
+		if y, ok := sortNumber(b); ok {
+			switch {
+			case x < y:
+				return -1
+			case x > y:
+				return 1
+			}
+			return 0
+		}
+	}
+	if x, ok := a.(string); ok {
+		if y, ok := b.(string); ok {
+			qx, errx := resource.ParseQuantity(x)
+			qy, erry := resource.ParseQuantity(y)
+			if errx == nil && erry == nil {
+				return qx.Cmp(qy)
+			}
+			return strings.Compare(x, y)
+		}
+	}
+	if x, ok := a.(bool); ok {
+		if y, ok := b.(bool); ok {
+			switch {
+			case x == y:
+				return 0
+			case !x:
+				return -1
+			}
+			return 1
+		}
+	}
+
+	if ra, rb := sortTypeRank(a), sortTypeRank(b); ra != rb {
+		if ra < rb {
+			return -1
+		}
+		return 1
+	}
+	return strings.Compare(fmt.Sprint(a), fmt.Sprint(b))
+}
+
+// sortNumber returns the value of a JSON number
+func sortNumber(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case int64:
+		return float64(n), true
+	case float64:
+		return n, true
+	case int:
+		return float64(n), true
+	}
+	return 0, false
+}
+
+// sortTypeRank orders values of different types: booleans, numbers, strings, then lists and objects
+func sortTypeRank(v interface{}) int {
+	if _, ok := sortNumber(v); ok {
+		return 1
+	}
+	switch v.(type) {
+	case bool:
+		return 0
+	case string:
+		return 2
+	}
+	return 3
+}
@@ -0,0 +1,82 @@
+package cmd
+
+import (
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// TestParseSortBy ensures the braces and leading dot are optional and broken expressions fail
+func TestParseSortBy(t *testing.T) {
+	obj := map[string]interface{}{"metadata": map[string]interface{}{"name": "web"}}
+	for _, expr := range []string{"metadata.name", ".metadata.name", "{.metadata.name}"} {
+		jp, err := parseSortBy(expr)
+		if err != nil {
+			t.Fatalf("unexpected error for %q: %v", expr, err)
+		}
+		if v := sortValue(jp, obj); v != "web" {
+			t.Errorf("expected web for %q, got %v", expr, v)
+		}
+	}
+	if _, err := parseSortBy("{.metadata.name"); err == nil {
+		t.Error("expected an error for an unterminated expression")
+	}
+}
+
+// TestSortObjects ensures the objects of all clusters are sorted together, numerically for numbers
+// and by amount for quantities, with missing fields last and ties in cluster order
+func TestSortObjects(t *testing.T) {
+	object := func(cluster, name string, value interface{}) clusterObject {
+		status := map[string]interface{}{}
+		if value != nil {
+			status["value"] = value
+		}
+		return clusterObject{Cluster: cluster, Object: &unstructured.Unstructured{Object: map[string]interface{}{
+			"metadata": map[string]interface{}{"name": name},
+			"status":   status,
+		}}}
+	}
+	jp, err := parseSortBy(".status.value")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name    string
+		objects []clusterObject
+		want    []string
+	}{
+		{"numbers", []clusterObject{
+			object("c1", "a", float64(10)), object("c1", "b", float64(2)), object("c2", "c", int64(5)),
+		}, []string{"b", "c", "a"}},
+		{"quantities", []clusterObject{
+			object("c1", "a", "1Gi"), object("c2", "b", "500Mi"), object("c2", "c", "2Gi"),
+		}, []string{"b", "a", "c"}},
+		{"timestamps", []clusterObject{
+			object("c1", "a", "2024-03-01T10:00:00Z"), object("c2", "b", "2024-01-15T08:00:00Z"),
+		}, []string{"b", "a"}},
+		{"missing last and ties stable", []clusterObject{
+			object("c1", "a", nil), object("c1", "b", "x"), object("c2", "c", "x"), object("c2", "d", "w"),
+		}, []string{"d", "b", "c", "a"}},
+		{"mixed types", []clusterObject{
+			object("c1", "a", "web"), object("c1", "b", float64(3)), object("c2", "c", true), object("c2", "d", nil),
+		}, []string{"c", "b", "a", "d"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sortObjects(tt.objects, jp)
+			var got []string
+			for _, o := range tt.objects {
+				got = append(got, o.Object.GetName())
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("expected %v, got %v", tt.want, got)
+			}
+			for i := range got {
+				if got[i] != tt.want[i] {
+					t.Fatalf("expected %v, got %v", tt.want, got)
+				}
+			}
+		})
+	}
+}
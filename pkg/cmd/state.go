@@ -0,0 +1,75 @@
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"kubectl-multi/pkg/util"
+)
+
+// runState records when commands run with --since-last-run last completed
+type runState struct {
+	LastRun map[string]time.Time `json:"lastRun"`
+}
+
+// runStateFile returns the path of the state file in the plugin's cache directory
+func runStateFile() (string, error) {
+	dir, err := util.CacheDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(dir, "last-run.json"), nil
+}
+
+// runStateKey identifies a command, its selector and the namespaces it runs in in the state file, e.g.
+// "delete pods -l app=e2e -n test". Without -n or -A, every cluster uses the namespace of its context.
+func runStateKey(command, selector, namespace string, allNamespaces bool) string {
+	key := strings.TrimSpace(command)
+	if selector != "" {
+		key += " -l " + selector
+	}
+	if allNamespaces {
+		key += " -A"
+	} else if namespace != "" {
+		key += " -n " + namespace
+	}
+	return key
+}
+
+// loadRunState reads the state file, a missing file is an empty state
+func loadRunState(path string) (*runState, error) {
+	state := &runState{LastRun: map[string]time.Time{}}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return state, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read state file: %v", err)
+	}
+	if err := json.Unmarshal(data, state); err != nil {
+		return nil, fmt.Errorf("failed to parse state file %s: %v", path, err)
+	}
+	if state.LastRun == nil {
+		state.LastRun = map[string]time.Time{}
+	}
+	return state, nil
+}
+
+// save writes the state file, creating its directory if needed
+func (s *runState) save(path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return fmt.Errorf("failed to create state directory: %v", err)
+	}
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, data, 0o644); err != nil {
+		return fmt.Errorf("failed to write state file: %v", err)
+	}
+	return nil
+}
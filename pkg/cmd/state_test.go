@@ -0,0 +1,114 @@
+package cmd
+
+import (
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// TestRunStatePersistence ensures recorded run times survive a save and load
+func TestRunStatePersistence(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "cache", "last-run.json")
+
+	state, err := loadRunState(path)
+	if err != nil {
+		t.Fatalf("unexpected error for a missing state file: %v", err)
+	}
+	if len(state.LastRun) != 0 {
+		t.Fatalf("expected an empty state, got %v", state.LastRun)
+	}
+
+	key := runStateKey("delete pods ", "app=e2e", "", false)
+	if key != "delete pods -l app=e2e" {
+		t.Errorf("unexpected key %q", key)
+	}
+	lastRun := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	state.LastRun[key] = lastRun
+	if err := state.save(path); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	loaded, err := loadRunState(path)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if got := loaded.LastRun[key]; !got.Equal(lastRun) {
+		t.Errorf("expected last run %v, got %v", lastRun, got)
+	}
+	if _, ok := loaded.LastRun[runStateKey("delete pods", "app=other", "", false)]; ok {
+		t.Error("expected other selectors to have no recorded run")
+	}
+}
+
+// TestRunStateKeyNamespaces ensures the runs in different namespaces, and across all namespaces, keep
+// separate marks
+func TestRunStateKeyNamespaces(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "last-run.json")
+	state, _ := loadRunState(path)
+	inA := runStateKey("delete pods ", "app=e2e", "a", false)
+	if inA != "delete pods -l app=e2e -n a" {
+		t.Errorf("unexpected key %q", inA)
+	}
+	lastRun := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	state.LastRun[inA] = lastRun
+	if err := state.save(path); err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	loaded, _ := loadRunState(path)
+	if got := loaded.LastRun[inA]; !got.Equal(lastRun) {
+		t.Errorf("expected last run %v in namespace a, got %v", lastRun, got)
+	}
+	for _, key := range []string{
+		runStateKey("delete pods ", "app=e2e", "b", false),
+		runStateKey("delete pods ", "app=e2e", "", true),
+		runStateKey("delete pods ", "app=e2e", "a", true),
+		runStateKey("delete pods ", "app=e2e", "", false),
+	} {
+		if _, ok := loaded.LastRun[key]; ok {
+			t.Errorf("expected %q to have no recorded run", key)
+		}
+	}
+}
+
+// TestAgeFilterSinceLastRun ensures only resources created since the last run are selected
+func TestAgeFilterSinceLastRun(t *testing.T) {
+	lastRun := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	now := lastRun.Add(48 * time.Hour)
+	resources := []agedResource{
+		{Name: "before", CreationTimestamp: lastRun.Add(-time.Hour)},
+		{Name: "at", CreationTimestamp: lastRun},
+		{Name: "after", CreationTimestamp: lastRun.Add(time.Hour)},
+		{Name: "recent", CreationTimestamp: now.Add(-time.Minute)},
+		{Name: "no-timestamp"},
+	}
+
+	got := ageFilter{SinceLastRun: true, Since: lastRun}.apply(resources, now)
+	if names := agedNames(got); names != "at,after,recent" {
+		t.Errorf("unexpected selection %s", names)
+	}
+
+	// Combined with --older-than, recent resources are left alone
+	got = ageFilter{OlderThan: time.Hour, SinceLastRun: true, Since: lastRun}.apply(resources, now)
+	if names := agedNames(got); names != "at,after" {
+		t.Errorf("unexpected selection %s", names)
+	}
+
+	// Without a previous run every resource with a timestamp is selected
+	got = ageFilter{SinceLastRun: true}.apply(resources, now)
+	if len(got) != 4 {
+		t.Errorf("expected 4 resources without a previous run, got %d", len(got))
+	}
+}
+
+// agedNames joins the names of resources with commas
+func agedNames(resources []agedResource) string {
+	names := ""
+	for i, r := range resources {
+		if i > 0 {
+			names += ","
+		}
+		names += r.Name
+	}
+	return names
+}
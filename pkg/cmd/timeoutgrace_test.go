@@ -0,0 +1,61 @@
+package cmd
+
+import (
+	"context"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeTrapKubectl is a kubectl that runs until it is stopped. On SIGTERM it writes its cleanup to
+// $FAKE_CLEANUP and exits, unless $FAKE_IGNORE_TERM is set, in which case only SIGKILL stops it.
+const fakeTrapKubectl = `#!/bin/sh
+if [ -n "$FAKE_IGNORE_TERM" ]; then
+	trap '' TERM
+else
+	trap 'echo cleaned up > "$FAKE_CLEANUP"; exit 143' TERM
+fi
+while :; do sleep 0.05; done
+`
+
+// TestStopGracefully ensures a timed out kubectl gets SIGTERM, sent to kubectl itself rather than its
+// process group, and the grace period to clean up, and is killed once the grace period elapsed when it
+// ignores SIGTERM
+func TestStopGracefully(t *testing.T) {
+	installFakeKubectl(t, fakeTrapKubectl)
+	defer func(g time.Duration) { timeoutGrace = g }(timeoutGrace)
+	// kubectl is left in the terminal's process group, for Ctrl-C to reach it
+	probe := exec.Command(kubectlPath)
+	stopGracefully(probe, time.Second)
+	if probe.SysProcAttr != nil || probe.WaitDelay != time.Second {
+		t.Errorf("expected kubectl to keep its process group and be killed a second after SIGTERM, got %+v and %s", probe.SysProcAttr, probe.WaitDelay)
+	}
+	cleanup := filepath.Join(t.TempDir(), "cleanup")
+	t.Setenv("FAKE_CLEANUP", cleanup)
+
+	timeoutGrace = 5 * time.Second
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	if err := runKubectlTo(ctx, []string{"get", "pods"}, "", io.Discard); err == nil {
+		t.Fatal("expected the timed out kubectl to fail")
+	}
+	if data, _ := os.ReadFile(cleanup); strings.TrimSpace(string(data)) != "cleaned up" {
+		t.Errorf("expected kubectl to clean up on SIGTERM, got %q", data)
+	}
+
+	t.Setenv("FAKE_IGNORE_TERM", "1")
+	timeoutGrace = 300 * time.Millisecond
+	ctx, cancel = context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	if err := runKubectlTo(ctx, []string{"get", "pods"}, "", io.Discard); err == nil {
+		t.Fatal("expected the killed kubectl to fail")
+	}
+	if elapsed := time.Since(start); elapsed < 500*time.Millisecond || elapsed > 5*time.Second {
+		t.Errorf("expected kubectl to be killed after the grace period, took %s", elapsed)
+	}
+}
@@ -0,0 +1,23 @@
+//go:build !windows
+
+package cmd
+
+import (
+	"os/exec"
+	"syscall"
+	"time"
+)
+
+// stopGracefully makes the cancellation of cmd's context send SIGTERM to kubectl, then kill it once
+// grace has elapsed if it is still running, so it can clean up. kubectl stays in the terminal's process
+// group, so Ctrl-C still reaches it. With no grace, cmd is killed at once as usual.
+func stopGracefully(cmd *exec.Cmd, grace time.Duration) {
+	if grace <= 0 {
+		return
+	}
+	cmd.Cancel = func() error {
+		return cmd.Process.Signal(syscall.SIGTERM)
+	}
+	// Wait kills kubectl and closes its pipes once grace has elapsed after the SIGTERM
+	cmd.WaitDelay = grace
+}
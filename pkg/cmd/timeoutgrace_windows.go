@@ -0,0 +1,12 @@
+//go:build windows
+
+package cmd
+
+import (
+	"os/exec"
+	"time"
+)
+
+// stopGracefully does nothing on Windows, which cannot send SIGTERM: cmd is killed at once when its
+// context is cancelled
+func stopGracefully(cmd *exec.Cmd, grace time.Duration) {}
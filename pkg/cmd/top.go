@@ -0,0 +1,136 @@
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"kubectl-multi/pkg/cluster"
+
+	"github.com/spf13/cobra"
+)
+
+func newTopCommand() *cobra.Command {
+	var selector string
+	var sortBy string
+	var containers bool
+
+	cmd := &cobra.Command{
+		Use:   "top (node | pod) [NAME | -l label]",
+		Short: "Display resource (CPU/memory/storage) usage across managed clusters",
+		Long: `Display the CPU and memory usage of nodes or pods in every managed cluster, from the Metrics API.
+
+Clusters without metrics-server fail without affecting the others, and are listed after the
+summary so metrics-server can be installed where it is missing.`,
+		Example: `# Show the usage of the nodes of every managed cluster
+kubectl multi top node
+
+# Show the usage of the pods of an app, heaviest first
+kubectl multi top pod -l app=web -A --sort-by=memory`,
+		Args: cobra.RangeArgs(1, 2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			switch strings.ToLower(args[0]) {
+			case "node", "nodes", "no":
+				if containers {
+					return fmt.Errorf("--containers can only be used with pods")
+				}
+			case "pod", "pods", "po":
+			default:
+				return fmt.Errorf("top supports node and pod, not %q", args[0])
+			}
+			kubeconfig, remoteCtx, _, namespace, allNamespaces := GetGlobalFlags()
+			return handleTopCommand(args, selector, sortBy, containers, kubeconfig, remoteCtx, namespace, allNamespaces)
+		},
+	}
+
+	cmd.Flags().StringVarP(&selector, "selector", "l", "", "selector (label query) to filter on")
+	cmd.Flags().StringVar(&sortBy, "sort-by", "", "sort the resources of each cluster by cpu or memory")
+	cmd.Flags().BoolVar(&containers, "containers", false, "print the usage of the containers within the pods")
+
+	return cmd
+}
+
+func handleTopCommand(args []string, selector, sortBy string, containers bool, kubeconfig, remoteCtx, namespace string, allNamespaces bool) error {
+	clusters, unreachable, err := discoverClusters(kubeconfig, remoteCtx)
+	if err != nil {
+		return fmt.Errorf("failed to discover clusters: %v", err)
+	}
+	if len(clusters) == 0 {
+		return fmt.Errorf("no clusters discovered")
+	}
+
+	results, err := fanOut(clusters, kubeconfig, remoteCtx, fanOutOptions{Unreachable: unreachable}, kubectlOp(kubeconfig, func(c cluster.ClusterInfo) []string {
+		return buildTopArgs(args, selector, sortBy, containers, namespace, allNamespaces, c.Context)
+	}))
+	printMissingMetrics(os.Stdout, results)
+	return err
+}
+
+// buildTopArgs builds the arguments of `kubectl top` for a cluster
+func buildTopArgs(resource []string, selector, sortBy string, containers bool, namespace string, allNamespaces bool, context string) []string {
+	args := append([]string{"top"}, resource...)
+	if selector != "" {
+		args = append(args, "-l", selector)
+	}
+	if sortBy != "" {
+		args = append(args, "--sort-by="+sortBy)
+	}
+	if containers {
+		args = append(args, "--containers")
+	}
+	isNode := false
+	switch strings.ToLower(resource[0]) {
+	case "node", "nodes", "no":
+		isNode = true
+	}
+	if !isNode {
+		if allNamespaces {
+			args = append(args, "-A")
+		} else if namespace != "" {
+			args = append(args, "-n", namespace)
+		}
+	}
+	return append(args, "--context", context)
+}
+
+// metricsUnavailableMessages are the errors of kubectl top when the Metrics API is not served,
+// i.e. metrics-server is not installed or not ready yet
+var metricsUnavailableMessages = []string{
+	"metrics api not available",
+	"metrics not available yet",
+}
+
+// isMetricsUnavailable reports whether a kubectl top failure comes from the Metrics API being unavailable,
+// as opposed to e.g. missing permissions on it
+func isMetricsUnavailable(err error) bool {
+	var kerr *kubectlError
+	if !errors.As(err, &kerr) {
+		return false
+	}
+	stderr := strings.ToLower(kerr.Stderr)
+	for _, msg := range metricsUnavailableMessages {
+		if strings.Contains(stderr, msg) {
+			return true
+		}
+	}
+	// The metrics.k8s.io API group is not registered, or its APIService has no healthy backend
+	return strings.Contains(stderr, "metrics.k8s.io") &&
+		(strings.Contains(stderr, "could not find the requested resource") || strings.Contains(stderr, "currently unable to handle the request"))
+}
+
+// printMissingMetrics lists the clusters whose Metrics API is unavailable, so metrics-server can be
+// installed there
+func printMissingMetrics(out io.Writer, results []clusterResult) {
+	var missing []string
+	for _, r := range results {
+		if r.Err != nil && isMetricsUnavailable(r.Err) {
+			missing = append(missing, r.displayName())
+		}
+	}
+	if len(missing) == 0 {
+		return
+	}
+	fmt.Fprintf(out, "Metrics API not available in %d of %d clusters, install metrics-server there: %s\n", len(missing), len(results), strings.Join(missing, ", "))
+}
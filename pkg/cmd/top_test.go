@@ -0,0 +1,69 @@
+package cmd
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// TestIsMetricsUnavailable ensures only a missing or unhealthy Metrics API is reported as such
+func TestIsMetricsUnavailable(t *testing.T) {
+	tests := []struct {
+		stderr string
+		want   bool
+	}{
+		{"error: Metrics API not available\n", true},
+		{"error: metrics not available yet\n", true},
+		{"Error from server (NotFound): the server could not find the requested resource (get pods.metrics.k8s.io)\n", true},
+		{"Error from server (ServiceUnavailable): the server is currently unable to handle the request (get nodes.metrics.k8s.io)\n", true},
+		{`Error from server (Forbidden): pods.metrics.k8s.io is forbidden: User "dev" cannot list resource "pods"` + "\n", false},
+		{"Unable to connect to the server: dial tcp 10.0.0.1:6443: connect: connection refused\n", false},
+	}
+	for _, tt := range tests {
+		err := &kubectlError{ExitCode: 1, Stderr: tt.stderr, Err: errors.New("exit status 1")}
+		if got := isMetricsUnavailable(err); got != tt.want {
+			t.Errorf("isMetricsUnavailable(%q) = %v, want %v", tt.stderr, got, tt.want)
+		}
+	}
+	if isMetricsUnavailable(errors.New("Metrics API not available")) {
+		t.Error("expected errors that did not come from kubectl to be ignored")
+	}
+}
+
+// TestPrintMissingMetrics ensures the clusters lacking metrics-server are counted and named
+func TestPrintMissingMetrics(t *testing.T) {
+	missing := &kubectlError{ExitCode: 1, Stderr: "error: Metrics API not available\n", Err: errors.New("exit status 1")}
+	results := []clusterResult{
+		{Context: "cluster1"},
+		{Context: "cluster2", DisplayName: "edge-1", Err: missing},
+		{Context: "cluster3", Err: errors.New("connection refused")},
+		{Context: "cluster4", Err: missing},
+	}
+
+	var out bytes.Buffer
+	printMissingMetrics(&out, results)
+	want := "Metrics API not available in 2 of 4 clusters, install metrics-server there: edge-1, cluster4\n"
+	if out.String() != want {
+		t.Errorf("expected %q, got %q", want, out.String())
+	}
+
+	out.Reset()
+	printMissingMetrics(&out, results[:1])
+	if out.Len() != 0 {
+		t.Errorf("expected no summary when every cluster has metrics, got %q", out.String())
+	}
+}
+
+// TestBuildTopArgs ensures namespaces are only passed for pods
+func TestBuildTopArgs(t *testing.T) {
+	got := buildTopArgs([]string{"pod"}, "app=web", "memory", true, "shop", false, "cluster1")
+	want := []string{"top", "pod", "-l", "app=web", "--sort-by=memory", "--containers", "-n", "shop", "--context", "cluster1"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("expected %v, got %v", want, got)
+	}
+	if got := buildTopArgs([]string{"nodes"}, "", "", false, "shop", true, "cluster1"); strings.Contains(strings.Join(got, " "), "-n") || strings.Contains(strings.Join(got, " "), "-A") {
+		t.Errorf("expected no namespace for nodes, got %v", got)
+	}
+}
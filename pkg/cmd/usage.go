@@ -0,0 +1,192 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"text/tabwriter"
+
+	"kubectl-multi/pkg/cluster"
+	"kubectl-multi/pkg/util"
+
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	"k8s.io/apimachinery/pkg/runtime"
+)
+
+// resourceUsage holds the summed CPU and memory requests and limits of a set of pods
+type resourceUsage struct {
+	CPURequests    resource.Quantity
+	CPULimits      resource.Quantity
+	MemoryRequests resource.Quantity
+	MemoryLimits   resource.Quantity
+}
+
+// addPod adds the requests and limits of the pod's containers
+func (u *resourceUsage) addPod(pod *corev1.Pod) {
+	for _, c := range pod.Spec.Containers {
+		if q, ok := c.Resources.Requests[corev1.ResourceCPU]; ok {
+			u.CPURequests.Add(q)
+		}
+		if q, ok := c.Resources.Limits[corev1.ResourceCPU]; ok {
+			u.CPULimits.Add(q)
+		}
+		if q, ok := c.Resources.Requests[corev1.ResourceMemory]; ok {
+			u.MemoryRequests.Add(q)
+		}
+		if q, ok := c.Resources.Limits[corev1.ResourceMemory]; ok {
+			u.MemoryLimits.Add(q)
+		}
+	}
+}
+
+// add adds other to u
+func (u *resourceUsage) add(other resourceUsage) {
+	u.CPURequests.Add(other.CPURequests)
+	u.CPULimits.Add(other.CPULimits)
+	u.MemoryRequests.Add(other.MemoryRequests)
+	u.MemoryLimits.Add(other.MemoryLimits)
+}
+
+// usageKey identifies a row of the usage report
+type usageKey struct {
+	Cluster   string
+	Namespace string
+}
+
+func newUsageCommand() *cobra.Command {
+	var by string
+
+	cmd := &cobra.Command{
+		Use:   "usage",
+		Short: "Show CPU and memory requests and limits summed across all managed clusters",
+		Long: `Sum the CPU and memory requests and limits of all running pods in all managed clusters
+and print a per-cluster (or per-namespace) breakdown followed by a fleet-wide total.
+Pods that have completed are not counted.`,
+		Example: `# Requests and limits per cluster
+kubectl multi usage
+
+# Requests and limits per namespace of each cluster
+kubectl multi usage --by=namespace
+
+# Only count the pods of one namespace
+kubectl multi usage -n production`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			kubeconfig, remoteCtx, _, namespace, allNamespaces := GetGlobalFlags()
+			return handleUsageCommand(by, kubeconfig, remoteCtx, namespace, allNamespaces)
+		},
+	}
+
+	cmd.Flags().StringVar(&by, "by", "cluster", "group the report by cluster or namespace")
+
+	return cmd
+}
+
+func handleUsageCommand(by, kubeconfig, remoteCtx, namespace string, allNamespaces bool) error {
+	if by != "cluster" && by != "namespace" {
+		return fmt.Errorf("invalid --by value %q: must be one of cluster|namespace", by)
+	}
+
+	clusters, _, err := discoverClusters(kubeconfig, remoteCtx)
+	if err != nil {
+		return fmt.Errorf("failed to discover clusters: %v", err)
+	}
+	if len(clusters) == 0 {
+		return fmt.Errorf("no clusters discovered")
+	}
+
+	// Capacity is a fleet-wide view, so count every namespace unless one is given
+	if namespace == "" {
+		allNamespaces = true
+	}
+
+	pods := fetchPods(clusters, kubeconfig, "", namespace, allNamespaces)
+	printUsageReport(util.GetOutputStream(), sumUsage(pods, by == "namespace"), by == "namespace")
+	return nil
+}
+
+// clusterPod is a pod tagged with the cluster it runs in
+type clusterPod struct {
+	Cluster string
+	Pod     *corev1.Pod
+}
+
+// fetchPods lists the pods matching selector in every cluster as typed pods
+func fetchPods(clusters []cluster.ClusterInfo, kubeconfig, selector, namespace string, allNamespaces bool) []clusterPod {
+	var pods []clusterPod
+	for _, o := range fetchObjects(clusters, kubeconfig, "pods", "", selector, namespace, allNamespaces, defaultChunkSize) {
+		pod := &corev1.Pod{}
+		if err := runtime.DefaultUnstructuredConverter.FromUnstructured(o.Object.Object, pod); err != nil {
+			fmt.Printf("Warning: failed to read pod %s in cluster %s: %v\n", o.Object.GetName(), o.Cluster, err)
+			continue
+		}
+		pods = append(pods, clusterPod{Cluster: o.Cluster, Pod: pod})
+	}
+	return pods
+}
+
+// sumUsage sums the requests and limits of running pods per cluster, or per namespace of each cluster
+func sumUsage(pods []clusterPod, byNamespace bool) map[usageKey]*resourceUsage {
+	usage := make(map[usageKey]*resourceUsage)
+	for _, p := range pods {
+		if p.Pod.Status.Phase == corev1.PodSucceeded || p.Pod.Status.Phase == corev1.PodFailed {
+			continue
+		}
+		key := usageKey{Cluster: p.Cluster}
+		if byNamespace {
+			key.Namespace = p.Pod.Namespace
+		}
+		if usage[key] == nil {
+			usage[key] = &resourceUsage{}
+		}
+		usage[key].addPod(p.Pod)
+	}
+	return usage
+}
+
+// printUsageReport prints one row per key in sorted order followed by the fleet total
+func printUsageReport(out io.Writer, usage map[usageKey]*resourceUsage, byNamespace bool) {
+	keys := make([]usageKey, 0, len(usage))
+	for k := range usage {
+		keys = append(keys, k)
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i].Cluster != keys[j].Cluster {
+			return keys[i].Cluster < keys[j].Cluster
+		}
+		return keys[i].Namespace < keys[j].Namespace
+	})
+
+	tw := tabwriter.NewWriter(out, 0, 0, 2, ' ', 0)
+	defer tw.Flush()
+
+	if byNamespace {
+		fmt.Fprintf(tw, "CLUSTER\tNAMESPACE\tCPU REQUESTS\tCPU LIMITS\tMEMORY REQUESTS\tMEMORY LIMITS\n")
+	} else {
+		fmt.Fprintf(tw, "CLUSTER\tCPU REQUESTS\tCPU LIMITS\tMEMORY REQUESTS\tMEMORY LIMITS\n")
+	}
+
+	var total resourceUsage
+	for _, k := range keys {
+		u := usage[k]
+		total.add(*u)
+		if byNamespace {
+			fmt.Fprintf(tw, "%s\t%s\t%s\n", k.Cluster, k.Namespace, formatUsage(u))
+		} else {
+			fmt.Fprintf(tw, "%s\t%s\n", k.Cluster, formatUsage(u))
+		}
+	}
+
+	if byNamespace {
+		fmt.Fprintf(tw, "TOTAL\t\t%s\n", formatUsage(&total))
+	} else {
+		fmt.Fprintf(tw, "TOTAL\t%s\n", formatUsage(&total))
+	}
+}
+
+// formatUsage renders the quantities of u as tab-separated columns
+func formatUsage(u *resourceUsage) string {
+	return fmt.Sprintf("%s\t%s\t%s\t%s", u.CPURequests.String(), u.CPULimits.String(), u.MemoryRequests.String(), u.MemoryLimits.String())
+}
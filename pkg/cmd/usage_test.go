@@ -0,0 +1,72 @@
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func testPod(namespace, cpuRequest, memLimit string, phase corev1.PodPhase) *corev1.Pod {
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: "pod", Namespace: namespace},
+		Spec: corev1.PodSpec{Containers: []corev1.Container{{
+			Resources: corev1.ResourceRequirements{
+				Requests: corev1.ResourceList{corev1.ResourceCPU: resource.MustParse(cpuRequest)},
+				Limits:   corev1.ResourceList{corev1.ResourceMemory: resource.MustParse(memLimit)},
+			},
+		}}},
+		Status: corev1.PodStatus{Phase: phase},
+	}
+}
+
+// TestSumUsageByCluster ensures requests and limits are summed per cluster and completed pods are ignored
+func TestSumUsageByCluster(t *testing.T) {
+	pods := []clusterPod{
+		{Cluster: "wds1", Pod: testPod("web", "250m", "256Mi", corev1.PodRunning)},
+		{Cluster: "wds1", Pod: testPod("db", "500m", "1Gi", corev1.PodRunning)},
+		{Cluster: "wds1", Pod: testPod("web", "4", "4Gi", corev1.PodSucceeded)},
+		{Cluster: "wds2", Pod: testPod("web", "1", "512Mi", corev1.PodPending)},
+	}
+
+	usage := sumUsage(pods, false)
+	wds1 := usage[usageKey{Cluster: "wds1"}]
+	if wds1 == nil || wds1.CPURequests.String() != "750m" || wds1.MemoryLimits.String() != "1280Mi" {
+		t.Errorf("unexpected wds1 usage: %+v", wds1)
+	}
+	if wds2 := usage[usageKey{Cluster: "wds2"}]; wds2 == nil || wds2.CPURequests.String() != "1" {
+		t.Errorf("unexpected wds2 usage: %+v", wds2)
+	}
+}
+
+// TestSumUsageByNamespace ensures each namespace of each cluster gets its own row
+func TestSumUsageByNamespace(t *testing.T) {
+	pods := []clusterPod{
+		{Cluster: "wds1", Pod: testPod("web", "250m", "256Mi", corev1.PodRunning)},
+		{Cluster: "wds1", Pod: testPod("db", "500m", "1Gi", corev1.PodRunning)},
+	}
+
+	usage := sumUsage(pods, true)
+	if len(usage) != 2 || usage[usageKey{Cluster: "wds1", Namespace: "db"}].CPURequests.String() != "500m" {
+		t.Errorf("unexpected usage: %v", usage)
+	}
+}
+
+// TestPrintUsageReportTotal ensures the report ends with the fleet-wide total
+func TestPrintUsageReportTotal(t *testing.T) {
+	pods := []clusterPod{
+		{Cluster: "wds1", Pod: testPod("web", "250m", "256Mi", corev1.PodRunning)},
+		{Cluster: "wds2", Pod: testPod("web", "750m", "768Mi", corev1.PodRunning)},
+	}
+
+	var out bytes.Buffer
+	printUsageReport(&out, sumUsage(pods, false), false)
+
+	lines := strings.Split(strings.TrimSpace(out.String()), "\n")
+	if got := strings.Join(strings.Fields(lines[len(lines)-1]), " "); got != "TOTAL 1 0 0 1Gi" {
+		t.Errorf("unexpected total row: %q", got)
+	}
+}
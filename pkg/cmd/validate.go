@@ -0,0 +1,75 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+
+	"kubectl-multi/pkg/cluster"
+)
+
+// validateOnClusters runs the command built by buildArgs with --dry-run=server on every target cluster,
+// so each cluster validates the manifests against its own schema (including its CRDs). The validation
+// runs up to --parallel clusters at once within --timeout and --timeout-total, but is not a fan-out of
+// its own: it has no hooks, --stagger, progress nor summary, and --count-only only counts the operation.
+// Clusters the fan-out would skip are not validated. It returns an error listing the clusters that
+// rejected the manifests, except for the failures accepted by accept, if set.
+func validateOnClusters(clusters []cluster.ClusterInfo, kubeconfig, remoteCtx string, opts fanOutOptions, buildArgs func(c cluster.ClusterInfo) []string, accept func(err error) bool) error {
+	opts.NoHooks = true
+	validate := kubectlOp(kubeconfig, func(c cluster.ClusterInfo) []string {
+		return append(buildArgs(c), "--dry-run=server")
+	})
+	op := func(ctx context.Context, c cluster.ClusterInfo, out io.Writer) error {
+		err := validate(ctx, c, out)
+		if err != nil && accept != nil && accept(err) {
+			return nil
+		}
+		return err
+	}
+
+	ctx, cancel := totalContext()
+	defer cancel()
+
+	// The operation itself reports the unreachable clusters and the ITS cluster, they are not validated
+	targets, _ := fanOutTargets(clusters, "", itsContext(remoteCtx))
+	results := make([]clusterResult, len(targets))
+	concurrency := parallelism
+	if concurrency < 1 {
+		concurrency = 1
+	}
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for i, c := range targets {
+		wg.Add(1)
+		go func(i int, c cluster.ClusterInfo) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			results[i] = runOnCluster(ctx, c, opts, op, io.Discard)
+		}(i, c)
+	}
+	wg.Wait()
+	sortResults(results, sortClustersBy, clusters)
+
+	// Only the clusters rejecting the manifests are shown, on stderr with --count-only so that stdout only
+	// holds the counts of the operation
+	out := io.Writer(os.Stdout)
+	if countOnly {
+		out = os.Stderr
+	}
+	var failed []string
+	for _, r := range results {
+		if r.Err != nil {
+			fmt.Fprintf(out, "Validation failed on cluster %s:\n%s\n", r.displayName(), strings.TrimSpace(r.Output))
+			failed = append(failed, r.displayName())
+		}
+	}
+	if len(failed) > 0 {
+		return fmt.Errorf("validation failed on %d of %d clusters (%s), nothing was changed; use --validate=false to skip validation",
+			len(failed), len(results), strings.Join(failed, ", "))
+	}
+	return nil
+}
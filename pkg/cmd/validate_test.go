@@ -0,0 +1,203 @@
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"kubectl-multi/pkg/cluster"
+)
+
+// fakeValidateKubectl is a kubectl logging its arguments to $FAKE_CALLS, where cluster2 rejects the
+// manifests and the namespace already exists in cluster3
+const fakeValidateKubectl = `#!/bin/sh
+echo "$*" >> "$FAKE_CALLS"
+case "$*" in
+*"--context cluster2"*) echo 'error: error validating "app.yaml": unknown field "replica"' >&2; exit 1;;
+*"--context cluster3"*) echo 'Error from server (AlreadyExists): namespaces "shop" already exists' >&2; exit 1;;
+esac
+echo 'configured (server dry run)'
+`
+
+// validateCalls installs the fake kubectl and returns a function reading the calls it received
+func validateCalls(t *testing.T) func() []string {
+	installFakeKubectl(t, fakeValidateKubectl)
+	calls := filepath.Join(t.TempDir(), "calls")
+	t.Setenv("FAKE_CALLS", calls)
+	return func() []string {
+		data, _ := os.ReadFile(calls)
+		return strings.Split(strings.TrimSpace(string(data)), "\n")
+	}
+}
+
+// TestValidateOnClusters ensures every cluster validates the manifests with a server dry run, in
+// parallel with --parallel, and that a single rejection fails the validation naming the cluster
+func TestValidateOnClusters(t *testing.T) {
+	defer func(n int) { parallelism = n }(parallelism)
+	calls := validateCalls(t)
+
+	clusters := []cluster.ClusterInfo{{Context: "cluster1"}, {Context: "cluster2", DisplayName: "eu"}, {Context: "cluster3"}}
+	for _, n := range []int{1, 3} {
+		parallelism = n
+		err := validateOnClusters(clusters, "", "", fanOutOptions{}, func(c cluster.ClusterInfo) []string {
+			return []string{"apply", "-f", "app.yaml", "--context", c.Context}
+		}, nil)
+		if err == nil || !strings.Contains(err.Error(), "validation failed on 2 of 3 clusters (eu, cluster3), nothing was changed") {
+			t.Errorf("--parallel %d: expected eu and cluster3 to fail the validation, got %v", n, err)
+		}
+	}
+	got := calls()
+	if len(got) != 6 {
+		t.Fatalf("expected 3 validations per run, got %v", got)
+	}
+	for _, call := range got {
+		if !strings.HasSuffix(call, "--dry-run=server") {
+			t.Errorf("expected only server dry runs, got %s", call)
+		}
+	}
+}
+
+// TestValidateOnClustersAccept ensures create --ignore-exists does not fail the validation of the
+// clusters that already have the resources, while other rejections still fail it
+func TestValidateOnClustersAccept(t *testing.T) {
+	validateCalls(t)
+
+	buildArgs := func(c cluster.ClusterInfo) []string {
+		return []string{"create", "-f", "ns.yaml", "--context", c.Context}
+	}
+	err := validateOnClusters([]cluster.ClusterInfo{{Context: "cluster1"}, {Context: "cluster3"}}, "", "", fanOutOptions{}, buildArgs, isAlreadyExists)
+	if err != nil {
+		t.Errorf("expected AlreadyExists to be accepted, got %v", err)
+	}
+	err = validateOnClusters([]cluster.ClusterInfo{{Context: "cluster2"}, {Context: "cluster3"}}, "", "", fanOutOptions{}, buildArgs, isAlreadyExists)
+	if err == nil || !strings.Contains(err.Error(), "validation failed on 1 of 2 clusters (cluster2)") {
+		t.Errorf("expected cluster2 to fail the validation, got %v", err)
+	}
+}
+
+// TestValidateOnClustersNoHooks ensures the hooks only run around the operation, not its validation
+func TestValidateOnClustersNoHooks(t *testing.T) {
+	validateCalls(t)
+	marker := filepath.Join(t.TempDir(), "hook")
+	setHooks(t, "touch "+marker, "", hookFailAbort)
+
+	err := validateOnClusters([]cluster.ClusterInfo{{Context: "cluster1"}}, "", "", fanOutOptions{}, func(c cluster.ClusterInfo) []string {
+		return []string{"apply", "-f", "app.yaml", "--context", c.Context}
+	}, nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if _, err := os.Stat(marker); err == nil {
+		t.Error("expected the pre-hook not to run for the validation")
+	}
+}
+
+// useFakeContexts writes a kubeconfig holding the given contexts, which are the clusters discovered
+// with --all-contexts until the test ends, and returns its path. The caches of the plugin are written
+// to a temporary directory.
+func useFakeContexts(t *testing.T, contexts ...string) string {
+	t.Helper()
+	useTempDirs(t)
+	var b strings.Builder
+	b.WriteString("apiVersion: v1\nkind: Config\nclusters:\n")
+	for _, c := range contexts {
+		fmt.Fprintf(&b, "- name: %s\n  cluster:\n    server: https://127.0.0.1:1\n", c)
+	}
+	b.WriteString("users:\n- name: user\n  user:\n    token: fake\ncontexts:\n")
+	for _, c := range contexts {
+		fmt.Fprintf(&b, "- name: %s\n  context:\n    cluster: %s\n    user: user\n", c, c)
+	}
+	kubeconfig := filepath.Join(t.TempDir(), "config")
+	if err := os.WriteFile(kubeconfig, []byte(b.String()), 0o600); err != nil {
+		t.Fatal(err)
+	}
+
+	previous, reachability, versions := allContexts, reachabilityCache, serverVersionCache
+	allContexts = true
+	t.Cleanup(func() { allContexts, reachabilityCache, serverVersionCache = previous, reachability, versions })
+	return kubeconfig
+}
+
+// captureOutput returns what f printed to stdout and stderr
+func captureOutput(t *testing.T, f func()) (string, string) {
+	t.Helper()
+	stdout, stderr := os.Stdout, os.Stderr
+	defer func() { os.Stdout, os.Stderr = stdout, stderr }()
+
+	read := func(w **os.File) <-chan string {
+		r, pipe, err := os.Pipe()
+		if err != nil {
+			t.Fatal(err)
+		}
+		*w = pipe
+		done := make(chan string)
+		go func() {
+			data, _ := io.ReadAll(r)
+			done <- string(data)
+		}()
+		return done
+	}
+	outDone, errDone := read(&os.Stdout), read(&os.Stderr)
+	f()
+	os.Stdout.Close()
+	os.Stderr.Close()
+	return <-outDone, <-errDone
+}
+
+// TestApplyValidateStagger ensures --stagger only waits between the clusters of the apply, not between
+// those of its validation
+func TestApplyValidateStagger(t *testing.T) {
+	defer func(d time.Duration, n int) { stagger, parallelism = d, n }(stagger, parallelism)
+	calls := validateCalls(t)
+	kubeconfig := useFakeContexts(t, "cluster1", "cluster4")
+	stagger, parallelism = 10*time.Millisecond, 1
+
+	var err error
+	stdout, _ := captureOutput(t, func() {
+		err = handleApplyCommand([]string{"app.yaml"}, "", false, "none", false, true, kubeconfig, "", "", false)
+	})
+	if err != nil {
+		t.Fatalf("unexpected error: %v\n%s", err, stdout)
+	}
+	if n := strings.Count(stdout, "(--stagger)"); n != 1 {
+		t.Errorf("expected a single wait between the 2 clusters of the apply, got %d:\n%s", n, stdout)
+	}
+	if n := strings.Count(stdout, "Summary:"); n != 1 {
+		t.Errorf("expected a single summary, got:\n%s", stdout)
+	}
+	if got := calls(); len(got) != 4 {
+		t.Errorf("expected 2 validations and 2 applies, got %v", got)
+	}
+}
+
+// TestCreateValidateCountOnly ensures --count-only prints the counts of the create alone on stdout, and
+// the clusters failing the validation on stderr
+func TestCreateValidateCountOnly(t *testing.T) {
+	defer func(b bool) { countOnly = b }(countOnly)
+	validateCalls(t)
+	countOnly = true
+
+	kubeconfig := useFakeContexts(t, "cluster1", "cluster4")
+	var err error
+	stdout, _ := captureOutput(t, func() {
+		err = handleCreateCommand("ns.yaml", false, "none", true, false, kubeconfig, "", "", false)
+	})
+	if err != nil || strings.Count(stdout, "\n") != 1 || !strings.HasPrefix(stdout, "succeeded=2 failed=0 ") {
+		t.Errorf("expected only the counts of the create on stdout, got %v:\n%s", err, stdout)
+	}
+
+	kubeconfig = useFakeContexts(t, "cluster1", "cluster2")
+	stdout, stderr := captureOutput(t, func() {
+		err = handleCreateCommand("ns.yaml", false, "none", true, false, kubeconfig, "", "", false)
+	})
+	if err == nil || !strings.Contains(err.Error(), "validation failed on 1 of 2 clusters (cluster2)") {
+		t.Errorf("expected cluster2 to fail the validation, got %v", err)
+	}
+	if stdout != "" || !strings.Contains(stderr, "Validation failed on cluster cluster2:\nerror: error validating") {
+		t.Errorf("expected the validation failure on stderr only, got stdout %q and stderr %q", stdout, stderr)
+	}
+}
@@ -0,0 +1,176 @@
+package cmd
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+
+	"kubectl-multi/pkg/cluster"
+	"kubectl-multi/pkg/util"
+
+	"github.com/spf13/cobra"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/watch"
+)
+
+// rewatchDelay is how long to wait before re-establishing a watch that failed to start
+const rewatchDelay = 5 * time.Second
+
+func newWatchRestartsCommand() *cobra.Command {
+	var selector string
+	var threshold int
+
+	cmd := &cobra.Command{
+		Use:   "watch-restarts [-l SELECTOR]",
+		Short: "Alert when pods restart in any managed cluster",
+		Long: `Watch pods in every managed cluster and print an alert line, tagged with the cluster,
+whenever the restart count of a pod increases. The command runs until interrupted with Ctrl-C.`,
+		Example: `# Alert on every restart of the web pods
+kubectl multi watch-restarts -l app=web
+
+# Only alert once a pod has restarted more than 3 times, in all namespaces
+kubectl multi watch-restarts -A --threshold 3`,
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			kubeconfig, remoteCtx, _, namespace, allNamespaces := GetGlobalFlags()
+			return handleWatchRestartsCommand(selector, threshold, kubeconfig, remoteCtx, namespace, allNamespaces)
+		},
+	}
+
+	cmd.Flags().StringVarP(&selector, "selector", "l", "", "selector (label query) of the pods to watch")
+	cmd.Flags().IntVar(&threshold, "threshold", 0, "only alert when the restart count of a pod is above this value")
+
+	return cmd
+}
+
+func handleWatchRestartsCommand(selector string, threshold int, kubeconfig, remoteCtx, namespace string, allNamespaces bool) error {
+	clusters, _, err := discoverClusters(kubeconfig, remoteCtx)
+	if err != nil {
+		return fmt.Errorf("failed to discover clusters: %v", err)
+	}
+	targets, _ := fanOutTargets(clusters, currentKubeContext(kubeconfig), itsContext(remoteCtx))
+	if len(targets) == 0 {
+		return fmt.Errorf("no clusters discovered")
+	}
+
+	// Ctrl-C cancels ctx, which stops every watch
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	tracker := newRestartTracker(util.GetOutputStream(), threshold)
+	fmt.Printf("Watching pod restarts in %d clusters, press Ctrl-C to stop\n", len(targets))
+
+	var wg sync.WaitGroup
+	for _, c := range targets {
+		if c.Client == nil {
+			fmt.Printf("Warning: no client available for cluster %s\n", c.Display())
+			continue
+		}
+		ns := cluster.TargetNamespace(c, namespace)
+		if allNamespaces {
+			ns = ""
+		}
+		wg.Add(1)
+		go func(c cluster.ClusterInfo) {
+			defer wg.Done()
+			watchPodRestarts(ctx, c, ns, selector, tracker)
+		}(c)
+	}
+	wg.Wait()
+	return nil
+}
+
+// watchPodRestarts feeds the pod events of a cluster to the tracker until ctx is done, re-establishing
+// the watch when the API server closes it
+func watchPodRestarts(ctx context.Context, c cluster.ClusterInfo, namespace, selector string, tracker *restartTracker) {
+	for ctx.Err() == nil {
+		w, err := c.Client.CoreV1().Pods(namespace).Watch(ctx, metav1.ListOptions{LabelSelector: selector})
+		if err != nil {
+			if ctx.Err() == nil {
+				tracker.warn(fmt.Sprintf("Warning: failed to watch pods in cluster %s: %v", c.Display(), err))
+			}
+			select {
+			case <-ctx.Done():
+			case <-time.After(rewatchDelay):
+			}
+			continue
+		}
+		for event := range w.ResultChan() {
+			pod, ok := event.Object.(*corev1.Pod)
+			if !ok {
+				continue
+			}
+			tracker.observe(c.Display(), event.Type, pod, time.Now())
+		}
+		w.Stop()
+	}
+}
+
+// restartTracker remembers the restart count of every pod seen in every cluster and prints an
+// alert when it increases. The watches of all clusters share it, so it is guarded by a mutex.
+type restartTracker struct {
+	mu        sync.Mutex
+	out       io.Writer
+	threshold int
+	restarts  map[string]int32
+}
+
+func newRestartTracker(out io.Writer, threshold int) *restartTracker {
+	return &restartTracker{out: out, threshold: threshold, restarts: make(map[string]int32)}
+}
+
+// podRestarts returns the total restart count of the containers of a pod
+func podRestarts(pod *corev1.Pod) int32 {
+	var total int32
+	for _, cs := range pod.Status.InitContainerStatuses {
+		total += cs.RestartCount
+	}
+	for _, cs := range pod.Status.ContainerStatuses {
+		total += cs.RestartCount
+	}
+	return total
+}
+
+// observe records a pod event. The first event of a pod only records its count, later events
+// alert when the count increased above the threshold.
+func (t *restartTracker) observe(clusterName string, eventType watch.EventType, pod *corev1.Pod, now time.Time) {
+	key := clusterName + "/" + pod.Namespace + "/" + pod.Name
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	if eventType == watch.Deleted {
+		delete(t.restarts, key)
+		return
+	}
+	restarts := podRestarts(pod)
+	previous, seen := t.restarts[key]
+	t.restarts[key] = restarts
+	if !seen || restarts <= previous || int(restarts) <= t.threshold {
+		return
+	}
+	fmt.Fprintf(t.out, "%s [%s] pod %s/%s restarted: %d -> %d restarts%s\n",
+		now.Format(time.RFC3339), clusterName, pod.Namespace, pod.Name, previous, restarts, lastTerminationReason(pod))
+}
+
+// warn prints a message without interleaving with the alerts of other clusters
+func (t *restartTracker) warn(msg string) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	fmt.Fprintln(t.out, msg)
+}
+
+// lastTerminationReason returns the reason the last restarted container terminated, e.g. " (OOMKilled)"
+func lastTerminationReason(pod *corev1.Pod) string {
+	for _, cs := range pod.Status.ContainerStatuses {
+		if term := cs.LastTerminationState.Terminated; term != nil && term.Reason != "" {
+			return fmt.Sprintf(" (%s)", term.Reason)
+		}
+	}
+	return ""
+}
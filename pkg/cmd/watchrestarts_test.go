@@ -0,0 +1,69 @@
+package cmd
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/watch"
+)
+
+func restartTestPod(name string, restarts int32, reason string) *corev1.Pod {
+	status := corev1.ContainerStatus{Name: "app", RestartCount: restarts}
+	if reason != "" {
+		status.LastTerminationState.Terminated = &corev1.ContainerStateTerminated{Reason: reason}
+	}
+	return &corev1.Pod{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"},
+		Status:     corev1.PodStatus{ContainerStatuses: []corev1.ContainerStatus{status}},
+	}
+}
+
+// TestRestartTrackerAlerts ensures only increases of a known pod's restart count are reported, per cluster
+func TestRestartTrackerAlerts(t *testing.T) {
+	var buf bytes.Buffer
+	tracker := newRestartTracker(&buf, 0)
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+
+	tracker.observe("cluster1", watch.Added, restartTestPod("web-1", 2, ""), now)
+	tracker.observe("cluster2", watch.Added, restartTestPod("web-1", 0, ""), now)
+	tracker.observe("cluster1", watch.Modified, restartTestPod("web-1", 2, ""), now)
+	tracker.observe("cluster1", watch.Modified, restartTestPod("web-1", 3, "OOMKilled"), now)
+
+	want := "2024-06-01T12:00:00Z [cluster1] pod default/web-1 restarted: 2 -> 3 restarts (OOMKilled)\n"
+	if buf.String() != want {
+		t.Errorf("expected:\n%s\ngot:\n%s", want, buf.String())
+	}
+}
+
+// TestRestartTrackerThreshold ensures no alert is printed until the count exceeds --threshold
+func TestRestartTrackerThreshold(t *testing.T) {
+	var buf bytes.Buffer
+	tracker := newRestartTracker(&buf, 3)
+	now := time.Now()
+
+	tracker.observe("cluster1", watch.Added, restartTestPod("web-1", 0, ""), now)
+	for i := int32(1); i <= 4; i++ {
+		tracker.observe("cluster1", watch.Modified, restartTestPod("web-1", i, ""), now)
+	}
+	if got := strings.Count(buf.String(), "\n"); got != 1 || !strings.Contains(buf.String(), "3 -> 4 restarts") {
+		t.Errorf("expected a single alert for the 4th restart, got:\n%s", buf.String())
+	}
+}
+
+// TestRestartTrackerDeleted ensures a recreated pod with the same name starts from its new count
+func TestRestartTrackerDeleted(t *testing.T) {
+	var buf bytes.Buffer
+	tracker := newRestartTracker(&buf, 0)
+	now := time.Now()
+
+	tracker.observe("cluster1", watch.Added, restartTestPod("web-1", 5, ""), now)
+	tracker.observe("cluster1", watch.Deleted, restartTestPod("web-1", 5, ""), now)
+	tracker.observe("cluster1", watch.Added, restartTestPod("web-1", 1, ""), now)
+	if buf.Len() != 0 {
+		t.Errorf("expected no alert, got:\n%s", buf.String())
+	}
+}
@@ -0,0 +1,38 @@
+package cmd
+
+import (
+	"kubectl-multi/pkg/cluster"
+
+	"github.com/spf13/cobra"
+)
+
+func newWDSCommand() *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "wds",
+		Short: "Run commands on the WDS (workload description) clusters only",
+		Long: `The wds commands run only on the WDS contexts of the kubeconfig, never on the ITS (control) cluster
+or the managed clusters, so scripts state which clusters they mean.
+
+They are the fleet commands of the same name restricted to the contexts classified as WDS, the ones
+--all-contexts reports with the wds role. The cluster selection flags, such as --clusters and
+--where, then choose among the WDS contexts.`,
+		Example: `# List the deployments of every WDS
+kubectl multi wds get deployments -A
+
+# Apply a BindingPolicy to every WDS
+kubectl multi wds apply -f bindingpolicy.yaml`,
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			if err := loadConfigDefaults(cmd, args); err != nil {
+				return err
+			}
+			// WDS contexts are only discovered among all the contexts of the kubeconfig
+			allContexts = true
+			clusterRole = cluster.RoleWDS
+			return nil
+		},
+	}
+
+	cmd.AddCommand(newGetCommand())
+	cmd.AddCommand(newApplyCommand())
+	return cmd
+}
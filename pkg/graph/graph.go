@@ -0,0 +1,143 @@
+package graph
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/types"
+)
+
+// Node is an object in the ownership tree of a workload
+type Node struct {
+	Cluster   string `json:"cluster"`
+	Kind      string `json:"kind"`
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name"`
+	// Status is a short state of the object, the phase of pods
+	Status   string  `json:"status,omitempty"`
+	Children []*Node `json:"children,omitempty"`
+}
+
+// ID returns a name identifying the node across clusters, e.g. "cluster1/ReplicaSet/default/web-7d9f"
+func (n *Node) ID() string {
+	return strings.Join([]string{n.Cluster, n.Kind, n.Namespace, n.Name}, "/")
+}
+
+// Label returns the name under which the node is displayed, e.g. "ReplicaSet/web-7d9f"
+func (n *Node) Label() string {
+	if n.Status != "" {
+		return fmt.Sprintf("%s/%s (%s)", n.Kind, n.Name, n.Status)
+	}
+	return n.Kind + "/" + n.Name
+}
+
+// Build returns the tree of root and the objects it transitively owns, found through the
+// owner references of objects. Objects not owned by root are ignored.
+func Build(clusterName string, root *unstructured.Unstructured, objects []*unstructured.Unstructured) *Node {
+	owned := make(map[types.UID][]*unstructured.Unstructured)
+	for _, obj := range objects {
+		for _, ref := range obj.GetOwnerReferences() {
+			owned[ref.UID] = append(owned[ref.UID], obj)
+		}
+	}
+
+	visited := make(map[types.UID]bool)
+	var build func(obj *unstructured.Unstructured) *Node
+	build = func(obj *unstructured.Unstructured) *Node {
+		visited[obj.GetUID()] = true
+		node := newNode(clusterName, obj)
+		for _, child := range owned[obj.GetUID()] {
+			if visited[child.GetUID()] {
+				continue
+			}
+			node.Children = append(node.Children, build(child))
+		}
+		sort.Slice(node.Children, func(i, j int) bool {
+			if node.Children[i].Kind != node.Children[j].Kind {
+				return node.Children[i].Kind < node.Children[j].Kind
+			}
+			return node.Children[i].Name < node.Children[j].Name
+		})
+		return node
+	}
+	return build(root)
+}
+
+func newNode(clusterName string, obj *unstructured.Unstructured) *Node {
+	node := &Node{Cluster: clusterName, Kind: obj.GetKind(), Namespace: obj.GetNamespace(), Name: obj.GetName()}
+	if obj.GetKind() == "Pod" {
+		node.Status, _, _ = unstructured.NestedString(obj.Object, "status", "phase")
+	}
+	return node
+}
+
+// WriteTree prints the trees as text, one per cluster:
+//
+//	cluster1
+//	└── Deployment/web
+//	    └── ReplicaSet/web-7d9f
+//	        └── Pod/web-7d9f-x2x4 (Running)
+func WriteTree(w io.Writer, roots []*Node) error {
+	for i, root := range roots {
+		if i > 0 {
+			fmt.Fprintln(w)
+		}
+		if _, err := fmt.Fprintln(w, root.Cluster); err != nil {
+			return err
+		}
+		writeTreeNode(w, root, "", true)
+	}
+	return nil
+}
+
+func writeTreeNode(w io.Writer, node *Node, prefix string, last bool) {
+	branch, indent := "├── ", "│   "
+	if last {
+		branch, indent = "└── ", "    "
+	}
+	fmt.Fprintf(w, "%s%s%s\n", prefix, branch, node.Label())
+	for i, child := range node.Children {
+		writeTreeNode(w, child, prefix+indent, i == len(node.Children)-1)
+	}
+}
+
+// WriteDOT prints the trees as a single Graphviz graph with one subgraph per cluster
+func WriteDOT(w io.Writer, roots []*Node) error {
+	var b strings.Builder
+	b.WriteString("digraph workload {\n")
+	b.WriteString("  rankdir=LR;\n")
+	for i, root := range roots {
+		fmt.Fprintf(&b, "  subgraph cluster_%d {\n", i)
+		fmt.Fprintf(&b, "    label=%q;\n", root.Cluster)
+		writeDOTNode(&b, root)
+		b.WriteString("  }\n")
+	}
+	b.WriteString("}\n")
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+func writeDOTNode(b *strings.Builder, node *Node) {
+	fmt.Fprintf(b, "    %q [label=%q];\n", node.ID(), node.Label())
+	for _, child := range node.Children {
+		fmt.Fprintf(b, "    %q -> %q;\n", node.ID(), child.ID())
+		writeDOTNode(b, child)
+	}
+}
+
+// WriteJSON prints the trees as a JSON array
+func WriteJSON(w io.Writer, roots []*Node) error {
+	if roots == nil {
+		roots = []*Node{}
+	}
+	data, err := json.MarshalIndent(roots, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(w, string(data))
+	return err
+}
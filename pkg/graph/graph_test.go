@@ -0,0 +1,102 @@
+package graph
+
+import (
+	"bytes"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+func testObject(kind, name, uid, ownerUID string) *unstructured.Unstructured {
+	obj := map[string]interface{}{
+		"kind":     kind,
+		"metadata": map[string]interface{}{"name": name, "namespace": "default", "uid": uid},
+	}
+	if ownerUID != "" {
+		obj["metadata"].(map[string]interface{})["ownerReferences"] = []interface{}{
+			map[string]interface{}{"uid": ownerUID, "name": "owner", "kind": "Owner", "apiVersion": "v1"},
+		}
+	}
+	if kind == "Pod" {
+		obj["status"] = map[string]interface{}{"phase": "Running"}
+	}
+	return &unstructured.Unstructured{Object: obj}
+}
+
+func testTree() *Node {
+	root := testObject("Deployment", "web", "d1", "")
+	objects := []*unstructured.Unstructured{
+		testObject("ReplicaSet", "web-new", "rs2", "d1"),
+		testObject("ReplicaSet", "web-old", "rs1", "d1"),
+		testObject("Pod", "web-new-a", "p1", "rs2"),
+		testObject("Pod", "other", "p2", "rs9"),
+	}
+	return Build("cluster1", root, objects)
+}
+
+// TestBuild ensures owned objects are found transitively and unrelated objects are left out
+func TestBuild(t *testing.T) {
+	tree := testTree()
+	if tree.Kind != "Deployment" || len(tree.Children) != 2 {
+		t.Fatalf("expected a deployment with 2 replicasets, got %+v", tree)
+	}
+	if tree.Children[0].Name != "web-new" || tree.Children[1].Name != "web-old" {
+		t.Errorf("expected the replicasets sorted by name, got %s, %s", tree.Children[0].Name, tree.Children[1].Name)
+	}
+	pods := tree.Children[0].Children
+	if len(pods) != 1 || pods[0].Name != "web-new-a" || pods[0].Status != "Running" {
+		t.Errorf("expected web-new to own the running pod web-new-a, got %+v", pods)
+	}
+}
+
+// TestWriteTree ensures the tree is printed under the cluster name with box-drawing branches
+func TestWriteTree(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteTree(&buf, []*Node{testTree()}); err != nil {
+		t.Fatal(err)
+	}
+	want := `cluster1
+└── Deployment/web
+    ├── ReplicaSet/web-new
+    │   └── Pod/web-new-a (Running)
+    └── ReplicaSet/web-old
+`
+	if buf.String() != want {
+		t.Errorf("expected:\n%s\ngot:\n%s", want, buf.String())
+	}
+}
+
+// TestWriteDOT ensures every cluster gets a subgraph and owner edges are drawn
+func TestWriteDOT(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteDOT(&buf, []*Node{testTree()}); err != nil {
+		t.Fatal(err)
+	}
+	out := buf.String()
+	for _, want := range []string{
+		`label="cluster1";`,
+		`"cluster1/Deployment/default/web" -> "cluster1/ReplicaSet/default/web-new";`,
+		`"cluster1/ReplicaSet/default/web-new" -> "cluster1/Pod/default/web-new-a";`,
+	} {
+		if !strings.Contains(out, want) {
+			t.Errorf("expected the graph to contain %s, got:\n%s", want, out)
+		}
+	}
+}
+
+// TestWriteJSON ensures the JSON output keeps the cluster of every node
+func TestWriteJSON(t *testing.T) {
+	var buf bytes.Buffer
+	if err := WriteJSON(&buf, []*Node{testTree()}); err != nil {
+		t.Fatal(err)
+	}
+	var roots []*Node
+	if err := json.Unmarshal(buf.Bytes(), &roots); err != nil {
+		t.Fatalf("invalid JSON: %v", err)
+	}
+	if len(roots) != 1 || roots[0].Cluster != "cluster1" || roots[0].Children[0].Children[0].Cluster != "cluster1" {
+		t.Errorf("unexpected JSON graph: %s", buf.String())
+	}
+}
@@ -0,0 +1,304 @@
+package selector
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode"
+
+	"kubectl-multi/pkg/cluster"
+)
+
+// Selector is a parsed cluster selection expression, such as
+//
+//	name =~ prod-.* && region == us-east
+//
+// Comparisons have a field on the left and a value on the right, with == and != comparing strings and
+// =~ and !~ matching a regular expression against the whole value. They are combined with &&, || and !,
+// and grouped with parentheses; && binds tighter than ||. A field on its own is true when it is set.
+//
+// The fields are name, context, display (the alias, else the context) and role. Any other field is a
+// cluster label, which is empty when the cluster does not have it; labels.KEY reads the label KEY even
+// when it is one of the field names. Values containing spaces, parentheses or &, |, =, ! must be quoted
+// with ' or ".
+type Selector struct {
+	expr string
+	root node
+}
+
+// Parse parses a selection expression
+func Parse(expr string) (*Selector, error) {
+	tokens, err := lex(expr)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{tokens: tokens}
+	if p.peek().kind == tokenEOF {
+		return nil, fmt.Errorf("empty expression")
+	}
+	root, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if t := p.peek(); t.kind != tokenEOF {
+		return nil, fmt.Errorf("position %d: unexpected %s", t.pos, t)
+	}
+	return &Selector{expr: expr, root: root}, nil
+}
+
+// Matches tells whether the cluster satisfies the expression
+func (s *Selector) Matches(c cluster.ClusterInfo) bool {
+	return s.root.eval(c)
+}
+
+// String returns the expression the selector was parsed from
+func (s *Selector) String() string {
+	return s.expr
+}
+
+// node is an expression evaluated against a cluster
+type node interface {
+	eval(c cluster.ClusterInfo) bool
+}
+
+type andNode struct{ left, right node }
+
+func (n andNode) eval(c cluster.ClusterInfo) bool { return n.left.eval(c) && n.right.eval(c) }
+
+type orNode struct{ left, right node }
+
+func (n orNode) eval(c cluster.ClusterInfo) bool { return n.left.eval(c) || n.right.eval(c) }
+
+type notNode struct{ operand node }
+
+func (n notNode) eval(c cluster.ClusterInfo) bool { return !n.operand.eval(c) }
+
+// setNode is a field on its own, true when the field is not empty
+type setNode struct{ field string }
+
+func (n setNode) eval(c cluster.ClusterInfo) bool { return fieldValue(c, n.field) != "" }
+
+// compareNode compares a field with a value, or matches it against a regular expression
+type compareNode struct {
+	field string
+	op    string
+	value string
+	re    *regexp.Regexp
+}
+
+func (n compareNode) eval(c cluster.ClusterInfo) bool {
+	v := fieldValue(c, n.field)
+	switch n.op {
+	case "==":
+		return v == n.value
+	case "!=":
+		return v != n.value
+	case "=~":
+		return n.re.MatchString(v)
+	default: // !~
+		return !n.re.MatchString(v)
+	}
+}
+
+// fieldValue returns the value of a field of the cluster, or of the label of that name
+func fieldValue(c cluster.ClusterInfo, field string) string {
+	switch field {
+	case "name":
+		return c.Name
+	case "context":
+		return c.Context
+	case "display":
+		return c.Display()
+	case "role":
+		return c.Role
+	}
+	return c.Labels[strings.TrimPrefix(field, "labels.")]
+}
+
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenWord
+	tokenString
+	tokenOp
+	tokenAnd
+	tokenOr
+	tokenNot
+	tokenLParen
+	tokenRParen
+)
+
+// token is a lexical element of an expression, pos is its 1-based position
+type token struct {
+	kind tokenKind
+	text string
+	pos  int
+}
+
+func (t token) String() string {
+	switch t.kind {
+	case tokenEOF:
+		return "end of expression"
+	case tokenString:
+		return fmt.Sprintf("string %q", t.text)
+	}
+	return fmt.Sprintf("%q", t.text)
+}
+
+// lex splits an expression into tokens
+func lex(expr string) ([]token, error) {
+	var tokens []token
+	runes := []rune(expr)
+	for i := 0; i < len(runes); {
+		r := runes[i]
+		pos := i + 1
+		next := func(n int) string {
+			if i+n > len(runes) {
+				return string(runes[i:])
+			}
+			return string(runes[i : i+n])
+		}
+		switch {
+		case unicode.IsSpace(r):
+			i++
+		case r == '(':
+			tokens = append(tokens, token{tokenLParen, "(", pos})
+			i++
+		case r == ')':
+			tokens = append(tokens, token{tokenRParen, ")", pos})
+			i++
+		case next(2) == "&&":
+			tokens = append(tokens, token{tokenAnd, "&&", pos})
+			i += 2
+		case next(2) == "||":
+			tokens = append(tokens, token{tokenOr, "||", pos})
+			i += 2
+		case next(2) == "==" || next(2) == "!=" || next(2) == "=~" || next(2) == "!~":
+			tokens = append(tokens, token{tokenOp, next(2), pos})
+			i += 2
+		case r == '!':
+			tokens = append(tokens, token{tokenNot, "!", pos})
+			i++
+		case r == '"' || r == '\'':
+			end := i + 1
+			for end < len(runes) && runes[end] != r {
+				end++
+			}
+			if end == len(runes) {
+				return nil, fmt.Errorf("position %d: unterminated string", pos)
+			}
+			tokens = append(tokens, token{tokenString, string(runes[i+1 : end]), pos})
+			i = end + 1
+		case strings.ContainsRune("&|=", r):
+			return nil, fmt.Errorf("position %d: unexpected %q, use &&, || or one of ==, !=, =~, !~", pos, r)
+		default:
+			end := i
+			for end < len(runes) && !unicode.IsSpace(runes[end]) && !strings.ContainsRune("()&|=!\"'", runes[end]) {
+				end++
+			}
+			tokens = append(tokens, token{tokenWord, string(runes[i:end]), pos})
+			i = end
+		}
+	}
+	return append(tokens, token{kind: tokenEOF, pos: len(runes) + 1}), nil
+}
+
+// parser is a recursive descent parser of the grammar
+//
+//	or         = and { "||" and }
+//	and        = unary { "&&" unary }
+//	unary      = "!" unary | "(" or ")" | comparison
+//	comparison = field [ ( "==" | "!=" | "=~" | "!~" ) value ]
+type parser struct {
+	tokens []token
+	i      int
+}
+
+func (p *parser) peek() token {
+	return p.tokens[p.i]
+}
+
+func (p *parser) next() token {
+	t := p.tokens[p.i]
+	if t.kind != tokenEOF {
+		p.i++
+	}
+	return t
+}
+
+func (p *parser) parseOr() (node, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().kind == tokenOr {
+		p.next()
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = orNode{left, right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseAnd() (node, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.peek().kind == tokenAnd {
+		p.next()
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = andNode{left, right}
+	}
+	return left, nil
+}
+
+func (p *parser) parseUnary() (node, error) {
+	switch t := p.next(); t.kind {
+	case tokenNot:
+		operand, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return notNode{operand}, nil
+	case tokenLParen:
+		inner, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if closing := p.next(); closing.kind != tokenRParen {
+			return nil, fmt.Errorf("position %d: expected \")\" to close the \"(\" at position %d, got %s", closing.pos, t.pos, closing)
+		}
+		return inner, nil
+	case tokenWord:
+		return p.parseComparison(t)
+	default:
+		return nil, fmt.Errorf("position %d: expected a field, \"!\" or \"(\", got %s", t.pos, t)
+	}
+}
+
+func (p *parser) parseComparison(field token) (node, error) {
+	if p.peek().kind != tokenOp {
+		return setNode{field.text}, nil
+	}
+	op := p.next()
+	value := p.next()
+	if value.kind != tokenWord && value.kind != tokenString {
+		return nil, fmt.Errorf("position %d: expected a value after %s, got %s", value.pos, op.text, value)
+	}
+	n := compareNode{field: field.text, op: op.text, value: value.text}
+	if op.text == "=~" || op.text == "!~" {
+		re, err := regexp.Compile("^(?:" + value.text + ")$")
+		if err != nil {
+			return nil, fmt.Errorf("position %d: invalid regular expression %q: %v", value.pos, value.text, err)
+		}
+		n.re = re
+	}
+	return n, nil
+}
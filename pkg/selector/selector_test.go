@@ -0,0 +1,110 @@
+package selector
+
+import (
+	"strings"
+	"testing"
+
+	"kubectl-multi/pkg/cluster"
+)
+
+var testClusters = []cluster.ClusterInfo{
+	{Name: "prod-us", Context: "prod-us", Role: cluster.RoleManaged, Labels: map[string]string{"region": "us-east", "tier": "prod", "gpu": "true"}},
+	{Name: "prod-eu", Context: "prod-eu", DisplayName: "paris", Role: cluster.RoleManaged, Labels: map[string]string{"region": "eu-west", "tier": "prod", "name": "eu-1"}},
+	{Name: "staging-us", Context: "staging-us", Role: cluster.RoleManaged, Labels: map[string]string{"region": "us-east", "tier": "staging"}},
+	{Name: "kind-local", Context: "kind-local", Role: cluster.RoleLocal},
+}
+
+// matching returns the contexts of the test clusters the expression selects
+func matching(t *testing.T, expr string) string {
+	t.Helper()
+	s, err := Parse(expr)
+	if err != nil {
+		t.Fatalf("%s: unexpected error: %v", expr, err)
+	}
+	var contexts []string
+	for _, c := range testClusters {
+		if s.Matches(c) {
+			contexts = append(contexts, c.Context)
+		}
+	}
+	return strings.Join(contexts, ",")
+}
+
+// TestMatches ensures comparisons, regular expressions, labels and boolean operators select the right clusters
+func TestMatches(t *testing.T) {
+	for _, tc := range []struct {
+		expr string
+		want string
+	}{
+		{"name =~ prod-.* && region == us-east", "prod-us"},
+		{"name =~ prod-.*", "prod-us,prod-eu"},
+		{"name=~prod-.*&&region==us-east", "prod-us"},
+		// Regular expressions match the whole value
+		{"name =~ prod", ""},
+		{"name =~ .*-us", "prod-us,staging-us"},
+		{"name !~ prod-.*", "staging-us,kind-local"},
+		{"region == us-east", "prod-us,staging-us"},
+		// Clusters without the label have an empty value
+		{"region != us-east", "prod-eu,kind-local"},
+		{"region == ''", "kind-local"},
+		{"tier == prod || tier == staging", "prod-us,prod-eu,staging-us"},
+		// && binds tighter than ||
+		{"tier == staging || region == eu-west && tier == prod", "prod-eu,staging-us"},
+		{"(tier == staging || region == eu-west) && name =~ .*-us", "staging-us"},
+		{"!(tier == prod)", "staging-us,kind-local"},
+		{"!!gpu", "prod-us"},
+		{"gpu", "prod-us"},
+		{"!region", "kind-local"},
+		{"display == paris", "prod-eu"},
+		{"context == kind-local || role == local", "kind-local"},
+		{"role == managed && !gpu", "prod-eu,staging-us"},
+		// labels.KEY reads a label named like a field
+		{"labels.name == eu-1", "prod-eu"},
+		{`name == "prod-eu"`, "prod-eu"},
+		{`name =~ 'prod-(us|eu)'`, "prod-us,prod-eu"},
+		{`topology.kubernetes.io/zone == ""`, "prod-us,prod-eu,staging-us,kind-local"},
+	} {
+		if got := matching(t, tc.expr); got != tc.want {
+			t.Errorf("%s: expected %q, got %q", tc.expr, tc.want, got)
+		}
+	}
+}
+
+// TestParseErrors ensures malformed expressions are rejected with the position of the problem
+func TestParseErrors(t *testing.T) {
+	for _, tc := range []struct {
+		expr string
+		want string
+	}{
+		{"", "empty expression"},
+		{"   ", "empty expression"},
+		{"name ==", "position 8: expected a value after =="},
+		{"name == prod &&", "position 16: expected a field"},
+		{"name == prod & region == us", "position 14: unexpected '&'"},
+		{"name = prod", "position 6: unexpected '='"},
+		{"(name == prod", `position 14: expected ")" to close the "(" at position 1`},
+		{"name == prod)", `position 13: unexpected ")"`},
+		{"name == prod region", `position 14: unexpected "region"`},
+		{"== prod", "position 1: expected a field"},
+		{`name == "prod`, "position 9: unterminated string"},
+		{"name =~ prod-[", "position 9: invalid regular expression"},
+		{"name == prod || || tier", "position 17: expected a field"},
+	} {
+		_, err := Parse(tc.expr)
+		if err == nil || !strings.Contains(err.Error(), tc.want) {
+			t.Errorf("%q: expected an error containing %q, got %v", tc.expr, tc.want, err)
+		}
+	}
+}
+
+// TestString ensures a selector keeps the expression it was parsed from
+func TestString(t *testing.T) {
+	expr := "name =~ prod-.* && region == us-east"
+	s, err := Parse(expr)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if s.String() != expr {
+		t.Errorf("expected %q, got %q", expr, s.String())
+	}
+}
@@ -0,0 +1,39 @@
+package util
+
+import (
+	"path/filepath"
+	"strings"
+)
+
+// DefaultInvocationName is how the plugin is run when installed as kubectl-multi on the PATH
+const DefaultInvocationName = "kubectl multi"
+
+// InvocationName is the command the user runs the plugin with, shown in usage strings and examples
+var InvocationName = DefaultInvocationName
+
+// InvocationNameFromBinary returns the command a binary is run with. kubectl plugins are run through
+// kubectl, so kubectl-multi is "kubectl multi" and kubectl-fleet_ops is "kubectl fleet-ops", following
+// the kubectl plugin naming rules. Other binaries are run directly under their own name.
+func InvocationNameFromBinary(arg0 string) string {
+	name := strings.TrimSuffix(filepath.Base(arg0), ".exe")
+	if name == "" || name == "." {
+		return DefaultInvocationName
+	}
+	plugin, ok := strings.CutPrefix(name, "kubectl-")
+	if !ok || plugin == "" {
+		return name
+	}
+	words := strings.Split(plugin, "-")
+	for i, w := range words {
+		words[i] = strings.ReplaceAll(w, "_", "-")
+	}
+	return "kubectl " + strings.Join(words, " ")
+}
+
+// RenderUsage replaces the default invocation name in usage strings and examples with InvocationName
+func RenderUsage(text string) string {
+	if InvocationName == DefaultInvocationName {
+		return text
+	}
+	return strings.ReplaceAll(text, DefaultInvocationName, InvocationName)
+}
@@ -109,7 +109,7 @@ func findSubcommand(cmd *cobra.Command, name string) *cobra.Command {
 // FormatMultiClusterHelp combines the kubectl command info with multi-cluster plugin information
 func FormatMultiClusterHelp(cmdInfo *CommandInfo, multiClusterInfo, multiClusterExamples, multiClusterUsage string) string {
 	if cmdInfo == nil {
-		return multiClusterInfo
+		return RenderUsage(multiClusterInfo)
 	}
 
 	// Build the combined help output
@@ -162,7 +162,7 @@ func FormatMultiClusterHelp(cmdInfo *CommandInfo, multiClusterInfo, multiCluster
 		result.WriteString("\n\n")
 	}
 
-	return strings.TrimSpace(result.String())
+	return RenderUsage(strings.TrimSpace(result.String()))
 }
 
 // FormatMultiClusterRootHelp formats the root command help
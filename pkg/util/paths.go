@@ -0,0 +1,35 @@
+package util
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// TempFilePrefix is the prefix of the temporary files and directories created by the plugin
+const TempFilePrefix = "kubectl-multi-"
+
+// CacheDir returns the directory where the plugin keeps its cached state
+// ($XDG_CACHE_HOME/kubectl-multi or the platform equivalent)
+func CacheDir() (string, error) {
+	base, err := os.UserCacheDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to determine user cache directory: %v", err)
+	}
+	return filepath.Join(base, "kubectl-multi"), nil
+}
+
+// TempFiles returns the leftover temporary files and directories created by the plugin
+func TempFiles() ([]string, error) {
+	return filepath.Glob(filepath.Join(os.TempDir(), TempFilePrefix+"*"))
+}
+
+// ConfigDir returns the directory holding the plugin's configuration files
+// ($XDG_CONFIG_HOME/kubectl-multi or the platform equivalent)
+func ConfigDir() (string, error) {
+	base, err := os.UserConfigDir()
+	if err != nil {
+		return "", fmt.Errorf("failed to determine user config directory: %v", err)
+	}
+	return filepath.Join(base, "kubectl-multi"), nil
+}